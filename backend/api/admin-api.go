@@ -0,0 +1,82 @@
+package api
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"area/controller"
+	"area/middlewares"
+	"area/schemas"
+)
+
+// AdminApi is a struct that provides an API for the administrators of the backend.
+// It contains a controller of type AdminController which manages the business logic
+// for the administration of the replicas.
+type AdminApi struct {
+	controller controller.AdminController
+}
+
+// NewAdminAPI creates a new instance of AdminApi with the provided controller and API routes,
+// and registers the admin routes, authorized with the ADMIN_TOKEN environment variable.
+//
+// Parameters:
+//   - controller: An instance of AdminController that handles the business logic for administration.
+//   - apiRoutes: A pointer to a gin.RouterGroup that defines the API routes for administration.
+//
+// Returns:
+//   - A pointer to an initialized AdminApi struct.
+func NewAdminAPI(controller controller.AdminController, apiRoutes *gin.RouterGroup) *AdminApi {
+	apiRoutes = apiRoutes.Group("/admin", middlewares.AuthorizeAdmin())
+	api := AdminApi{
+		controller: controller,
+	}
+	api.GetAreaOwnership(apiRoutes)
+	api.GetProviderMetrics(apiRoutes)
+	return &api
+}
+
+// GetAreaOwnership godoc
+//
+//	@Summary		Get Area Ownership
+//	@Description	get the scheduler nodes of the backend replicas with the areas each one checks
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Success		200	{object}	schemas.AreaOwnership
+//	@Failure		401	{object}	schemas.ErrorResponse
+//	@Failure		403	{object}	schemas.ErrorResponse
+//	@Failure		500	{object}	schemas.ErrorResponse
+//	@Router			/admin/area-ownership [get]
+func (api *AdminApi) GetAreaOwnership(apiRoutes *gin.RouterGroup) {
+	apiRoutes.GET("/area-ownership", func(ctx *gin.Context) {
+		response, err := api.controller.GetAreaOwnership(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response)
+	})
+}
+
+// GetProviderMetrics godoc
+//
+//	@Summary		Get Provider Metrics
+//	@Description	get the requests sent to every provider by the replica, with its rate limits and circuit
+//	@Tags			Admin
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Success		200	{array}		schemas.ProviderMetrics
+//	@Failure		401	{object}	schemas.ErrorResponse
+//	@Failure		403	{object}	schemas.ErrorResponse
+//	@Router			/admin/provider-metrics [get]
+func (api *AdminApi) GetProviderMetrics(apiRoutes *gin.RouterGroup) {
+	apiRoutes.GET("/provider-metrics", func(ctx *gin.Context) {
+		ctx.JSON(http.StatusOK, api.controller.GetProviderMetrics(ctx))
+	})
+}
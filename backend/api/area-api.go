@@ -1,6 +1,7 @@
 package api
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 
@@ -47,6 +48,28 @@ func NewAreaAPI(
 	return &api
 }
 
+// optionErrorResponse answers with the fields that do not match their schema when err
+// is an option validation error.
+//
+// Parameters:
+//   - ctx: The Gin context of the request.
+//   - err: The error returned by the controller.
+//
+// Returns:
+//   - bool: true if the response was sent, false if err is not an option validation error.
+func optionErrorResponse(ctx *gin.Context, err error) bool {
+	var validationErr *schemas.OptionValidationError
+	if !errors.As(err, &validationErr) {
+		return false
+	}
+	ctx.JSON(http.StatusBadRequest, &schemas.OptionErrorResponse{
+		Error:  validationErr.Error(),
+		Option: validationErr.Option,
+		Fields: validationErr.Fields,
+	})
+	return true
+}
+
 // CreateArea godoc
 //
 //	@Summary		Create Area
@@ -57,6 +80,7 @@ func NewAreaAPI(
 //	@Security		bearerAuth
 //	@Param			payload	body		schemas.AreaMessage	true	"Area Payload"
 //	@Success		200		{object}	schemas.Response
+//	@Failure		400		{object}	schemas.OptionErrorResponse
 //	@Failure		500		{object}	schemas.ErrorResponse
 //	@Router			/area [post]
 func (api *AreaApi) CreateArea(apiRoutes *gin.RouterGroup) {
@@ -64,6 +88,9 @@ func (api *AreaApi) CreateArea(apiRoutes *gin.RouterGroup) {
 		response, err := api.controller.CreateArea(ctx)
 		if err != nil {
 			fmt.Printf("Error: %v\n", err.Error())
+			if optionErrorResponse(ctx, err) {
+				return
+			}
 			ctx.JSON(http.StatusInternalServerError, &schemas.ErrorResponse{
 				Error: err.Error(),
 			})
@@ -105,20 +132,25 @@ func (api *AreaApi) GetUserAreas(apiRoutes *gin.RouterGroup) {
 // UpdateUserArea godoc
 //
 //	@Summary		Update User Area
-//	@Description	update user area list
+//	@Description	partially update a user area, only the fields present in the payload are changed
 //	@Tags			Area
 //	@Accept			json
 //	@Produce		json
 //	@Security		bearerAuth
-//	@Param			area	path		schemas.Area	true	"Updated Area"
+//	@Param			payload	body		schemas.AreaUpdateMessage	true	"Area Update"
 //	@Success		200		{object}	schemas.Area
+//	@Failure		400		{object}	schemas.OptionErrorResponse
 //	@Failure		401		{object}	schemas.ErrorResponse
 //	@Failure		500		{object}	schemas.ErrorResponse
 //	@Router			/area [put]
+//	@Router			/area [patch]
 func (api *AreaApi) UpdateUserArea(apiRoutes *gin.RouterGroup) {
-	apiRoutes.PUT("/", func(ctx *gin.Context) {
+	handler := func(ctx *gin.Context) {
 		response, err := api.controller.UpdateUserArea(ctx)
 		if err != nil {
+			if optionErrorResponse(ctx, err) {
+				return
+			}
 			ctx.JSON(http.StatusInternalServerError, &schemas.ErrorResponse{
 				Error: err.Error(),
 			})
@@ -126,7 +158,9 @@ func (api *AreaApi) UpdateUserArea(apiRoutes *gin.RouterGroup) {
 		}
 
 		ctx.JSON(http.StatusOK, response)
-	})
+	}
+	apiRoutes.PUT("/", handler)
+	apiRoutes.PATCH("/", handler)
 }
 
 // DeleteUserArea godoc
@@ -38,20 +38,29 @@ func NewAreaResultAPI(
 		controller: controller,
 	}
 	api.GetUserAreaResultsByAreaID(apiRoutes)
+	api.GetUserWorkflowRunsByAreaID(apiRoutes)
 	return &api
 }
 
 // GetUserAreaResultsByAreaID godoc
 //
 //	@Summary		Get User Area Results By Area ID
-//	@Description	get user areas results list by area id
+//	@Description	get user areas results list by area id, newest first, filtered by status and creation date
 //	@Tags			AreaResults
 //	@Accept			json
 //	@Produce		json
 //	@Security		bearerAuth
-//	@Success		200	{object}	[]schemas.AreaResult
-//	@Failure		401	{object}	schemas.ErrorResponse
-//	@Failure		500	{object}	schemas.ErrorResponse
+//	@Param			id			path		int		true	"Area ID"
+//	@Param			status		query		string	false	"Status of the results"	Enums(success, failure, skipped)
+//	@Param			from		query		string	false	"Results created at or after this RFC 3339 time"
+//	@Param			to			query		string	false	"Results created before this RFC 3339 time"
+//	@Param			page		query		int		false	"Page to return, starting at 1"
+//	@Param			page_size	query		int		false	"Number of results per page"
+//	@Success		200			{object}	[]schemas.AreaResult
+//	@Header			200			{integer}	X-Total-Count	"Number of results matching the filter"
+//	@Failure		400			{object}	schemas.ErrorResponse
+//	@Failure		401			{object}	schemas.ErrorResponse
+//	@Failure		500			{object}	schemas.ErrorResponse
 //	@Router			/area-result/:id [get]
 func (api *AreaResultApi) GetUserAreaResultsByAreaID(apiRoutes *gin.RouterGroup) {
 	apiRoutes.GET("/:id", func(ctx *gin.Context) {
@@ -66,7 +75,66 @@ func (api *AreaResultApi) GetUserAreaResultsByAreaID(apiRoutes *gin.RouterGroup)
 			return
 		}
 
-		response, err := api.controller.GetUserAreaResultsByAreaID(ctx, idInt)
+		var filter schemas.AreaResultFilter
+		err = ctx.ShouldBindQuery(&filter)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		response, total, err := api.controller.GetUserAreaResultsByAreaID(ctx, idInt, filter)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		ctx.Header("X-Total-Count", strconv.FormatInt(total, 10))
+		ctx.JSON(http.StatusOK, response)
+	})
+}
+
+// GetUserWorkflowRunsByAreaID godoc
+//
+//	@Summary		Get User Workflow Runs By Area ID
+//	@Description	get the workflow runs of a user area, newest first, with the status and the output of each step
+//	@Tags			AreaResults
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			id			path		int		true	"Area ID"
+//	@Param			status		query		string	false	"Status of the runs"	Enums(running, success, failure)
+//	@Param			page		query		int		false	"Page to return, starting at 1"
+//	@Param			page_size	query		int		false	"Number of runs per page"
+//	@Success		200			{object}	[]schemas.WorkflowRun
+//	@Header			200			{integer}	X-Total-Count	"Number of runs matching the filter"
+//	@Failure		400			{object}	schemas.ErrorResponse
+//	@Failure		401			{object}	schemas.ErrorResponse
+//	@Failure		500			{object}	schemas.ErrorResponse
+//	@Router			/area-result/:id/workflow-runs [get]
+func (api *AreaResultApi) GetUserWorkflowRunsByAreaID(apiRoutes *gin.RouterGroup) {
+	apiRoutes.GET("/:id/workflow-runs", func(ctx *gin.Context) {
+		idInt, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		var filter schemas.WorkflowRunFilter
+		err = ctx.ShouldBindQuery(&filter)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		response, total, err := api.controller.GetUserWorkflowRunsByAreaID(ctx, idInt, filter)
 		if err != nil {
 			ctx.JSON(http.StatusInternalServerError, &schemas.ErrorResponse{
 				Error: err.Error(),
@@ -74,6 +142,7 @@ func (api *AreaResultApi) GetUserAreaResultsByAreaID(apiRoutes *gin.RouterGroup)
 			return
 		}
 
+		ctx.Header("X-Total-Count", strconv.FormatInt(total, 10))
 		ctx.JSON(http.StatusOK, response)
 	})
 }
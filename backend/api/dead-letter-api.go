@@ -0,0 +1,154 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"area/controller"
+	"area/middlewares"
+	"area/schemas"
+	"area/service"
+)
+
+// DeadLetterApi is a struct that provides an API for handling the dead letters of the user.
+// It contains a controller of type DeadLetterController which manages the business logic
+// for dead letter-related actions.
+type DeadLetterApi struct {
+	controller controller.DeadLetterController
+}
+
+// NewDeadLetterAPI creates a new instance of DeadLetterApi with the provided controller and API
+// routes, and registers the routes to list, retry and discard the dead letters.
+//
+// Parameters:
+//   - controller: An instance of DeadLetterController that handles the business logic for dead letters.
+//   - apiRoutes: A pointer to a gin.RouterGroup that defines the API routes for the dead letters.
+//   - serviceUser: An instance of UserService used for JWT authorization middleware.
+//
+// Returns:
+//   - A pointer to an initialized DeadLetterApi struct.
+func NewDeadLetterAPI(
+	controller controller.DeadLetterController,
+	apiRoutes *gin.RouterGroup,
+	serviceUser service.UserService,
+) *DeadLetterApi {
+	apiRoutes = apiRoutes.Group("/dead-letter", middlewares.AuthorizeJWT(serviceUser))
+	api := DeadLetterApi{
+		controller: controller,
+	}
+	api.GetUserDeadLetters(apiRoutes)
+	api.RetryUserDeadLetter(apiRoutes)
+	api.DiscardUserDeadLetter(apiRoutes)
+	return &api
+}
+
+// deadLetterErrorResponse answers with the error of a request on a dead letter, a 404 status
+// when the user has no such dead letter.
+func deadLetterErrorResponse(ctx *gin.Context, err error) {
+	status := http.StatusInternalServerError
+	if errors.Is(err, schemas.ErrDeadLetterNotFound) {
+		status = http.StatusNotFound
+	}
+	ctx.JSON(status, &schemas.ErrorResponse{
+		Error: err.Error(),
+	})
+}
+
+// GetUserDeadLetters godoc
+//
+//	@Summary		Get User Dead Letters
+//	@Description	get the triggers of the user areas whose reactions failed for good, newest first
+//	@Tags			DeadLetter
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Success		200	{object}	[]schemas.DeadLetter
+//	@Failure		401	{object}	schemas.ErrorResponse
+//	@Failure		500	{object}	schemas.ErrorResponse
+//	@Router			/dead-letter [get]
+func (api *DeadLetterApi) GetUserDeadLetters(apiRoutes *gin.RouterGroup) {
+	apiRoutes.GET("/", func(ctx *gin.Context) {
+		response, err := api.controller.GetUserDeadLetters(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response)
+	})
+}
+
+// RetryUserDeadLetter godoc
+//
+//	@Summary		Retry User Dead Letter
+//	@Description	replay the trigger of a dead letter from its failed reaction and remove it from the list
+//	@Tags			DeadLetter
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			id	path		int	true	"Dead Letter ID"
+//	@Success		200	{object}	schemas.DeadLetter
+//	@Failure		400	{object}	schemas.ErrorResponse
+//	@Failure		401	{object}	schemas.ErrorResponse
+//	@Failure		404	{object}	schemas.ErrorResponse
+//	@Failure		500	{object}	schemas.ErrorResponse
+//	@Router			/dead-letter/:id/retry [post]
+func (api *DeadLetterApi) RetryUserDeadLetter(apiRoutes *gin.RouterGroup) {
+	apiRoutes.POST("/:id/retry", func(ctx *gin.Context) {
+		idInt, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		response, err := api.controller.RetryUserDeadLetter(ctx, idInt)
+		if err != nil {
+			deadLetterErrorResponse(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response)
+	})
+}
+
+// DiscardUserDeadLetter godoc
+//
+//	@Summary		Discard User Dead Letter
+//	@Description	remove a dead letter from the list without replaying its trigger
+//	@Tags			DeadLetter
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			id	path		int	true	"Dead Letter ID"
+//	@Success		200	{object}	schemas.DeadLetter
+//	@Failure		400	{object}	schemas.ErrorResponse
+//	@Failure		401	{object}	schemas.ErrorResponse
+//	@Failure		404	{object}	schemas.ErrorResponse
+//	@Failure		500	{object}	schemas.ErrorResponse
+//	@Router			/dead-letter/:id [delete]
+func (api *DeadLetterApi) DiscardUserDeadLetter(apiRoutes *gin.RouterGroup) {
+	apiRoutes.DELETE("/:id", func(ctx *gin.Context) {
+		idInt, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		response, err := api.controller.DiscardUserDeadLetter(ctx, idInt)
+		if err != nil {
+			deadLetterErrorResponse(ctx, err)
+			return
+		}
+
+		ctx.JSON(http.StatusOK, response)
+	})
+}
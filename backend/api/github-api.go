@@ -1,7 +1,9 @@
 package api
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 
@@ -41,6 +43,10 @@ func NewGithubAPI(
 	api.HandleServiceCallbackMobile(apiRoutes)
 	apiRoutesInfo := apiRoutes.Group("/info", middlewares.AuthorizeJWT(serviceUser))
 	api.GetUserInfo(apiRoutesInfo)
+	apiRoutesWebhook := apiRoutes.Group("/webhook", middlewares.AuthorizeJWT(serviceUser))
+	api.RegisterWebhook(apiRoutesWebhook)
+	// the deliveries are authenticated by their signature
+	api.HandleWebhook(apiRoutes.Group("/webhook"))
 	return &api
 }
 
@@ -140,3 +146,88 @@ func (api *GithubAPI) GetUserInfo(apiRoutes *gin.RouterGroup) {
 		}
 	})
 }
+
+// RegisterWebhook godoc
+//
+//	@Summary		Register Webhook
+//	@Description	register a webhook on a repository of the user, whose events trigger the github actions of the user areas right away
+//	@Tags			Github
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			payload	body		schemas.GithubWebhookRequest	true	"Webhook Payload"
+//	@Success		200		{object}	schemas.GithubWebhook
+//	@Failure		401		{object}	schemas.ErrorResponse
+//	@Failure		500		{object}	schemas.ErrorResponse
+//	@Router			/github/webhook [post]
+func (api *GithubAPI) RegisterWebhook(apiRoutes *gin.RouterGroup) {
+	apiRoutes.POST("/", func(ctx *gin.Context) {
+		webhook, err := api.controller.RegisterWebhook(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusInternalServerError, &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+		} else {
+			ctx.JSON(http.StatusOK, webhook)
+		}
+	})
+}
+
+// HandleWebhook godoc
+//
+//	@Summary		Handle Webhook
+//	@Description	receive a delivery of a github webhook, signed in the X-Hub-Signature-256 header
+//	@Tags			Github
+//	@Accept			json
+//	@Produce		json
+//	@Param			id					path		int		true	"Webhook ID"
+//	@Param			X-GitHub-Event		header		string	true	"Event name"
+//	@Param			X-Hub-Signature-256	header		string	true	"Signature of the body"
+//	@Success		200					{object}	schemas.Response
+//	@Failure		400					{object}	schemas.ErrorResponse
+//	@Failure		401					{object}	schemas.ErrorResponse
+//	@Failure		404					{object}	schemas.ErrorResponse
+//	@Failure		500					{object}	schemas.ErrorResponse
+//	@Router			/github/webhook/:id [post]
+func (api *GithubAPI) HandleWebhook(apiRoutes *gin.RouterGroup) {
+	apiRoutes.POST("/:id", func(ctx *gin.Context) {
+		idInt, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		err = api.controller.HandleWebhook(ctx, idInt)
+		if err != nil {
+			ctx.JSON(webhookErrorStatus(err), &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &schemas.Response{Message: "delivery received"})
+	})
+}
+
+// webhookErrorStatus returns the status of the response to a refused webhook delivery: 404
+// for an unknown webhook, 401 for an invalid signature or secret, 400 for a body that is not a
+// JSON object, 413 for a body too large, and 500 otherwise.
+func webhookErrorStatus(err error) int {
+	var maxBytesError *http.MaxBytesError
+	switch {
+	case errors.Is(err, schemas.ErrGithubWebhookNotFound),
+		errors.Is(err, schemas.ErrWebhookEndpointNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, schemas.ErrInvalidWebhookSignature),
+		errors.Is(err, schemas.ErrInvalidWebhookSecret):
+		return http.StatusUnauthorized
+	case errors.Is(err, schemas.ErrInvalidWebhookPayload):
+		return http.StatusBadRequest
+	case errors.As(err, &maxBytesError):
+		return http.StatusRequestEntityTooLarge
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -2,8 +2,10 @@ package api_test
 
 import (
 	"context"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/gin-gonic/gin"
@@ -39,6 +41,16 @@ func (m *MockGithubController) GetUserInfo(ctx *gin.Context) (schemas.UserCreden
 	return args.Get(0).(schemas.UserCredentials), args.Error(1)
 }
 
+func (m *MockGithubController) RegisterWebhook(ctx *gin.Context) (schemas.GithubWebhook, error) {
+	args := m.Called(ctx)
+	return args.Get(0).(schemas.GithubWebhook), args.Error(1)
+}
+
+func (m *MockGithubController) HandleWebhook(ctx *gin.Context, id uint64) error {
+	args := m.Called(ctx, id)
+	return args.Error(0)
+}
+
 func TestGithubAPI(t *testing.T) {
 	t.Parallel()
 
@@ -96,6 +108,31 @@ func TestGithubAPI(t *testing.T) {
 		assert.Equal(t, http.StatusOK, responseRecorder.Code)
 		assert.Contains(t, responseRecorder.Body.String(), "mock_mobile_token")
 	})
+	t.Run("TestHandleWebhook", func(t *testing.T) {
+		t.Parallel()
+
+		mockController.On("HandleWebhook", mock.Anything, uint64(1)).Return(nil)
+		mockController.On("HandleWebhook", mock.Anything, uint64(2)).
+			Return(fmt.Errorf("can't handle webhook delivery: %w",
+				schemas.ErrInvalidWebhookSignature))
+
+		for path, status := range map[string]int{
+			"/api/github/webhook/1":    http.StatusOK,
+			"/api/github/webhook/2":    http.StatusUnauthorized,
+			"/api/github/webhook/hook": http.StatusBadRequest,
+		} {
+			responseRecorder := httptest.NewRecorder()
+			req, _ := http.NewRequestWithContext(
+				context.Background(),
+				http.MethodPost,
+				path,
+				strings.NewReader(`{"zen":"Design for failure."}`),
+			)
+			router.ServeHTTP(responseRecorder, req)
+
+			assert.Equal(t, status, responseRecorder.Code, path)
+		}
+	})
 	// t.Run("TestHandleServiceCallback", func(t *testing.T) {
 	// 	mockUserInfo := schemas.UserCredentials{Username: "Test User", Email: "aze"}
 	// 	mockController.On("GetUserInfo", mock.Anything).Return(mockUserInfo, nil)
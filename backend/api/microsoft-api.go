@@ -40,6 +40,7 @@ func NewMicrosoftAPI(
 	api.RedirectToService(apiRoutes)
 	api.HandleServiceCallback(apiRoutes)
 	api.HandleServiceCallbackMobile(apiRoutes)
+	api.HandleNotifications(apiRoutes)
 	apiRoutesInfo := apiRoutes.Group("/info", middlewares.AuthorizeJWT(serviceUser))
 	api.GetUserInfo(apiRoutesInfo)
 	return &api
@@ -117,6 +118,39 @@ func (api *MicrosoftAPI) HandleServiceCallbackMobile(apiRoutes *gin.RouterGroup)
 	})
 }
 
+// HandleNotifications godoc
+//
+//	@Summary		Handle Notifications
+//	@Description	answer the validation of a graph subscription with its token, or receive a delivery of graph notifications
+//	@Tags			Microsoft
+//	@Accept			json
+//	@Produce		json
+//	@Param			validationToken	query		string	false	"Validation token of a subscription being created"
+//	@Success		200				{string}	string
+//	@Success		202				{object}	schemas.Response
+//	@Failure		401				{object}	schemas.ErrorResponse
+//	@Failure		413				{object}	schemas.ErrorResponse
+//	@Failure		500				{object}	schemas.ErrorResponse
+//	@Router			/microsoft/webhook [post]
+func (api *MicrosoftAPI) HandleNotifications(apiRoutes *gin.RouterGroup) {
+	apiRoutes.POST("/webhook", func(ctx *gin.Context) {
+		validationToken, err := api.controller.HandleNotifications(ctx)
+		if err != nil {
+			ctx.JSON(webhookErrorStatus(err), &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		if validationToken != "" {
+			// Graph expects the token back as plain text
+			ctx.String(http.StatusOK, validationToken)
+			return
+		}
+		ctx.JSON(http.StatusAccepted, &schemas.Response{Message: "notifications received"})
+	})
+}
+
 // GetUserInfo godoc
 //
 //	@Summary		Get User Info
@@ -39,6 +39,11 @@ func (m *MockMicrosoftController) GetUserInfo(ctx *gin.Context) (schemas.UserCre
 	return args.Get(0).(schemas.UserCredentials), args.Error(1)
 }
 
+func (m *MockMicrosoftController) HandleNotifications(ctx *gin.Context) (string, error) {
+	args := m.Called(ctx)
+	return args.String(0), args.Error(1)
+}
+
 func TestMicrosoftAPI(t *testing.T) {
 	mockController := new(MockMicrosoftController)
 
@@ -78,6 +83,40 @@ func TestMicrosoftAPI(t *testing.T) {
 		assert.Equal(t, http.StatusOK, w.Code)
 		assert.Contains(t, w.Body.String(), "mock_mobile_token")
 	})
+	t.Run("TestHandleNotifications", func(t *testing.T) {
+		hasQuery := func(query string) any {
+			return mock.MatchedBy(func(ctx *gin.Context) bool {
+				return ctx.Request.URL.RawQuery == query
+			})
+		}
+		mockController.On("HandleNotifications", hasQuery("validationToken=token%3C1%3E")).
+			Return("token<1>", nil)
+		mockController.On("HandleNotifications", hasQuery("")).Return("", nil)
+		mockController.On("HandleNotifications", hasQuery("forged=true")).
+			Return("", schemas.ErrInvalidWebhookSignature)
+
+		// the handshake of a subscription gets its token back as plain text
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(
+			"POST",
+			"/api/microsoft/webhook?validationToken=token%3C1%3E",
+			nil,
+		)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "token<1>", w.Body.String())
+		assert.Contains(t, w.Header().Get("Content-Type"), "text/plain")
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/api/microsoft/webhook", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+
+		w = httptest.NewRecorder()
+		req, _ = http.NewRequest("POST", "/api/microsoft/webhook?forged=true", nil)
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+	})
 
 	t.Run("TestGetUserInfoNoToken", func(t *testing.T) {
 		mockUserInfo := schemas.UserCredentials{Username: "test_user", Email: "test@example.com"}
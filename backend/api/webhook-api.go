@@ -0,0 +1,128 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"area/controller"
+	"area/middlewares"
+	"area/schemas"
+	"area/service"
+)
+
+// WebhookAPI is a struct that provides an interface to interact with the WebhookController.
+// It contains a single field, controller, which is an instance of WebhookController.
+type WebhookAPI struct {
+	controller controller.WebhookController
+}
+
+// NewWebhookAPI initializes a new WebhookAPI instance, sets up the necessary routes,
+// and returns a pointer to the created WebhookAPI instance.
+//
+// Parameters:
+//   - controller: An instance of WebhookController to handle the inbound webhooks.
+//   - apiRoutes: A pointer to a gin.RouterGroup where the Webhook API routes will be registered.
+//   - serviceUser: An instance of UserService to handle user-related operations.
+//
+// Returns:
+//   - A pointer to the initialized WebhookAPI instance.
+func NewWebhookAPI(
+	controller controller.WebhookController,
+	apiRoutes *gin.RouterGroup,
+	serviceUser service.UserService,
+) *WebhookAPI {
+	apiRoutes = apiRoutes.Group("/webhook")
+	api := WebhookAPI{
+		controller: controller,
+	}
+	apiRoutesArea := apiRoutes.Group("/area", middlewares.AuthorizeJWT(serviceUser))
+	api.GetAreaWebhook(apiRoutesArea)
+	// the deliveries are authenticated by the token of their URL and the option of their area
+	api.HandleDelivery(apiRoutes.Group("/webhook"))
+	return &api
+}
+
+// GetAreaWebhook godoc
+//
+//	@Summary		Get Area Webhook
+//	@Description	give the url of an area whose action is ReceiveWebhook and the secret authenticating its deliveries
+//	@Tags			Webhook
+//	@Accept			json
+//	@Produce		json
+//	@Security		bearerAuth
+//	@Param			id	path		int	true	"Area ID"
+//	@Success		200	{object}	schemas.WebhookEndpointInfo
+//	@Failure		400	{object}	schemas.ErrorResponse
+//	@Failure		401	{object}	schemas.ErrorResponse
+//	@Failure		404	{object}	schemas.ErrorResponse
+//	@Failure		500	{object}	schemas.ErrorResponse
+//	@Router			/webhook/area/:id [get]
+func (api *WebhookAPI) GetAreaWebhook(apiRoutes *gin.RouterGroup) {
+	apiRoutes.GET("/:id", func(ctx *gin.Context) {
+		idInt, err := strconv.ParseUint(ctx.Param("id"), 10, 64)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		info, err := api.controller.GetAreaWebhook(ctx, idInt)
+		if err != nil {
+			ctx.JSON(areaWebhookErrorStatus(err), &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, info)
+	})
+}
+
+// HandleDelivery godoc
+//
+//	@Summary		Handle Delivery
+//	@Description	trigger the area of the url with the posted json object as payload, authenticated by the X-Webhook-Secret or X-Webhook-Signature header when the area asks so
+//	@Tags			Webhook
+//	@Accept			json
+//	@Produce		json
+//	@Param			token				path		string	true	"Token of the url"
+//	@Param			X-Webhook-Secret	header		string	false	"Secret of the url"
+//	@Param			X-Webhook-Signature	header		string	false	"Signature of the body"
+//	@Success		200					{object}	schemas.Response
+//	@Failure		400					{object}	schemas.ErrorResponse
+//	@Failure		401					{object}	schemas.ErrorResponse
+//	@Failure		404					{object}	schemas.ErrorResponse
+//	@Failure		413					{object}	schemas.ErrorResponse
+//	@Failure		500					{object}	schemas.ErrorResponse
+//	@Router			/webhook/webhook/:token [post]
+func (api *WebhookAPI) HandleDelivery(apiRoutes *gin.RouterGroup) {
+	apiRoutes.POST("/:token", func(ctx *gin.Context) {
+		err := api.controller.HandleDelivery(ctx, ctx.Param("token"))
+		if err != nil {
+			ctx.JSON(webhookErrorStatus(err), &schemas.ErrorResponse{
+				Error: err.Error(),
+			})
+			return
+		}
+
+		ctx.JSON(http.StatusOK, &schemas.Response{Message: "delivery received"})
+	})
+}
+
+// areaWebhookErrorStatus returns the status of the response to a request for the URL of an
+// area: 404 when the user has no such area, 400 when its action is not a webhook, and 500
+// otherwise.
+func areaWebhookErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, schemas.ErrAreaNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, schemas.ErrNotWebhookArea):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
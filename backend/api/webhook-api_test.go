@@ -0,0 +1,80 @@
+package api_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"area/api"
+	"area/schemas"
+	"area/test"
+)
+
+type MockWebhookController struct {
+	mock.Mock
+}
+
+func (m *MockWebhookController) GetAreaWebhook(
+	ctx *gin.Context,
+	areaId uint64,
+) (schemas.WebhookEndpointInfo, error) {
+	args := m.Called(ctx, areaId)
+	return args.Get(0).(schemas.WebhookEndpointInfo), args.Error(1)
+}
+
+func (m *MockWebhookController) HandleDelivery(ctx *gin.Context, token string) error {
+	args := m.Called(ctx, token)
+	return args.Error(0)
+}
+
+func TestWebhookAPI(t *testing.T) {
+	t.Parallel()
+
+	mockController := new(MockWebhookController)
+	router := gin.Default()
+	apiRoutes := router.Group("/api")
+	mockUserService := new(test.MockUserService)
+	api.NewWebhookAPI(mockController, apiRoutes, mockUserService)
+
+	t.Run("TestHandleDelivery", func(t *testing.T) {
+		t.Parallel()
+
+		refusals := map[string]error{
+			"unknown":   schemas.ErrWebhookEndpointNotFound,
+			"secret":    schemas.ErrInvalidWebhookSecret,
+			"signature": schemas.ErrInvalidWebhookSignature,
+			"payload":   schemas.ErrInvalidWebhookPayload,
+		}
+		mockController.On("HandleDelivery", mock.Anything, "token").Return(nil)
+		for token, err := range refusals {
+			mockController.On("HandleDelivery", mock.Anything, token).
+				Return(fmt.Errorf("can't handle webhook delivery: %w", err))
+		}
+
+		for token, status := range map[string]int{
+			"token":     http.StatusOK,
+			"unknown":   http.StatusNotFound,
+			"secret":    http.StatusUnauthorized,
+			"signature": http.StatusUnauthorized,
+			"payload":   http.StatusBadRequest,
+		} {
+			responseRecorder := httptest.NewRecorder()
+			req, _ := http.NewRequestWithContext(
+				context.Background(),
+				http.MethodPost,
+				"/api/webhook/webhook/"+token,
+				strings.NewReader(`{"status":"deployed"}`),
+			)
+			router.ServeHTTP(responseRecorder, req)
+
+			assert.Equal(t, status, responseRecorder.Code, token)
+		}
+	})
+}
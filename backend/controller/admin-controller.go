@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"area/schemas"
+	"area/service"
+)
+
+// AdminController defines the interface for the administration of the backend replicas.
+//
+// Methods:
+//   - GetAreaOwnership: Retrieves the distribution of the areas over the scheduler nodes.
+//   - GetProviderMetrics: Retrieves the counters of the requests sent to the providers.
+type AdminController interface {
+	GetAreaOwnership(ctx *gin.Context) (ownership schemas.AreaOwnership, err error)
+	GetProviderMetrics(ctx *gin.Context) (metrics []schemas.ProviderMetrics)
+}
+
+// adminController is a struct that handles the administration operations.
+// It uses an AreaLeaseService to find which replica owns which area, and the ProviderClient
+// to report the requests sent to the providers.
+type adminController struct {
+	areaLeaseService service.AreaLeaseService
+	providerClient   service.ProviderClient
+}
+
+// NewAdminController creates a new instance of AdminController with the provided
+// AreaLeaseService and ProviderClient.
+//
+// Parameters:
+//   - areaLeaseService: An implementation of the AreaLeaseService interface.
+//   - providerClient: The ProviderClient shared by the services.
+//
+// Returns:
+//   - AdminController: A new instance of AdminController.
+func NewAdminController(
+	areaLeaseService service.AreaLeaseService,
+	providerClient service.ProviderClient,
+) AdminController {
+	return &adminController{
+		areaLeaseService: areaLeaseService,
+		providerClient:   providerClient,
+	}
+}
+
+// GetAreaOwnership retrieves the scheduler nodes with the areas they own.
+//
+// Parameters:
+//   - ctx: The Gin context which provides request-specific information.
+//
+// Returns:
+//   - ownership: The distribution of the areas over the scheduler nodes.
+//   - err: An error if the ownership cannot be retrieved.
+func (controller *adminController) GetAreaOwnership(
+	ctx *gin.Context,
+) (ownership schemas.AreaOwnership, err error) {
+	ownership, err = controller.areaLeaseService.GetAreaOwnership()
+	if err != nil {
+		return ownership, fmt.Errorf("can't get area ownership: %w", err)
+	}
+	return ownership, nil
+}
+
+// GetProviderMetrics retrieves the counters of the requests sent to the providers by the
+// replica that answers the request.
+//
+// Parameters:
+//   - ctx: The Gin context which provides request-specific information.
+//
+// Returns:
+//   - metrics: The counters of every provider requested since the replica started.
+func (controller *adminController) GetProviderMetrics(
+	ctx *gin.Context,
+) (metrics []schemas.ProviderMetrics) {
+	return controller.providerClient.GetMetrics()
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
 
 	"area/schemas"
 	"area/service"
@@ -16,7 +17,7 @@ import (
 // Methods:
 //   - CreateArea: Creates a new area and returns its ID or an error.
 //   - GetUserAreas: Retrieves a list of areas associated with the user.
-//   - UpdateUserArea: Updates an existing area and returns the updated area or an error.
+//   - UpdateUserArea: Partially updates an existing area and returns the updated area or an error.
 //   - DeleteUserArea: Deletes an existing area and returns the deleted area or an error.
 type AreaController interface {
 	CreateArea(ctx *gin.Context) (string, error)
@@ -46,8 +47,8 @@ func NewAreaController(service service.AreaService) AreaController {
 }
 
 // CreateArea handles the creation of a new area.
-// It decodes the request body into an AreaMessage schema and retrieves the
-// authorization token from the request header. It then calls the service layer
+// It decodes the request body into an AreaMessage schema, validates its retry policy,
+// and retrieves the authorization token from the request header. It then calls the service layer
 // to create the area with the provided data and token.
 //
 // Parameters:
@@ -66,6 +67,12 @@ func (controller *areaController) CreateArea(ctx *gin.Context) (string, error) {
 		println(fmt.Errorf("can't bind credentials: %w", err))
 		return "", fmt.Errorf("can't bind credentials: %w", err)
 	}
+	if result.RetryPolicy != nil {
+		err = binding.Validator.ValidateStruct(result.RetryPolicy)
+		if err != nil {
+			return "", fmt.Errorf("can't validate retry policy: %w", err)
+		}
+	}
 
 	authHeader := ctx.GetHeader("Authorization")
 	token := authHeader[len("Bearer "):]
@@ -96,8 +103,8 @@ func (controller *areaController) GetUserAreas(
 	return areaList, nil
 }
 
-// UpdateUserArea updates the area information for a user.
-// It decodes the request body into a schemas.Area object and updates the user's area using the provided service.
+// UpdateUserArea partially updates the area information for a user.
+// It binds the request body to a schemas.AreaUpdateMessage object and updates the user's area using the provided service.
 // The function expects a Bearer token in the Authorization header for authentication.
 //
 // Parameters:
@@ -111,12 +118,12 @@ func (controller *areaController) GetUserAreas(
 func (controller *areaController) UpdateUserArea(
 	ctx *gin.Context,
 ) (newArea schemas.Area, err error) {
-	var result schemas.Area
+	var result schemas.AreaUpdateMessage
 
-	err = json.NewDecoder(ctx.Request.Body).Decode(&result)
+	err = ctx.ShouldBindJSON(&result)
 	if err != nil {
-		println(fmt.Errorf("can't bind credentials: %w", err))
-		return newArea, fmt.Errorf("can't bind credentials: %w", err)
+		println(fmt.Errorf("can't bind area update: %w", err).Error())
+		return newArea, fmt.Errorf("can't bind area update: %w", err)
 	}
 
 	authHeader := ctx.GetHeader("Authorization")
@@ -20,10 +20,6 @@ type MockAreaService struct {
 	mock.Mock
 }
 
-func (m *MockAreaService) InitArea(area schemas.Area) {
-	m.Called(area)
-}
-
 func (m *MockAreaService) CreateArea(area schemas.AreaMessage, token string) (string, error) {
 	args := m.Called(area, token)
 	return args.String(0), args.Error(1)
@@ -34,8 +30,11 @@ func (m *MockAreaService) GetUserAreas(token string) ([]schemas.Area, error) {
 	return args.Get(0).([]schemas.Area), args.Error(1)
 }
 
-func (m *MockAreaService) UpdateUserArea(token string, area schemas.Area) (schemas.Area, error) {
-	args := m.Called(token, area)
+func (m *MockAreaService) UpdateUserArea(
+	token string,
+	update schemas.AreaUpdateMessage,
+) (schemas.Area, error) {
+	args := m.Called(token, update)
 	return args.Get(0).(schemas.Area), args.Error(1)
 }
 
@@ -117,25 +116,56 @@ func TestUpdateUserArea(t *testing.T) {
 
 	gin.SetMode(gin.TestMode)
 	router := gin.Default()
-	router.PUT("/update", func(ctx *gin.Context) {
+	router.PATCH("/update", func(ctx *gin.Context) {
 		area, err := controller.UpdateUserArea(ctx)
 		if err != nil {
 			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 			return
 		}
-		ctx.JSON(http.StatusOK, area)
+		ctx.JSON(http.StatusOK, gin.H{"id": area.Id, "title": area.Title})
 	})
 
-	area := schemas.Area{ /* fill with appropriate fields */ }
-	areaJSON, _ := json.Marshal(area)
-	mockService.On("UpdateUserArea", "test-token", area).Return(area, nil)
+	title := "New title"
+	update := schemas.AreaUpdateMessage{Id: 1, Title: &title}
+	mockService.On("UpdateUserArea", "test-token", update).
+		Return(schemas.Area{Id: 1, Title: title}, nil)
 
-	req, _ := http.NewRequest(http.MethodPut, "/update", bytes.NewBuffer(areaJSON))
+	req, _ := http.NewRequest(
+		http.MethodPatch,
+		"/update",
+		bytes.NewBufferString(`{"id":1,"title":"New title"}`),
+	)
 	req.Header.Set("Authorization", "Bearer test-token")
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 
-	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"id":1,"title":"New title"}`, w.Body.String())
+	mockService.AssertExpectations(t)
+}
+
+func TestUpdateUserAreaWithoutId(t *testing.T) {
+	mockService := new(MockAreaService)
+	controller := controller.NewAreaController(mockService)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.Default()
+	router.PATCH("/update", func(ctx *gin.Context) {
+		_, err := controller.UpdateUserArea(ctx)
+		if err != nil {
+			ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		ctx.Status(http.StatusOK)
+	})
+
+	req, _ := http.NewRequest(http.MethodPatch, "/update", bytes.NewBufferString(`{"title":"x"}`))
+	req.Header.Set("Authorization", "Bearer test-token")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	mockService.AssertNotCalled(t, "UpdateUserArea", mock.Anything, mock.Anything)
 }
 
 func TestDeleteUserArea(t *testing.T) {
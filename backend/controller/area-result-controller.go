@@ -13,7 +13,13 @@ type AreaResultController interface {
 	GetUserAreaResultsByAreaID(
 		ctx *gin.Context,
 		areaID uint64,
-	) (areaList []schemas.AreaResult, err error)
+		filter schemas.AreaResultFilter,
+	) (areaList []schemas.AreaResult, total int64, err error)
+	GetUserWorkflowRunsByAreaID(
+		ctx *gin.Context,
+		areaID uint64,
+		filter schemas.WorkflowRunFilter,
+	) (runList []schemas.WorkflowRun, total int64, err error)
 }
 
 // areaResultController is a controller that handles requests related to area results.
@@ -43,32 +49,80 @@ func NewAreaResultController(
 // GetUserAreaResultsByAreaID retrieves the area results for a specific area ID that belongs to the user.
 // It extracts the authorization token from the request header, fetches the user's areas using the token,
 // and then searches for the specified area ID within the user's areas. If the area is found, it returns
-// the area results; otherwise, it returns an error indicating that the area was not found.
+// the page of area results matching the filter; otherwise, it returns an error indicating that the area
+// was not found.
 //
 // Parameters:
 //   - ctx: The Gin context which provides request-specific information.
 //   - areaID: The ID of the area for which to retrieve results.
+//   - filter: The status, creation time range and page of the results.
 //
 // Returns:
-//   - areaResultList: A list of area results for the specified area ID.
+//   - areaResultList: A page of area results for the specified area ID.
+//   - total: The number of area results matching the filter.
 //   - err: An error if the area is not found or if there is an issue retrieving the user's areas.
 func (controller *areaResultController) GetUserAreaResultsByAreaID(
 	ctx *gin.Context,
 	areaID uint64,
-) (areaResultList []schemas.AreaResult, err error) {
+	filter schemas.AreaResultFilter,
+) (areaResultList []schemas.AreaResult, total int64, err error) {
+	authHeader := ctx.GetHeader("Authorization")
+	token := authHeader[len("Bearer "):]
+
+	areaList, err := controller.serviceArea.GetUserAreas(token)
+	if err != nil {
+		return nil, 0, fmt.Errorf("can't get user areas: %w", err)
+	}
+
+	for _, area := range areaList {
+		if area.Id == areaID {
+			areaResultList, total, err = controller.service.FindByAreaIDAndFilter(areaID, filter)
+			if err != nil {
+				return nil, 0, fmt.Errorf("can't get area results: %w", err)
+			}
+			return areaResultList, total, nil
+		}
+	}
+	return areaResultList, 0, fmt.Errorf("area not found")
+}
+
+// GetUserWorkflowRunsByAreaID retrieves the workflow runs, with the state of their steps, of a
+// specific area that belongs to the user. The area is searched within the user's areas, found
+// with the authorization token of the request, like for the area results.
+//
+// Parameters:
+//   - ctx: The Gin context which provides request-specific information.
+//   - areaID: The ID of the area for which to retrieve the workflow runs.
+//   - filter: The status and page of the runs.
+//
+// Returns:
+//   - runList: A page of workflow runs for the specified area ID.
+//   - total: The number of workflow runs matching the filter.
+//   - err: An error if the area is not found or if there is an issue retrieving the user's areas.
+func (controller *areaResultController) GetUserWorkflowRunsByAreaID(
+	ctx *gin.Context,
+	areaID uint64,
+	filter schemas.WorkflowRunFilter,
+) (runList []schemas.WorkflowRun, total int64, err error) {
 	authHeader := ctx.GetHeader("Authorization")
 	token := authHeader[len("Bearer "):]
 
 	areaList, err := controller.serviceArea.GetUserAreas(token)
 	if err != nil {
-		return nil, fmt.Errorf("can't get user areas: %w", err)
+		return nil, 0, fmt.Errorf("can't get user areas: %w", err)
 	}
 
 	for _, area := range areaList {
 		if area.Id == areaID {
-			areaResultList = controller.service.FindByAreaID(areaID)
-			return areaResultList, nil
+			runList, total, err = controller.service.FindWorkflowRunsByAreaIDAndFilter(
+				areaID,
+				filter,
+			)
+			if err != nil {
+				return nil, 0, fmt.Errorf("can't get workflow runs: %w", err)
+			}
+			return runList, total, nil
 		}
 	}
-	return areaResultList, fmt.Errorf("area not found")
+	return runList, 0, fmt.Errorf("area not found")
 }
@@ -0,0 +1,107 @@
+package controller
+
+import (
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+
+	"area/schemas"
+	"area/service"
+)
+
+// DeadLetterController defines the interface for managing the dead letters of the user.
+//
+// Methods:
+//   - GetUserDeadLetters: Retrieves the dead letters of the user.
+//   - RetryUserDeadLetter: Replays the trigger of a dead letter and returns the dead letter or an error.
+//   - DiscardUserDeadLetter: Deletes a dead letter and returns it or an error.
+type DeadLetterController interface {
+	GetUserDeadLetters(ctx *gin.Context) (deadLetterList []schemas.DeadLetter, err error)
+	RetryUserDeadLetter(ctx *gin.Context, id uint64) (deadLetter schemas.DeadLetter, err error)
+	DiscardUserDeadLetter(ctx *gin.Context, id uint64) (deadLetter schemas.DeadLetter, err error)
+}
+
+// deadLetterController is a struct that handles dead letter-related operations.
+// It uses a DeadLetterService to perform business logic related to dead letters.
+type deadLetterController struct {
+	service service.DeadLetterService
+}
+
+// NewDeadLetterController creates a new instance of DeadLetterController with the provided
+// DeadLetterService.
+//
+// Parameters:
+//   - service: An implementation of the DeadLetterService interface.
+//
+// Returns:
+//   - DeadLetterController: A new instance of DeadLetterController.
+func NewDeadLetterController(service service.DeadLetterService) DeadLetterController {
+	return &deadLetterController{
+		service: service,
+	}
+}
+
+// GetUserDeadLetters retrieves the dead letters of the user of the bearer token of the request.
+//
+// Parameters:
+//   - ctx: The Gin context which provides request-specific information.
+//
+// Returns:
+//   - deadLetterList: The dead letters of the user, newest first.
+//   - err: An error if the dead letters cannot be retrieved.
+func (controller *deadLetterController) GetUserDeadLetters(
+	ctx *gin.Context,
+) (deadLetterList []schemas.DeadLetter, err error) {
+	authHeader := ctx.GetHeader("Authorization")
+	token := authHeader[len("Bearer "):]
+	deadLetterList, err = controller.service.GetUserDeadLetters(token)
+	if err != nil {
+		return nil, fmt.Errorf("can't get user dead letters: %w", err)
+	}
+	return deadLetterList, nil
+}
+
+// RetryUserDeadLetter replays the trigger of a dead letter of the user of the bearer token of
+// the request.
+//
+// Parameters:
+//   - ctx: The Gin context which provides request-specific information.
+//   - id: The ID of the dead letter.
+//
+// Returns:
+//   - deadLetter: The retried dead letter.
+//   - err: An error wrapping schemas.ErrDeadLetterNotFound if the user has no such dead letter.
+func (controller *deadLetterController) RetryUserDeadLetter(
+	ctx *gin.Context,
+	id uint64,
+) (deadLetter schemas.DeadLetter, err error) {
+	authHeader := ctx.GetHeader("Authorization")
+	token := authHeader[len("Bearer "):]
+	deadLetter, err = controller.service.RetryUserDeadLetter(token, id)
+	if err != nil {
+		return deadLetter, fmt.Errorf("can't retry dead letter: %w", err)
+	}
+	return deadLetter, nil
+}
+
+// DiscardUserDeadLetter deletes a dead letter of the user of the bearer token of the request.
+//
+// Parameters:
+//   - ctx: The Gin context which provides request-specific information.
+//   - id: The ID of the dead letter.
+//
+// Returns:
+//   - deadLetter: The discarded dead letter.
+//   - err: An error wrapping schemas.ErrDeadLetterNotFound if the user has no such dead letter.
+func (controller *deadLetterController) DiscardUserDeadLetter(
+	ctx *gin.Context,
+	id uint64,
+) (deadLetter schemas.DeadLetter, err error) {
+	authHeader := ctx.GetHeader("Authorization")
+	token := authHeader[len("Bearer "):]
+	deadLetter, err = controller.service.DiscardUserDeadLetter(token, id)
+	if err != nil {
+		return deadLetter, fmt.Errorf("can't discard dead letter: %w", err)
+	}
+	return deadLetter, nil
+}
@@ -1,7 +1,10 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 
@@ -18,11 +21,15 @@ import (
 //   - HandleServiceCallback: Handles the callback from the GitHub OAuth service and returns a token or error.
 //   - HandleServiceCallbackMobile: Handles the callback from the GitHub OAuth service for mobile clients and returns a token or error.
 //   - GetUserInfo: Retrieves user information based on the provided context and returns user credentials or an error.
+//   - RegisterWebhook: Registers a webhook on a repository of the user and returns it or an error.
+//   - HandleWebhook: Triggers the areas concerned by a delivery of a webhook or returns an error.
 type GithubController interface {
 	RedirectToService(ctx *gin.Context) (oauthURL string, err error)
 	HandleServiceCallback(ctx *gin.Context) (string, error)
 	HandleServiceCallbackMobile(ctx *gin.Context) (string, error)
 	GetUserInfo(ctx *gin.Context) (userInfo schemas.UserCredentials, err error)
+	RegisterWebhook(ctx *gin.Context) (webhook schemas.GithubWebhook, err error)
+	HandleWebhook(ctx *gin.Context, id uint64) error
 }
 
 // githubController is a struct that holds various service dependencies
@@ -33,11 +40,13 @@ type GithubController interface {
 // - serviceUser: An instance of UserService for managing user-related operations.
 // - serviceToken: An instance of TokenService for handling token-related operations.
 // - serviceService: An instance of ServiceService for managing additional services.
+// - areaScheduler: An instance of AreaScheduler queuing the triggers of the webhook deliveries.
 type githubController struct {
 	service        service.GithubService
 	serviceUser    service.UserService
 	serviceToken   service.TokenService
 	serviceService service.ServiceService
+	areaScheduler  service.AreaScheduler
 }
 
 // NewGithubController creates a new instance of GithubController with the provided services.
@@ -46,6 +55,7 @@ type githubController struct {
 //   - serviceUser: an instance of UserService to handle user related operations.
 //   - serviceToken: an instance of TokenService to handle token related operations.
 //   - serviceService: an instance of ServiceService to handle service related operations.
+//   - areaScheduler: an instance of AreaScheduler to queue the triggers of the webhook deliveries.
 //
 // Returns:
 //   - GithubController: a new instance of GithubController.
@@ -54,12 +64,14 @@ func NewGithubController(
 	serviceUser service.UserService,
 	serviceToken service.TokenService,
 	serviceService service.ServiceService,
+	areaScheduler service.AreaScheduler,
 ) GithubController {
 	return &githubController{
 		service:        service,
 		serviceUser:    serviceUser,
 		serviceToken:   serviceToken,
 		serviceService: serviceService,
+		areaScheduler:  areaScheduler,
 	}
 }
 
@@ -200,3 +212,79 @@ func (controller *githubController) GetUserInfo(
 
 	return userInfo, nil
 }
+
+// RegisterWebhook registers a webhook on the repository of the request body with the GitHub
+// token of the user of the bearer token of the request, so that the events of the repository
+// trigger the areas of the user right away.
+//
+// Parameters:
+//   - ctx: The Gin context containing the request data.
+//
+// Returns:
+//   - webhook: The webhook registered on the repository.
+//   - err: An error if the body is not valid or the webhook can not be registered.
+func (controller *githubController) RegisterWebhook(
+	ctx *gin.Context,
+) (webhook schemas.GithubWebhook, err error) {
+	var request schemas.GithubWebhookRequest
+	err = ctx.ShouldBindJSON(&request)
+	if err != nil {
+		return webhook, fmt.Errorf("can't bind webhook request: %w", err)
+	}
+
+	authHeader := ctx.GetHeader("Authorization")
+	tokenString := authHeader[len("Bearer "):]
+	user, err := controller.serviceUser.GetUserInfo(tokenString)
+	if err != nil {
+		return webhook, fmt.Errorf("can't get user info: %w", err)
+	}
+
+	webhook, err = controller.service.RegisterWebhook(ctx, user.Id, request.RepoName)
+	if err != nil {
+		return webhook, fmt.Errorf("can't register webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// HandleWebhook handles a delivery of a GitHub webhook: its signature is checked against its
+// raw body, and the triggers of its event are queued for the areas it concerns, once they
+// match their trigger filter.
+//
+// Parameters:
+//   - ctx: The Gin context containing the delivery.
+//   - id: The ID of the webhook, from the URL of the delivery.
+//
+// Returns:
+//   - error: An error wrapping schemas.ErrGithubWebhookNotFound or
+//     schemas.ErrInvalidWebhookSignature if the delivery is refused, or an error if its
+//     triggers can not be queued.
+func (controller *githubController) HandleWebhook(ctx *gin.Context, id uint64) error {
+	body, err := io.ReadAll(http.MaxBytesReader(
+		ctx.Writer,
+		ctx.Request.Body,
+		schemas.WebhookMaxBodySize,
+	))
+	if err != nil {
+		return fmt.Errorf("can't read webhook delivery: %w", err)
+	}
+
+	triggers, err := controller.service.HandleWebhook(
+		id,
+		ctx.GetHeader(schemas.GithubEventHeader),
+		ctx.GetHeader(schemas.GithubSignatureHeader),
+		body,
+	)
+	if err != nil {
+		return fmt.Errorf("can't handle webhook delivery: %w", err)
+	}
+
+	// a trigger that can not be queued does not stop the triggers of the other areas
+	var triggerErrors []error
+	for _, trigger := range triggers {
+		err = controller.areaScheduler.TriggerArea(trigger.Area, trigger.Trigger)
+		if err != nil {
+			triggerErrors = append(triggerErrors, fmt.Errorf("can't trigger area: %w", err))
+		}
+	}
+	return errors.Join(triggerErrors...)
+}
@@ -1,7 +1,10 @@
 package controller
 
 import (
+	"errors"
 	"fmt"
+	"io"
+	"net/http"
 
 	"github.com/gin-gonic/gin"
 
@@ -18,11 +21,14 @@ import (
 // - HandleServiceCallback(ctx *gin.Context) (string, error): Handles the callback from the Microsoft OAuth service.
 // - HandleServiceCallbackMobile(ctx *gin.Context) (string, error): Handles the callback from the Microsoft OAuth service for mobile clients.
 // - GetUserInfo(ctx *gin.Context) (userInfo schemas.UserCredentials, err error): Retrieves user information from the Microsoft OAuth service.
+// - HandleNotifications(ctx *gin.Context) (validationToken string, err error): Answers the validation of a Graph subscription
+// or triggers the areas concerned by a delivery of notifications.
 type MicrosoftController interface {
 	RedirectToService(ctx *gin.Context) (oauthURL string, err error)
 	HandleServiceCallback(ctx *gin.Context) (string, error)
 	HandleServiceCallbackMobile(ctx *gin.Context) (string, error)
 	GetUserInfo(ctx *gin.Context) (userInfo schemas.UserCredentials, err error)
+	HandleNotifications(ctx *gin.Context) (validationToken string, err error)
 }
 
 // microsoftController is a struct that holds various service interfaces
@@ -33,11 +39,13 @@ type MicrosoftController interface {
 // - serviceUser: An interface for user service operations.
 // - serviceToken: An interface for token service operations.
 // - serviceService: An interface for general service operations.
+// - areaScheduler: An interface queuing the triggers of the Graph notifications.
 type microsoftController struct {
 	service        service.MicrosoftService
 	serviceUser    service.UserService
 	serviceToken   service.TokenService
 	serviceService service.ServiceService
+	areaScheduler  service.AreaScheduler
 }
 
 // NewMicrosoftController creates a new instance of MicrosoftController with the provided services.
@@ -46,6 +54,7 @@ type microsoftController struct {
 //   - serviceUser: an instance of UserService to manage user-related operations.
 //   - serviceToken: an instance of TokenService to handle token-related operations.
 //   - serviceService: an instance of ServiceService to manage general service operations.
+//   - areaScheduler: an instance of AreaScheduler to queue the triggers of the Graph notifications.
 //
 // Returns:
 //   - MicrosoftController: a new instance of MicrosoftController initialized with the provided services.
@@ -54,12 +63,14 @@ func NewMicrosoftController(
 	serviceUser service.UserService,
 	serviceToken service.TokenService,
 	serviceService service.ServiceService,
+	areaScheduler service.AreaScheduler,
 ) MicrosoftController {
 	return &microsoftController{
 		service:        service,
 		serviceUser:    serviceUser,
 		serviceToken:   serviceToken,
 		serviceService: serviceService,
+		areaScheduler:  areaScheduler,
 	}
 }
 
@@ -206,3 +217,48 @@ func (controller *microsoftController) GetUserInfo(
 	userInfo.Username = microsoftUserInfo.Username
 	return userInfo, nil
 }
+
+// HandleNotifications handles a request of Microsoft Graph on the notification URL of the
+// subscriptions. A request holding a validation token is the handshake of a subscription being
+// created, whose token must be sent back. Otherwise the request is a delivery of notifications,
+// whose triggers are queued for the areas they concern, once they match their trigger filter.
+//
+// Parameters:
+//   - ctx: The Gin context containing the request.
+//
+// Returns:
+//   - validationToken: The validation token to send back, or "" for a delivery.
+//   - err: An error wrapping schemas.ErrInvalidWebhookSignature if the delivery is refused, or
+//     an error if its triggers can not be queued.
+func (controller *microsoftController) HandleNotifications(
+	ctx *gin.Context,
+) (validationToken string, err error) {
+	validationToken = ctx.Query(schemas.MicrosoftValidationTokenParam)
+	if validationToken != "" {
+		return validationToken, nil
+	}
+
+	body, err := io.ReadAll(http.MaxBytesReader(
+		ctx.Writer,
+		ctx.Request.Body,
+		schemas.WebhookMaxBodySize,
+	))
+	if err != nil {
+		return "", fmt.Errorf("can't read notifications: %w", err)
+	}
+
+	triggers, err := controller.service.HandleNotifications(ctx.Request.Context(), body)
+	if err != nil {
+		return "", fmt.Errorf("can't handle notifications: %w", err)
+	}
+
+	// a trigger that can not be queued does not stop the triggers of the other areas
+	var triggerErrors []error
+	for _, trigger := range triggers {
+		err = controller.areaScheduler.TriggerArea(trigger.Area, trigger.Trigger)
+		if err != nil {
+			triggerErrors = append(triggerErrors, fmt.Errorf("can't trigger area: %w", err))
+		}
+	}
+	return "", errors.Join(triggerErrors...)
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"area/schemas"
+	"area/service"
+)
+
+// WebhookController defines the interface for handling the URLs of the areas triggered by
+// inbound webhooks.
+//
+// Methods:
+//   - GetAreaWebhook: Returns the URL of an area of the user and its secret, or an error.
+//   - HandleDelivery: Triggers the area of the URL a JSON object was posted to, or returns an error.
+type WebhookController interface {
+	GetAreaWebhook(ctx *gin.Context, areaId uint64) (info schemas.WebhookEndpointInfo, err error)
+	HandleDelivery(ctx *gin.Context, token string) error
+}
+
+// webhookController is a struct that holds the service dependencies required for handling
+// the inbound webhooks.
+//
+// Fields:
+// - service: An instance of WebhookService managing the URLs of the areas.
+// - serviceUser: An instance of UserService for managing user-related operations.
+// - areaScheduler: An instance of AreaScheduler queuing the triggers of the deliveries.
+type webhookController struct {
+	service       service.WebhookService
+	serviceUser   service.UserService
+	areaScheduler service.AreaScheduler
+}
+
+// NewWebhookController creates a new instance of WebhookController with the provided services.
+// Parameters:
+//   - service: an instance of WebhookService to manage the URLs of the areas.
+//   - serviceUser: an instance of UserService to handle user related operations.
+//   - areaScheduler: an instance of AreaScheduler to queue the triggers of the deliveries.
+//
+// Returns:
+//   - WebhookController: a new instance of WebhookController.
+func NewWebhookController(
+	service service.WebhookService,
+	serviceUser service.UserService,
+	areaScheduler service.AreaScheduler,
+) WebhookController {
+	return &webhookController{
+		service:       service,
+		serviceUser:   serviceUser,
+		areaScheduler: areaScheduler,
+	}
+}
+
+// GetAreaWebhook returns the URL of the area of the user of the bearer token of the request,
+// with the secret authenticating its deliveries.
+//
+// Parameters:
+//   - ctx: The Gin context containing the request data.
+//   - areaId: The ID of the area, from the URL of the request.
+//
+// Returns:
+//   - info: The URL of the area and its secret.
+//   - err: An error wrapping schemas.ErrAreaNotFound or schemas.ErrNotWebhookArea if the area
+//     has no URL for the user, or an error if the URL can not be created.
+func (controller *webhookController) GetAreaWebhook(
+	ctx *gin.Context,
+	areaId uint64,
+) (info schemas.WebhookEndpointInfo, err error) {
+	authHeader := ctx.GetHeader("Authorization")
+	tokenString := authHeader[len("Bearer "):]
+	user, err := controller.serviceUser.GetUserInfo(tokenString)
+	if err != nil {
+		return info, fmt.Errorf("can't get user info: %w", err)
+	}
+
+	info, err = controller.service.GetAreaWebhook(user.Id, areaId)
+	if err != nil {
+		return info, fmt.Errorf("can't get area webhook: %w", err)
+	}
+	return info, nil
+}
+
+// HandleDelivery handles a JSON object posted to the URL of an area: the delivery is
+// authenticated as asked by the option of the area, and its body is queued as the payload of
+// a trigger of the area, once it matches its trigger filter.
+//
+// Parameters:
+//   - ctx: The Gin context containing the delivery.
+//   - token: The token of the URL of the area.
+//
+// Returns:
+//   - error: An error wrapping schemas.ErrWebhookEndpointNotFound,
+//     schemas.ErrInvalidWebhookSecret, schemas.ErrInvalidWebhookSignature or
+//     schemas.ErrInvalidWebhookPayload if the delivery is refused, or an error if its trigger
+//     can not be queued.
+func (controller *webhookController) HandleDelivery(ctx *gin.Context, token string) error {
+	body, err := io.ReadAll(http.MaxBytesReader(
+		ctx.Writer,
+		ctx.Request.Body,
+		schemas.WebhookMaxBodySize,
+	))
+	if err != nil {
+		return fmt.Errorf("can't read webhook delivery: %w", err)
+	}
+
+	triggers, err := controller.service.HandleDelivery(
+		token,
+		ctx.GetHeader(schemas.WebhookSecretHeader),
+		ctx.GetHeader(schemas.WebhookSignatureHeader),
+		body,
+	)
+	if err != nil {
+		return fmt.Errorf("can't handle webhook delivery: %w", err)
+	}
+
+	var triggerErrors []error
+	for _, trigger := range triggers {
+		err = controller.areaScheduler.TriggerArea(trigger.Area, trigger.Trigger)
+		if err != nil {
+			triggerErrors = append(triggerErrors, fmt.Errorf("can't trigger area: %w", err))
+		}
+	}
+	return errors.Join(triggerErrors...)
+}
@@ -1,8 +1,15 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"net/http"
 	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -35,12 +42,11 @@ func ping(router *gin.RouterGroup) {
 	})
 }
 
-func setupRouter() *gin.Engine {
-	appPort := os.Getenv("BACKEND_PORT")
-	if appPort == "" {
-		panic("BACKEND_PORT is not set")
-	}
-
+// setupRouter connects to the database, builds the repositories, services, controllers and
+// routes of the backend, and starts the area scheduler of the run mode: the API replicas only
+// notify the workers of the area changes, the workers check the areas. The REST API is
+// documented as served on appPort, empty for the workers, which do not serve it.
+func setupRouter(mode schemas.RunMode, appPort string) (*gin.Engine, service.AreaScheduler) {
 	router := gin.Default()
 	router.Use(cors.Default())
 
@@ -61,6 +67,7 @@ func setupRouter() *gin.Engine {
 	spotifyRepository := repository.NewSpotifyRepository(databaseConnection)
 	dropboxRepository := repository.NewDropboxRepository(databaseConnection)
 	microsoftRepository := repository.NewMicrosoftRepository(databaseConnection)
+	webhookRepository := repository.NewWebhookRepository(databaseConnection)
 	timerRepository := repository.NewTimerRepository(databaseConnection)
 	openweathermapRepository := repository.NewOpenWeatherMapRepository(databaseConnection)
 	userRepository := repository.NewUserRepository(databaseConnection)
@@ -70,43 +77,82 @@ func setupRouter() *gin.Engine {
 	areaRepository := repository.NewAreaRepository(databaseConnection)
 	tokenRepository := repository.NewTokenRepository(databaseConnection)
 	areaResultRepository := repository.NewAreaResultRepository(databaseConnection)
+	triggerQueueRepository := repository.NewTriggerQueueRepository(databaseConnection)
+	workflowRunRepository := repository.NewWorkflowRunRepository(databaseConnection)
+	deadLetterRepository := repository.NewDeadLetterRepository(databaseConnection)
+	areaLeaseRepository := repository.NewAreaLeaseRepository(databaseConnection)
+	notificationRepository := repository.NewNotificationRepository(databaseConnection)
 
 	// Services
+	providerClient := service.NewProviderClient()
+	pollCache := service.NewPollCache()
 	githubService := service.NewGithubService(
 		githubRepository,
 		serviceRepository,
 		areaRepository,
 		tokenRepository,
+		providerClient,
+		pollCache,
 	)
 	googleService := service.NewGoogleService(
 		gmailRepository,
 		serviceRepository,
 		areaRepository,
 		tokenRepository,
+		providerClient,
+		pollCache,
 	)
 	spotifyService := service.NewSpotifyService(
 		spotifyRepository,
 		serviceRepository,
 		areaRepository,
 		tokenRepository,
+		providerClient,
+		pollCache,
 	)
+	dropboxLongpoller := service.NewDropboxLongpoller(schemas.DropboxNotifyURL)
 	dropboxService := service.NewDropboxService(
 		dropboxRepository,
 		serviceRepository,
 		areaRepository,
 		tokenRepository,
+		providerClient,
+		pollCache,
+		dropboxLongpoller,
+	)
+	microsoftSubscriptionManager := service.NewMicrosoftSubscriptionManager(
+		schemas.MicrosoftGraphURL,
+		microsoftRepository,
+		areaRepository,
+		tokenRepository,
+		providerClient,
 	)
 	microsoftService := service.NewMicrosoftService(
 		microsoftRepository,
 		serviceRepository,
 		areaRepository,
 		tokenRepository,
+		providerClient,
+		pollCache,
+		microsoftSubscriptionManager,
+	)
+	timerService := service.NewTimerService(
+		timerRepository,
+		serviceRepository,
+		areaRepository,
+		providerClient,
 	)
-	timerService := service.NewTimerService(timerRepository, serviceRepository, areaRepository)
 	openWeatherMapService := service.NewOpenWeatherMapService(
 		openweathermapRepository,
 		serviceRepository,
 		areaRepository,
+		providerClient,
+		pollCache,
+	)
+	webhookService := service.NewWebhookService(
+		webhookRepository,
+		serviceRepository,
+		areaRepository,
 	)
 	jwtService := service.NewJWTService()
 	userService := service.NewUserService(userRepository, jwtService)
@@ -119,10 +165,41 @@ func setupRouter() *gin.Engine {
 		dropboxService,
 		microsoftService,
 		openWeatherMapService,
+		webhookService,
 	)
 	actionService := service.NewActionService(actionRepository, serviceService)
 	reactionService := service.NewReactionService(reactionRepository, serviceService)
-	areaResultService := service.NewAreaResultService(areaResultRepository)
+	areaResultService := service.NewAreaResultService(areaResultRepository, workflowRunRepository)
+	tokenService := service.NewTokenService(
+		tokenRepository,
+		userService,
+		serviceService,
+		providerClient,
+	)
+	areaLeaseService := service.NewAreaLeaseService(areaLeaseRepository, areaRepository)
+	areaScheduler := service.NewAreaNotifier(
+		triggerQueueRepository,
+		notificationRepository,
+		areaResultService,
+	)
+	if mode != schemas.RunModeApi {
+		areaScheduler = service.NewAreaScheduler(
+			areaRepository,
+			serviceService,
+			tokenService,
+			areaResultService,
+			triggerQueueRepository,
+			workflowRunRepository,
+			deadLetterRepository,
+			areaLeaseService,
+			notificationRepository,
+			dropboxLongpoller,
+			schemas.AreaSchedulerWorkers,
+		)
+
+		// The changes of the Dropbox folders check their areas right away
+		dropboxLongpoller.Start(areaScheduler.CheckArea)
+	}
 	areaService := service.NewAreaService(
 		areaRepository,
 		serviceService,
@@ -130,8 +207,16 @@ func setupRouter() *gin.Engine {
 		reactionService,
 		userService,
 		areaResultService,
+		areaScheduler,
 	)
-	tokenService := service.NewTokenService(tokenRepository, userService)
+	deadLetterService := service.NewDeadLetterService(
+		deadLetterRepository,
+		userService,
+		areaScheduler,
+	)
+
+	// The scheduler resumes the areas as it acquires their leases
+	areaScheduler.Start()
 
 	// Controllers
 	spotifyController := controller.NewSpotifyController(
@@ -145,6 +230,8 @@ func setupRouter() *gin.Engine {
 		userService,
 		tokenService,
 		serviceService,
+
+		areaScheduler,
 	)
 	gmailController := controller.NewGoogleController(
 		googleService,
@@ -163,6 +250,12 @@ func setupRouter() *gin.Engine {
 		userService,
 		tokenService,
 		serviceService,
+		areaScheduler,
+	)
+	webhookController := controller.NewWebhookController(
+		webhookService,
+		userService,
+		areaScheduler,
 	)
 	userController := controller.NewUserController(userService, jwtService, tokenService)
 	serviceController := controller.NewServiceController(
@@ -175,6 +268,8 @@ func setupRouter() *gin.Engine {
 	areaController := controller.NewAreaController(areaService)
 	tokenController := controller.NewTokenController(tokenService)
 	areaResultController := controller.NewAreaResultController(areaResultService, areaService)
+	deadLetterController := controller.NewDeadLetterController(deadLetterService)
+	adminController := controller.NewAdminController(areaLeaseService, providerClient)
 
 	// API routes
 	api.NewActionApi(actionController, apiRoutes, userService)
@@ -189,8 +284,11 @@ func setupRouter() *gin.Engine {
 	api.NewGithubAPI(githubController, apiRoutes, userService)
 	api.NewDropboxAPI(dropboxController, apiRoutes, userService)
 	api.NewMicrosoftAPI(microsoftController, apiRoutes, userService)
+	api.NewWebhookAPI(webhookController, apiRoutes, userService)
 	api.NewAreaAPI(areaController, apiRoutes, userService)
 	api.NewAreaResultAPI(areaResultController, apiRoutes, userService)
+	api.NewDeadLetterAPI(deadLetterController, apiRoutes, userService)
+	api.NewAdminAPI(adminController, apiRoutes)
 
 	// basic about.json route
 	router.GET("/about.json", serviceAPI.AboutJSON)
@@ -205,7 +303,7 @@ func setupRouter() *gin.Engine {
 		c.JSON(http.StatusNotFound, gin.H{"error": "not found", "path": path, "method": method})
 	})
 
-	return router
+	return router, areaScheduler
 }
 
 // func init() {
@@ -220,16 +318,62 @@ func setupRouter() *gin.Engine {
 // @name						Authorization
 // @description				Use "Bearer <token>" as the format for the Authorization header.
 func main() {
-	router := setupRouter()
+	modeFlag := flag.String("mode", string(schemas.RunModeAll), "part of the backend to run: "+
+		"api to serve the REST API, worker to check the areas, or all for both")
+	flag.Parse()
+	mode := schemas.RunMode(*modeFlag)
+	if !slices.Contains(
+		[]schemas.RunMode{schemas.RunModeApi, schemas.RunModeWorker, schemas.RunModeAll},
+		mode,
+	) {
+		panic(schemas.ErrInvalidRunMode)
+	}
 
-	// Listen and Server in 0.0.0.0:8000
+	// The workers do not serve the REST API
 	appPort := os.Getenv("BACKEND_PORT")
-	if appPort == "" {
+	if appPort == "" && mode != schemas.RunModeWorker {
 		panic("BACKEND_PORT is not set")
 	}
 
-	err := router.Run(":" + appPort)
+	router, areaScheduler := setupRouter(mode, appPort)
+
+	server := &http.Server{
+		Handler:           router,
+		ReadHeaderTimeout: time.Second * schemas.ReadHeaderTimeout,
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if mode != schemas.RunModeWorker {
+		// Listen and Server in 0.0.0.0:8000
+		server.Addr = ":" + appPort
+		go func() {
+			err := server.ListenAndServe()
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				panic("Error when running the server")
+			}
+		}()
+	}
+
+	<-ctx.Done()
+	stop()
+	println("Shutting down the server")
+
+	// The requests and the area checks share the drain window
+	shutdownCtx, cancel := context.WithTimeout(
+		context.Background(),
+		time.Second*schemas.ShutdownTimeout,
+	)
+	defer cancel()
+
+	err := server.Shutdown(shutdownCtx)
+	if err != nil {
+		println("error shutting down the server: " + err.Error())
+	}
+	err = areaScheduler.Stop(shutdownCtx)
 	if err != nil {
-		panic("Error when running the server")
+		println("error stopping the area scheduler: " + err.Error())
 	}
+	println("Server stopped")
 }
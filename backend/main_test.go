@@ -40,7 +40,7 @@ func TestPingRoute(t *testing.T) {
 		require.NoError(t, err)
 	}()
 	// Set up the router (defined in main.go)
-	router := setupRouter()
+	router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 	// Perform the HTTP request
 	responseRecorder := httptest.NewRecorder()
@@ -73,7 +73,7 @@ func TestAboutJsonRoute(t *testing.T) {
 	}()
 
 	// Set up the router (defined in main.go)
-	router := setupRouter()
+	router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 	// Perform the HTTP request
 	responseRecorder := httptest.NewRecorder()
@@ -115,7 +115,7 @@ func TestNotFoundRoute(t *testing.T) {
 	}()
 
 	// Set up the router (defined in main.go)
-	router := setupRouter()
+	router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 	// Perform the HTTP request
 	responseRecorder := httptest.NewRecorder()
@@ -150,7 +150,7 @@ func TestGoogleRedirectToServiceRoute(t *testing.T) {
 	}()
 
 	// Set up the router (defined in main.go)
-	router := setupRouter()
+	router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 	// Perform the HTTP request
 	responseRecorder := httptest.NewRecorder()
@@ -188,7 +188,7 @@ func TestSpotifyRedirectToServiceRoute(t *testing.T) {
 	}()
 
 	// Set up the router (defined in main.go)
-	router := setupRouter()
+	router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 	// Perform the HTTP request
 	responseRecorder := httptest.NewRecorder()
@@ -226,7 +226,7 @@ func TestGithubRedirectToServiceRoute(t *testing.T) {
 	}()
 
 	// Set up the router (defined in main.go)
-	router := setupRouter()
+	router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 	// Perform the HTTP request
 	responseRecorder := httptest.NewRecorder()
@@ -264,7 +264,7 @@ func TestDropboxRedirectToServiceRoute(t *testing.T) {
 	}()
 
 	// Set up the router (defined in main.go)
-	router := setupRouter()
+	router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 	// Perform the HTTP request
 	responseRecorder := httptest.NewRecorder()
@@ -302,7 +302,7 @@ func TestRegisterUserRoute(t *testing.T) {
 	}()
 
 	// Set up the router (defined in main.go)
-	router := setupRouter()
+	router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 	test.RegisterUser(t, router)
 }
@@ -326,7 +326,7 @@ func TestLoginUserRoute(t *testing.T) {
 		}()
 
 		// Set up the router (defined in main.go)
-		router := setupRouter()
+		router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 		// Define the raw JSON body for the test
 		requestBody := `{
@@ -375,7 +375,7 @@ func TestLoginUserRoute(t *testing.T) {
 		}()
 
 		// Set up the router (defined in main.go)
-		router := setupRouter()
+		router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 		test.RegisterUser(t, router)
 
@@ -402,7 +402,7 @@ func TestActionRoute(t *testing.T) {
 		}()
 
 		// Set up the router (defined in main.go)
-		router := setupRouter()
+		router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 		// Perform the HTTP POST request
 		responseRecorder := httptest.NewRecorder()
@@ -449,7 +449,7 @@ func TestActionRoute(t *testing.T) {
 		}()
 
 		// Set up the router (defined in main.go)
-		router := setupRouter()
+		router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 		bearerToken := test.RegisterUser(t, router)
 
@@ -494,7 +494,7 @@ func TestActionRoute(t *testing.T) {
 		}()
 
 		// Set up the router (defined in main.go)
-		router := setupRouter()
+		router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 		bearerToken := test.RegisterUser(t, router)
 
@@ -542,7 +542,7 @@ func TestReactionRoute(t *testing.T) {
 		}()
 
 		// Set up the router (defined in main.go)
-		router := setupRouter()
+		router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 		// Perform the HTTP POST request
 		responseRecorder := httptest.NewRecorder()
@@ -589,7 +589,7 @@ func TestReactionRoute(t *testing.T) {
 		}()
 
 		// Set up the router (defined in main.go)
-		router := setupRouter()
+		router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 		bearerToken := test.RegisterUser(t, router)
 
@@ -634,7 +634,7 @@ func TestReactionRoute(t *testing.T) {
 		}()
 
 		// Set up the router (defined in main.go)
-		router := setupRouter()
+		router, _ := setupRouter(schemas.RunModeAll, os.Getenv("BACKEND_PORT"))
 
 		bearerToken := test.RegisterUser(t, router)
 
@@ -0,0 +1,48 @@
+package middlewares
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"os"
+
+	"github.com/gin-gonic/gin"
+
+	"area/schemas"
+)
+
+// AuthorizeAdmin is a middleware function for authorizing the requests of the administrators
+// of the backend. It extracts the token from the "Authorization" header and compares it with
+// the ADMIN_TOKEN environment variable. If they match, the request is allowed to proceed.
+// Otherwise, it responds with an unauthorized error and aborts the request. The admin routes
+// are closed when ADMIN_TOKEN is not set.
+//
+// Returns:
+// - gin.HandlerFunc: a function that handles the HTTP request and performs admin authorization.
+func AuthorizeAdmin() gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		adminToken := os.Getenv("ADMIN_TOKEN")
+		if adminToken == "" {
+			ctx.JSON(http.StatusForbidden, schemas.ErrorResponse{
+				Error: schemas.ErrAdminTokenNotSet.Error(),
+			})
+			ctx.Abort()
+			return
+		}
+		authHeader := ctx.GetHeader("Authorization")
+		if len(authHeader) <= len(schemas.BearerTokenType) {
+			ctx.JSON(http.StatusUnauthorized, schemas.ErrorResponse{
+				Error: "No token provided",
+			})
+			ctx.Abort()
+			return
+		}
+		tokenString := authHeader[len(schemas.BearerTokenType):]
+		if subtle.ConstantTimeCompare([]byte(tokenString), []byte(adminToken)) != 1 {
+			ctx.JSON(http.StatusUnauthorized, schemas.ErrorResponse{
+				Error: "Invalid token",
+			})
+			ctx.Abort()
+			return
+		}
+	}
+}
@@ -0,0 +1,254 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"area/schemas"
+)
+
+// AreaLeaseRepository defines the interface of the ownership of the areas by the scheduler
+// nodes. It provides methods to record the heartbeats of the nodes and to acquire, renew and
+// release the leases of the areas. The heartbeats and the leases are timed by the clock of the
+// database, so that the nodes agree on them whatever the drift of their own clocks.
+//
+// Methods:
+//   - Now() (now time.Time, err error): Retrieves the current time of the database.
+//   - Heartbeat(node schemas.SchedulerNode) error: Records the node as alive now.
+//   - FindNodes() (nodes []schemas.SchedulerNode, err error): Retrieves the known nodes.
+//   - DeleteNode(nodeId string) error: Removes a node and releases its leases.
+//   - Acquire(areaId uint64, nodeId string, duration time.Duration) (acquired bool, err error): Takes the lease of an area.
+//   - Renew(nodeId string, duration time.Duration) error: Extends the leases of a node.
+//   - Release(areaId uint64, nodeId string) error: Gives up the lease of an area held by a node.
+//   - FindLeases() (leases []schemas.AreaLease, err error): Retrieves the leases of the areas.
+type AreaLeaseRepository interface {
+	Now() (now time.Time, err error)
+	Heartbeat(node schemas.SchedulerNode) error
+	FindNodes() (nodes []schemas.SchedulerNode, err error)
+	DeleteNode(nodeId string) error
+	Acquire(areaId uint64, nodeId string, duration time.Duration) (acquired bool, err error)
+	Renew(nodeId string, duration time.Duration) error
+	Release(areaId uint64, nodeId string) error
+	FindLeases() (leases []schemas.AreaLease, err error)
+}
+
+// areaLeaseRepository is a struct that provides access to the database for the area leases.
+// It contains a single field, db, which is a pointer to a Database schema.
+type areaLeaseRepository struct {
+	db *schemas.Database
+}
+
+// NewAreaLeaseRepository creates a new instance of AreaLeaseRepository.
+// It performs an automatic migration for the SchedulerNode and AreaLease schemas using the
+// provided gorm.DB connection. If the migration fails, it panics with an error message.
+//
+// Parameters:
+//   - conn: A pointer to a gorm.DB instance representing the database connection.
+//
+// Returns:
+//   - An instance of AreaLeaseRepository.
+func NewAreaLeaseRepository(conn *gorm.DB) AreaLeaseRepository {
+	err := conn.AutoMigrate(&schemas.SchedulerNode{}, &schemas.AreaLease{})
+	if err != nil {
+		panic("failed to migrate database")
+	}
+	return &areaLeaseRepository{
+		db: &schemas.Database{
+			Connection: conn,
+		},
+	}
+}
+
+// sqliteTimeFormat is the format of the times written by databaseTime in SQLite, which the
+// driver reads back as UTC times.
+const sqliteTimeFormat = "2006-01-02 15:04:05.000"
+
+// databaseTime returns the SQL expression of the current time of the database of conn shifted
+// by the given offset.
+func databaseTime(conn *gorm.DB, offset time.Duration) clause.Expr {
+	if conn.Dialector.Name() == "sqlite" {
+		// SQLite, used by the tests, has no now() and no interval
+		return gorm.Expr(
+			"strftime('%Y-%m-%d %H:%M:%f', 'now', ?)",
+			fmt.Sprintf("%+.3f seconds", offset.Seconds()),
+		)
+	}
+	return gorm.Expr("now() + ? * interval '1 microsecond'", offset.Microseconds())
+}
+
+// Now retrieves the current time of the database, which the heartbeats and the leases are
+// compared with.
+//
+// Returns:
+//   - now: The current time of the database.
+//   - err: An error if the query fails.
+func (repo *areaLeaseRepository) Now() (now time.Time, err error) {
+	if repo.db.Connection.Dialector.Name() == "sqlite" {
+		var text string
+		query := repo.db.Connection.Raw("SELECT ?", databaseTime(repo.db.Connection, 0))
+		err = query.Scan(&text).Error
+		if err == nil {
+			now, err = time.Parse(sqliteTimeFormat, text)
+		}
+	} else {
+		err = repo.db.Connection.Raw("SELECT now()").Scan(&now).Error
+	}
+	if err != nil {
+		return now, fmt.Errorf("failed to find database time: %w", err)
+	}
+	return now, nil
+}
+
+// Heartbeat records the given node as alive at the current time of the database, creating it
+// on its first heartbeat.
+//
+// Parameters:
+//   - node: The SchedulerNode schema instance sending the heartbeat.
+//
+// Returns:
+//   - error: An error if the save operation fails.
+func (repo *areaLeaseRepository) Heartbeat(node schemas.SchedulerNode) error {
+	result := repo.db.Connection.Model(&schemas.SchedulerNode{}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"hostname", "heartbeat_at", "update_at"}),
+	}).Create(map[string]any{
+		"id":           node.Id,
+		"hostname":     node.Hostname,
+		"started_at":   node.StartedAt,
+		"heartbeat_at": databaseTime(repo.db.Connection, 0),
+		"update_at":    databaseTime(repo.db.Connection, 0),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to save scheduler node heartbeat: %w", result.Error)
+	}
+	return nil
+}
+
+// FindNodes retrieves the known scheduler nodes, alive or not, oldest first.
+//
+// Returns:
+//   - nodes: The scheduler nodes.
+//   - err: An error if the query fails.
+func (repo *areaLeaseRepository) FindNodes() (nodes []schemas.SchedulerNode, err error) {
+	result := repo.db.Connection.Order("started_at, id").Find(&nodes)
+	if result.Error != nil {
+		return nodes, fmt.Errorf("failed to find scheduler nodes: %w", result.Error)
+	}
+	return nodes, nil
+}
+
+// DeleteNode removes a scheduler node and releases all its leases in a single transaction,
+// so that the other nodes take its areas over without waiting for the leases to expire.
+//
+// Parameters:
+//   - nodeId: The ID of the node.
+//
+// Returns:
+//   - error: An error if the delete operation fails.
+func (repo *areaLeaseRepository) DeleteNode(nodeId string) error {
+	err := repo.db.Connection.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("node_id = ?", nodeId).Delete(&schemas.AreaLease{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Where("id = ?", nodeId).Delete(&schemas.SchedulerNode{}).Error
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete scheduler node: %w", err)
+	}
+	return nil
+}
+
+// Acquire takes the lease of an area for a node, if the area has no lease, if its lease
+// expired, or if the node already holds it. The lease is taken with a single
+// INSERT ... ON CONFLICT DO UPDATE ... WHERE statement, so that two nodes acquiring the
+// same area at the same time never both get it. The lease expires at the time of the database
+// shifted by duration, and is expired once the time of the database passed it.
+//
+// Parameters:
+//   - areaId: The ID of the area.
+//   - nodeId: The ID of the node taking the lease.
+//   - duration: The time the lease lasts from the time of the database unless it is renewed.
+//
+// Returns:
+//   - acquired: Whether the node holds the lease of the area.
+//   - err: An error if the save operation fails.
+func (repo *areaLeaseRepository) Acquire(
+	areaId uint64,
+	nodeId string,
+	duration time.Duration,
+) (acquired bool, err error) {
+	result := repo.db.Connection.Model(&schemas.AreaLease{}).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "area_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"node_id", "expires_at", "update_at"}),
+		Where: clause.Where{Exprs: []clause.Expression{clause.Expr{
+			SQL:  "area_leases.node_id = excluded.node_id OR area_leases.expires_at < ?",
+			Vars: []any{databaseTime(repo.db.Connection, 0)},
+		}}},
+	}).Create(map[string]any{
+		"area_id":    areaId,
+		"node_id":    nodeId,
+		"expires_at": databaseTime(repo.db.Connection, duration),
+		"update_at":  databaseTime(repo.db.Connection, 0),
+	})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to acquire area lease: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// Renew extends all the leases held by a node.
+//
+// Parameters:
+//   - nodeId: The ID of the node.
+//   - duration: The time the leases last from the time of the database unless they are
+//     renewed again.
+//
+// Returns:
+//   - error: An error if the update operation fails.
+func (repo *areaLeaseRepository) Renew(nodeId string, duration time.Duration) error {
+	result := repo.db.Connection.Model(&schemas.AreaLease{}).
+		Where("node_id = ?", nodeId).
+		Updates(map[string]any{
+			"expires_at": databaseTime(repo.db.Connection, duration),
+			"update_at":  databaseTime(repo.db.Connection, 0),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to renew area leases: %w", result.Error)
+	}
+	return nil
+}
+
+// Release gives up the lease of an area, if the node holds it.
+//
+// Parameters:
+//   - areaId: The ID of the area.
+//   - nodeId: The ID of the node giving the lease up.
+//
+// Returns:
+//   - error: An error if the delete operation fails.
+func (repo *areaLeaseRepository) Release(areaId uint64, nodeId string) error {
+	result := repo.db.Connection.
+		Where("area_id = ? AND node_id = ?", areaId, nodeId).
+		Delete(&schemas.AreaLease{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to release area lease: %w", result.Error)
+	}
+	return nil
+}
+
+// FindLeases retrieves the leases of the areas, expired or not, ordered by area ID.
+//
+// Returns:
+//   - leases: The area leases.
+//   - err: An error if the query fails.
+func (repo *areaLeaseRepository) FindLeases() (leases []schemas.AreaLease, err error) {
+	result := repo.db.Connection.Order("area_id").Find(&leases)
+	if result.Error != nil {
+		return leases, fmt.Errorf("failed to find area leases: %w", result.Error)
+	}
+	return leases, nil
+}
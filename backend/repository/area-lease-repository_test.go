@@ -0,0 +1,134 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/repository"
+	"area/schemas"
+	"area/test"
+)
+
+func TestAreaLease_AcquireIsExclusive(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewAreaLeaseRepository(db)
+	acquired, err := repo.Acquire(1, "first", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = repo.Acquire(1, "second", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	// the owner can take its own lease again
+	acquired, err = repo.Acquire(1, "first", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	leases, err := repo.FindLeases()
+	assert.NoError(t, err)
+	assert.Len(t, leases, 1)
+	assert.Equal(t, "first", leases[0].NodeId)
+}
+
+func TestAreaLease_AcquireExpiredLease(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewAreaLeaseRepository(db)
+	acquired, err := repo.Acquire(1, "first", -time.Second)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	acquired, err = repo.Acquire(1, "second", time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, acquired)
+
+	leases, err := repo.FindLeases()
+	assert.NoError(t, err)
+	assert.Len(t, leases, 1)
+	assert.Equal(t, "second", leases[0].NodeId)
+}
+
+func TestAreaLease_RenewAndRelease(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewAreaLeaseRepository(db)
+	_, err = repo.Acquire(1, "first", -time.Second)
+	assert.NoError(t, err)
+	_, err = repo.Acquire(2, "first", -time.Second)
+	assert.NoError(t, err)
+
+	assert.NoError(t, repo.Renew("first", time.Minute))
+	acquired, err := repo.Acquire(1, "second", time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, acquired)
+
+	// releasing the lease of another node does nothing
+	assert.NoError(t, repo.Release(1, "second"))
+	assert.NoError(t, repo.Release(2, "first"))
+	leases, err := repo.FindLeases()
+	assert.NoError(t, err)
+	assert.Len(t, leases, 1)
+	assert.Equal(t, uint64(1), leases[0].AreaId)
+	assert.True(t, leases[0].ExpiresAt.After(time.Now()))
+}
+
+func TestAreaLease_HeartbeatAndDeleteNode(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewAreaLeaseRepository(db)
+	node := schemas.SchedulerNode{Id: "first", Hostname: "backend", StartedAt: time.Now()}
+	assert.NoError(t, repo.Heartbeat(node))
+	assert.NoError(t, repo.Heartbeat(node))
+	assert.NoError(t, repo.Heartbeat(schemas.SchedulerNode{Id: "second", StartedAt: time.Now()}))
+	_, err = repo.Acquire(1, "first", time.Minute)
+	assert.NoError(t, err)
+
+	nodes, err := repo.FindNodes()
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 2)
+	assert.Equal(t, "first", nodes[0].Id)
+	assert.Equal(t, "backend", nodes[0].Hostname)
+	assert.False(t, nodes[0].HeartbeatAt.IsZero())
+
+	assert.NoError(t, repo.DeleteNode("first"))
+	nodes, err = repo.FindNodes()
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 1)
+	leases, err := repo.FindLeases()
+	assert.NoError(t, err)
+	assert.Empty(t, leases)
+}
+
+func TestAreaLease_TimedByDatabase(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewAreaLeaseRepository(db)
+	// the clock of the node is ignored
+	assert.NoError(t, repo.Heartbeat(schemas.SchedulerNode{
+		Id:          "first",
+		StartedAt:   time.Now(),
+		HeartbeatAt: time.Now().Add(-time.Hour),
+	}))
+	_, err = repo.Acquire(1, "first", time.Minute)
+	assert.NoError(t, err)
+	now, err := repo.Now()
+	assert.NoError(t, err)
+
+	nodes, err := repo.FindNodes()
+	assert.NoError(t, err)
+	assert.Len(t, nodes, 1)
+	assert.WithinDuration(t, now, nodes[0].HeartbeatAt, time.Second)
+	leases, err := repo.FindLeases()
+	assert.NoError(t, err)
+	assert.Len(t, leases, 1)
+	assert.WithinDuration(t, now.Add(time.Minute), leases[0].ExpiresAt, time.Second)
+}
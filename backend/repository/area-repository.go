@@ -1,9 +1,12 @@
 package repository
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"area/schemas"
 )
@@ -15,18 +18,29 @@ import (
 //   - SaveArea(area schemas.Area) (areaID uint64, err error): Saves a new area and returns its ID.
 //   - Save(area schemas.Area) error: Saves a new area.
 //   - Update(area schemas.Area) error: Updates an existing area.
+//   - UpdateStorageVariable(area schemas.Area) error: Updates the storage variable of an area.
+//   - SwapStorageVariable(area schemas.Area, storageVariable json.RawMessage) (bool, error):
+//     Updates the storage variable of an area if it did not change since it was loaded.
+//   - UpdateColumns(areaId uint64, columns map[string]any) error: Updates columns of an area.
 //   - Delete(area schemas.Area) error: Deletes an existing area.
 //   - FindAll() (areas []schemas.Area, err error): Retrieves all areas.
+//   - FindEnabledIds() (areaIds []uint64, err error): Retrieves the IDs of the enabled areas.
 //   - FindByUserId(userID uint64) (areas []schemas.Area, err error): Retrieves areas associated with a specific user ID.
 //   - FindById(id uint64) (area schemas.Area, err error): Retrieves an area by its ID.
+//   - ReplaceReactions(areaId uint64, reactions []schemas.AreaReaction) error: Replaces the reactions of an area.
 type AreaRepository interface {
 	SaveArea(area schemas.Area) (areaID uint64, err error)
 	Save(area schemas.Area) error
 	Update(area schemas.Area) error
+	UpdateStorageVariable(area schemas.Area) error
+	SwapStorageVariable(area schemas.Area, storageVariable json.RawMessage) (bool, error)
+	UpdateColumns(areaId uint64, columns map[string]any) error
 	Delete(area schemas.Area) error
 	FindAll() (areas []schemas.Area, err error)
+	FindEnabledIds() (areaIds []uint64, err error)
 	FindByUserId(userID uint64) (areas []schemas.Area, err error)
 	FindById(id uint64) (area schemas.Area, err error)
+	ReplaceReactions(areaId uint64, reactions []schemas.AreaReaction) error
 }
 
 // areaRepository is a struct that provides methods to interact with the area-related data in the database.
@@ -36,7 +50,7 @@ type areaRepository struct {
 }
 
 // NewAreaRepository creates a new instance of AreaRepository.
-// It performs an automatic migration for the Area schema using the provided gorm.DB connection.
+// It performs an automatic migration for the Area and AreaReaction schemas using the provided gorm.DB connection.
 // If the migration fails, it panics with an error message.
 // It returns an implementation of the AreaRepository interface.
 //
@@ -46,7 +60,7 @@ type areaRepository struct {
 // Returns:
 //   - An implementation of the AreaRepository interface.
 func NewAreaRepository(conn *gorm.DB) AreaRepository {
-	err := conn.AutoMigrate(&schemas.Area{})
+	err := conn.AutoMigrate(&schemas.Area{}, &schemas.AreaReaction{})
 	if err != nil {
 		panic("failed to migrate database")
 	}
@@ -101,6 +115,7 @@ func (repo *areaRepository) Save(action schemas.Area) error {
 // Update updates an existing area record in the database.
 // It first searches for the area by its ID. If the area is found,
 // it updates the record with the new data provided in the action parameter.
+// The associations of the area, such as its reactions, are not written.
 // If the area is not found or if there is an error during the update process,
 // it returns an error.
 //
@@ -116,7 +131,7 @@ func (repo *areaRepository) Update(action schemas.Area) error {
 		return fmt.Errorf("failed to find area: %w", err)
 	}
 	if area.Id == action.Id {
-		err = repo.db.Connection.Save(&action).Error
+		err = repo.db.Connection.Omit(clause.Associations).Save(&action).Error
 		if err != nil {
 			return fmt.Errorf("failed to update area: %w", err)
 		}
@@ -124,6 +139,82 @@ func (repo *areaRepository) Update(action schemas.Area) error {
 	return nil
 }
 
+// UpdateStorageVariable writes the storage variable of the area, leaving its other columns
+// untouched, so that a check of the action of the area does not write back the rest of the
+// area it loaded, which may have been edited since. The storage variable is not written if the
+// action option of the area changed since it was loaded: the edit of the option reset it.
+//
+// Parameters:
+//   - area: The area, as loaded by the check, with its new storage variable.
+//
+// Returns:
+//   - error: An error if the update fails.
+func (repo *areaRepository) UpdateStorageVariable(area schemas.Area) error {
+	err := repo.storageQuery(area).Update("storage_variable", area.StorageVariable).Error
+	if err != nil {
+		return fmt.Errorf("failed to update area storage variable: %w", err)
+	}
+	return nil
+}
+
+// SwapStorageVariable writes the new storage variable of the area only if its storage variable
+// is still the one it was loaded with, so that two writers of the storage variable of the area
+// do not overwrite each other. Like UpdateStorageVariable, it leaves the other columns untouched
+// and writes nothing if the action option of the area changed since it was loaded.
+//
+// Parameters:
+//   - area: The area, as loaded by the writer, with its previous storage variable.
+//   - storageVariable: The new storage variable of the area.
+//
+// Returns:
+//   - bool: Whether the storage variable was written.
+//   - error: An error if the update fails.
+func (repo *areaRepository) SwapStorageVariable(
+	area schemas.Area,
+	storageVariable json.RawMessage,
+) (bool, error) {
+	query := repo.storageQuery(area)
+	if len(area.StorageVariable) == 0 {
+		query = query.Where("storage_variable IS NULL")
+	} else {
+		query = query.Where("storage_variable = ?", area.StorageVariable)
+	}
+	result := query.Update("storage_variable", storageVariable)
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to swap area storage variable: %w", result.Error)
+	}
+	return result.RowsAffected == 1, nil
+}
+
+// storageQuery selects the area if its action option is still the one it was loaded with.
+func (repo *areaRepository) storageQuery(area schemas.Area) *gorm.DB {
+	query := repo.db.Connection.Model(&schemas.Area{}).Where("id = ?", area.Id)
+	if len(area.ActionOption) == 0 {
+		return query.Where("action_option IS NULL")
+	}
+	return query.Where("action_option = ?", area.ActionOption)
+}
+
+// UpdateColumns writes the given columns of an area, leaving the others untouched, so that an
+// edit of the area does not write back the columns a check of its action updated meanwhile.
+//
+// Parameters:
+//   - areaId: The ID of the area.
+//   - columns: The new values of the columns, by column name.
+//
+// Returns:
+//   - error: An error if the update fails.
+func (repo *areaRepository) UpdateColumns(areaId uint64, columns map[string]any) error {
+	if len(columns) == 0 {
+		return nil
+	}
+	err := repo.db.Connection.Model(&schemas.Area{}).Where("id = ?", areaId).Updates(columns).Error
+	if err != nil {
+		return fmt.Errorf("failed to update area columns: %w", err)
+	}
+	return nil
+}
+
 // Delete removes an area record from the database.
 // It takes an Area schema as input and returns an error if the deletion fails.
 //
@@ -142,17 +233,46 @@ func (repo *areaRepository) Delete(action schemas.Area) error {
 	return nil
 }
 
-// FindAll retrieves all areas from the database, including their associated services.
+// orderByPosition orders the preloaded reactions of an area by their position.
+func orderByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
+// FindAll retrieves all areas from the database, including their associated user,
+// action and reactions (with their services).
 // It returns a slice of Area schemas and an error if the operation fails.
 // If the operation is successful, the error will be nil.
 func (repo *areaRepository) FindAll() (areas []schemas.Area, err error) {
-	err = repo.db.Connection.Preload("Service").Find(&areas).Error
+	err = repo.db.Connection.
+		Preload("User").
+		Preload("Action.Service").
+		Preload("Reaction.Service").
+		Preload("Reactions", orderByPosition).
+		Preload("Reactions.Reaction.Service").
+		Find(&areas).Error
 	if err != nil {
 		return areas, fmt.Errorf("failed to find all areas: %w", err)
 	}
 	return areas, nil
 }
 
+// FindEnabledIds retrieves the IDs of the enabled areas, without loading the areas.
+//
+// Returns:
+//   - areaIds: The IDs of the enabled areas, in ascending order.
+//   - err: An error if the query fails.
+func (repo *areaRepository) FindEnabledIds() (areaIds []uint64, err error) {
+	err = repo.db.Connection.Model(&schemas.Area{}).
+		Where("enable = ?", true).
+		Order("id").
+		Pluck("id", &areaIds).
+		Error
+	if err != nil {
+		return areaIds, fmt.Errorf("failed to find enabled area ids: %w", err)
+	}
+	return areaIds, nil
+}
+
 // FindByUserId retrieves a list of areas associated with a specific user ID.
 // It preloads the User, Action.Service, and Reaction.Service associations
 // to ensure related data is also fetched. If an error occurs during the
@@ -169,6 +289,8 @@ func (repo *areaRepository) FindByUserId(userID uint64) (areas []schemas.Area, e
 		Preload("User").
 		Preload("Action.Service").
 		Preload("Reaction.Service").
+		Preload("Reactions", orderByPosition).
+		Preload("Reactions.Reaction.Service").
 		Where(&schemas.Area{UserId: userID}).
 		Find(&areas).Error
 	if err != nil {
@@ -178,7 +300,7 @@ func (repo *areaRepository) FindByUserId(userID uint64) (areas []schemas.Area, e
 	return areas, nil
 }
 
-// FindById retrieves an Area by its ID from the database, including its associated Action and Reaction.
+// FindById retrieves an Area by its ID from the database, including its associated Action and Reactions.
 // It returns the Area and an error if the operation fails.
 //
 // Parameters:
@@ -186,7 +308,7 @@ func (repo *areaRepository) FindByUserId(userID uint64) (areas []schemas.Area, e
 //
 // Returns:
 //   - area: the retrieved Area, including its associated Action and Reaction.
-//   - err: an error if the operation fails, otherwise nil.
+//   - err: an error if the operation fails, wrapping schemas.ErrAreaNotFound if the area does not exist.
 func (repo *areaRepository) FindById(id uint64) (area schemas.Area, err error) {
 	err = repo.db.Connection.Where(&schemas.Area{Id: id}).First(&area).Error
 	var actionResult schemas.Action
@@ -195,9 +317,46 @@ func (repo *areaRepository) FindById(id uint64) (area schemas.Area, err error) {
 	var reactionResult schemas.Reaction
 	repo.db.Connection.Where(&schemas.Reaction{Id: area.ReactionId}).First(&reactionResult)
 	area.Reaction = reactionResult
+	repo.db.Connection.Preload("Reaction").
+		Where("area_id = ?", area.Id).
+		Order("position").
+		Find(&area.Reactions)
 
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return area, fmt.Errorf("failed to find area by id: %w", schemas.ErrAreaNotFound)
+	}
 	if err != nil {
 		return area, fmt.Errorf("failed to find action by id: %w", err)
 	}
 	return area, nil
 }
+
+// ReplaceReactions replaces the reactions of an area by the given ones, in a single transaction.
+//
+// Parameters:
+//   - areaId: The ID of the area whose reactions are replaced.
+//   - reactions: The new ordered reactions of the area.
+//
+// Returns:
+//   - error: An error if the old reactions can not be deleted or the new ones saved.
+func (repo *areaRepository) ReplaceReactions(
+	areaId uint64,
+	reactions []schemas.AreaReaction,
+) error {
+	return repo.db.Connection.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("area_id = ?", areaId).Delete(&schemas.AreaReaction{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to delete area reactions: %w", err)
+		}
+		for index := range reactions {
+			reactions[index].Id = 0
+			reactions[index].AreaId = areaId
+			reactions[index].Position = index
+			err = tx.Omit("Reaction").Create(&reactions[index]).Error
+			if err != nil {
+				return fmt.Errorf("failed to save area reaction: %w", err)
+			}
+		}
+		return nil
+	})
+}
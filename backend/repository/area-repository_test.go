@@ -135,3 +135,132 @@ func TestAreaFindById(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, area.Id, foundArea.UserId)
 }
+
+func TestReplaceReactions(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewAreaRepository(db)
+	areaID, err := repo.SaveArea(schemas.Area{
+		ActionRefreshRate: 10,
+		Reactions: []schemas.AreaReaction{
+			{ReactionOption: []byte(`{"to":"first"}`)},
+		},
+	})
+	assert.NoError(t, err)
+
+	area, err := repo.FindById(areaID)
+	assert.NoError(t, err)
+	assert.Len(t, area.Reactions, 1)
+
+	err = repo.ReplaceReactions(areaID, []schemas.AreaReaction{
+		{ReactionOption: []byte(`{"to":"second"}`)},
+		{ReactionOption: []byte(`{"to":"third"}`), ContinueOnFailure: true},
+	})
+	assert.NoError(t, err)
+
+	area, err = repo.FindById(areaID)
+	assert.NoError(t, err)
+	assert.Len(t, area.Reactions, 2)
+	assert.Equal(t, 0, area.Reactions[0].Position)
+	assert.JSONEq(t, `{"to":"second"}`, string(area.Reactions[0].ReactionOption))
+	assert.Equal(t, 1, area.Reactions[1].Position)
+	assert.True(t, area.Reactions[1].ContinueOnFailure)
+}
+
+func TestUpdateStaleAreaKeepsReplacedReactions(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewAreaRepository(db)
+	areaID, err := repo.SaveArea(schemas.Area{
+		ActionRefreshRate: 10,
+		Reactions: []schemas.AreaReaction{
+			{ReactionOption: []byte(`{"a":1}`)},
+		},
+	})
+	assert.NoError(t, err)
+	stale, err := repo.FindById(areaID)
+	assert.NoError(t, err)
+
+	err = repo.ReplaceReactions(areaID, []schemas.AreaReaction{
+		{ReactionOption: []byte(`{"b":2}`)},
+	})
+	assert.NoError(t, err)
+	stale.ActionRefreshRate = 20
+	err = repo.Update(stale)
+	assert.NoError(t, err)
+	stale.StorageVariable = []byte(`{"time":"2024-01-01T00:00:00Z"}`)
+	err = repo.UpdateStorageVariable(stale)
+	assert.NoError(t, err)
+
+	area, err := repo.FindById(areaID)
+	assert.NoError(t, err)
+	assert.Len(t, area.Reactions, 1)
+	assert.JSONEq(t, `{"b":2}`, string(area.Reactions[0].ReactionOption))
+	assert.Equal(t, uint64(20), area.ActionRefreshRate)
+	assert.JSONEq(t, `{"time":"2024-01-01T00:00:00Z"}`, string(area.StorageVariable))
+}
+
+func TestUpdateColumnsKeepsOtherColumns(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewAreaRepository(db)
+	areaID, err := repo.SaveArea(schemas.Area{
+		Title:             "Morning",
+		ActionRefreshRate: 10,
+		ActionOption:      []byte(`{"hour":7}`),
+		StorageVariable:   []byte(`{"time":"2024-01-01T00:00:00Z"}`),
+	})
+	assert.NoError(t, err)
+	checked, err := repo.FindById(areaID)
+	assert.NoError(t, err)
+
+	err = repo.UpdateColumns(areaID, map[string]any{
+		"title":            "Evening",
+		"action_option":    []byte(`{"hour":19}`),
+		"storage_variable": []byte(`{}`),
+	})
+	assert.NoError(t, err)
+	// the check that loaded the area before its option changed does not write its storage
+	checked.StorageVariable = []byte(`{"time":"2024-01-02T00:00:00Z"}`)
+	err = repo.UpdateStorageVariable(checked)
+	assert.NoError(t, err)
+
+	area, err := repo.FindById(areaID)
+	assert.NoError(t, err)
+	assert.Equal(t, "Evening", area.Title)
+	assert.Equal(t, uint64(10), area.ActionRefreshRate)
+	assert.JSONEq(t, `{"hour":19}`, string(area.ActionOption))
+	assert.JSONEq(t, `{}`, string(area.StorageVariable))
+}
+
+func TestSwapStorageVariable(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewAreaRepository(db)
+	areaID, err := repo.SaveArea(schemas.Area{
+		ActionRefreshRate: 10,
+		ActionOption:      []byte(`{}`),
+		StorageVariable:   []byte(`{"time":"2024-01-01T00:00:00Z"}`),
+	})
+	assert.NoError(t, err)
+	notified, err := repo.FindById(areaID)
+	assert.NoError(t, err)
+	checked, err := repo.FindById(areaID)
+	assert.NoError(t, err)
+
+	swapped, err := repo.SwapStorageVariable(checked, []byte(`{"time":"2024-01-02T00:00:00Z"}`))
+	assert.NoError(t, err)
+	assert.True(t, swapped)
+	// the notification loaded the area before the check stored its variable
+	swapped, err = repo.SwapStorageVariable(notified, []byte(`{"time":"2024-01-03T00:00:00Z"}`))
+	assert.NoError(t, err)
+	assert.False(t, swapped)
+
+	area, err := repo.FindById(areaID)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"time":"2024-01-02T00:00:00Z"}`, string(area.StorageVariable))
+}
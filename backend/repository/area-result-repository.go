@@ -17,12 +17,18 @@ import (
 //   - Delete(action schemas.AreaResult): Removes an area result.
 //   - FindAll() []schemas.AreaResult: Retrieves all area results.
 //   - FindByAreaId(userID uint64) []schemas.AreaResult: Retrieves area results by a specific area ID.
+//   - FindByAreaIdAndFilter(areaId uint64, filter schemas.AreaResultFilter): Retrieves a filtered
+//     page of the area results of an area and the number of results matching the filter.
 type AreaResultRepository interface {
 	Save(action schemas.AreaResult)
 	Update(action schemas.AreaResult)
 	Delete(action schemas.AreaResult)
 	FindAll() []schemas.AreaResult
 	FindByAreaId(userID uint64) []schemas.AreaResult
+	FindByAreaIdAndFilter(
+		areaId uint64,
+		filter schemas.AreaResultFilter,
+	) (results []schemas.AreaResult, total int64, err error)
 }
 
 // areaResultRepository is a struct that provides access to the database for area results.
@@ -128,3 +134,44 @@ func (repo *areaResultRepository) FindByAreaId(areaId uint64) []schemas.AreaResu
 	}
 	return actions
 }
+
+// FindByAreaIdAndFilter retrieves the area results of the given area matching the filter,
+// newest first. Only the requested page is returned, along with the number of results
+// matching the filter on all pages.
+//
+// Parameters:
+//   - areaId: The ID of the area to filter the AreaResult records.
+//   - filter: The status, creation time range and page of the results.
+//
+// Returns:
+//   - results: The area results of the requested page.
+//   - total: The number of area results matching the filter.
+//   - err: An error if the query fails.
+func (repo *areaResultRepository) FindByAreaIdAndFilter(
+	areaId uint64,
+	filter schemas.AreaResultFilter,
+) (results []schemas.AreaResult, total int64, err error) {
+	query := repo.db.Connection.Model(&schemas.AreaResult{}).
+		Where(&schemas.AreaResult{AreaId: areaId, Status: filter.Status})
+	if !filter.From.IsZero() {
+		query = query.Where("created_at >= ?", filter.From)
+	}
+	if !filter.To.IsZero() {
+		query = query.Where("created_at < ?", filter.To)
+	}
+
+	err = query.Count(&total).Error
+	if err != nil {
+		return results, 0, fmt.Errorf("failed to count area results: %w", err)
+	}
+
+	err = query.Order("created_at desc, id desc").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&results).
+		Error
+	if err != nil {
+		return results, 0, fmt.Errorf("failed to find area results by area id: %w", err)
+	}
+	return results, total, nil
+}
@@ -2,6 +2,7 @@ package repository_test
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
@@ -95,3 +96,53 @@ func TestAreaResult_FindByAreaId(t *testing.T) {
 	assert.Len(t, results, 1)
 	assert.Equal(t, action.Result, results[0].Result)
 }
+
+func TestAreaResult_FindByAreaIdAndFilter(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewAreaResultRepository(db)
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	for index := range 5 {
+		status := schemas.AreaResultSuccess
+		if index%2 == 1 {
+			status = schemas.AreaResultFailure
+		}
+		repo.Save(schemas.AreaResult{
+			AreaId:    1,
+			Result:    "Test Result",
+			Status:    status,
+			CreatedAt: start.Add(time.Duration(index) * time.Hour),
+		})
+	}
+	repo.Save(schemas.AreaResult{AreaId: 2, Result: "Other Result", CreatedAt: start})
+
+	results, total, err := repo.FindByAreaIdAndFilter(1, schemas.AreaResultFilter{
+		Page:     1,
+		PageSize: 2,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(5), total)
+	assert.Len(t, results, 2)
+	assert.True(t, results[0].CreatedAt.Equal(start.Add(4*time.Hour)))
+
+	results, total, err = repo.FindByAreaIdAndFilter(1, schemas.AreaResultFilter{
+		Status:   schemas.AreaResultFailure,
+		Page:     1,
+		PageSize: 10,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, results, 2)
+
+	results, total, err = repo.FindByAreaIdAndFilter(1, schemas.AreaResultFilter{
+		From:     start.Add(time.Hour),
+		To:       start.Add(3 * time.Hour),
+		Page:     2,
+		PageSize: 1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, results, 1)
+	assert.True(t, results[0].CreatedAt.Equal(start.Add(time.Hour)))
+}
@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"area/schemas"
+)
+
+// DeadLetterRepository defines the interface for interacting with dead letter data.
+// It provides methods to save, delete, and retrieve the triggers whose reactions failed for good.
+//
+// Methods:
+//   - Save(deadLetter schemas.DeadLetter) error: Persists a new dead letter.
+//   - Delete(deadLetter schemas.DeadLetter) error: Removes a dead letter.
+//   - FindById(id uint64) (deadLetter schemas.DeadLetter, err error): Retrieves a dead letter by its ID.
+//   - FindByUserId(userId uint64) (deadLetters []schemas.DeadLetter, err error): Retrieves the dead letters of a user.
+type DeadLetterRepository interface {
+	Save(deadLetter schemas.DeadLetter) error
+	Delete(deadLetter schemas.DeadLetter) error
+	FindById(id uint64) (deadLetter schemas.DeadLetter, err error)
+	FindByUserId(userId uint64) (deadLetters []schemas.DeadLetter, err error)
+}
+
+// deadLetterRepository is a struct that provides access to the database for dead letters.
+// It contains a single field, db, which is a pointer to a Database schema.
+type deadLetterRepository struct {
+	db *schemas.Database
+}
+
+// NewDeadLetterRepository creates a new instance of DeadLetterRepository.
+// It performs an automatic migration for the DeadLetter schema using the provided gorm.DB connection.
+// If the migration fails, it panics with an error message.
+//
+// Parameters:
+//   - conn: A pointer to a gorm.DB instance representing the database connection.
+//
+// Returns:
+//   - An instance of DeadLetterRepository.
+func NewDeadLetterRepository(conn *gorm.DB) DeadLetterRepository {
+	err := conn.AutoMigrate(&schemas.DeadLetter{})
+	if err != nil {
+		panic("failed to migrate database")
+	}
+	return &deadLetterRepository{
+		db: &schemas.Database{
+			Connection: conn,
+		},
+	}
+}
+
+// Save stores the given dead letter in the database.
+//
+// Parameters:
+//   - deadLetter: The DeadLetter schema instance to be saved.
+//
+// Returns:
+//   - error: An error if the save operation fails.
+func (repo *deadLetterRepository) Save(deadLetter schemas.DeadLetter) error {
+	err := repo.db.Connection.Omit("Area").Create(&deadLetter)
+	if err.Error != nil {
+		return fmt.Errorf("failed to save dead letter: %w", err.Error)
+	}
+	return nil
+}
+
+// Delete removes the given dead letter from the database.
+//
+// Parameters:
+//   - deadLetter: The DeadLetter schema instance to be deleted.
+//
+// Returns:
+//   - error: An error if the delete operation fails.
+func (repo *deadLetterRepository) Delete(deadLetter schemas.DeadLetter) error {
+	err := repo.db.Connection.Delete(&deadLetter)
+	if err.Error != nil {
+		return fmt.Errorf("failed to delete dead letter: %w", err.Error)
+	}
+	return nil
+}
+
+// FindById retrieves the dead letter with the given ID.
+//
+// Parameters:
+//   - id: The ID of the dead letter.
+//
+// Returns:
+//   - deadLetter: The dead letter.
+//   - err: schemas.ErrDeadLetterNotFound if no dead letter has this ID, or an error if the query fails.
+func (repo *deadLetterRepository) FindById(id uint64) (deadLetter schemas.DeadLetter, err error) {
+	err = repo.db.Connection.Where("id = ?", id).First(&deadLetter).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return deadLetter, schemas.ErrDeadLetterNotFound
+	}
+	if err != nil {
+		return deadLetter, fmt.Errorf("failed to find dead letter by id: %w", err)
+	}
+	return deadLetter, nil
+}
+
+// FindByUserId retrieves the dead letters of the areas of the given user, newest first.
+//
+// Parameters:
+//   - userId: The ID of the user owning the areas.
+//
+// Returns:
+//   - deadLetters: The dead letters of the user.
+//   - err: An error if the query fails.
+func (repo *deadLetterRepository) FindByUserId(
+	userId uint64,
+) (deadLetters []schemas.DeadLetter, err error) {
+	result := repo.db.Connection.Where("user_id = ?", userId).
+		Order("created_at desc, id desc").
+		Find(&deadLetters)
+	if result.Error != nil {
+		return deadLetters, fmt.Errorf("failed to find dead letters by user id: %w", result.Error)
+	}
+	return deadLetters, nil
+}
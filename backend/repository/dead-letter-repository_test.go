@@ -0,0 +1,53 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/repository"
+	"area/schemas"
+	"area/test"
+)
+
+func TestDeadLetter_SaveAndFindByUserId(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewDeadLetterRepository(db)
+	err = repo.Save(schemas.DeadLetter{UserId: 1, AreaId: 1, TriggerMessage: "first"})
+	assert.NoError(t, err)
+	err = repo.Save(schemas.DeadLetter{UserId: 1, AreaId: 2, TriggerMessage: "second"})
+	assert.NoError(t, err)
+	err = repo.Save(schemas.DeadLetter{UserId: 2, AreaId: 3, TriggerMessage: "other"})
+	assert.NoError(t, err)
+
+	deadLetters, err := repo.FindByUserId(1)
+	assert.NoError(t, err)
+	assert.Len(t, deadLetters, 2)
+	assert.Equal(t, "second", deadLetters[0].TriggerMessage)
+	assert.Equal(t, "first", deadLetters[1].TriggerMessage)
+}
+
+func TestDeadLetter_FindByIdAndDelete(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewDeadLetterRepository(db)
+	err = repo.Save(schemas.DeadLetter{UserId: 1, AreaId: 1, TriggerMessage: "first"})
+	assert.NoError(t, err)
+
+	deadLetters, err := repo.FindByUserId(1)
+	assert.NoError(t, err)
+	assert.Len(t, deadLetters, 1)
+
+	deadLetter, err := repo.FindById(deadLetters[0].Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "first", deadLetter.TriggerMessage)
+
+	err = repo.Delete(deadLetter)
+	assert.NoError(t, err)
+
+	_, err = repo.FindById(deadLetter.Id)
+	assert.ErrorIs(t, err, schemas.ErrDeadLetterNotFound)
+}
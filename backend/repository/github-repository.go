@@ -1,12 +1,34 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+
 	"gorm.io/gorm"
 
 	"area/schemas"
 )
 
-type GithubRepository interface{}
+// GithubRepository defines the interface for interacting with the GitHub data, the webhooks
+// registered on the repositories of the users.
+//
+// Methods:
+//   - SaveWebhook(webhook schemas.GithubWebhook) (webhookId uint64, err error): Persists a new webhook and returns its ID.
+//   - UpdateWebhook(webhook schemas.GithubWebhook) error: Updates a webhook.
+//   - DeleteWebhook(webhook schemas.GithubWebhook) error: Removes a webhook.
+//   - FindWebhookById(id uint64) (webhook schemas.GithubWebhook, err error): Retrieves a webhook by its ID.
+//   - FindWebhookByUserIdAndRepoName(userId uint64, repoName string) (webhook schemas.GithubWebhook, err error):
+//     Retrieves the webhook of a user on a repository.
+type GithubRepository interface {
+	SaveWebhook(webhook schemas.GithubWebhook) (webhookId uint64, err error)
+	UpdateWebhook(webhook schemas.GithubWebhook) error
+	DeleteWebhook(webhook schemas.GithubWebhook) error
+	FindWebhookById(id uint64) (webhook schemas.GithubWebhook, err error)
+	FindWebhookByUserIdAndRepoName(
+		userId uint64,
+		repoName string,
+	) (webhook schemas.GithubWebhook, err error)
+}
 
 // Define a struct that embeds `*schemas.Database` and implements `GithubRepository`.
 type githubRepository struct {
@@ -14,7 +36,8 @@ type githubRepository struct {
 }
 
 // NewGithubRepository creates a new instance of GithubRepository with the provided gorm.DB connection.
-// It initializes the database connection within the githubRepository struct.
+// It initializes the database connection within the githubRepository struct, and performs an
+// automatic migration for the GithubWebhook schema. If the migration fails, it panics.
 //
 // Parameters:
 //
@@ -24,9 +47,108 @@ type githubRepository struct {
 //
 //	A new instance of GithubRepository.
 func NewGithubRepository(conn *gorm.DB) GithubRepository {
+	err := conn.AutoMigrate(&schemas.GithubWebhook{})
+	if err != nil {
+		panic("failed to migrate database")
+	}
 	return &githubRepository{
 		db: &schemas.Database{
 			Connection: conn,
 		},
 	}
 }
+
+// SaveWebhook stores the given webhook in the database.
+//
+// Parameters:
+//   - webhook: The GithubWebhook schema instance to be saved.
+//
+// Returns:
+//   - webhookId: The ID of the saved webhook.
+//   - err: An error if the save operation fails.
+func (repo *githubRepository) SaveWebhook(
+	webhook schemas.GithubWebhook,
+) (webhookId uint64, err error) {
+	err = repo.db.Connection.Omit("User").Create(&webhook).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to save github webhook: %w", err)
+	}
+	return webhook.Id, nil
+}
+
+// UpdateWebhook updates the given webhook in the database.
+//
+// Parameters:
+//   - webhook: The GithubWebhook schema instance to be updated.
+//
+// Returns:
+//   - error: An error if the update operation fails.
+func (repo *githubRepository) UpdateWebhook(webhook schemas.GithubWebhook) error {
+	err := repo.db.Connection.Omit("User").Save(&webhook).Error
+	if err != nil {
+		return fmt.Errorf("failed to update github webhook: %w", err)
+	}
+	return nil
+}
+
+// DeleteWebhook removes the given webhook from the database.
+//
+// Parameters:
+//   - webhook: The GithubWebhook schema instance to be deleted.
+//
+// Returns:
+//   - error: An error if the delete operation fails.
+func (repo *githubRepository) DeleteWebhook(webhook schemas.GithubWebhook) error {
+	err := repo.db.Connection.Delete(&webhook).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete github webhook: %w", err)
+	}
+	return nil
+}
+
+// FindWebhookById retrieves the webhook with the given ID.
+//
+// Parameters:
+//   - id: The ID of the webhook.
+//
+// Returns:
+//   - webhook: The webhook.
+//   - err: schemas.ErrGithubWebhookNotFound if no webhook has this ID, or an error if the query fails.
+func (repo *githubRepository) FindWebhookById(
+	id uint64,
+) (webhook schemas.GithubWebhook, err error) {
+	err = repo.db.Connection.Where("id = ?", id).First(&webhook).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return webhook, schemas.ErrGithubWebhookNotFound
+	}
+	if err != nil {
+		return webhook, fmt.Errorf("failed to find github webhook by id: %w", err)
+	}
+	return webhook, nil
+}
+
+// FindWebhookByUserIdAndRepoName retrieves the webhook registered by the user on the repository.
+//
+// Parameters:
+//   - userId: The ID of the user who registered the webhook.
+//   - repoName: The repository of the webhook, in the format "owner/repo".
+//
+// Returns:
+//   - webhook: The webhook.
+//   - err: schemas.ErrGithubWebhookNotFound if the user registered no webhook on the
+//     repository, or an error if the query fails.
+func (repo *githubRepository) FindWebhookByUserIdAndRepoName(
+	userId uint64,
+	repoName string,
+) (webhook schemas.GithubWebhook, err error) {
+	err = repo.db.Connection.
+		Where("user_id = ? AND LOWER(repo_name) = LOWER(?)", userId, repoName).
+		First(&webhook).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return webhook, schemas.ErrGithubWebhookNotFound
+	}
+	if err != nil {
+		return webhook, fmt.Errorf("failed to find github webhook by repository: %w", err)
+	}
+	return webhook, nil
+}
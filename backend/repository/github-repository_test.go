@@ -0,0 +1,60 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/repository"
+	"area/schemas"
+	"area/test"
+)
+
+func TestGithubWebhook_SaveAndFind(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewGithubRepository(db)
+	webhookId, err := repo.SaveWebhook(schemas.GithubWebhook{
+		UserId:   1,
+		RepoName: "Owner/Repo",
+		Secret:   "secret",
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, webhookId)
+
+	webhook, err := repo.FindWebhookById(webhookId)
+	assert.NoError(t, err)
+	assert.Equal(t, "secret", webhook.Secret)
+
+	// the repository names of GitHub are case insensitive
+	webhook, err = repo.FindWebhookByUserIdAndRepoName(1, "owner/repo")
+	assert.NoError(t, err)
+	assert.Equal(t, webhookId, webhook.Id)
+	_, err = repo.FindWebhookByUserIdAndRepoName(2, "owner/repo")
+	assert.ErrorIs(t, err, schemas.ErrGithubWebhookNotFound)
+}
+
+func TestGithubWebhook_UpdateAndDelete(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewGithubRepository(db)
+	webhookId, err := repo.SaveWebhook(schemas.GithubWebhook{UserId: 1, RepoName: "owner/repo"})
+	assert.NoError(t, err)
+
+	webhook, err := repo.FindWebhookById(webhookId)
+	assert.NoError(t, err)
+	webhook.HookId = 42
+	err = repo.UpdateWebhook(webhook)
+	assert.NoError(t, err)
+
+	webhook, err = repo.FindWebhookById(webhookId)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(42), webhook.HookId)
+
+	err = repo.DeleteWebhook(webhook)
+	assert.NoError(t, err)
+	_, err = repo.FindWebhookById(webhookId)
+	assert.ErrorIs(t, err, schemas.ErrGithubWebhookNotFound)
+}
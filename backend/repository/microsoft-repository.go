@@ -1,12 +1,36 @@
 package repository
 
 import (
+	"errors"
+	"fmt"
+
 	"gorm.io/gorm"
 
 	"area/schemas"
 )
 
-type MicrosoftRepository interface{}
+// MicrosoftRepository defines the interface for interacting with the Microsoft data, the Graph
+// subscriptions created for the areas.
+//
+// Methods:
+//   - SaveSubscription(subscription schemas.MicrosoftSubscription) error: Persists a new subscription.
+//   - UpdateSubscription(subscription schemas.MicrosoftSubscription) error: Updates a subscription.
+//   - DeleteSubscription(subscription schemas.MicrosoftSubscription) error: Removes a subscription.
+//   - FindSubscriptionByAreaId(areaId uint64) (subscription schemas.MicrosoftSubscription, err error):
+//     Retrieves the subscription of an area.
+//   - FindSubscriptionBySubscriptionId(subscriptionId string) (subscription schemas.MicrosoftSubscription, err error):
+//     Retrieves a subscription by its ID on Graph.
+type MicrosoftRepository interface {
+	SaveSubscription(subscription schemas.MicrosoftSubscription) error
+	UpdateSubscription(subscription schemas.MicrosoftSubscription) error
+	DeleteSubscription(subscription schemas.MicrosoftSubscription) error
+	FindSubscriptionByAreaId(
+		areaId uint64,
+	) (subscription schemas.MicrosoftSubscription, err error)
+	FindSubscriptionBySubscriptionId(
+		subscriptionId string,
+	) (subscription schemas.MicrosoftSubscription, err error)
+}
 
 // Define a struct that embeds `*schemas.Database` and implements `MicrosoftRepository`.
 type microsoftRepository struct {
@@ -14,7 +38,8 @@ type microsoftRepository struct {
 }
 
 // NewMicrosoftRepository creates a new instance of MicrosoftRepository with the provided database connection.
-// It initializes the microsoftRepository struct with a schemas.Database that holds the given gorm.DB connection.
+// It initializes the microsoftRepository struct with a schemas.Database that holds the given gorm.DB connection,
+// and performs an automatic migration for the MicrosoftSubscription schema. If the migration fails, it panics.
 //
 // Parameters:
 //   - conn: A pointer to a gorm.DB instance representing the database connection.
@@ -22,9 +47,110 @@ type microsoftRepository struct {
 // Returns:
 //   - MicrosoftRepository: A new instance of MicrosoftRepository.
 func NewMicrosoftRepository(conn *gorm.DB) MicrosoftRepository {
+	err := conn.AutoMigrate(&schemas.MicrosoftSubscription{})
+	if err != nil {
+		panic("failed to migrate database")
+	}
 	return &microsoftRepository{
 		db: &schemas.Database{
 			Connection: conn,
 		},
 	}
 }
+
+// SaveSubscription stores the given subscription in the database.
+//
+// Parameters:
+//   - subscription: The MicrosoftSubscription schema instance to be saved.
+//
+// Returns:
+//   - error: An error if the save operation fails.
+func (repo *microsoftRepository) SaveSubscription(
+	subscription schemas.MicrosoftSubscription,
+) error {
+	err := repo.db.Connection.Omit("Area").Create(&subscription).Error
+	if err != nil {
+		return fmt.Errorf("failed to save microsoft subscription: %w", err)
+	}
+	return nil
+}
+
+// UpdateSubscription updates the given subscription in the database.
+//
+// Parameters:
+//   - subscription: The MicrosoftSubscription schema instance to be updated.
+//
+// Returns:
+//   - error: An error if the update operation fails.
+func (repo *microsoftRepository) UpdateSubscription(
+	subscription schemas.MicrosoftSubscription,
+) error {
+	err := repo.db.Connection.Omit("Area").Save(&subscription).Error
+	if err != nil {
+		return fmt.Errorf("failed to update microsoft subscription: %w", err)
+	}
+	return nil
+}
+
+// DeleteSubscription removes the given subscription from the database.
+//
+// Parameters:
+//   - subscription: The MicrosoftSubscription schema instance to be deleted.
+//
+// Returns:
+//   - error: An error if the delete operation fails.
+func (repo *microsoftRepository) DeleteSubscription(
+	subscription schemas.MicrosoftSubscription,
+) error {
+	err := repo.db.Connection.Delete(&subscription).Error
+	if err != nil {
+		return fmt.Errorf("failed to delete microsoft subscription: %w", err)
+	}
+	return nil
+}
+
+// FindSubscriptionByAreaId retrieves the subscription created for the area.
+//
+// Parameters:
+//   - areaId: The ID of the area of the subscription.
+//
+// Returns:
+//   - subscription: The subscription.
+//   - err: schemas.ErrMicrosoftSubscriptionNotFound if the area has no subscription, or an
+//     error if the query fails.
+func (repo *microsoftRepository) FindSubscriptionByAreaId(
+	areaId uint64,
+) (subscription schemas.MicrosoftSubscription, err error) {
+	err = repo.db.Connection.Where("area_id = ?", areaId).First(&subscription).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return subscription, schemas.ErrMicrosoftSubscriptionNotFound
+	}
+	if err != nil {
+		return subscription, fmt.Errorf("failed to find microsoft subscription by area id: %w", err)
+	}
+	return subscription, nil
+}
+
+// FindSubscriptionBySubscriptionId retrieves the subscription with the given ID on Graph.
+//
+// Parameters:
+//   - subscriptionId: The ID of the subscription on Graph, from a notification.
+//
+// Returns:
+//   - subscription: The subscription.
+//   - err: schemas.ErrMicrosoftSubscriptionNotFound if no subscription has this ID, or an
+//     error if the query fails.
+func (repo *microsoftRepository) FindSubscriptionBySubscriptionId(
+	subscriptionId string,
+) (subscription schemas.MicrosoftSubscription, err error) {
+	err = repo.db.Connection.
+		Where("subscription_id = ?", subscriptionId).
+		First(&subscription).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return subscription, schemas.ErrMicrosoftSubscriptionNotFound
+	}
+	if err != nil {
+		return subscription, fmt.Errorf("failed to find microsoft subscription: %w", err)
+	}
+	return subscription, nil
+}
@@ -0,0 +1,63 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/repository"
+	"area/schemas"
+	"area/test"
+)
+
+func TestMicrosoftSubscription_SaveAndFind(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewMicrosoftRepository(db)
+	err = repo.SaveSubscription(schemas.MicrosoftSubscription{
+		AreaId:         1,
+		SubscriptionId: "7f105c7d",
+		Resource:       "me/messages",
+		ClientState:    "secret",
+		ExpiresAt:      time.Now().Add(time.Hour),
+	})
+	assert.NoError(t, err)
+
+	subscription, err := repo.FindSubscriptionByAreaId(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "7f105c7d", subscription.SubscriptionId)
+	assert.Equal(t, "secret", subscription.ClientState)
+
+	subscription, err = repo.FindSubscriptionBySubscriptionId("7f105c7d")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), subscription.AreaId)
+	_, err = repo.FindSubscriptionByAreaId(2)
+	assert.ErrorIs(t, err, schemas.ErrMicrosoftSubscriptionNotFound)
+}
+
+func TestMicrosoftSubscription_UpdateAndDelete(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewMicrosoftRepository(db)
+	err = repo.SaveSubscription(schemas.MicrosoftSubscription{AreaId: 1, SubscriptionId: "old"})
+	assert.NoError(t, err)
+
+	subscription, err := repo.FindSubscriptionByAreaId(1)
+	assert.NoError(t, err)
+	subscription.SubscriptionId = "new"
+	err = repo.UpdateSubscription(subscription)
+	assert.NoError(t, err)
+
+	_, err = repo.FindSubscriptionBySubscriptionId("old")
+	assert.ErrorIs(t, err, schemas.ErrMicrosoftSubscriptionNotFound)
+	subscription, err = repo.FindSubscriptionBySubscriptionId("new")
+	assert.NoError(t, err)
+
+	err = repo.DeleteSubscription(subscription)
+	assert.NoError(t, err)
+	_, err = repo.FindSubscriptionByAreaId(1)
+	assert.ErrorIs(t, err, schemas.ErrMicrosoftSubscriptionNotFound)
+}
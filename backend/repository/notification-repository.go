@@ -0,0 +1,117 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+
+	"area/schemas"
+)
+
+// NotificationRepository defines the interface of the notifications sent between the
+// processes of the backend through the database, with Postgres LISTEN/NOTIFY.
+// The notifications are not stored: a process only receives the ones sent while it listens.
+//
+// Methods:
+//   - Notify(channel string, payload string) error: Sends a notification on a channel.
+//   - Listen(ctx context.Context, channels []string, handle func(channel string, payload string)) error:
+//     Receives the notifications of the channels until ctx is done.
+type NotificationRepository interface {
+	Notify(channel string, payload string) error
+	Listen(
+		ctx context.Context,
+		channels []string,
+		handle func(channel string, payload string),
+	) error
+}
+
+// notificationRepository is a struct that provides access to the notifications of the database.
+// It contains a single field, db, which is a pointer to a Database schema.
+type notificationRepository struct {
+	db *schemas.Database
+}
+
+// NewNotificationRepository creates a new instance of NotificationRepository.
+//
+// Parameters:
+//   - conn: A pointer to a gorm.DB instance representing the database connection.
+//
+// Returns:
+//   - An instance of NotificationRepository.
+func NewNotificationRepository(conn *gorm.DB) NotificationRepository {
+	return &notificationRepository{
+		db: &schemas.Database{
+			Connection: conn,
+		},
+	}
+}
+
+// Notify sends a notification to the processes listening to the channel.
+//
+// Parameters:
+//   - channel: The name of the channel.
+//   - payload: The payload of the notification.
+//
+// Returns:
+//   - error: An error if the notification can not be sent.
+func (repo *notificationRepository) Notify(channel string, payload string) error {
+	result := repo.db.Connection.Exec("SELECT pg_notify(?, ?)", channel, payload)
+	if result.Error != nil {
+		return fmt.Errorf("failed to notify %s: %w", channel, result.Error)
+	}
+	return nil
+}
+
+// Listen takes a connection out of the pool to listen to the channels, and calls handle for
+// every notification received, until ctx is done or the connection fails.
+//
+// Parameters:
+//   - ctx: The context bounding the listening.
+//   - channels: The names of the channels.
+//   - handle: The function called with the channel and the payload of every notification.
+//
+// Returns:
+//   - error: The error that stopped the listening, wrapping the ctx error when ctx is done,
+//     or schemas.ErrNotificationsNotSupported if the database is not Postgres.
+func (repo *notificationRepository) Listen(
+	ctx context.Context,
+	channels []string,
+	handle func(channel string, payload string),
+) error {
+	sqlDB, err := repo.db.Connection.DB()
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get database connection: %w", err)
+	}
+	defer conn.Close()
+
+	err = conn.Raw(func(driverConn any) error {
+		stdlibConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return schemas.ErrNotificationsNotSupported
+		}
+		pgxConn := stdlibConn.Conn()
+		// the connection goes back to the pool once done
+		defer pgxConn.Exec(context.Background(), "UNLISTEN *")
+		for _, channel := range channels {
+			_, err := pgxConn.Exec(ctx, "LISTEN "+pgx.Identifier{channel}.Sanitize())
+			if err != nil {
+				return err
+			}
+		}
+		for {
+			notification, err := pgxConn.WaitForNotification(ctx)
+			if err != nil {
+				return err
+			}
+			handle(notification.Channel, notification.Payload)
+		}
+	})
+	return fmt.Errorf("failed to listen to notifications: %w", err)
+}
@@ -2,6 +2,7 @@ package repository
 
 import (
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
 
@@ -19,6 +20,16 @@ type TokenRepository interface {
 	// Returns an error if the operation fails.
 	Update(token schemas.Token) error
 
+	// UpdateRefreshed persists the rotated access token, refresh token and expiration of a
+	// refreshed token, unless its refresh token changed since refreshToken was read.
+	// Returns whether the token was updated and an error if the operation fails.
+	UpdateRefreshed(token schemas.Token, refreshToken string) (updated bool, err error)
+
+	// MarkNeedsReauth marks a token as needing to be authorized again, unless its refresh
+	// token changed since it was read.
+	// Returns whether the token was marked and an error if the operation fails.
+	MarkNeedsReauth(token schemas.Token) (marked bool, err error)
+
 	// Delete removes a token from the repository.
 	// Returns an error if the operation fails.
 	Delete(token schemas.Token) error
@@ -105,6 +116,60 @@ func (repo *tokenRepository) Update(token schemas.Token) error {
 	return nil
 }
 
+// UpdateRefreshed updates the access token, refresh token and expiration of a refreshed token,
+// and clears its need to be authorized again. The update only applies while the refresh token
+// in the database is still the one exchanged for the new tokens, so that a refresh rotating
+// the token meanwhile, in this process or in another replica, is not overwritten.
+//
+// Parameters:
+//   - token: The token with its new access token, refresh token and expiration.
+//   - refreshToken: The refresh token exchanged for the new tokens.
+//
+// Returns:
+//   - updated: Whether the token was updated, false if it was rotated meanwhile.
+//   - err: An error if the update fails.
+func (repo *tokenRepository) UpdateRefreshed(
+	token schemas.Token,
+	refreshToken string,
+) (updated bool, err error) {
+	result := repo.db.Connection.Model(&schemas.Token{}).
+		Where("id = ? AND refresh_token = ?", token.Id, refreshToken).
+		Updates(map[string]any{
+			"token":         token.Token,
+			"refresh_token": token.RefreshToken,
+			"expire_at":     token.ExpireAt,
+			"needs_reauth":  false,
+			"update_at":     time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to update refreshed token: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
+// MarkNeedsReauth sets the need to be authorized again of a token whose refresh token was
+// rejected, without writing its other columns. The token is left as is if its refresh token
+// changed since it was read, because another refresh rotated it meanwhile.
+//
+// Parameters:
+//   - token: The token whose refresh token was rejected.
+//
+// Returns:
+//   - marked: Whether the token was marked, false if it was rotated meanwhile.
+//   - err: An error if the update fails.
+func (repo *tokenRepository) MarkNeedsReauth(token schemas.Token) (marked bool, err error) {
+	result := repo.db.Connection.Model(&schemas.Token{}).
+		Where("id = ? AND refresh_token = ?", token.Id, token.RefreshToken).
+		Updates(map[string]any{
+			"needs_reauth": true,
+			"update_at":    time.Now(),
+		})
+	if result.Error != nil {
+		return false, fmt.Errorf("failed to mark token needs reauth: %w", result.Error)
+	}
+	return result.RowsAffected > 0, nil
+}
+
 // Delete removes the specified token from the database.
 // It returns an error if the deletion fails.
 //
@@ -0,0 +1,60 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/repository"
+	"area/schemas"
+	"area/test"
+)
+
+func TestUpdateRefreshedKeepsTokenRotatedMeanwhile(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewTokenRepository(db)
+	err = repo.Save(schemas.Token{
+		UserId:       1,
+		ServiceId:    2,
+		Token:        "access token",
+		RefreshToken: "refresh token",
+	})
+	assert.NoError(t, err)
+	token, err := repo.FindByUserIdAndServiceId(1, 2)
+	assert.NoError(t, err)
+
+	rotated := token
+	rotated.Token = "rotated access token"
+	rotated.RefreshToken = "rotated refresh token"
+	rotated.ExpireAt = time.Now().Add(time.Hour)
+	updated, err := repo.UpdateRefreshed(rotated, "refresh token")
+	assert.NoError(t, err)
+	assert.True(t, updated)
+
+	stale := token
+	stale.Token = "stale access token"
+	stale.RefreshToken = "stale refresh token"
+	updated, err = repo.UpdateRefreshed(stale, "refresh token")
+	assert.NoError(t, err)
+	assert.False(t, updated)
+	marked, err := repo.MarkNeedsReauth(token)
+	assert.NoError(t, err)
+	assert.False(t, marked)
+
+	stored, err := repo.FindById(token.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "rotated access token", stored.Token)
+	assert.Equal(t, "rotated refresh token", stored.RefreshToken)
+	assert.False(t, stored.NeedsReauth)
+
+	marked, err = repo.MarkNeedsReauth(stored)
+	assert.NoError(t, err)
+	assert.True(t, marked)
+	stored, err = repo.FindById(token.Id)
+	assert.NoError(t, err)
+	assert.Equal(t, "rotated access token", stored.Token)
+	assert.True(t, stored.NeedsReauth)
+}
@@ -0,0 +1,270 @@
+package repository
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"area/schemas"
+)
+
+// TriggerQueueRepository defines the interface of the persistent queue of the triggers waiting
+// for the reaction workers. It provides methods to queue, claim, update and acknowledge them.
+// The leases of the triggers are timed by the clock of the database, and the updates of a
+// claimed trigger only apply to the delivery holding its lease.
+//
+// Methods:
+//   - Enqueue(trigger schemas.QueuedTrigger) (triggerId uint64, err error): Queues a new trigger and returns its ID.
+//   - Claim(lease time.Duration) (trigger schemas.QueuedTrigger, claimed bool, err error): Claims the oldest available trigger.
+//   - Progress(trigger schemas.QueuedTrigger, workflowRunId uint64, position int, lease time.Duration) error: Saves the progress of a claimed trigger.
+//   - Renew(trigger schemas.QueuedTrigger, lease time.Duration) error: Extends the lease of a claimed trigger.
+//   - Release(trigger schemas.QueuedTrigger, delay time.Duration) error: Makes a claimed trigger available again after delay.
+//   - Ack(trigger schemas.QueuedTrigger) error: Removes a claimed trigger whose workflow run is over.
+//   - FindByAreaId(areaId uint64) (triggers []schemas.QueuedTrigger, err error): Retrieves the queued triggers of an area.
+type TriggerQueueRepository interface {
+	Enqueue(trigger schemas.QueuedTrigger) (triggerId uint64, err error)
+	Claim(lease time.Duration) (trigger schemas.QueuedTrigger, claimed bool, err error)
+	Progress(
+		trigger schemas.QueuedTrigger,
+		workflowRunId uint64,
+		position int,
+		lease time.Duration,
+	) error
+	Renew(trigger schemas.QueuedTrigger, lease time.Duration) error
+	Release(trigger schemas.QueuedTrigger, delay time.Duration) error
+	Ack(trigger schemas.QueuedTrigger) error
+	FindByAreaId(areaId uint64) (triggers []schemas.QueuedTrigger, err error)
+}
+
+// triggerQueueRepository is a struct that provides access to the database for the trigger queue.
+// It contains a single field, db, which is a pointer to a Database schema.
+type triggerQueueRepository struct {
+	db *schemas.Database
+}
+
+// NewTriggerQueueRepository creates a new instance of TriggerQueueRepository.
+// It performs an automatic migration for the QueuedTrigger schema using the provided gorm.DB connection.
+// If the migration fails, it panics with an error message.
+//
+// Parameters:
+//   - conn: A pointer to a gorm.DB instance representing the database connection.
+//
+// Returns:
+//   - An instance of TriggerQueueRepository.
+func NewTriggerQueueRepository(conn *gorm.DB) TriggerQueueRepository {
+	err := conn.AutoMigrate(&schemas.QueuedTrigger{})
+	if err != nil {
+		panic("failed to migrate database")
+	}
+	return &triggerQueueRepository{
+		db: &schemas.Database{
+			Connection: conn,
+		},
+	}
+}
+
+// Enqueue stores the given trigger in the queue, available right away.
+//
+// Parameters:
+//   - trigger: The QueuedTrigger schema instance to be queued.
+//
+// Returns:
+//   - triggerId: The ID of the queued trigger.
+//   - err: An error if the save operation fails.
+func (repo *triggerQueueRepository) Enqueue(
+	trigger schemas.QueuedTrigger,
+) (triggerId uint64, err error) {
+	trigger.LockedUntil = time.Time{}
+	result := repo.db.Connection.Omit("Area").Create(&trigger)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to enqueue trigger: %w", result.Error)
+	}
+	return trigger.Id, nil
+}
+
+// Claim locks the oldest available trigger of the queue for lease and counts its delivery.
+// The trigger is selected with SELECT ... FOR UPDATE SKIP LOCKED, so that concurrent workers,
+// of this process or of other replicas, never claim the same trigger, and do not wait for
+// each other. The lease ends at the time of the database shifted by lease.
+//
+// Parameters:
+//   - lease: The time the trigger stays hidden from the other workers.
+//
+// Returns:
+//   - trigger: The claimed trigger, whose deliveries identify the claim.
+//   - claimed: Whether a trigger was available.
+//   - err: An error if the claim fails.
+func (repo *triggerQueueRepository) Claim(
+	lease time.Duration,
+) (trigger schemas.QueuedTrigger, claimed bool, err error) {
+	err = repo.db.Connection.Transaction(func(tx *gorm.DB) error {
+		triggers := []schemas.QueuedTrigger{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("locked_until < ?", databaseTime(tx, 0)).
+			Order("id").
+			Limit(1).
+			Find(&triggers).
+			Error
+		if err != nil {
+			return err
+		}
+		if len(triggers) == 0 {
+			return nil
+		}
+		err = tx.Model(&schemas.QueuedTrigger{}).
+			Where("id = ?", triggers[0].Id).
+			Updates(map[string]any{
+				"locked_until": databaseTime(tx, lease),
+				"deliveries":   triggers[0].Deliveries + 1,
+				"update_at":    gorm.Expr("CURRENT_TIMESTAMP"),
+			}).
+			Error
+		if err != nil {
+			return err
+		}
+		claimed = true
+		return tx.First(&trigger, triggers[0].Id).Error
+	})
+	if err != nil {
+		return schemas.QueuedTrigger{}, false, fmt.Errorf("failed to claim trigger: %w", err)
+	}
+	return trigger, claimed, nil
+}
+
+// claimQuery returns the query matching the trigger only while it is held by the given claim,
+// the deliveries of the trigger counting the claims.
+func (repo *triggerQueueRepository) claimQuery(trigger schemas.QueuedTrigger) *gorm.DB {
+	return repo.db.Connection.Model(&schemas.QueuedTrigger{}).
+		Where("id = ? AND deliveries = ?", trigger.Id, trigger.Deliveries)
+}
+
+// Progress saves the workflow run of a claimed trigger and the position of its first reaction
+// that did not complete, and renews the lease of the trigger.
+//
+// Parameters:
+//   - trigger: The trigger, as returned by its claim.
+//   - workflowRunId: The ID of the workflow run of the trigger.
+//   - position: The position of the first reaction that did not complete.
+//   - lease: The time the trigger stays hidden from the other workers from now on.
+//
+// Returns:
+//   - error: schemas.ErrTriggerLeaseLost if the trigger was claimed again or acknowledged
+//     since, or an error if the update operation fails.
+func (repo *triggerQueueRepository) Progress(
+	trigger schemas.QueuedTrigger,
+	workflowRunId uint64,
+	position int,
+	lease time.Duration,
+) error {
+	result := repo.claimQuery(trigger).Updates(map[string]any{
+		"workflow_run_id":   workflowRunId,
+		"reaction_position": position,
+		"locked_until":      databaseTime(repo.db.Connection, lease),
+		"update_at":         gorm.Expr("CURRENT_TIMESTAMP"),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to save trigger progress: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return schemas.ErrTriggerLeaseLost
+	}
+	return nil
+}
+
+// Renew extends the lease of a claimed trigger whose workflow run is still running.
+//
+// Parameters:
+//   - trigger: The trigger, as returned by its claim.
+//   - lease: The time the trigger stays hidden from the other workers from now on.
+//
+// Returns:
+//   - error: schemas.ErrTriggerLeaseLost if the trigger was claimed again or acknowledged
+//     since, or an error if the update operation fails.
+func (repo *triggerQueueRepository) Renew(
+	trigger schemas.QueuedTrigger,
+	lease time.Duration,
+) error {
+	result := repo.claimQuery(trigger).Updates(map[string]any{
+		"locked_until": databaseTime(repo.db.Connection, lease),
+		"update_at":    gorm.Expr("CURRENT_TIMESTAMP"),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to renew trigger lease: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return schemas.ErrTriggerLeaseLost
+	}
+	return nil
+}
+
+// Release ends the lease of a claimed trigger, so that it is delivered again after delay.
+//
+// Parameters:
+//   - trigger: The trigger, as returned by its claim.
+//   - delay: The time before the trigger is available again, zero for right away.
+//
+// Returns:
+//   - error: schemas.ErrTriggerLeaseLost if the trigger was claimed again or acknowledged
+//     since, or an error if the update operation fails.
+func (repo *triggerQueueRepository) Release(
+	trigger schemas.QueuedTrigger,
+	delay time.Duration,
+) error {
+	var lockedUntil any = time.Time{}
+	if delay > 0 {
+		lockedUntil = databaseTime(repo.db.Connection, delay)
+	}
+	result := repo.claimQuery(trigger).Updates(map[string]any{
+		"locked_until": lockedUntil,
+		"update_at":    gorm.Expr("CURRENT_TIMESTAMP"),
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to release trigger: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return schemas.ErrTriggerLeaseLost
+	}
+	return nil
+}
+
+// Ack removes a claimed trigger from the queue once its workflow run is over.
+// A trigger claimed again by another delivery since, or already acknowledged, is left as is.
+//
+// Parameters:
+//   - trigger: The trigger, as returned by its claim.
+//
+// Returns:
+//   - error: schemas.ErrTriggerLeaseLost if the trigger was claimed again or acknowledged
+//     since, or an error if the delete operation fails.
+func (repo *triggerQueueRepository) Ack(trigger schemas.QueuedTrigger) error {
+	result := repo.db.Connection.
+		Where("id = ? AND deliveries = ?", trigger.Id, trigger.Deliveries).
+		Delete(&schemas.QueuedTrigger{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to acknowledge trigger: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return schemas.ErrTriggerLeaseLost
+	}
+	return nil
+}
+
+// FindByAreaId retrieves the queued triggers of the given area, oldest first.
+//
+// Parameters:
+//   - areaId: The ID of the area to filter the queued triggers.
+//
+// Returns:
+//   - triggers: The queued triggers of the area.
+//   - err: An error if the query fails.
+func (repo *triggerQueueRepository) FindByAreaId(
+	areaId uint64,
+) (triggers []schemas.QueuedTrigger, err error) {
+	result := repo.db.Connection.Where("area_id = ?", areaId).Order("id").Find(&triggers)
+	if result.Error != nil {
+		return triggers, fmt.Errorf("failed to find queued triggers by area id: %w", result.Error)
+	}
+	return triggers, nil
+}
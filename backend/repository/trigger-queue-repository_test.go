@@ -0,0 +1,131 @@
+package repository_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/repository"
+	"area/schemas"
+	"area/test"
+)
+
+func TestTriggerQueue_ClaimOldestAvailableTrigger(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewTriggerQueueRepository(db)
+	_, err = repo.Enqueue(schemas.QueuedTrigger{AreaId: 1, Message: "first"})
+	assert.NoError(t, err)
+	_, err = repo.Enqueue(schemas.QueuedTrigger{AreaId: 2, Message: "second"})
+	assert.NoError(t, err)
+
+	trigger, claimed, err := repo.Claim(time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, "first", trigger.Message)
+	assert.Equal(t, uint(1), trigger.Deliveries)
+
+	trigger, claimed, err = repo.Claim(time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, "second", trigger.Message)
+
+	_, claimed, err = repo.Claim(time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+}
+
+func TestTriggerQueue_ReleaseAndProgress(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewTriggerQueueRepository(db)
+	triggerId, err := repo.Enqueue(schemas.QueuedTrigger{AreaId: 1, Message: "first"})
+	assert.NoError(t, err)
+
+	trigger, claimed, err := repo.Claim(time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.True(t, trigger.LockedUntil.After(time.Now()))
+	err = repo.Progress(trigger, 7, 2, time.Minute)
+	assert.NoError(t, err)
+	err = repo.Release(trigger, 0)
+	assert.NoError(t, err)
+
+	trigger, claimed, err = repo.Claim(time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, triggerId, trigger.Id)
+	assert.Equal(t, uint64(7), trigger.WorkflowRunId)
+	assert.Equal(t, 2, trigger.ReactionPosition)
+	assert.Equal(t, uint(2), trigger.Deliveries)
+
+	err = repo.Release(trigger, time.Minute)
+	assert.NoError(t, err)
+	_, claimed, err = repo.Claim(time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+}
+
+func TestTriggerQueue_RenewExtendsLease(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewTriggerQueueRepository(db)
+	_, err = repo.Enqueue(schemas.QueuedTrigger{AreaId: 1, Message: "first"})
+	assert.NoError(t, err)
+
+	trigger, claimed, err := repo.Claim(-time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	err = repo.Renew(trigger, time.Minute)
+	assert.NoError(t, err)
+
+	_, claimed, err = repo.Claim(time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+}
+
+func TestTriggerQueue_UpdatesFencedByClaim(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewTriggerQueueRepository(db)
+	_, err = repo.Enqueue(schemas.QueuedTrigger{AreaId: 1, Message: "first"})
+	assert.NoError(t, err)
+
+	expired, claimed, err := repo.Claim(-time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	trigger, claimed, err := repo.Claim(time.Minute)
+	assert.NoError(t, err)
+	assert.True(t, claimed)
+	assert.Equal(t, expired.Id, trigger.Id)
+
+	err = repo.Renew(expired, time.Minute)
+	assert.ErrorIs(t, err, schemas.ErrTriggerLeaseLost)
+	err = repo.Progress(expired, 7, 2, time.Minute)
+	assert.ErrorIs(t, err, schemas.ErrTriggerLeaseLost)
+	err = repo.Release(expired, 0)
+	assert.ErrorIs(t, err, schemas.ErrTriggerLeaseLost)
+	err = repo.Ack(expired)
+	assert.ErrorIs(t, err, schemas.ErrTriggerLeaseLost)
+
+	triggers, err := repo.FindByAreaId(1)
+	assert.NoError(t, err)
+	assert.Len(t, triggers, 1)
+	assert.Equal(t, 0, triggers[0].ReactionPosition)
+	_, claimed, err = repo.Claim(time.Minute)
+	assert.NoError(t, err)
+	assert.False(t, claimed)
+
+	err = repo.Ack(trigger)
+	assert.NoError(t, err)
+	err = repo.Ack(trigger)
+	assert.ErrorIs(t, err, schemas.ErrTriggerLeaseLost)
+	triggers, err = repo.FindByAreaId(1)
+	assert.NoError(t, err)
+	assert.Empty(t, triggers)
+}
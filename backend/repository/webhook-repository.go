@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"area/schemas"
+)
+
+// WebhookRepository defines the interface for interacting with the Webhook data, the URLs of
+// the areas triggered by inbound webhooks.
+//
+// Methods:
+//   - SaveEndpoint(endpoint schemas.WebhookEndpoint) error: Persists a new endpoint.
+//   - FindEndpointByAreaId(areaId uint64) (endpoint schemas.WebhookEndpoint, err error):
+//     Retrieves the endpoint of an area.
+//   - FindEndpointByToken(token string) (endpoint schemas.WebhookEndpoint, err error):
+//     Retrieves an endpoint by the token of its URL.
+type WebhookRepository interface {
+	SaveEndpoint(endpoint schemas.WebhookEndpoint) error
+	FindEndpointByAreaId(areaId uint64) (endpoint schemas.WebhookEndpoint, err error)
+	FindEndpointByToken(token string) (endpoint schemas.WebhookEndpoint, err error)
+}
+
+// Define a struct that embeds `*schemas.Database` and implements `WebhookRepository`.
+type webhookRepository struct {
+	db *schemas.Database
+}
+
+// NewWebhookRepository creates a new instance of WebhookRepository with the provided database connection.
+// It initializes the webhookRepository struct with a schemas.Database that holds the given gorm.DB connection,
+// and performs an automatic migration for the WebhookEndpoint schema. If the migration fails, it panics.
+//
+// Parameters:
+//   - conn: A pointer to a gorm.DB instance representing the database connection.
+//
+// Returns:
+//   - WebhookRepository: A new instance of WebhookRepository.
+func NewWebhookRepository(conn *gorm.DB) WebhookRepository {
+	err := conn.AutoMigrate(&schemas.WebhookEndpoint{})
+	if err != nil {
+		panic("failed to migrate database")
+	}
+	return &webhookRepository{
+		db: &schemas.Database{
+			Connection: conn,
+		},
+	}
+}
+
+// SaveEndpoint stores the given endpoint in the database.
+//
+// Parameters:
+//   - endpoint: The WebhookEndpoint schema instance to be saved.
+//
+// Returns:
+//   - error: An error if the save operation fails.
+func (repo *webhookRepository) SaveEndpoint(endpoint schemas.WebhookEndpoint) error {
+	err := repo.db.Connection.Omit("Area").Create(&endpoint).Error
+	if err != nil {
+		return fmt.Errorf("failed to save webhook endpoint: %w", err)
+	}
+	return nil
+}
+
+// FindEndpointByAreaId retrieves the endpoint of the area.
+//
+// Parameters:
+//   - areaId: The ID of the area of the endpoint.
+//
+// Returns:
+//   - endpoint: The endpoint.
+//   - err: schemas.ErrWebhookEndpointNotFound if the area has no endpoint, or an error if the
+//     query fails.
+func (repo *webhookRepository) FindEndpointByAreaId(
+	areaId uint64,
+) (endpoint schemas.WebhookEndpoint, err error) {
+	err = repo.db.Connection.Where("area_id = ?", areaId).First(&endpoint).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return endpoint, schemas.ErrWebhookEndpointNotFound
+	}
+	if err != nil {
+		return endpoint, fmt.Errorf("failed to find webhook endpoint by area id: %w", err)
+	}
+	return endpoint, nil
+}
+
+// FindEndpointByToken retrieves the endpoint whose URL ends with the token.
+//
+// Parameters:
+//   - token: The token of the endpoint, from the URL of a delivery.
+//
+// Returns:
+//   - endpoint: The endpoint.
+//   - err: schemas.ErrWebhookEndpointNotFound if no endpoint has this token, or an error if
+//     the query fails.
+func (repo *webhookRepository) FindEndpointByToken(
+	token string,
+) (endpoint schemas.WebhookEndpoint, err error) {
+	err = repo.db.Connection.Where("token = ?", token).First(&endpoint).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return endpoint, schemas.ErrWebhookEndpointNotFound
+	}
+	if err != nil {
+		return endpoint, fmt.Errorf("failed to find webhook endpoint by token: %w", err)
+	}
+	return endpoint, nil
+}
@@ -0,0 +1,37 @@
+package repository_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/repository"
+	"area/schemas"
+	"area/test"
+)
+
+func TestWebhookEndpoint_SaveAndFind(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewWebhookRepository(db)
+	err = repo.SaveEndpoint(schemas.WebhookEndpoint{AreaId: 1, Token: "token", Secret: "secret"})
+	assert.NoError(t, err)
+
+	endpoint, err := repo.FindEndpointByAreaId(1)
+	assert.NoError(t, err)
+	assert.Equal(t, "token", endpoint.Token)
+	assert.Equal(t, "secret", endpoint.Secret)
+
+	endpoint, err = repo.FindEndpointByToken("token")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(1), endpoint.AreaId)
+	_, err = repo.FindEndpointByToken("other")
+	assert.ErrorIs(t, err, schemas.ErrWebhookEndpointNotFound)
+	_, err = repo.FindEndpointByAreaId(2)
+	assert.ErrorIs(t, err, schemas.ErrWebhookEndpointNotFound)
+
+	// an area has one endpoint
+	err = repo.SaveEndpoint(schemas.WebhookEndpoint{AreaId: 1, Token: "again"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,150 @@
+package repository
+
+import (
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+
+	"area/schemas"
+)
+
+// WorkflowRunRepository defines the interface for interacting with workflow run data.
+// It provides methods to save, update, and retrieve the runs of the workflows of the areas.
+//
+// Methods:
+//   - Save(run schemas.WorkflowRun) (runId uint64, err error): Persists a new workflow run and returns its ID.
+//   - Update(run schemas.WorkflowRun) error: Updates the status and the steps of a workflow run.
+//   - FindById(id uint64) (run schemas.WorkflowRun, err error): Retrieves a workflow run by its ID.
+//   - FindByAreaIdAndFilter(areaId uint64, filter schemas.WorkflowRunFilter): Retrieves a filtered
+//     page of the workflow runs of an area and the number of runs matching the filter.
+type WorkflowRunRepository interface {
+	Save(run schemas.WorkflowRun) (runId uint64, err error)
+	Update(run schemas.WorkflowRun) error
+	FindById(id uint64) (run schemas.WorkflowRun, err error)
+	FindByAreaIdAndFilter(
+		areaId uint64,
+		filter schemas.WorkflowRunFilter,
+	) (runs []schemas.WorkflowRun, total int64, err error)
+}
+
+// workflowRunRepository is a struct that provides access to the database for workflow runs.
+// It contains a single field, db, which is a pointer to a Database schema.
+type workflowRunRepository struct {
+	db *schemas.Database
+}
+
+// NewWorkflowRunRepository creates a new instance of WorkflowRunRepository.
+// It performs an automatic migration for the WorkflowRun schema using the provided gorm.DB connection.
+// If the migration fails, it panics with an error message.
+//
+// Parameters:
+//   - conn: A pointer to a gorm.DB instance representing the database connection.
+//
+// Returns:
+//   - An instance of WorkflowRunRepository.
+func NewWorkflowRunRepository(conn *gorm.DB) WorkflowRunRepository {
+	err := conn.AutoMigrate(&schemas.WorkflowRun{})
+	if err != nil {
+		panic("failed to migrate database")
+	}
+	return &workflowRunRepository{
+		db: &schemas.Database{
+			Connection: conn,
+		},
+	}
+}
+
+// Save stores the given workflow run in the database and returns its ID.
+//
+// Parameters:
+//   - run: The WorkflowRun schema instance to be saved.
+//
+// Returns:
+//   - runId: The ID of the saved workflow run.
+//   - err: An error if the save operation fails.
+func (repo *workflowRunRepository) Save(run schemas.WorkflowRun) (runId uint64, err error) {
+	result := repo.db.Connection.Omit("Area").Create(&run)
+	if result.Error != nil {
+		return 0, fmt.Errorf("failed to save workflow run: %w", result.Error)
+	}
+	return run.Id, nil
+}
+
+// Update stores the status, the steps and the end time of the given workflow run.
+//
+// Parameters:
+//   - run: The WorkflowRun schema instance holding the new state of the run.
+//
+// Returns:
+//   - error: An error if the update operation fails.
+func (repo *workflowRunRepository) Update(run schemas.WorkflowRun) error {
+	result := repo.db.Connection.Model(&schemas.WorkflowRun{}).
+		Where("id = ?", run.Id).
+		Updates(map[string]any{
+			"status":      run.Status,
+			"steps":       run.Steps,
+			"finished_at": run.FinishedAt,
+			"update_at":   gorm.Expr("CURRENT_TIMESTAMP"),
+		})
+	if result.Error != nil {
+		return fmt.Errorf("failed to update workflow run: %w", result.Error)
+	}
+	return nil
+}
+
+// FindById retrieves the workflow run with the given ID.
+//
+// Parameters:
+//   - id: The ID of the workflow run.
+//
+// Returns:
+//   - run: The workflow run.
+//   - err: schemas.ErrWorkflowRunNotFound if no run has this ID, or an error if the query fails.
+func (repo *workflowRunRepository) FindById(id uint64) (run schemas.WorkflowRun, err error) {
+	err = repo.db.Connection.Where("id = ?", id).First(&run).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return run, schemas.ErrWorkflowRunNotFound
+	}
+	if err != nil {
+		return run, fmt.Errorf("failed to find workflow run by id: %w", err)
+	}
+	return run, nil
+}
+
+// FindByAreaIdAndFilter retrieves the workflow runs of the given area matching the filter,
+// newest first. Only the requested page is returned, along with the number of runs
+// matching the filter on all pages.
+//
+// Parameters:
+//   - areaId: The ID of the area to filter the WorkflowRun records.
+//   - filter: The status and page of the runs.
+//
+// Returns:
+//   - runs: The workflow runs of the requested page.
+//   - total: The number of workflow runs matching the filter.
+//   - err: An error if the query fails.
+func (repo *workflowRunRepository) FindByAreaIdAndFilter(
+	areaId uint64,
+	filter schemas.WorkflowRunFilter,
+) (runs []schemas.WorkflowRun, total int64, err error) {
+	query := repo.db.Connection.Model(&schemas.WorkflowRun{}).Where("area_id = ?", areaId)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+
+	err = query.Count(&total).Error
+	if err != nil {
+		return runs, 0, fmt.Errorf("failed to count workflow runs: %w", err)
+	}
+
+	err = query.Order("created_at desc, id desc").
+		Offset((filter.Page - 1) * filter.PageSize).
+		Limit(filter.PageSize).
+		Find(&runs).
+		Error
+	if err != nil {
+		return runs, 0, fmt.Errorf("failed to find workflow runs by area id: %w", err)
+	}
+	return runs, total, nil
+}
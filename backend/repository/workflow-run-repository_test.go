@@ -0,0 +1,86 @@
+package repository_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/repository"
+	"area/schemas"
+	"area/test"
+)
+
+func TestWorkflowRun_SaveUpdateAndFindById(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewWorkflowRunRepository(db)
+	runId, err := repo.Save(schemas.WorkflowRun{
+		AreaId:         1,
+		TriggerMessage: "triggered",
+		Status:         schemas.WorkflowRunRunning,
+		Steps: schemas.WorkflowSteps{
+			{Position: 0, ReactionName: "first", Status: schemas.WorkflowStepPending},
+			{Position: 1, ReactionName: "second", Status: schemas.WorkflowStepPending},
+		},
+	})
+	assert.NoError(t, err)
+	assert.NotZero(t, runId)
+
+	run, err := repo.FindById(runId)
+	assert.NoError(t, err)
+	run.Status = schemas.WorkflowRunSuccess
+	run.Steps[0].Status = schemas.WorkflowStepSuccess
+	run.Steps[0].Output = json.RawMessage(`{"message":"done"}`)
+	run.Steps[1].Status = schemas.WorkflowStepSkipped
+	run.FinishedAt = time.Now()
+	err = repo.Update(run)
+	assert.NoError(t, err)
+
+	run, err = repo.FindById(runId)
+	assert.NoError(t, err)
+	assert.Equal(t, schemas.WorkflowRunSuccess, run.Status)
+	assert.Equal(t, "triggered", run.TriggerMessage)
+	assert.Len(t, run.Steps, 2)
+	assert.Equal(t, schemas.WorkflowStepSuccess, run.Steps[0].Status)
+	assert.JSONEq(t, `{"message":"done"}`, string(run.Steps[0].Output))
+	assert.Equal(t, schemas.WorkflowStepSkipped, run.Steps[1].Status)
+	assert.False(t, run.FinishedAt.IsZero())
+}
+
+func TestWorkflowRun_FindByIdNotFound(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewWorkflowRunRepository(db)
+	_, err = repo.FindById(42)
+	assert.ErrorIs(t, err, schemas.ErrWorkflowRunNotFound)
+}
+
+func TestWorkflowRun_FindByAreaIdAndFilter(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+
+	repo := repository.NewWorkflowRunRepository(db)
+	for _, run := range []schemas.WorkflowRun{
+		{AreaId: 1, TriggerMessage: "first", Status: schemas.WorkflowRunSuccess},
+		{AreaId: 1, TriggerMessage: "second", Status: schemas.WorkflowRunFailure},
+		{AreaId: 1, TriggerMessage: "third", Status: schemas.WorkflowRunSuccess},
+		{AreaId: 2, TriggerMessage: "other", Status: schemas.WorkflowRunSuccess},
+	} {
+		_, err = repo.Save(run)
+		assert.NoError(t, err)
+	}
+
+	runs, total, err := repo.FindByAreaIdAndFilter(1, schemas.WorkflowRunFilter{
+		Status:   schemas.WorkflowRunSuccess,
+		Page:     1,
+		PageSize: 1,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	assert.Len(t, runs, 1)
+	assert.Equal(t, "third", runs[0].TriggerMessage)
+}
@@ -13,6 +13,22 @@ type ActionJSON struct {
 	Description string `json:"description"` // The description of the action
 }
 
+// ActionResult represents a trigger reported by an action when it detects a new event.
+// An action check can report zero, one or several triggers.
+// The payload is a JSON object describing the event, whose fields can be used in the
+// reaction options with {{action.field}} placeholders.
+type ActionResult struct {
+	Message string          `json:"message"` // Description of the event that triggered the action
+	Payload json.RawMessage `json:"payload"` // Structured description of the event
+}
+
+// AreaTrigger is a trigger of the action of an area pushed by the provider of the action,
+// such as a webhook delivery, rather than reported by a check of the area.
+type AreaTrigger struct {
+	Area    Area         // Area whose action is triggered
+	Trigger ActionResult // Trigger of the action
+}
+
 // Action represents an action entity with various attributes such as ID, name, description, service, options, and timestamps.
 // Fields:
 // - Id: The unique identifier for the action.
@@ -21,6 +37,7 @@ type ActionJSON struct {
 // - ServiceId: The foreign key for the associated service.
 // - Service: The service that the action belongs to. This field is required.
 // - Option: The option of the action, stored as a JSONB type. This field is required.
+// - OptionSchema: The JSON Schema of the option of the action, stored as a JSONB type.
 // - CreatedAt: The timestamp when the action was created. Defaults to the current timestamp.
 // - UpdateAt: The timestamp when the action was last updated. Defaults to the current timestamp.
 // - MinimumRefreshRate: The minimum refresh rate for the action. This field is required.
@@ -31,6 +48,7 @@ type Action struct {
 	ServiceId          uint64          `                                          json:"-"`                                       // Foreign key for Service
 	Service            Service         `gorm:"foreignKey:ServiceId;references:Id" json:"service,omitempty"    binding:"required"` // The service that the action belongs to
 	Option             json.RawMessage `gorm:"type:jsonb"                         json:"option"               binding:"required"` // The option of the action
+	OptionSchema       json.RawMessage `gorm:"type:jsonb"                         json:"option_schema"`                           // The JSON Schema of the option of the action
 	CreatedAt          time.Time       `gorm:"default:CURRENT_TIMESTAMP"          json:"createdAt"`                               // The timestamp when the action was created
 	UpdateAt           time.Time       `gorm:"default:CURRENT_TIMESTAMP"          json:"update_at"`                               // The timestamp when the action was last updated
 	MinimumRefreshRate uint64          `                                          json:"minimum_refresh_rate" binding:"required"` // The minimum refresh rate for the action
@@ -15,23 +15,60 @@ type AboutJSON struct {
 	} `json:"server"` // Server information
 }
 
+// RunMode is the part of the backend run by the process.
+type RunMode string
+
+const (
+	RunModeApi    RunMode = "api"    // Serves the REST API, the area changes are applied by the workers
+	RunModeWorker RunMode = "worker" // Checks the areas and runs their reactions, without the REST API
+	RunModeAll    RunMode = "all"    // Serves the REST API and checks the areas
+)
+
 const (
 	EmailMinimumLength    = 4         // Minimum length of an email address
 	UsernameMinimumLength = 4         // Minimum length of a username
 	PasswordMinimumLength = 8         // Minimum length of a password
 	BearerTokenDuration   = 72        // Duration of the bearer token in hours
 	CSRFTokenLength       = 16        // Length of the CSRF token
+	WebhookSecretLength   = 32        // Length in bytes of the secret signing the deliveries of a webhook
+	WebhookMaxBodySize    = 1 << 20   // Largest body in bytes accepted from a webhook delivery
 	BearerTokenType       = "Bearer " // Bearer token type
+	AreaSchedulerWorkers  = 10        // Number of areas checked at the same time by the scheduler
+	TriggerQueueLease     = 600       // Seconds a claimed trigger is hidden from the other reaction workers
+	TriggerQueueRenew     = 60        // Seconds between two renewals of the lease of a trigger being delivered
+	TriggerQueuePoll      = 5         // Seconds between two claims of an idle reaction worker
+	TriggerQueueRetry     = 30        // Seconds before a trigger whose area can not run is delivered again
+	AreaLeaseDuration     = 30        // Seconds an area stays owned by a scheduler node that stopped renewing its lease
+	AreaLeaseRenew        = 10        // Seconds between two heartbeats and lease renewals of a scheduler node
+	SchedulerNodeTimeout  = 30        // Seconds without heartbeat after which a scheduler node is considered dead
+	NotificationRetry     = 5         // Seconds before listening to the database notifications again after a failure
+	ProviderTimeout       = 30        // Seconds allowed to a request to a provider, the response body included
+	ProviderMaxWait       = 10        // Longest wait in seconds for the end of a rate limit before sending a request
+	ProviderMaxRetries    = 2         // Times a request rate limited by a provider is sent again
+	ProviderFailures      = 5         // Consecutive failures of a provider that open its circuit
+	ProviderCooldown      = 30        // Seconds a provider circuit stays open before a probe request
+	PollCacheRetention    = 3600      // Seconds a poll fetch is kept in the poll cache
+	ShutdownTimeout       = 30        // Drain window in seconds given to the requests and area checks on shutdown
+	ReadHeaderTimeout     = 10        // Time in seconds allowed to read the headers of a request
+	TokenRefreshMargin    = 5         // Minutes before its expiration when a service token is refreshed
+	AreaResultPageSize    = 20        // Default number of area results per page
+	AreaResultMaxPageSize = 100       // Maximum number of area results per page
 )
 
 // Errors Messages.
 var (
+	ErrInvalidRunMode = errors.New(
+		"mode must be one of api, worker, all",
+	) // Error message for an unknown run mode
 	ErrBackendPortNotSet = errors.New(
 		"BACKEND_PORT is not set",
 	) // Error message for missing BACKEND_PORT environment variable
 	ErrFrontendPortNotSet = errors.New(
 		"FRONTEND_PORT is not set",
 	) // Error message for missing FRONTEND_PORT environment variable
+	ErrBackendExternalHostNotSet = errors.New(
+		"BACKEND_EXTERNAL_HOST is not set",
+	) // Error message for missing BACKEND_EXTERNAL_HOST environment variable
 	ErrFrontendExternalHostNotSet = errors.New(
 		"FRONTEND_EXTERNAL_HOST is not set",
 	) // Error message for missing FRONTEND_EXTERNAL_HOST environment variable
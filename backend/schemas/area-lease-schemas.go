@@ -0,0 +1,78 @@
+package schemas
+
+import (
+	"errors"
+	"time"
+)
+
+// SchedulerNode is a replica of the backend running the area scheduler.
+// A node records a heartbeat every AreaLeaseRenew seconds, and is considered dead once its
+// last heartbeat is older than SchedulerNodeTimeout seconds. The areas are spread over the
+// live nodes, so that a new node takes its share of the areas and the areas of a dead node
+// are taken over by the others.
+//
+// Fields:
+// - Id: Unique identifier for the node, generated when the node starts.
+// - Hostname: The hostname of the machine or container running the node.
+// - StartedAt: Timestamp for when the node started.
+// - HeartbeatAt: Timestamp for the last heartbeat of the node.
+// - CreatedAt: Timestamp for when the node was created, with a default value of the current timestamp.
+// - UpdateAt: Timestamp for when the node was last updated, with a default value of the current timestamp.
+type SchedulerNode struct {
+	Id          string    `gorm:"primaryKey;type:varchar(100)" json:"id"`           // Unique identifier for the node
+	Hostname    string    `gorm:"type:varchar(100)"            json:"hostname"`     // Hostname of the node
+	StartedAt   time.Time `                                    json:"started_at"`   // Time when the node started
+	HeartbeatAt time.Time `gorm:"index"                        json:"heartbeat_at"` // Time of the last heartbeat of the node
+	CreatedAt   time.Time `gorm:"default:CURRENT_TIMESTAMP"    json:"created_at"`   // Time when the node was created
+	UpdateAt    time.Time `gorm:"default:CURRENT_TIMESTAMP"    json:"update_at"`    // Time when the node was last updated
+}
+
+// AreaLease is the ownership of an area by a scheduler node.
+// Only the node holding the lease of an area checks it, so that an area is checked by
+// exactly one replica at a time. The owner renews its leases every AreaLeaseRenew seconds,
+// and a lease that was not renewed for AreaLeaseDuration seconds can be taken over.
+//
+// Fields:
+// - AreaId: Foreign key for the associated Area, one lease per area.
+// - Area: The Area that the lease belongs to, with a cascade delete constraint.
+// - NodeId: The ID of the node owning the area.
+// - ExpiresAt: Timestamp for when the lease expires unless it is renewed.
+// - CreatedAt: Timestamp for when the lease was created, with a default value of the current timestamp.
+// - UpdateAt: Timestamp for when the lease was last updated, with a default value of the current timestamp.
+type AreaLease struct {
+	AreaId    uint64    `gorm:"primaryKey;autoIncrement:false"                               json:"area_id"`    // Foreign key for Area
+	Area      Area      `gorm:"foreignKey:AreaId;references:Id;constraint:OnDelete:CASCADE;" json:"-"`          // Area that the lease belongs to
+	NodeId    string    `gorm:"type:varchar(100);index"                                      json:"node_id"`    // Node owning the area
+	ExpiresAt time.Time `                                                                    json:"expires_at"` // Time when the lease expires
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"                                    json:"created_at"` // Time when the lease was created
+	UpdateAt  time.Time `gorm:"default:CURRENT_TIMESTAMP"                                    json:"update_at"`  // Time when the lease was last updated
+}
+
+// SchedulerNodeOwnership is a scheduler node with the areas it owns.
+//
+// Fields:
+// - Node: The scheduler node.
+// - Alive: Whether the node sent a heartbeat in the last SchedulerNodeTimeout seconds.
+// - AreaIds: The IDs of the areas whose lease the node holds.
+type SchedulerNodeOwnership struct {
+	Node    SchedulerNode `json:"node"`     // Scheduler node
+	Alive   bool          `json:"alive"`    // Whether the node is alive
+	AreaIds []uint64      `json:"area_ids"` // Areas owned by the node
+}
+
+// AreaOwnership is the distribution of the enabled areas over the scheduler nodes.
+//
+// Fields:
+// - NodeId: The ID of the node that answered the request.
+// - Nodes: The known scheduler nodes with the areas they own.
+// - UnownedAreaIds: The IDs of the enabled areas that no node owns yet, waiting for a lease.
+type AreaOwnership struct {
+	NodeId         string                   `json:"node_id"`          // Node that answered the request
+	Nodes          []SchedulerNodeOwnership `json:"nodes"`            // Scheduler nodes with their areas
+	UnownedAreaIds []uint64                 `json:"unowned_area_ids"` // Enabled areas without owner
+}
+
+// Errors Messages.
+var ErrAdminTokenNotSet = errors.New(
+	"ADMIN_TOKEN is not set",
+) // Error message for missing ADMIN_TOKEN environment variable
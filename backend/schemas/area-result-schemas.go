@@ -1,25 +1,78 @@
 package schemas
 
 import (
+	"encoding/json"
 	"time"
 )
 
-// AreaResult represents the result of an area with associated metadata.
-// It includes the unique identifier, foreign key to the Area, the result string,
-// and timestamps for creation and last update.
+// AreaResultStatus is the outcome of a run of an area.
+type AreaResultStatus string
+
+const (
+	AreaResultSuccess AreaResultStatus = "success" // The reaction ran successfully.
+	AreaResultFailure AreaResultStatus = "failure" // The action or the reaction failed.
+	AreaResultSkipped AreaResultStatus = "skipped" // The run did not happen, for example because of a missing token.
+)
+
+// AreaResult is the execution record of one run of an area.
+// A run is either one reaction triggered by the action of the area, or a check of the area
+// that stopped before any reaction because the action failed or the run was skipped.
 //
 // Fields:
 // - Id: Unique identifier for the area result.
 // - AreaId: Foreign key for the associated Area.
 // - Area: The Area that the result belongs to, with a required binding and cascade delete constraint.
-// - Result: The result of the area, with a required binding.
+// - Result: A human readable summary of the run, the reaction output or the error message.
+// - Status: The outcome of the run, success, failure or skipped.
+// - ReactionName: The name of the reaction that ran, empty if no reaction ran.
+// - ReactionPosition: The position of the reaction that ran in the reactions of the area.
+// - WorkflowRunId: The ID of the workflow run the reaction ran in, zero if no reaction ran.
+// - TriggerMessage: The description of the trigger reported by the action, empty if the action did not trigger.
+// - TriggerPayload: The structured payload of the trigger reported by the action.
+// - ReactionInput: The reaction option the reaction ran with, with its placeholders rendered.
+// - ReactionOutput: The output of the reaction.
+// - Error: The error message of the run, empty on success.
+// - Attempt: The number of times the reaction was run for the trigger.
+// - Duration: The duration of the run in milliseconds.
+// - StartedAt: Timestamp for when the run started.
+// - FinishedAt: Timestamp for when the run finished.
 // - CreatedAt: Timestamp for when the area result was created, with a default value of the current timestamp.
 // - UpdateAt: Timestamp for when the area result was last updated, with a default value of the current timestamp.
 type AreaResult struct {
-	Id        uint64    `gorm:"primaryKey;autoIncrement"                                     json:"id,omitempty"`                      // Unique identifier for the area result
-	AreaId    uint64    `                                                                    json:"-"`                                 // Foreign key for Area
-	Area      Area      `gorm:"foreignKey:AreaId;references:Id;constraint:OnDelete:CASCADE;" json:"area,omitempty" binding:"required"` // Area that the result belongs to
-	Result    string    `                                                                    json:"result"         binding:"required"` // Result of the area
-	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"                                    json:"created_at"`                        // Time when the area result was created
-	UpdateAt  time.Time `gorm:"default:CURRENT_TIMESTAMP"                                    json:"update_at"`                         // Time when the area result was last updated
+	Id               uint64           `gorm:"primaryKey;autoIncrement"                                     json:"id,omitempty"`                         // Unique identifier for the area result
+	AreaId           uint64           `                                                                    json:"-"`                                    // Foreign key for Area
+	Area             Area             `gorm:"foreignKey:AreaId;references:Id;constraint:OnDelete:CASCADE;" json:"area,omitempty"    binding:"required"` // Area that the result belongs to
+	Result           string           `                                                                    json:"result"            binding:"required"` // Summary of the run
+	Status           AreaResultStatus `gorm:"index"                                                        json:"status"`                               // Outcome of the run
+	ReactionName     string           `                                                                    json:"reaction_name"`                        // Name of the reaction that ran
+	ReactionPosition int              `                                                                    json:"reaction_position"`                    // Position of the reaction in the area
+	WorkflowRunId    uint64           `gorm:"index"                                                        json:"workflow_run_id"`                      // Workflow run the reaction ran in
+	TriggerMessage   string           `                                                                    json:"trigger_message"`                      // Description of the trigger reported by the action
+	TriggerPayload   json.RawMessage  `gorm:"type:jsonb"                                                   json:"trigger_payload"`                      // Payload of the trigger reported by the action
+	ReactionInput    json.RawMessage  `gorm:"type:jsonb"                                                   json:"reaction_input"`                       // Reaction option the reaction ran with
+	ReactionOutput   string           `                                                                    json:"reaction_output"`                      // Output of the reaction
+	Error            string           `                                                                    json:"error"`                                // Error message of the run
+	Attempt          uint             `                                                                    json:"attempt"`                              // Number of times the reaction was run
+	Duration         int64            `                                                                    json:"duration_ms"`                          // Duration of the run in milliseconds
+	StartedAt        time.Time        `                                                                    json:"started_at"`                           // Time when the run started
+	FinishedAt       time.Time        `                                                                    json:"finished_at"`                          // Time when the run finished
+	CreatedAt        time.Time        `gorm:"default:CURRENT_TIMESTAMP;index"                              json:"created_at"`                           // Time when the area result was created
+	UpdateAt         time.Time        `gorm:"default:CURRENT_TIMESTAMP"                                    json:"update_at"`                            // Time when the area result was last updated
+}
+
+// AreaResultFilter is the query of the area results of an area.
+// The results are returned newest first, one page at a time.
+//
+// Fields:
+// - Status: Keeps only the results with this status, all statuses when empty.
+// - From: Keeps only the results created at or after this time, no lower bound when zero.
+// - To: Keeps only the results created before this time, no upper bound when zero.
+// - Page: The page to return, starting at 1.
+// - PageSize: The number of results per page, at most AreaResultMaxPageSize.
+type AreaResultFilter struct {
+	Status   AreaResultStatus `form:"status"    binding:"omitempty,oneof=success failure skipped"` // Status of the results
+	From     time.Time        `form:"from"      time_format:"2006-01-02T15:04:05Z07:00"`           // Lower bound of the creation time
+	To       time.Time        `form:"to"        time_format:"2006-01-02T15:04:05Z07:00"`           // Upper bound of the creation time
+	Page     int              `form:"page"      binding:"omitempty,min=1"`                         // Page to return
+	PageSize int              `form:"page_size" binding:"omitempty,min=1"`                         // Number of results per page
 }
@@ -2,24 +2,83 @@ package schemas
 
 import (
 	"encoding/json"
+	"errors"
 	"time"
 )
 
+// AreaReactionMessage represents one reaction of an area in an area message.
+type AreaReactionMessage struct {
+	ReactionId        uint64          `json:"reaction_id"`         // Foreign key for Reaction
+	ReactionOption    json.RawMessage `json:"reaction_option"`     // The option of the reaction
+	ContinueOnFailure bool            `json:"continue_on_failure"` // Whether the next reactions run when this one fails
+}
+
 // AreaMessage represents the schema for an area message in the system.
 // It contains information about the action and reaction options, their respective IDs,
 // and additional metadata such as title, description, and action refresh rate.
+// The reactions of the area are given in order by Reactions. An area with a single reaction
+// can give it with ReactionId and ReactionOption instead. An area without RetryPolicy gets
+// the DefaultRetryPolicy.
 type AreaMessage struct {
-	ActionOption      json.RawMessage `gorm:"type:jsonb" json:"action_option"       binding:"required"` // The option of the action
-	ActionId          uint64          `                  json:"action_id"`                              // Foreign key for Action
-	ReactionOption    json.RawMessage `gorm:"type:jsonb" json:"reaction_option"     binding:"required"` // The option of the reaction
-	ReactionId        uint64          `                  json:"reaction_id"`                            // Foreign key for Reaction
-	Title             string          `                  json:"title"               binding:"required"` // The title of the area
-	Description       string          `                  json:"description"         binding:"required"` // The description of the area
-	ActionRefreshRate int             `                  json:"action_refresh_rate" binding:"required"` // The refresh rate for the action
+	ActionOption      json.RawMessage       `gorm:"type:jsonb" json:"action_option"       binding:"required"` // The option of the action
+	ActionId          uint64                `                  json:"action_id"`                              // Foreign key for Action
+	ReactionOption    json.RawMessage       `gorm:"type:jsonb" json:"reaction_option"`                        // The option of the single reaction
+	ReactionId        uint64                `                  json:"reaction_id"`                            // Foreign key for the single Reaction
+	Reactions         []AreaReactionMessage `                  json:"reactions"`                              // The ordered reactions of the area
+	Filter            json.RawMessage       `                  json:"filter"`                                 // The trigger filter of the area
+	RetryPolicy       *RetryPolicy          `                  json:"retry_policy"`                           // The retry policy of the reactions of the area
+	Title             string                `                  json:"title"               binding:"required"` // The title of the area
+	Description       string                `                  json:"description"         binding:"required"` // The description of the area
+	ActionRefreshRate int                   `                  json:"action_refresh_rate" binding:"required"` // The refresh rate for the action
+}
+
+// AreaUpdateMessage represents the schema for an update of an area.
+// Only the fields that are present are changed, the missing ones keep their current value.
+// The user, the action and the storage variable of an area can not be updated.
+// Reactions replaces every reaction of the area, while ReactionOption only changes the option
+// of its first reaction. A null Filter removes the trigger filter of the area.
+type AreaUpdateMessage struct {
+	Id                uint64                `json:"id"                  binding:"required"`        // The identifier of the area to update
+	ActionOption      json.RawMessage       `json:"action_option"`                                 // The new option of the action
+	ReactionOption    json.RawMessage       `json:"reaction_option"`                               // The new option of the first reaction
+	Reactions         []AreaReactionMessage `json:"reactions"`                                     // The new ordered reactions of the area
+	Filter            json.RawMessage       `json:"filter"`                                        // The new trigger filter of the area
+	RetryPolicy       *RetryPolicy          `json:"retry_policy"`                                  // The new retry policy of the reactions of the area
+	Title             *string               `json:"title"`                                         // The new title of the area
+	Description       *string               `json:"description"`                                   // The new description of the area
+	Enable            *bool                 `json:"enable"`                                        // Enable or disable the area
+	ActionRefreshRate *uint64               `json:"action_refresh_rate" binding:"omitempty,min=1"` // The new refresh rate for the action
+}
+
+// AreaReaction is one of the ordered reactions of an area, run for every trigger of its action.
+//
+// Fields:
+//   - Id: Unique identifier for the area reaction.
+//   - AreaId: Foreign key for the Area running the reaction.
+//   - Position: The position of the reaction in the reactions of the area, starting at 0.
+//   - ReactionId: Foreign key for Reaction.
+//   - Reaction: The reaction to run.
+//   - ReactionOption: The option of the reaction.
+//   - ContinueOnFailure: Whether the next reactions run when this one fails.
+//   - CreatedAt: Time when the area reaction was created.
+//   - UpdateAt: Time when the area reaction was last updated.
+type AreaReaction struct {
+	Id                uint64          `gorm:"primaryKey;autoIncrement"            json:"id,omitempty"`        // Unique identifier for the area reaction
+	AreaId            uint64          `gorm:"index"                               json:"-"`                   // Foreign key for Area
+	Position          int             `                                           json:"position"`            // Position of the reaction in the area
+	ReactionId        uint64          `                                           json:"-"`                   // Foreign key for Reaction
+	Reaction          Reaction        `gorm:"foreignKey:ReactionId;references:Id" json:"reaction,omitempty"`  // Reaction to run
+	ReactionOption    json.RawMessage `gorm:"type:jsonb"                          json:"reaction_option"`     // The option of the reaction
+	ContinueOnFailure bool            `gorm:"default:false"                       json:"continue_on_failure"` // Whether the next reactions run when this one fails
+	CreatedAt         time.Time       `gorm:"default:CURRENT_TIMESTAMP"           json:"created_at"`          // Time when the area reaction was created
+	UpdateAt          time.Time       `gorm:"default:CURRENT_TIMESTAMP"           json:"update_at"`           // Time when the area reaction was last updated
 }
 
 // Area represents a specific area in the system with associated actions and reactions.
 // It includes metadata such as title, description, and timestamps for creation and updates.
+// ReactionId, ReactionOption and Reaction hold the first reaction of the area, Reactions holds
+// all of them in order. An area created before it could have several reactions has no Reactions
+// and runs its single reaction.
 //
 // Fields:
 //   - Id: Unique identifier for the area.
@@ -31,6 +90,9 @@ type AreaMessage struct {
 //   - ReactionOption: The option of the reaction.
 //   - ReactionId: Foreign key for Reaction.
 //   - Reaction: Reaction that the area belongs to.
+//   - Reactions: The ordered reactions of the area.
+//   - Filter: The trigger filter of the area, a schemas.TriggerFilter. The triggers that do not match it are skipped.
+//   - RetryPolicy: The retry policy of the reactions of the area failing with a transient error.
 //   - Enable: Enable or disable the area.
 //   - Title: The title of the area.
 //   - Description: The description of the area.
@@ -48,6 +110,9 @@ type Area struct {
 	ReactionOption    json.RawMessage `gorm:"type:jsonb"                                                   json:"reaction_option"     binding:"required"` // The option of the reaction
 	ReactionId        uint64          `                                                                    json:"-"`                                      // Foreign key for Reaction
 	Reaction          Reaction        `gorm:"foreignKey:ReactionId;references:Id"                          json:"reaction,omitempty"  binding:"required"` // Reaction that the area belongs to
+	Reactions         []AreaReaction  `gorm:"foreignKey:AreaId;references:Id;constraint:OnDelete:CASCADE;" json:"reactions"`                              // Ordered reactions of the area
+	Filter            json.RawMessage `gorm:"type:jsonb"                                                   json:"filter"`                                 // Trigger filter of the area
+	RetryPolicy       RetryPolicy     `gorm:"embedded;embeddedPrefix:retry_"                               json:"retry_policy"`                           // Retry policy of the reactions of the area
 	Enable            bool            `gorm:"default:true"                                                 json:"enable"`                                 // Enable or disable the area
 	Title             string          `                                                                    json:"title"               binding:"required"` // The title of the area
 	Description       string          `                                                                    json:"description"         binding:"required"` // The description of the area
@@ -56,3 +121,16 @@ type Area struct {
 	UpdateAt          time.Time       `gorm:"default:CURRENT_TIMESTAMP"                                    json:"update_at"`                              // Time when the area was last updated
 	ActionRefreshRate uint64          `                                                                    json:"action_refresh_rate" binding:"required"` // The refresh rate for the action
 }
+
+// Errors Messages.
+var (
+	ErrAreaNotFound = errors.New(
+		"area not found",
+	) // Error message for area not found
+	ErrAreaWithoutReaction = errors.New(
+		"an area needs at least one reaction",
+	) // Error message for an area update removing every reaction
+	ErrStorageVariableChanged = errors.New(
+		"the storage variable of the area changed meanwhile",
+	) // Error message for a storage variable written since the area was loaded
+)
@@ -0,0 +1,21 @@
+package schemas
+
+import "errors"
+
+// ConditionalState is the validators of the last response of a provider to the poll of an
+// area, kept in the storage variable of the area. They are sent back on the next poll with
+// the If-None-Match and If-Modified-Since headers, so that the provider answers 304 Not
+// Modified without the resource when it did not change.
+//
+// Fields:
+// - ETag: The ETag header of the last response.
+// - LastModified: The Last-Modified header of the last response.
+type ConditionalState struct {
+	ETag         string `json:"etag,omitempty"`          // ETag of the last response
+	LastModified string `json:"last_modified,omitempty"` // Last-Modified of the last response
+}
+
+// Errors Messages.
+var ErrNotModified = errors.New(
+	"resource not modified",
+) // Error message for a 304 response to a conditional request
@@ -0,0 +1,47 @@
+package schemas
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// DeadLetter is a trigger whose workflow run stopped on a reaction that failed for good,
+// either with a permanent error or with a transient error once its retries were exhausted.
+// The dead letters of a user can be inspected, retried, which resumes the workflow run from
+// the failed reaction, or discarded.
+//
+// Fields:
+// - Id: Unique identifier for the dead letter.
+// - UserId: Foreign key for the User owning the area.
+// - AreaId: Foreign key for the associated Area.
+// - Area: The Area that the trigger belongs to, with a cascade delete constraint.
+// - WorkflowRunId: The ID of the workflow run that stopped on the reaction.
+// - ReactionPosition: The position of the failed reaction in the reactions of the area.
+// - ReactionName: The name of the failed reaction.
+// - TriggerMessage: The description of the trigger reported by the action.
+// - TriggerPayload: The structured payload of the trigger reported by the action.
+// - Error: The error message of the last run of the reaction.
+// - Transient: Whether the last error was transient, so that a retry may succeed as is.
+// - Attempts: The number of times the reaction was run for the trigger.
+// - CreatedAt: Timestamp for when the dead letter was recorded, with a default value of the current timestamp.
+type DeadLetter struct {
+	Id               uint64          `gorm:"primaryKey;autoIncrement"                                     json:"id,omitempty"`      // Unique identifier for the dead letter
+	UserId           uint64          `gorm:"index"                                                        json:"-"`                 // Foreign key for User
+	AreaId           uint64          `gorm:"index"                                                        json:"area_id"`           // Foreign key for Area
+	Area             Area            `gorm:"foreignKey:AreaId;references:Id;constraint:OnDelete:CASCADE;" json:"-"`                 // Area that the trigger belongs to
+	WorkflowRunId    uint64          `                                                                    json:"workflow_run_id"`   // Workflow run that stopped on the reaction
+	ReactionPosition int             `                                                                    json:"reaction_position"` // Position of the failed reaction in the area
+	ReactionName     string          `                                                                    json:"reaction_name"`     // Name of the failed reaction
+	TriggerMessage   string          `                                                                    json:"trigger_message"`   // Description of the trigger reported by the action
+	TriggerPayload   json.RawMessage `gorm:"type:jsonb"                                                   json:"trigger_payload"`   // Payload of the trigger reported by the action
+	Error            string          `                                                                    json:"error"`             // Error message of the last run of the reaction
+	Transient        bool            `                                                                    json:"transient"`         // Whether the last error was transient
+	Attempts         uint            `                                                                    json:"attempts"`          // Number of runs of the reaction
+	CreatedAt        time.Time       `gorm:"default:CURRENT_TIMESTAMP;index"                              json:"created_at"`        // Time when the dead letter was recorded
+}
+
+// Errors Messages.
+var ErrDeadLetterNotFound = errors.New(
+	"dead letter not found",
+) // Error message for dead letter not found
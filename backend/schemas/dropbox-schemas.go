@@ -59,6 +59,13 @@ type DropboxUserInfo struct {
 var (
 	ErrDropboxSecretNotSet   = errors.New("DROPBOX_SECRET is not set")
 	ErrDropboxClientIdNotSet = errors.New("DROPBOX_CLIENT_ID is not set")
+	ErrDropboxCursorReset    = errors.New("dropbox cursor was reset")
+)
+
+const (
+	DropboxNotifyURL       = "https://notify.dropboxapi.com" // Base URL of the Dropbox long polls
+	DropboxLongpollTimeout = 480                             // Seconds a long poll waits for changes, the longest allowed by Dropbox
+	DropboxLongpollJitter  = 90                              // Longest delay in seconds added by Dropbox to a long poll
 )
 
 type DropboxMobileTokenRequest struct {
@@ -119,6 +126,32 @@ type DropboxListFolderResult struct {
 	HasMore bool           `json:"has_more"`
 }
 
+// DropboxListFolderRequest is the body of a list_folder request.
+type DropboxListFolderRequest struct {
+	Path      string `json:"path"`      // The path of the folder, "" for the root of the Dropbox
+	Recursive bool   `json:"recursive"` // Whether the entries of the subfolders are listed
+}
+
+// DropboxCursorRequest is the body of the list_folder/continue and list_folder/longpoll requests.
+type DropboxCursorRequest struct {
+	Cursor  string `json:"cursor"`            // The cursor of the folder, from a previous listing
+	Timeout uint64 `json:"timeout,omitempty"` // Seconds a long poll waits for changes
+}
+
+// DropboxLongpollResult is the response of a list_folder/longpoll request.
+type DropboxLongpollResult struct {
+	Changes bool   `json:"changes"` // Whether the folder changed since the cursor
+	Backoff uint64 `json:"backoff"` // Seconds to wait before the next long poll, if any
+}
+
+// DropboxErrorResponse is the body of a 409 response of the Dropbox API.
+type DropboxErrorResponse struct {
+	ErrorSummary string `json:"error_summary"`
+	Error        struct {
+		Tag string `json:".tag"`
+	} `json:"error"`
+}
+
 type DropboxCountFileRequestsResult struct {
 	FileRequestCount uint64 `json:"file_request_count"`
 }
@@ -133,10 +166,73 @@ type DropboxSaveUrlResult struct {
 	AsyncJobID string `json:"async_job_id"`
 }
 
+// DropboxActionUpdateInFolder is the option of the UpdateInFolder action.
 type DropboxActionUpdateInFolder struct {
-	Path string `json:"path"`
+	Path       string   `json:"path"`       // The path of the folder, relative to the root of the Dropbox
+	Recursive  bool     `json:"recursive"`  // Whether the changes of the subfolders are reported
+	Glob       string   `json:"glob"`       // Pattern the paths of the reported entries match, if any
+	Extensions []string `json:"extensions"` // Extensions the names of the reported entries end with, if any
 }
 
+// DropboxActionUpdateInFolderStorage is the storage variable of the UpdateInFolder action.
+//
+// Fields:
+// - Cursor: The cursor of the changes of the folder since the last check.
+// - Options: The options the cursor was created for, a new cursor being created when they change.
+// - Entries: The revision of every entry of the folder matching the filters, by lowercased path,
+// "" for the folders.
 type DropboxActionUpdateInFolderStorage struct {
-	Time time.Time `json:"time"`
+	Cursor  string                      `json:"cursor,omitempty"`
+	Options DropboxActionUpdateInFolder `json:"options"`
+	Entries map[string]string           `json:"entries"`
+}
+
+// DropboxUpdateInFolderPayload is the trigger payload of the Dropbox update in folder action.
+type DropboxUpdateInFolderPayload struct {
+	Path     string   `json:"path"`     // The path of the updated folder
+	Added    []string `json:"added"`    // The paths of the entries added to the folder
+	Modified []string `json:"modified"` // The paths of the files modified
+	Deleted  []string `json:"deleted"`  // The lowercased paths of the entries deleted or moved away
 }
+
+// DropboxActionUpdateInFolderSchema is the JSON Schema of the DropboxActionUpdateInFolder option.
+var DropboxActionUpdateInFolderSchema = NewObjectOptionSchema(map[string]OptionSchema{
+	"path": {
+		Type:        "string",
+		Title:       "Path",
+		Description: "The path of the folder, relative to the root of the Dropbox",
+	},
+	"recursive": {
+		Type:        "boolean",
+		Title:       "Recursive",
+		Description: "Whether the changes of the subfolders are reported",
+	},
+	"glob": {
+		Type:  "string",
+		Title: "Glob",
+		Description: "Pattern the paths of the reported entries match, such as *.pdf, " +
+			"matched against the name of the entries when it has no /",
+	},
+	"extensions": {
+		Type:        "array",
+		Title:       "Extensions",
+		Description: "Extensions the names of the reported entries end with, such as pdf",
+		Items:       &OptionSchema{Type: "string", MinLength: OptionLength(1)},
+	},
+}, "path")
+
+// DropboxSaveUrlReactionOptionSchema is the JSON Schema of the DropboxSaveUrlReactionOption option.
+var DropboxSaveUrlReactionOptionSchema = NewObjectOptionSchema(map[string]OptionSchema{
+	"path": {
+		Type:        "string",
+		Title:       "Path",
+		Description: "The path of the file to create, relative to the root of the Dropbox",
+		MinLength:   OptionLength(1),
+	},
+	"url": {
+		Type:        "string",
+		Title:       "URL",
+		Description: "The URL of the content to save",
+		MinLength:   OptionLength(1),
+	},
+}, "path", "url")
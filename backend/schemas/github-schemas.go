@@ -83,6 +83,7 @@ type GithubActionOption struct {
 
 type GithubActionOptionStorage struct {
 	Time time.Time `json:"time"`
+	ConditionalState
 }
 
 type GithubActor struct {
@@ -548,3 +549,41 @@ type GithubWorkflowRunsList struct {
 	TotalCount   int              `json:"total_count"`
 	WorkflowRuns []GithubWorkflow `json:"workflow_runs"`
 }
+
+// GithubCommitPayload is the trigger payload of the UpdateCommitInRepo action.
+type GithubCommitPayload struct {
+	Repository string    `json:"repository"` // The name of the repository
+	Sha        string    `json:"sha"`        // The SHA of the latest commit
+	Author     string    `json:"author"`     // The name of the author of the commit
+	Message    string    `json:"message"`    // The message of the commit
+	Url        string    `json:"url"`        // The URL of the commit
+	Date       time.Time `json:"date"`       // The date of the commit
+}
+
+// GithubPullRequestPayload is the trigger payload of the UpdatePullRequestInRepo action.
+type GithubPullRequestPayload struct {
+	Repository string `json:"repository"` // The name of the repository
+	Number     int    `json:"number"`     // The number of the latest pull request
+	Title      string `json:"title"`      // The title of the pull request
+	Author     string `json:"author"`     // The login of the author of the pull request
+	Url        string `json:"url"`        // The URL of the pull request
+}
+
+// GithubWorkflowRunPayload is the trigger payload of the UpdateWorkflowRunInRepo action.
+type GithubWorkflowRunPayload struct {
+	Repository string `json:"repository"` // The name of the repository
+	Name       string `json:"name"`       // The name of the latest workflow run
+	Status     string `json:"status"`     // The status of the workflow run
+	Branch     string `json:"branch"`     // The branch the workflow run ran on
+	Url        string `json:"url"`        // The URL of the workflow run
+}
+
+// GithubActionOptionSchema is the JSON Schema of the GithubActionOption option.
+var GithubActionOptionSchema = NewObjectOptionSchema(map[string]OptionSchema{
+	"repo_name": {
+		Type:        "string",
+		Title:       "Repository",
+		Description: "The repository, written as owner/repo",
+		Pattern:     `^[A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+$`,
+	},
+}, "repo_name")
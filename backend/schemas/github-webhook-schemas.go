@@ -0,0 +1,118 @@
+package schemas
+
+import (
+	"errors"
+	"time"
+)
+
+// GithubWebhookEvent is the name of a GitHub event, sent in the X-GitHub-Event header of the
+// deliveries of a webhook.
+type GithubWebhookEvent string
+
+const (
+	GithubEventPing        GithubWebhookEvent = "ping"         // Sent once when the webhook is created
+	GithubEventPush        GithubWebhookEvent = "push"         // Commits pushed to a branch or a tag
+	GithubEventPullRequest GithubWebhookEvent = "pull_request" // Activity on a pull request
+	GithubEventWorkflowRun GithubWebhookEvent = "workflow_run" // Activity on a workflow run
+)
+
+const (
+	GithubEventHeader     = "X-GitHub-Event"      // Header of the name of the event of a delivery
+	GithubSignatureHeader = "X-Hub-Signature-256" // Header of the signature of a delivery
+	GithubSignaturePrefix = "sha256="             // Prefix of the signature in its header
+)
+
+// GithubWebhook is a webhook registered on a GitHub repository with the token of a user, so
+// that GitHub pushes the events of the repository to the backend. The events trigger the
+// GitHub actions of the areas of the user on the repository right away, their checks still
+// polling the repository in case a delivery is lost.
+//
+// Fields:
+// - Id: Unique identifier for the webhook, part of the URL its deliveries are sent to.
+// - UserId: Foreign key for the User who registered the webhook.
+// - User: The User who registered the webhook, with a cascade delete constraint.
+// - RepoName: The repository of the webhook, in the format "owner/repo".
+// - HookId: The ID of the webhook on GitHub.
+// - Secret: The secret signing the deliveries of the webhook.
+// - CreatedAt: Timestamp for when the webhook was registered, with a default value of the current timestamp.
+type GithubWebhook struct {
+	Id        uint64    `gorm:"primaryKey;autoIncrement"                                     json:"id,omitempty"` // Unique identifier for the webhook
+	UserId    uint64    `gorm:"index"                                                        json:"-"`            // Foreign key for User
+	User      User      `gorm:"foreignKey:UserId;references:Id;constraint:OnDelete:CASCADE;" json:"-"`            // User who registered the webhook
+	RepoName  string    `                                                                    json:"repo_name"`    // Repository of the webhook
+	HookId    uint64    `                                                                    json:"hook_id"`      // ID of the webhook on GitHub
+	Secret    string    `                                                                    json:"-"`            // Secret signing the deliveries
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"                                    json:"created_at"`   // Time when the webhook was registered
+}
+
+// GithubWebhookRequest is the body of a request registering a webhook on a repository.
+type GithubWebhookRequest struct {
+	RepoName string `json:"repo_name" binding:"required"` // Repository in the format "owner/repo"
+}
+
+// GithubHookConfig is the configuration of a webhook sent to GitHub when creating it.
+type GithubHookConfig struct {
+	URL         string `json:"url"`          // URL the deliveries are sent to
+	ContentType string `json:"content_type"` // Format of the deliveries
+	Secret      string `json:"secret"`       // Secret signing the deliveries
+}
+
+// GithubHookRequest is the body of the request creating a webhook on a repository.
+type GithubHookRequest struct {
+	Name   string               `json:"name"`   // Always "web"
+	Active bool                 `json:"active"` // Whether the deliveries are sent
+	Events []GithubWebhookEvent `json:"events"` // Events sent to the webhook
+	Config GithubHookConfig     `json:"config"` // Configuration of the webhook
+}
+
+// GithubHook is the webhook created on a repository, as returned by GitHub.
+type GithubHook struct {
+	Id uint64 `json:"id"` // ID of the webhook on GitHub
+}
+
+// GithubEventRepository is the repository of a webhook event.
+type GithubEventRepository struct {
+	FullName string `json:"full_name"` // Name of the repository in the format "owner/repo"
+}
+
+// GithubPushCommit is the head commit of a push event.
+type GithubPushCommit struct {
+	Id        string    `json:"id"`        // SHA of the commit
+	Message   string    `json:"message"`   // Message of the commit
+	Timestamp time.Time `json:"timestamp"` // Date of the commit
+	URL       string    `json:"url"`       // URL of the commit
+	Author    struct {
+		Name string `json:"name"` // Name of the author of the commit
+	} `json:"author"` // Author of the commit
+}
+
+// GithubPushEvent is the payload of a push event.
+// HeadCommit is nil when the push deleted a branch.
+type GithubPushEvent struct {
+	Repository GithubEventRepository `json:"repository"`  // Repository of the push
+	HeadCommit *GithubPushCommit     `json:"head_commit"` // Latest commit of the push
+}
+
+// GithubPullRequestEvent is the payload of a pull_request event.
+type GithubPullRequestEvent struct {
+	Action      string                `json:"action"`       // Activity on the pull request, such as "opened"
+	Repository  GithubEventRepository `json:"repository"`   // Repository of the pull request
+	PullRequest GithubPullRequest     `json:"pull_request"` // Pull request of the event
+}
+
+// GithubWorkflowRunEvent is the payload of a workflow_run event.
+type GithubWorkflowRunEvent struct {
+	Action      string                `json:"action"`       // Activity on the workflow run, such as "requested"
+	Repository  GithubEventRepository `json:"repository"`   // Repository of the workflow run
+	WorkflowRun GithubWorkflow        `json:"workflow_run"` // Workflow run of the event
+}
+
+// Errors Messages.
+var (
+	ErrGithubWebhookNotFound = errors.New(
+		"github webhook not found",
+	) // Error message for github webhook not found
+	ErrInvalidWebhookSignature = errors.New(
+		"invalid webhook signature",
+	) // Error message for a webhook delivery whose signature does not match its body
+)
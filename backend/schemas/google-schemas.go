@@ -73,10 +73,23 @@ type GmailReactionSendMailOption struct {
 var (
 	ErrGoogleSecretNotSet   = errors.New("GOOGLE_SECRET is not set")
 	ErrGoogleClientIdNotSet = errors.New("GOOGLE_CLIENT_ID is not set")
+	ErrGmailHistoryExpired  = errors.New("gmail history id expired")
+	ErrGmailNotFound        = errors.New("gmail resource not found")
 )
 
+// GmailResyncMaxMessages is the largest number of emails reported by a full resync of the
+// mailbox, the newest ones being kept.
+const GmailResyncMaxMessages = 100
+
+// GoogleVariableReceiveMail is the storage variable of the ReceiveGoogleMail action.
+//
+// Fields:
+// - Time: The reception time of the last email reported, the emails received after it being
+// reported by a full resync of the mailbox.
+// - HistoryId: The history ID of the mailbox the next check lists the added emails from.
 type GoogleVariableReceiveMail struct {
-	Time time.Time `json:"time"`
+	Time      time.Time `json:"time"`
+	HistoryId string    `json:"history_id,omitempty"`
 }
 
 type GmailMessage struct {
@@ -85,17 +98,33 @@ type GmailMessage struct {
 }
 
 type GmailEmailResponse struct {
-	Messages []GmailMessage `json:"messages"`
+	Messages      []GmailMessage `json:"messages"`
+	NextPageToken string         `json:"nextPageToken"`
+}
+
+// GmailHistoryResponse is a page of the changes of a mailbox since a history ID, as returned
+// by users.history.list.
+type GmailHistoryResponse struct {
+	History []struct {
+		Id            string `json:"id"`
+		MessagesAdded []struct {
+			Message GmailMessage `json:"message"`
+		} `json:"messagesAdded"`
+	} `json:"history"`
+	NextPageToken string `json:"nextPageToken"` // Token of the next page, if any
+	HistoryId     string `json:"historyId"`     // Current history ID of the mailbox
 }
 
 type EmailDetails struct {
-	Date    string `json:"date"`
-	From    string `json:"from"`
-	Subject string `json:"body"`
+	Date       string    `json:"date"`
+	From       string    `json:"from"`
+	Subject    string    `json:"body"`
+	ReceivedAt time.Time `json:"received_at"` // Time Gmail received the email
 }
 
 type GmailMessageResponse struct {
-	Payload struct {
+	InternalDate string `json:"internalDate"` // Reception time in milliseconds since the epoch
+	Payload      struct {
 		Headers []struct {
 			Name  string `json:"name"`
 			Value string `json:"value"`
@@ -108,3 +137,42 @@ type GmailMessageResponse struct {
 		} `json:"parts"`
 	} `json:"payload"`
 }
+
+// GmailMailPayload is the trigger payload of the Gmail receive mail action.
+type GmailMailPayload struct {
+	Sender  string    `json:"sender"`  // The sender of the email
+	Subject string    `json:"subject"` // The subject of the email
+	Date    time.Time `json:"date"`    // The date the email was received
+}
+
+// GmailSentMailPayload is the output payload of the Gmail send mail reaction.
+type GmailSentMailPayload struct {
+	Id       string `json:"id"`        // The ID of the sent email
+	ThreadId string `json:"thread_id"` // The ID of the thread of the sent email
+	To       string `json:"to"`        // The recipient of the email
+	Subject  string `json:"subject"`   // The subject of the email
+}
+
+// GmailReceiveMailSchema is the JSON Schema of the option of the ReceiveGoogleMail action.
+var GmailReceiveMailSchema = NewObjectOptionSchema(nil)
+
+// GmailReactionSendMailOptionSchema is the JSON Schema of the GmailReactionSendMailOption option.
+var GmailReactionSendMailOptionSchema = NewObjectOptionSchema(map[string]OptionSchema{
+	"to": {
+		Type:        "string",
+		Title:       "To",
+		Description: "The email address of the recipient",
+		Format:      "email",
+	},
+	"subject": {
+		Type:        "string",
+		Title:       "Subject",
+		Description: "The subject of the email",
+		MinLength:   OptionLength(1),
+	},
+	"body": {
+		Type:        "string",
+		Title:       "Body",
+		Description: "The body of the email",
+	},
+}, "to", "subject", "body")
@@ -45,14 +45,51 @@ type MicrosoftTokenResponse struct {
 	RefreshToken string `json:"refresh_token"` // The refresh token
 }
 
+// MicrosoftVariableTime is the storage variable of the ReceiveMicrosoftMail action.
+//
+// Fields:
+// - Time: The reception time of the last email reported, only the emails received after it
+// being reported.
+// - DeltaLink: The link of the delta query of the inbox returning its changes since the last check.
 type MicrosoftVariableTime struct {
-	Time time.Time `json:"time"`
+	Time      time.Time `json:"time"`
+	DeltaLink string    `json:"delta_link,omitempty"`
 }
 
+// MicrosoftUpcomingEvent is an event of the calendar of the user whose subject is the one
+// watched by an EventStarting action, kept until its end.
+type MicrosoftUpcomingEvent struct {
+	Id       string `json:"id"`       // The ID of the event
+	Subject  string `json:"subject"`  // The subject of the event
+	Start    string `json:"start"`    // The start date and time of the event, in UTC
+	End      string `json:"end"`      // The end date and time of the event, in UTC
+	Reported bool   `json:"reported"` // Whether the start of the event was reported
+}
+
+// MicrosoftVariableEvents is the storage variable of the EventStarting action.
+//
+// Fields:
+// - DeltaLink: The link of the delta query of the calendar view returning its changes since
+// the last check.
+// - WindowEnd: The end of the calendar view watched by the delta query.
+// - Events: The events of the calendar view with the watched subject, by start.
+type MicrosoftVariableEvents struct {
+	DeltaLink string                   `json:"delta_link,omitempty"`
+	WindowEnd time.Time                `json:"window_end"`
+	Events    []MicrosoftUpcomingEvent `json:"events"`
+}
+
+const (
+	MicrosoftCalendarWindow        = 30 // Days of the calendar watched by the EventStarting action
+	MicrosoftCalendarWindowRenewal = 7  // Days before the end of the watched calendar when a new window is watched
+	MicrosoftNotificationAttempts  = 3  // Writes of the storage variable tried by a notification racing the checks of its area
+)
+
 // error messages
 var (
 	ErrMicrosoftClientIdNotSet = errors.New("MICROSOFT_CLIENT_ID is not set")
 	ErrMicrosoftSecretNotSet   = errors.New("MICROSOFT_SECRET is not set")
+	ErrMicrosoftDeltaExpired   = errors.New("microsoft delta link expired")
 )
 
 type MicrosoftUserInfo struct {
@@ -61,27 +98,140 @@ type MicrosoftUserInfo struct {
 	DisplayName       string `json:"displayName"`
 }
 
-type MicrosoftEmailResponse struct {
-	Value []struct {
-		ID      string `json:"id"`
-		Subject string `json:"subject"`
-		From    struct {
-			EmailAddress struct {
-				Address string `json:"address"`
-			} `json:"emailAddress"`
-		} `json:"from"`
-		ReceivedDateTime string `json:"receivedDateTime"`
-	} `json:"value"`
-}
-
-type MicrosoftEventListResponse struct {
-	Value []struct {
-		Subject string `json:"subject"`
-		Start   struct {
-			DateTime string `json:"dateTime"`
-		} `json:"start"`
-		End struct {
-			DateTime string `json:"dateTime"`
-		} `json:"end"`
-	} `json:"value"`
+// MicrosoftRemoved marks an item returned by a delta query as removed from its collection.
+type MicrosoftRemoved struct {
+	Reason string `json:"reason"` // "deleted", or "changed" when moved out of the collection
+}
+
+// MicrosoftDeltaResponse is a page of a delta query of Microsoft Graph. The last page holds
+// the delta link returning the changes following the query, the others the link of the next
+// page.
+type MicrosoftDeltaResponse[T any] struct {
+	Value     []T    `json:"value"`            // The items changed, with their current state
+	NextLink  string `json:"@odata.nextLink"`  // The link of the next page, if any
+	DeltaLink string `json:"@odata.deltaLink"` // The link of the next delta query, on the last page
+}
+
+// MicrosoftEmail is an email of the mailbox of the user, as returned by Microsoft Graph.
+type MicrosoftEmail struct {
+	ID      string            `json:"id"`
+	Removed *MicrosoftRemoved `json:"@removed,omitempty"`
+	Subject string            `json:"subject"`
+	From    struct {
+		EmailAddress struct {
+			Address string `json:"address"`
+		} `json:"emailAddress"`
+	} `json:"from"`
+	ReceivedDateTime string `json:"receivedDateTime"`
+}
+
+// MicrosoftEvent is an event of the calendar of the user, as returned by Microsoft Graph.
+type MicrosoftEvent struct {
+	Id      string            `json:"id"`
+	Removed *MicrosoftRemoved `json:"@removed,omitempty"`
+	Subject string            `json:"subject"`
+	Start   struct {
+		DateTime string `json:"dateTime"`
+	} `json:"start"`
+	End struct {
+		DateTime string `json:"dateTime"`
+	} `json:"end"`
 }
+
+// MicrosoftMailPayload is the trigger payload of the Microsoft receive mail action.
+type MicrosoftMailPayload struct {
+	Sender  string `json:"sender"`  // The address of the sender of the email
+	Subject string `json:"subject"` // The subject of the email
+	Date    string `json:"date"`    // The date the email was received
+}
+
+// MicrosoftEventPayload is the trigger payload of the Microsoft event starting action.
+type MicrosoftEventPayload struct {
+	Subject string `json:"subject"` // The subject of the event
+	Start   string `json:"start"`   // The start date and time of the event
+	End     string `json:"end"`     // The end date and time of the event
+}
+
+// MicrosoftSentMailPayload is the output payload of the Microsoft send mail reaction.
+type MicrosoftSentMailPayload struct {
+	Recipient string `json:"recipient"` // The address of the recipient of the email
+	Subject   string `json:"subject"`   // The subject of the email
+}
+
+// MicrosoftCreatedEventPayload is the output payload of the Microsoft create event reaction.
+type MicrosoftCreatedEventPayload struct {
+	Id      string `json:"id"`      // The ID of the created event
+	Subject string `json:"subject"` // The subject of the event
+	Start   string `json:"start"`   // The start date and time of the event
+	End     string `json:"end"`     // The end date and time of the event
+	Url     string `json:"url"`     // The URL of the event in Outlook
+}
+
+// microsoftDateTimePattern is the pattern of the dates of the Microsoft options.
+const microsoftDateTimePattern = `^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}$`
+
+// MicrosoftReceiveMailSchema is the JSON Schema of the option of the ReceiveMicrosoftMail action.
+var MicrosoftReceiveMailSchema = NewObjectOptionSchema(nil)
+
+// MicrosoftEventIncomingOptionsSchema is the JSON Schema of the MicrosoftEventIncomingOptions option.
+var MicrosoftEventIncomingOptionsSchema = NewObjectOptionSchema(map[string]OptionSchema{
+	"name": {
+		Type:        "string",
+		Title:       "Name",
+		Description: "The name of the event",
+		MinLength:   OptionLength(1),
+	},
+}, "name")
+
+// MicrosoftReactionSendMailOptionsSchema is the JSON Schema of the MicrosoftReactionSendMailOptions option.
+var MicrosoftReactionSendMailOptionsSchema = NewObjectOptionSchema(map[string]OptionSchema{
+	"subject": {
+		Type:        "string",
+		Title:       "Subject",
+		Description: "The subject of the email",
+		MinLength:   OptionLength(1),
+	},
+	"body": {
+		Type:        "string",
+		Title:       "Body",
+		Description: "The body of the email",
+	},
+	"recipient": {
+		Type:        "string",
+		Title:       "Recipient",
+		Description: "The email address of the recipient",
+		Format:      "email",
+	},
+}, "subject", "body", "recipient")
+
+// MicrosoftCreateEventOptionsSchema is the JSON Schema of the MicrosoftCreateEventOptions option.
+var MicrosoftCreateEventOptionsSchema = NewObjectOptionSchema(map[string]OptionSchema{
+	"subject": {
+		Type:        "string",
+		Title:       "Subject",
+		Description: "The subject of the event",
+		MinLength:   OptionLength(1),
+	},
+	"body": {
+		Type:        "string",
+		Title:       "Body",
+		Description: "The body of the event",
+	},
+	"location": {
+		Type:        "string",
+		Title:       "Location",
+		Description: "The location of the event",
+	},
+	"start": {
+		Type:        "string",
+		Title:       "Start",
+		Description: "The start time of the event, written as YYYY-MM-DDTHH:MM:SS",
+		Pattern:     microsoftDateTimePattern,
+	},
+	"end": {
+		Type:        "string",
+		Title:       "End",
+		Description: "The end time of the event, written as YYYY-MM-DDTHH:MM:SS",
+		Pattern:     microsoftDateTimePattern,
+	},
+}, "subject", "body", "location", "start", "end")
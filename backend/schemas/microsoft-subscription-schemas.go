@@ -0,0 +1,74 @@
+package schemas
+
+import (
+	"errors"
+	"time"
+)
+
+const (
+	MicrosoftGraphURL             = "https://graph.microsoft.com/v1.0" // Base URL of the Microsoft Graph API
+	MicrosoftValidationTokenParam = "validationToken"                  // Query parameter of the handshake of a subscription
+	MicrosoftSubscriptionLifetime = 4230                               // Minutes a subscription lasts once created or renewed
+	MicrosoftSubscriptionMargin   = 1440                               // Minutes before its expiration when a subscription is renewed
+)
+
+// MicrosoftSubscription is a Microsoft Graph subscription created for an area with the token of
+// its user, so that Graph notifies the backend of the changes of the resource watched by the
+// action of the area. The notifications trigger the area right away, its checks still polling
+// Graph in case a notification is lost.
+//
+// Fields:
+// - Id: Unique identifier for the subscription.
+// - AreaId: Foreign key for the Area whose action the subscription watches the resource of.
+// - Area: The Area of the subscription, with a cascade delete constraint.
+// - SubscriptionId: The ID of the subscription on Microsoft Graph.
+// - Resource: The resource watched by the subscription, such as "me/messages".
+// - ClientState: The secret sent back by Graph in every notification of the subscription.
+// - ExpiresAt: Timestamp for when Graph removes the subscription unless it is renewed.
+// - CreatedAt: Timestamp for when the subscription was created, with a default value of the current timestamp.
+type MicrosoftSubscription struct {
+	Id             uint64    `gorm:"primaryKey;autoIncrement"                                     json:"id,omitempty"`    // Unique identifier for the subscription
+	AreaId         uint64    `gorm:"uniqueIndex"                                                  json:"-"`               // Foreign key for Area
+	Area           Area      `gorm:"foreignKey:AreaId;references:Id;constraint:OnDelete:CASCADE;" json:"-"`               // Area of the subscription
+	SubscriptionId string    `gorm:"index"                                                        json:"subscription_id"` // ID of the subscription on Graph
+	Resource       string    `                                                                    json:"resource"`        // Resource watched by the subscription
+	ClientState    string    `                                                                    json:"-"`               // Secret of the notifications
+	ExpiresAt      time.Time `                                                                    json:"expires_at"`      // Time when Graph removes the subscription
+	CreatedAt      time.Time `gorm:"default:CURRENT_TIMESTAMP"                                    json:"created_at"`      // Time when the subscription was created
+}
+
+// MicrosoftSubscriptionRequest is the body of the request creating a subscription on Graph.
+type MicrosoftSubscriptionRequest struct {
+	ChangeType         string `json:"changeType"`         // Changes notified, separated by commas
+	NotificationURL    string `json:"notificationUrl"`    // URL the notifications are sent to
+	Resource           string `json:"resource"`           // Resource watched by the subscription
+	ExpirationDateTime string `json:"expirationDateTime"` // Time when Graph removes the subscription
+	ClientState        string `json:"clientState"`        // Secret sent back in every notification
+}
+
+// MicrosoftSubscriptionRenewal is the body of the request renewing a subscription on Graph.
+type MicrosoftSubscriptionRenewal struct {
+	ExpirationDateTime string `json:"expirationDateTime"` // New time when Graph removes the subscription
+}
+
+// MicrosoftGraphSubscription is a subscription, as returned by Graph.
+type MicrosoftGraphSubscription struct {
+	Id                 string    `json:"id"`                 // ID of the subscription on Graph
+	ExpirationDateTime time.Time `json:"expirationDateTime"` // Time when Graph removes the subscription
+}
+
+// MicrosoftNotification is a change notified by Graph for a subscription.
+type MicrosoftNotification struct {
+	SubscriptionId string `json:"subscriptionId"` // ID of the subscription on Graph
+	ClientState    string `json:"clientState"`    // Secret of the subscription
+	ChangeType     string `json:"changeType"`     // Kind of the change, such as "created"
+	Resource       string `json:"resource"`       // Path of the changed item, relative to the Graph URL
+}
+
+// MicrosoftNotificationList is the body of a delivery of notifications by Graph.
+type MicrosoftNotificationList struct {
+	Value []MicrosoftNotification `json:"value"` // Notifications of the delivery
+}
+
+// error messages
+var ErrMicrosoftSubscriptionNotFound = errors.New("microsoft subscription not found")
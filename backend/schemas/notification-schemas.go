@@ -0,0 +1,24 @@
+package schemas
+
+import "errors"
+
+const (
+	AreaChangeChannel   = "area_changes"  // Notification channel of the changes of the areas
+	TriggerQueueChannel = "trigger_queue" // Notification channel of the triggers queued for the reaction workers
+)
+
+// AreaChange is the payload of a notification on the AreaChangeChannel channel, sent when an
+// area is created, updated or deleted, so that the workers apply the change right away.
+//
+// Fields:
+// - AreaId: The ID of the area that changed.
+// - NodeId: The ID of the scheduler node that changed the area, empty for an API replica.
+type AreaChange struct {
+	AreaId uint64 `json:"area_id"` // Area that changed
+	NodeId string `json:"node_id"` // Scheduler node that changed the area
+}
+
+// Errors Messages.
+var ErrNotificationsNotSupported = errors.New(
+	"the database does not support notifications",
+) // Error message for a database connection that can not listen to notifications
@@ -131,3 +131,54 @@ type OpenWeatherMapReactionGiveTime struct{}
 type OpenWeatherMapReactionApiResponse struct{}
 
 var ErrOpenWeatherMapApiKeyNotSet = errors.New("OPENWEATHERMAP_API_KEY is not set")
+
+// OpenWeatherMapWeatherPayload is the trigger payload of the specific weather action.
+type OpenWeatherMapWeatherPayload struct {
+	City    string           `json:"city"`    // The city of the weather
+	Weather WeatherCondition `json:"weather"` // The current weather in the city
+}
+
+// OpenWeatherMapTemperaturePayload is the trigger payload of the temperature actions.
+type OpenWeatherMapTemperaturePayload struct {
+	City        string  `json:"city"`        // The city of the temperature
+	Temperature float64 `json:"temperature"` // The current temperature in the city in degrees Celsius
+}
+
+// openWeatherMapCitySchema is the JSON Schema of the city of the OpenWeatherMap options.
+var openWeatherMapCitySchema = OptionSchema{
+	Type:        "string",
+	Title:       "City",
+	Description: "The name of the city",
+	MinLength:   OptionLength(1),
+}
+
+// OpenWeatherMapActionSpecificWeatherSchema is the JSON Schema of the OpenWeatherMapActionSpecificWeather option.
+var OpenWeatherMapActionSpecificWeatherSchema = NewObjectOptionSchema(map[string]OptionSchema{
+	"city": openWeatherMapCitySchema,
+	"weather": {
+		Type:        "string",
+		Title:       "Weather",
+		Description: "The weather condition to check",
+		Enum: []any{
+			Thunderstorm, Drizzle, Rain, Snow, Mist, Smoke, Haze, Dust,
+			Fog, Sand, Ash, Squall, Tornado, Clear, Clouds,
+		},
+	},
+}, "city", "weather")
+
+// OpenWeatherMapActionSpecificTemperatureSchema is the JSON Schema of the OpenWeatherMapActionSpecificTemperature option.
+var OpenWeatherMapActionSpecificTemperatureSchema = NewObjectOptionSchema(map[string]OptionSchema{
+	"city": openWeatherMapCitySchema,
+	"temperature": {
+		Type:        "integer",
+		Title:       "Temperature",
+		Description: "The temperature to check, in degrees Celsius",
+		Minimum:     OptionBound(-100),
+		Maximum:     OptionBound(100),
+	},
+}, "city", "temperature")
+
+// OpenWeatherMapReactionOptionSchema is the JSON Schema of the OpenWeatherMapReactionOption option.
+var OpenWeatherMapReactionOptionSchema = NewObjectOptionSchema(map[string]OptionSchema{
+	"city": openWeatherMapCitySchema,
+}, "city")
@@ -0,0 +1,108 @@
+package schemas
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OptionSchemaDraft is the JSON Schema dialect of the action and reaction option schemas.
+const OptionSchemaDraft = "https://json-schema.org/draft/2020-12/schema"
+
+// OptionSchema is the JSON Schema describing the option of an action or a reaction.
+// Only the keywords needed to describe the options of the services are supported,
+// so that the web and mobile clients can generate a form from it.
+//
+// Fields:
+// - Schema: The JSON Schema dialect, only set on the root schema.
+// - Type: The JSON type of the value, object, string, integer, number, boolean or array.
+// - Title: A short label for the value.
+// - Description: A description of the value.
+// - Properties: The schemas of the fields of an object.
+// - Required: The names of the fields that an object must have.
+// - AdditionalProperties: Whether an object can have fields that are not in Properties.
+// - Enum: The values the value must be one of.
+// - Minimum: The lowest value of a number.
+// - Maximum: The highest value of a number.
+// - MinLength: The shortest length of a string.
+// - MaxLength: The longest length of a string.
+// - Pattern: The regular expression a string must match.
+// - Format: The format of a string, such as email or date-time.
+// - Items: The schema of the items of an array.
+// - Default: The value used by default.
+type OptionSchema struct {
+	Schema               string                  `json:"$schema,omitempty"`              // JSON Schema dialect
+	Type                 string                  `json:"type"`                           // JSON type of the value
+	Title                string                  `json:"title,omitempty"`                // Short label for the value
+	Description          string                  `json:"description,omitempty"`          // Description of the value
+	Properties           map[string]OptionSchema `json:"properties,omitempty"`           // Schemas of the fields of an object
+	Required             []string                `json:"required,omitempty"`             // Required fields of an object
+	AdditionalProperties *bool                   `json:"additionalProperties,omitempty"` // Whether unknown fields are allowed
+	Enum                 []any                   `json:"enum,omitempty"`                 // Allowed values
+	Minimum              *float64                `json:"minimum,omitempty"`              // Lowest value of a number
+	Maximum              *float64                `json:"maximum,omitempty"`              // Highest value of a number
+	MinLength            *int                    `json:"minLength,omitempty"`            // Shortest length of a string
+	MaxLength            *int                    `json:"maxLength,omitempty"`            // Longest length of a string
+	Pattern              string                  `json:"pattern,omitempty"`              // Regular expression of a string
+	Format               string                  `json:"format,omitempty"`               // Format of a string
+	Items                *OptionSchema           `json:"items,omitempty"`                // Schema of the items of an array
+	Default              any                     `json:"default,omitempty"`              // Default value
+}
+
+// NewObjectOptionSchema returns the root schema of an option, an object with the given
+// properties that rejects unknown fields.
+//
+// Parameters:
+//   - properties: The schemas of the fields of the option.
+//   - required: The names of the fields that the option must have.
+//
+// Returns:
+//   - OptionSchema: The schema of the option.
+func NewObjectOptionSchema(properties map[string]OptionSchema, required ...string) OptionSchema {
+	additionalProperties := false
+	return OptionSchema{
+		Schema:               OptionSchemaDraft,
+		Type:                 "object",
+		Properties:           properties,
+		Required:             required,
+		AdditionalProperties: &additionalProperties,
+	}
+}
+
+// OptionBound returns a pointer to a bound of an option schema, for Minimum and Maximum.
+func OptionBound(value float64) *float64 {
+	return &value
+}
+
+// OptionLength returns a pointer to a length of an option schema, for MinLength and MaxLength.
+func OptionLength(value int) *int {
+	return &value
+}
+
+// OptionFieldError is the error of one field of an option that does not match its schema.
+type OptionFieldError struct {
+	Field   string `json:"field"`   // Path of the field, such as "hour" or "items[0].name"
+	Message string `json:"message"` // Why the field does not match its schema
+}
+
+// OptionValidationError is returned when an action or a reaction option does not match
+// its schema. It lists every field that does not match.
+type OptionValidationError struct {
+	Option string             `json:"option"` // Option that was validated, action or reaction
+	Fields []OptionFieldError `json:"fields"` // Fields that do not match the schema
+}
+
+// Error returns the description of every field that does not match its schema.
+func (err *OptionValidationError) Error() string {
+	messages := make([]string, 0, len(err.Fields))
+	for _, field := range err.Fields {
+		messages = append(messages, field.Field+": "+field.Message)
+	}
+	return fmt.Sprintf("invalid %s option: %s", err.Option, strings.Join(messages, ", "))
+}
+
+// OptionErrorResponse is the response sent when an option does not match its schema.
+type OptionErrorResponse struct {
+	Error  string             `json:"error"`  // Description of the error
+	Option string             `json:"option"` // Option that was validated, action or reaction
+	Fields []OptionFieldError `json:"fields"` // Fields that do not match the schema
+}
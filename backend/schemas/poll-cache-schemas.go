@@ -0,0 +1,15 @@
+package schemas
+
+// PollKey identifies a fetch of the action of an area that the areas polling the same
+// resource of a provider with the same credential share.
+//
+// Fields:
+// - Provider: The name of the provider polled.
+// - Resource: The resource polled, such as the path of the request with its parameters.
+// - Credential: The credential of the request, hashed by the poll cache, or "" for the
+// credentials of the application.
+type PollKey struct {
+	Provider   ServiceName // Name of the provider
+	Resource   string      // Resource polled
+	Credential string      // Credential of the request
+}
@@ -0,0 +1,52 @@
+package schemas
+
+import (
+	"errors"
+	"time"
+)
+
+// CircuitState is the state of the circuit breaker of a provider.
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"    // The requests are sent to the provider
+	CircuitOpen     CircuitState = "open"      // The requests fail without being sent to the provider
+	CircuitHalfOpen CircuitState = "half-open" // A single request is sent to probe the provider
+)
+
+// ProviderMetrics are the counters of the requests sent by this replica to a provider
+// through the shared provider HTTP client, since the replica started.
+//
+// Fields:
+// - Provider: The name of the provider.
+// - Requests: The requests sent to the provider, the retries included.
+// - Failures: The requests that failed with a network error or a 5xx response.
+// - RateLimited: The 429 responses of the provider.
+// - Retries: The requests sent again after a 429 response.
+// - Throttled: The requests delayed or refused because of a rate limit of the provider.
+// - Rejected: The requests refused because the circuit of the provider was open.
+// - AverageLatencyMs: The average duration of the requests, in milliseconds.
+// - Circuit: The state of the circuit breaker of the provider.
+// - RateLimitedUntil: The end of the rate limit of the whole provider, if any.
+type ProviderMetrics struct {
+	Provider         ServiceName  `json:"provider"`                     // Name of the provider
+	Requests         uint64       `json:"requests"`                     // Requests sent
+	Failures         uint64       `json:"failures"`                     // Network errors and 5xx responses
+	RateLimited      uint64       `json:"rate_limited"`                 // 429 responses
+	Retries          uint64       `json:"retries"`                      // Requests sent again after a 429 response
+	Throttled        uint64       `json:"throttled"`                    // Requests delayed or refused by a rate limit
+	Rejected         uint64       `json:"rejected"`                     // Requests refused by the open circuit
+	AverageLatencyMs float64      `json:"average_latency_ms"`           // Average duration of the requests
+	Circuit          CircuitState `json:"circuit"`                      // State of the circuit breaker
+	RateLimitedUntil *time.Time   `json:"rate_limited_until,omitempty"` // End of the rate limit of the provider
+}
+
+// Errors Messages.
+var (
+	ErrRateLimited = errors.New(
+		"rate limited by the provider",
+	) // Error message for a request refused because of a rate limit of the provider
+	ErrCircuitOpen = errors.New(
+		"provider circuit is open",
+	) // Error message for a request refused because the provider keeps failing
+)
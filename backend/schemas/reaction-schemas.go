@@ -13,6 +13,14 @@ type ReactionJSON struct {
 	Description string `json:"description"` // Description of the reaction
 }
 
+// ReactionResult represents the outcome of a reaction that ran successfully.
+// The payload is the typed output of the reaction, available to the next steps of the
+// workflow of the area.
+type ReactionResult struct {
+	Message string          `json:"message"` // Description of what the reaction did
+	Payload json.RawMessage `json:"payload"` // Structured output of the reaction
+}
+
 // Reaction represents a reaction entity with details such as name, description, associated service, and options.
 // It includes metadata like creation and update timestamps.
 //
@@ -23,15 +31,17 @@ type ReactionJSON struct {
 // - ServiceId: Foreign key for the associated Service.
 // - Service: The Service that the reaction belongs to (required).
 // - Option: JSON options for the reaction (required).
+// - OptionSchema: JSON Schema of the options of the reaction.
 // - CreatedAt: Timestamp when the reaction was created.
 // - UpdateAt: Timestamp when the reaction was last updated.
 type Reaction struct {
-	Id          uint64          `gorm:"primaryKey;autoIncrement"           json:"id,omitempty"`                         // Unique identifier for the reaction
-	Name        string          `                                          json:"name"              binding:"required"` // Name of the reaction
-	Description string          `                                          json:"description"       binding:"required"` // Description of the reaction
-	ServiceId   uint64          `                                          json:"-"`                                    // Foreign key for Service
-	Service     Service         `gorm:"foreignKey:ServiceId;references:Id" json:"service,omitempty" binding:"required"` // Service that the reaction belongs to
-	Option      json.RawMessage `gorm:"type:jsonb"                         json:"option"            binding:"required"` // Option of the reaction
-	CreatedAt   time.Time       `gorm:"default:CURRENT_TIMESTAMP"          json:"created_at"`                           // Time when the reaction was created
-	UpdateAt    time.Time       `gorm:"default:CURRENT_TIMESTAMP"          json:"update_at"`                            // Time when the reaction was last updated
+	Id           uint64          `gorm:"primaryKey;autoIncrement"           json:"id,omitempty"`                         // Unique identifier for the reaction
+	Name         string          `                                          json:"name"              binding:"required"` // Name of the reaction
+	Description  string          `                                          json:"description"       binding:"required"` // Description of the reaction
+	ServiceId    uint64          `                                          json:"-"`                                    // Foreign key for Service
+	Service      Service         `gorm:"foreignKey:ServiceId;references:Id" json:"service,omitempty" binding:"required"` // Service that the reaction belongs to
+	Option       json.RawMessage `gorm:"type:jsonb"                         json:"option"            binding:"required"` // Option of the reaction
+	OptionSchema json.RawMessage `gorm:"type:jsonb"                         json:"option_schema"`                        // JSON Schema of the option of the reaction
+	CreatedAt    time.Time       `gorm:"default:CURRENT_TIMESTAMP"          json:"created_at"`                           // Time when the reaction was created
+	UpdateAt     time.Time       `gorm:"default:CURRENT_TIMESTAMP"          json:"update_at"`                            // Time when the reaction was last updated
 }
@@ -0,0 +1,59 @@
+package schemas
+
+import "errors"
+
+// RetryPolicy is the policy applied by the scheduler when a reaction of an area fails with a
+// transient error, such as a timeout or a 5xx response of a service. The reaction is run again
+// after an exponential backoff: the first retry waits InitialBackoffMs, every next retry waits
+// Multiplier times longer, up to MaxBackoffMs, and every wait is randomly shortened or lengthened
+// by up to Jitter of its duration. The reactions failing with a permanent error are not retried.
+// The zero fields, except Jitter, take the values of DefaultRetryPolicy.
+//
+// Fields:
+// - MaxAttempts: The number of times a reaction is run for a trigger, the first run included.
+// - InitialBackoffMs: The wait before the first retry, in milliseconds.
+// - MaxBackoffMs: The longest wait between two retries, in milliseconds.
+// - Multiplier: The factor applied to the wait after every retry.
+// - Jitter: The fraction of the wait, between 0 and 1, by which it is randomly changed.
+type RetryPolicy struct {
+	MaxAttempts      uint    `json:"max_attempts"       binding:"omitempty,min=1,max=10"` // Runs of a reaction for a trigger
+	InitialBackoffMs uint64  `json:"initial_backoff_ms" binding:"omitempty,max=300000"`   // Wait before the first retry
+	MaxBackoffMs     uint64  `json:"max_backoff_ms"     binding:"omitempty,max=300000"`   // Longest wait between two retries
+	Multiplier       float64 `json:"multiplier"         binding:"omitempty,min=1,max=10"` // Factor applied to the wait after every retry
+	Jitter           float64 `json:"jitter"             binding:"omitempty,min=0,max=1"`  // Fraction of the wait randomly changed
+}
+
+// DefaultRetryPolicy is the retry policy of the areas that do not set one.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts:      3,
+	InitialBackoffMs: 1000,
+	MaxBackoffMs:     30000,
+	Multiplier:       2,
+	Jitter:           0.2,
+}
+
+// WithDefaults returns the policy with its zero fields, except Jitter, set to the values of
+// DefaultRetryPolicy. The longest wait is at least the first one.
+func (policy RetryPolicy) WithDefaults() RetryPolicy {
+	if policy.MaxAttempts == 0 {
+		policy.MaxAttempts = DefaultRetryPolicy.MaxAttempts
+	}
+	if policy.InitialBackoffMs == 0 {
+		policy.InitialBackoffMs = DefaultRetryPolicy.InitialBackoffMs
+	}
+	if policy.MaxBackoffMs == 0 {
+		policy.MaxBackoffMs = DefaultRetryPolicy.MaxBackoffMs
+	}
+	if policy.MaxBackoffMs < policy.InitialBackoffMs {
+		policy.MaxBackoffMs = policy.InitialBackoffMs
+	}
+	if policy.Multiplier == 0 {
+		policy.Multiplier = DefaultRetryPolicy.Multiplier
+	}
+	return policy
+}
+
+// Errors Messages.
+var ErrTransient = errors.New(
+	"transient error",
+) // Error wrapped by the errors that may not happen again, such as a 5xx response
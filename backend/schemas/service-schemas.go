@@ -15,6 +15,7 @@ const (
 	Github         ServiceName = "Github"         // Github is a service for Github.
 	Dropbox        ServiceName = "Dropbox"        // Dropbox is a service for Dropbox.
 	Microsoft      ServiceName = "Microsoft"      // Microsoft is a service for Microsoft.
+	Webhook        ServiceName = "Webhook"        // Webhook is a service for inbound webhooks.
 )
 
 type ServiceJSON struct {
@@ -85,8 +85,27 @@ const (
 	SpotifyStorageVariableFalse SpotifyStorageVariable = 2
 )
 
+// SpotifyTrackPayload is the trigger payload of the Spotify music playing action.
+type SpotifyTrackPayload struct {
+	Track   string `json:"track"`   // The name of the track being played
+	Artists string `json:"artists"` // The comma separated names of the artists of the track
+}
+
 // Errors Messages.
 var (
 	ErrSpotifySecretNotSet   = errors.New("SPOTIFY_SECRET is not set")
 	ErrSpotifyClientIdNotSet = errors.New("SPOTIFY_CLIENT_ID is not set")
 )
+
+// SpotifyActionMusicPlayedOptionSchema is the JSON Schema of the SpotifyActionMusicPlayedOption option.
+var SpotifyActionMusicPlayedOptionSchema = NewObjectOptionSchema(map[string]OptionSchema{
+	"name": {
+		Type:        "string",
+		Title:       "Name",
+		Description: "The name of the music",
+		MinLength:   OptionLength(1),
+	},
+}, "name")
+
+// SpotifyReactionSkipMusicSchema is the JSON Schema of the option of the skip music reactions.
+var SpotifyReactionSkipMusicSchema = NewObjectOptionSchema(nil)
@@ -43,3 +43,30 @@ type TimeApiResponse struct {
 	DayOfWeek    string `json:"dayOfWeek"`    // The day of the week
 	DstActive    bool   `json:"dstActive"`    // The daylight saving time status
 }
+
+// TimerPayload is the trigger payload of the timer action.
+type TimerPayload struct {
+	Time string `json:"time"` // The current time, formatted as HH:MM
+	Date string `json:"date"` // The current date, formatted as MM/DD/YYYY
+}
+
+// TimerActionSpecificHourSchema is the JSON Schema of the TimerActionSpecificHour option.
+var TimerActionSpecificHourSchema = NewObjectOptionSchema(map[string]OptionSchema{
+	"hour": {
+		Type:        "integer",
+		Title:       "Hour",
+		Description: "The hour of the action",
+		Minimum:     OptionBound(0),
+		Maximum:     OptionBound(23),
+	},
+	"minute": {
+		Type:        "integer",
+		Title:       "Minute",
+		Description: "The minute of the action",
+		Minimum:     OptionBound(0),
+		Maximum:     OptionBound(59),
+	},
+}, "hour", "minute")
+
+// TimerReactionGiveTimeSchema is the JSON Schema of the TimerReactionGiveTime option.
+var TimerReactionGiveTimeSchema = NewObjectOptionSchema(nil)
@@ -15,6 +15,7 @@ type Token struct {
 	Token        string    `                                                                    json:"token"`         // Token
 	RefreshToken string    `                                                                    json:"refresh_token"` // Refresh token
 	ExpireAt     time.Time `                                                                    json:"expire_at"`     // Time when the token expires
+	NeedsReauth  bool      `gorm:"default:false"                                                json:"needs_reauth"`  // Whether the user must authorize the service again
 	CreatedAt    time.Time `gorm:"default:CURRENT_TIMESTAMP"                                    json:"created_at"`    // Time when the token was created
 	UpdateAt     time.Time `gorm:"default:CURRENT_TIMESTAMP"                                    json:"update_at"`     // Time when the token was last updated
 }
@@ -33,4 +34,19 @@ var (
 	ErrTokenBelongToUser = errors.New(
 		"token belongs to user",
 	) // Error message for token belongs to user
+	ErrTokenNotFound = errors.New(
+		"token not found",
+	) // Error message for token not found
+	ErrUnauthorized = errors.New(
+		"unauthorized by the service",
+	) // Error message for a service call rejected with a 401 status
+	ErrRefreshTokenRejected = errors.New(
+		"refresh token rejected by the service",
+	) // Error message for a refresh token rejected by the service
+	ErrTokenNeedsReauth = errors.New(
+		"token needs to be authorized again",
+	) // Error message for a token that can not be refreshed anymore
+	ErrTokenNotRefreshable = errors.New(
+		"token of the service can not be refreshed",
+	) // Error message for a service without refresh tokens
 )
@@ -0,0 +1,34 @@
+package schemas
+
+// TriggerFilterOperator is the operator of a condition of a trigger filter.
+type TriggerFilterOperator string
+
+const (
+	FilterAnd            TriggerFilterOperator = "and"      // Every condition of the group matches
+	FilterOr             TriggerFilterOperator = "or"       // At least one condition of the group matches
+	FilterEquals         TriggerFilterOperator = "equals"   // The field equals the value
+	FilterContains       TriggerFilterOperator = "contains" // The field contains the value
+	FilterRegex          TriggerFilterOperator = "regex"    // The field matches the regular expression of the value
+	FilterGreaterThan    TriggerFilterOperator = "gt"       // The field is greater than the value
+	FilterGreaterOrEqual TriggerFilterOperator = "gte"      // The field is greater than or equal to the value
+	FilterLessThan       TriggerFilterOperator = "lt"       // The field is less than the value
+	FilterLessOrEqual    TriggerFilterOperator = "lte"      // The field is less than or equal to the value
+)
+
+// TriggerFilter is a condition evaluated against the payload of a trigger before the reactions
+// of an area run. The triggers that do not match the filter of their area are skipped.
+// A condition is either a group, and or or, of other conditions, or a comparison of a field of
+// the payload with a value. Nested payload fields are reached with dotted names, such as
+// "author.name".
+//
+// Fields:
+// - Operator: The operator of the condition.
+// - Field: The field of the payload compared by a comparison.
+// - Value: The value the field is compared with by a comparison.
+// - Conditions: The conditions of a group.
+type TriggerFilter struct {
+	Operator   TriggerFilterOperator `json:"operator"`             // Operator of the condition
+	Field      string                `json:"field,omitempty"`      // Field of the payload compared
+	Value      any                   `json:"value,omitempty"`      // Value the field is compared with
+	Conditions []TriggerFilter       `json:"conditions,omitempty"` // Conditions of a group
+}
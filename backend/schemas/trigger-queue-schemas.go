@@ -0,0 +1,46 @@
+package schemas
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// QueuedTrigger is a trigger reported by the action of an area, waiting in the persistent
+// trigger queue for the reaction workers to run the workflow of the area.
+// A reaction worker claims a trigger for a lease, during which the other workers skip it,
+// and acknowledges it by deleting it once its workflow run is over. A trigger whose worker
+// stops or crashes before is delivered again once its lease expires, so every trigger is
+// delivered at least once. The progress of the workflow run is saved on the trigger after
+// every step, so that a delivery resumes the run with the first step that did not complete.
+//
+// Fields:
+// - Id: Unique identifier for the queued trigger.
+// - AreaId: Foreign key for the associated Area.
+// - Area: The Area that the trigger belongs to, with a cascade delete constraint.
+// - Message: The message of the trigger reported by the action.
+// - Payload: The structured payload of the trigger reported by the action.
+// - ReactionPosition: The position of the first reaction of the area that did not complete for the trigger.
+// - WorkflowRunId: The ID of the workflow run of the trigger, zero until a worker starts it.
+// - Deliveries: The number of times the trigger was claimed by a reaction worker.
+// - LockedUntil: The end of the lease of the worker that claimed the trigger, in the past when the trigger is available.
+// - CreatedAt: Timestamp for when the trigger was queued, with a default value of the current timestamp.
+// - UpdateAt: Timestamp for when the trigger was last updated, with a default value of the current timestamp.
+type QueuedTrigger struct {
+	Id               uint64          `gorm:"primaryKey;autoIncrement"                                     json:"id,omitempty"`      // Unique identifier for the queued trigger
+	AreaId           uint64          `gorm:"index"                                                        json:"area_id"`           // Foreign key for Area
+	Area             Area            `gorm:"foreignKey:AreaId;references:Id;constraint:OnDelete:CASCADE;" json:"-"`                 // Area that the trigger belongs to
+	Message          string          `                                                                    json:"message"`           // Message of the trigger
+	Payload          json.RawMessage `gorm:"type:jsonb"                                                   json:"payload"`           // Payload of the trigger
+	ReactionPosition int             `                                                                    json:"reaction_position"` // Position of the first reaction to run
+	WorkflowRunId    uint64          `                                                                    json:"workflow_run_id"`   // Workflow run of the trigger
+	Deliveries       uint            `                                                                    json:"deliveries"`        // Number of claims of the trigger
+	LockedUntil      time.Time       `gorm:"index"                                                        json:"locked_until"`      // End of the lease of the claiming worker
+	CreatedAt        time.Time       `gorm:"default:CURRENT_TIMESTAMP"                                    json:"created_at"`        // Time when the trigger was queued
+	UpdateAt         time.Time       `gorm:"default:CURRENT_TIMESTAMP"                                    json:"update_at"`         // Time when the trigger was last updated
+}
+
+// Errors Messages.
+var ErrTriggerLeaseLost = errors.New(
+	"the lease of the trigger was lost",
+) // Error message for a trigger claimed again or acknowledged since its claim
@@ -0,0 +1,90 @@
+package schemas
+
+import (
+	"errors"
+	"time"
+)
+
+type WebhookAction string
+
+const (
+	ReceiveWebhook WebhookAction = "ReceiveWebhook" // JSON posted to the URL of the area
+)
+
+// WebhookVerification is the way the deliveries of the URL of an area are authenticated.
+type WebhookVerification string
+
+const (
+	WebhookVerificationNone   WebhookVerification = "none"   // Any delivery is accepted
+	WebhookVerificationSecret WebhookVerification = "secret" // The secret is sent in a header
+	WebhookVerificationHmac   WebhookVerification = "hmac"   // The body is signed with the secret
+)
+
+const (
+	WebhookSecretHeader    = "X-Webhook-Secret"    // Header of the secret of a delivery
+	WebhookSignatureHeader = "X-Webhook-Signature" // Header of the signature of a delivery
+	WebhookSignaturePrefix = "sha256="             // Prefix of the signature in its header
+)
+
+// WebhookEndpoint is the URL of an area whose action is ReceiveWebhook. The JSON objects
+// posted to it trigger the area right away, each of them being the payload of its trigger.
+//
+// Fields:
+// - Id: Unique identifier for the endpoint.
+// - AreaId: Foreign key for the Area triggered by the deliveries of the endpoint.
+// - Area: The Area of the endpoint, with a cascade delete constraint.
+// - Token: The random token of the endpoint, the last path segment of its URL.
+// - Secret: The secret authenticating the deliveries, when the option of the area asks so.
+// - CreatedAt: Timestamp for when the endpoint was created, with a default value of the current timestamp.
+type WebhookEndpoint struct {
+	Id        uint64    `gorm:"primaryKey;autoIncrement"                                     json:"id,omitempty"` // Unique identifier for the endpoint
+	AreaId    uint64    `gorm:"uniqueIndex"                                                  json:"-"`            // Foreign key for Area
+	Area      Area      `gorm:"foreignKey:AreaId;references:Id;constraint:OnDelete:CASCADE;" json:"-"`            // Area of the endpoint
+	Token     string    `gorm:"uniqueIndex"                                                  json:"-"`            // Token of the URL of the endpoint
+	Secret    string    `                                                                    json:"-"`            // Secret authenticating the deliveries
+	CreatedAt time.Time `gorm:"default:CURRENT_TIMESTAMP"                                    json:"created_at"`   // Time when the endpoint was created
+}
+
+// WebhookEndpointInfo is the URL of an area, as given to its user to set up the sender of the
+// deliveries.
+type WebhookEndpointInfo struct {
+	URL          string              `json:"url"`          // URL the JSON objects are posted to
+	Verification WebhookVerification `json:"verification"` // Way the deliveries are authenticated
+	Secret       string              `json:"secret"`       // Secret authenticating the deliveries
+}
+
+// WebhookActionReceive is the option of the ReceiveWebhook action.
+type WebhookActionReceive struct {
+	Verification WebhookVerification `json:"verification"` // Way the deliveries are authenticated
+}
+
+// WebhookActionReceiveSchema is the JSON Schema of the WebhookActionReceive option.
+var WebhookActionReceiveSchema = NewObjectOptionSchema(map[string]OptionSchema{
+	"verification": {
+		Type:  "string",
+		Title: "Verification",
+		Description: "How the deliveries are authenticated: not at all, by the secret sent in " +
+			"the " + WebhookSecretHeader + " header, or by the HMAC-SHA256 signature of the " +
+			"body with the secret sent in the " + WebhookSignatureHeader + " header",
+		Enum: []any{
+			WebhookVerificationNone, WebhookVerificationSecret, WebhookVerificationHmac,
+		},
+		Default: WebhookVerificationNone,
+	},
+})
+
+// Errors Messages.
+var (
+	ErrWebhookEndpointNotFound = errors.New(
+		"webhook endpoint not found",
+	) // Error message for webhook endpoint not found
+	ErrNotWebhookArea = errors.New(
+		"the action of the area is not a webhook",
+	) // Error message for the URL of an area whose action is not ReceiveWebhook
+	ErrInvalidWebhookPayload = errors.New(
+		"the body of a webhook delivery must be a JSON object",
+	) // Error message for a webhook delivery whose body is not a JSON object
+	ErrInvalidWebhookSecret = errors.New(
+		"invalid webhook secret",
+	) // Error message for a webhook delivery without the secret of its endpoint
+)
@@ -0,0 +1,150 @@
+package schemas
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// WorkflowRunStatus is the state of a run of the workflow of an area.
+type WorkflowRunStatus string
+
+const (
+	WorkflowRunRunning WorkflowRunStatus = "running" // The steps are running, or were interrupted and wait to be replayed.
+	WorkflowRunSuccess WorkflowRunStatus = "success" // Every step succeeded.
+	WorkflowRunFailure WorkflowRunStatus = "failure" // At least one step failed.
+)
+
+// WorkflowStepStatus is the state of one step of a run of the workflow of an area.
+type WorkflowStepStatus string
+
+const (
+	WorkflowStepPending WorkflowStepStatus = "pending" // The step did not run yet.
+	WorkflowStepSuccess WorkflowStepStatus = "success" // The step succeeded.
+	WorkflowStepFailure WorkflowStepStatus = "failure" // The step failed.
+	WorkflowStepSkipped WorkflowStepStatus = "skipped" // The step did not run because a previous step failed.
+)
+
+// WorkflowStep is the state of one step of a run of the workflow of an area.
+// The steps of a workflow are the reactions of the area, run in order for each trigger.
+// The output of a step is the typed payload of its reaction result, with the message of the
+// result as its message field when the payload has none. The reaction options of the later
+// steps reach it with {{steps.N.field}} placeholders, N being the position of the step.
+//
+// Fields:
+// - Position: The position of the step in the reactions of the area.
+// - ReactionName: The name of the reaction of the step.
+// - Status: The state of the step, pending, success, failure or skipped.
+// - Output: The output of the step, set once it succeeded.
+// - Error: The error message of the step, empty unless it failed.
+// - StartedAt: Timestamp for when the step started, zero if it did not run.
+// - FinishedAt: Timestamp for when the step finished, zero if it did not run.
+type WorkflowStep struct {
+	Position     int                `json:"position"`         // Position of the step in the area
+	ReactionName string             `json:"reaction_name"`    // Name of the reaction of the step
+	Status       WorkflowStepStatus `json:"status"`           // State of the step
+	Output       json.RawMessage    `json:"output,omitempty"` // Output of the step
+	Error        string             `json:"error,omitempty"`  // Error message of the step
+	StartedAt    time.Time          `json:"started_at"`       // Time when the step started
+	FinishedAt   time.Time          `json:"finished_at"`      // Time when the step finished
+}
+
+// WorkflowSteps is the list of the steps of a workflow run, stored as a JSON column.
+type WorkflowSteps []WorkflowStep
+
+// Value encodes the steps as JSON to store them in the database.
+func (steps WorkflowSteps) Value() (driver.Value, error) {
+	if steps == nil {
+		return "[]", nil
+	}
+	encoded, err := json.Marshal([]WorkflowStep(steps))
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode workflow steps: %w", err)
+	}
+	return string(encoded), nil
+}
+
+// Scan decodes the steps stored as JSON in the database.
+func (steps *WorkflowSteps) Scan(value any) error {
+	var data []byte
+	switch typed := value.(type) {
+	case nil:
+		*steps = nil
+		return nil
+	case []byte:
+		data = typed
+	case string:
+		data = []byte(typed)
+	default:
+		return fmt.Errorf("unable to decode workflow steps of type %T", value)
+	}
+	err := json.Unmarshal(data, (*[]WorkflowStep)(steps))
+	if err != nil {
+		return fmt.Errorf("unable to decode workflow steps: %w", err)
+	}
+	return nil
+}
+
+// Outputs returns the outputs of the steps indexed by position, nil for the steps
+// that did not succeed.
+func (steps WorkflowSteps) Outputs() []json.RawMessage {
+	outputs := make([]json.RawMessage, len(steps))
+	for index, step := range steps {
+		if step.Status == WorkflowStepSuccess {
+			outputs[index] = step.Output
+		}
+	}
+	return outputs
+}
+
+// WorkflowRun is the run of the workflow of an area for one trigger of its action.
+// The workflow of an area is its action followed by its reactions, the steps, run in order.
+// The run tracks the state of every step, so that the interrupted runs are resumed with the
+// outputs of the steps that already ran.
+//
+// Fields:
+// - Id: Unique identifier for the workflow run.
+// - AreaId: Foreign key for the associated Area.
+// - Area: The Area that the run belongs to, with a cascade delete constraint.
+// - TriggerMessage: The description of the trigger reported by the action.
+// - TriggerPayload: The structured payload of the trigger reported by the action.
+// - Status: The state of the run, running, success or failure.
+// - Steps: The state of every step of the run, ordered by position.
+// - StartedAt: Timestamp for when the run started.
+// - FinishedAt: Timestamp for when the run finished, zero while it is running.
+// - CreatedAt: Timestamp for when the workflow run was created, with a default value of the current timestamp.
+// - UpdateAt: Timestamp for when the workflow run was last updated, with a default value of the current timestamp.
+type WorkflowRun struct {
+	Id             uint64            `gorm:"primaryKey;autoIncrement"                                     json:"id,omitempty"`    // Unique identifier for the workflow run
+	AreaId         uint64            `gorm:"index"                                                        json:"area_id"`         // Foreign key for Area
+	Area           Area              `gorm:"foreignKey:AreaId;references:Id;constraint:OnDelete:CASCADE;" json:"-"`               // Area that the run belongs to
+	TriggerMessage string            `                                                                    json:"trigger_message"` // Description of the trigger reported by the action
+	TriggerPayload json.RawMessage   `gorm:"type:jsonb"                                                   json:"trigger_payload"` // Payload of the trigger reported by the action
+	Status         WorkflowRunStatus `gorm:"index"                                                        json:"status"`          // State of the run
+	Steps          WorkflowSteps     `gorm:"type:jsonb"                                                   json:"steps"`           // State of every step of the run
+	StartedAt      time.Time         `                                                                    json:"started_at"`      // Time when the run started
+	FinishedAt     time.Time         `                                                                    json:"finished_at"`     // Time when the run finished
+	CreatedAt      time.Time         `gorm:"default:CURRENT_TIMESTAMP;index"                              json:"created_at"`      // Time when the workflow run was created
+	UpdateAt       time.Time         `gorm:"default:CURRENT_TIMESTAMP"                                    json:"update_at"`       // Time when the workflow run was last updated
+}
+
+// WorkflowRunFilter is the query of the workflow runs of an area.
+// The runs are returned newest first, one page at a time, with the same page sizes as
+// the area results.
+//
+// Fields:
+// - Status: Keeps only the runs with this status, all statuses when empty.
+// - Page: The page to return, starting at 1.
+// - PageSize: The number of runs per page, at most AreaResultMaxPageSize.
+type WorkflowRunFilter struct {
+	Status   WorkflowRunStatus `form:"status"    binding:"omitempty,oneof=running success failure"` // Status of the runs
+	Page     int               `form:"page"      binding:"omitempty,min=1"`                         // Page to return
+	PageSize int               `form:"page_size" binding:"omitempty,min=1"`                         // Number of runs per page
+}
+
+// Errors Messages.
+var ErrWorkflowRunNotFound = errors.New(
+	"workflow run not found",
+) // Error message for workflow run not found
@@ -102,7 +102,9 @@ func (service *actionService) GetAllServicesByServiceId(
 // checks if each service implements the ServiceAction interface, and retrieves
 // the service action information. For each action, it attempts to find an existing
 // action by name in the repository. If no action is found, it saves the new action
-// to the repository. Errors encountered during these operations are logged to the console.
+// to the repository, otherwise it updates the option schema of the existing action so
+// that it follows the one declared by the service. Errors encountered during these
+// operations are logged to the console.
 func (service *actionService) SaveAllAction() {
 	for _, services := range service.serviceService.GetServices() {
 		if serviceAction, ok := services.(ServiceAction); ok {
@@ -117,6 +119,12 @@ func (service *actionService) SaveAllAction() {
 					if err != nil {
 						fmt.Println("Error when save action")
 					}
+				} else if optionChanged(actionByName[0].OptionSchema, action.OptionSchema) {
+					actionByName[0].OptionSchema = action.OptionSchema
+					err = service.repository.Update(actionByName[0])
+					if err != nil {
+						fmt.Println("Error when update action")
+					}
 				}
 			}
 		} else {
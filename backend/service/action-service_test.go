@@ -1,6 +1,7 @@
 package service_test
 
 import (
+	"encoding/json"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -97,6 +98,28 @@ func TestSaveAllAction(t *testing.T) {
 	mockRepo.AssertCalled(t, "Save", expectedActions[0])
 }
 
+func TestSaveAllActionKeepsSchemaReformattedByDatabase(t *testing.T) {
+	mockRepo := new(test.MockActionRepository)
+	mockServiceService := new(test.MockServiceService)
+	mockServiceAction := new(MockServiceAction)
+
+	mockServiceAction.On("GetServiceActionInfo").Return([]schemas.Action{{
+		Name:         "Test Action",
+		OptionSchema: json.RawMessage(`{"type":"object","required":["hour"]}`),
+	}})
+	mockServiceService.On("GetServices").Return([]interface{}{mockServiceAction})
+	// jsonb stores the keys of the objects in its own order and spacing
+	mockRepo.On("FindByName", "Test Action").Return([]schemas.Action{{
+		Name:         "Test Action",
+		OptionSchema: json.RawMessage(`{"required": ["hour"], "type": "object"}`),
+	}}, nil)
+
+	service := service.NewActionService(mockRepo, mockServiceService)
+	service.SaveAllAction()
+
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
 func TestGetActionsInfo(t *testing.T) {
 	mockRepo := new(test.MockActionRepository)
 	mockServiceService := new(test.MockServiceService)
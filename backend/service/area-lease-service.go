@@ -0,0 +1,300 @@
+package service
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"area/repository"
+	"area/schemas"
+	"area/tools"
+)
+
+// AreaLeaseService defines the interface of the ownership of the areas by the replicas of the
+// backend, so that every area is checked by exactly one replica at a time.
+// Every replica is a scheduler node, recording its heartbeats in the database. The enabled areas
+// are spread over the live nodes with rendezvous hashing, and a node only checks the areas
+// whose lease it holds. A node joining takes its share of the areas over once their owners
+// released them, and the areas of a dead node are taken over once their leases expired.
+type AreaLeaseService interface {
+	// NodeId returns the ID of the scheduler node of this replica.
+	NodeId() string
+
+	// Owns reports whether this node holds the lease of the area.
+	Owns(areaId uint64) bool
+
+	// Rebalance records the heartbeat of the node, renews its leases, and acquires or
+	// releases the leases of the areas to match the live nodes.
+	Rebalance() (acquired []uint64, released []uint64, err error)
+
+	// Leave releases the leases of the node and removes it from the live nodes.
+	Leave() error
+
+	// GetAreaOwnership returns the distribution of the enabled areas over the nodes.
+	GetAreaOwnership() (schemas.AreaOwnership, error)
+}
+
+// areaLeaseService is the implementation of AreaLeaseService.
+//
+// Fields:
+//   - repository: Repository of the scheduler nodes and of the area leases.
+//   - areaRepository: Repository used to find the enabled areas.
+//   - node: The scheduler node of this replica.
+//   - mutex: Guards owned.
+//   - owned: The end of the leases held by the node by area ID, as seen by the node.
+type areaLeaseService struct {
+	repository     repository.AreaLeaseRepository
+	areaRepository repository.AreaRepository
+	node           schemas.SchedulerNode
+	mutex          sync.Mutex
+	owned          map[uint64]time.Time
+}
+
+// NewAreaLeaseService creates a new instance of AreaLeaseService for the scheduler node of
+// this replica. The ID of the node is the hostname of the replica followed by a random
+// suffix, so that a restarted replica is a new node.
+//
+// Parameters:
+//   - repository: an instance of AreaLeaseRepository to store the nodes and the leases.
+//   - areaRepository: an instance of AreaRepository to find the enabled areas.
+//
+// Returns:
+//   - AreaLeaseService: a new instance of AreaLeaseService.
+func NewAreaLeaseService(
+	repository repository.AreaLeaseRepository,
+	areaRepository repository.AreaRepository,
+) AreaLeaseService {
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "backend"
+	}
+	suffix := make([]byte, 4)
+	_, err = rand.Read(suffix)
+	if err != nil {
+		panic(fmt.Errorf("unable to generate scheduler node id: %w", err))
+	}
+	return &areaLeaseService{
+		repository:     repository,
+		areaRepository: areaRepository,
+		node: schemas.SchedulerNode{
+			Id:        hostname + "-" + hex.EncodeToString(suffix),
+			Hostname:  hostname,
+			StartedAt: time.Now(),
+		},
+		owned: make(map[uint64]time.Time),
+	}
+}
+
+// NodeId returns the ID of the scheduler node of this replica.
+func (service *areaLeaseService) NodeId() string {
+	return service.node.Id
+}
+
+// Owns reports whether this node holds the lease of the area. A lease the node could not
+// renew in time is not owned anymore, even before another node takes it over.
+func (service *areaLeaseService) Owns(areaId uint64) bool {
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	expiresAt, ok := service.owned[areaId]
+	return ok && time.Now().Before(expiresAt)
+}
+
+// isAlive reports whether the node sent a heartbeat in the last SchedulerNodeTimeout seconds.
+func isAlive(node schemas.SchedulerNode, now time.Time) bool {
+	return node.HeartbeatAt.After(now.Add(-time.Second * schemas.SchedulerNodeTimeout))
+}
+
+// Rebalance records the heartbeat of the node and renews its leases, then places every
+// enabled area on a live node with rendezvous hashing. The node releases the leases of the
+// areas placed on other nodes and of the areas that are not enabled anymore, and acquires the
+// leases of the areas placed on it that no other node holds. An area placed on this node
+// whose lease is still held by another node is acquired on a later call, once the other node
+// released it or its lease expired.
+//
+// Returns:
+//   - acquired: The IDs of the areas the node owns since this call.
+//   - released: The IDs of the areas the node does not own anymore since this call.
+//   - err: An error if the heartbeat or the renewal of the leases fails, the leases of the
+//     node being kept until they expire.
+func (service *areaLeaseService) Rebalance() (acquired []uint64, released []uint64, err error) {
+	startedAt := time.Now()
+	err = service.repository.Heartbeat(service.node)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't record scheduler node heartbeat: %w", err)
+	}
+	err = service.repository.Renew(service.node.Id, time.Second*schemas.AreaLeaseDuration)
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't renew area leases: %w", err)
+	}
+	expiresAt := startedAt.Add(time.Second * schemas.AreaLeaseDuration)
+	// the heartbeats and the leases are timed by the clock of the database, not the one of the node
+	now, err := service.repository.Now()
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't find database time: %w", err)
+	}
+
+	nodes, err := service.repository.FindNodes()
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't find scheduler nodes: %w", err)
+	}
+	nodeIds := []string{service.node.Id}
+	for _, node := range nodes {
+		if node.Id != service.node.Id && isAlive(node, now) {
+			nodeIds = append(nodeIds, node.Id)
+		}
+	}
+	areaIds, err := service.areaRepository.FindEnabledIds()
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't find enabled areas: %w", err)
+	}
+	leases, err := service.repository.FindLeases()
+	if err != nil {
+		return nil, nil, fmt.Errorf("can't find area leases: %w", err)
+	}
+	leasesByArea := make(map[uint64]schemas.AreaLease, len(leases))
+	for _, lease := range leases {
+		leasesByArea[lease.AreaId] = lease
+	}
+
+	owned := make(map[uint64]time.Time)
+	for _, areaId := range areaIds {
+		lease, leased := leasesByArea[areaId]
+		delete(leasesByArea, areaId)
+		held := leased && lease.NodeId == service.node.Id
+		if tools.RendezvousOwner(nodeIds, areaId) != service.node.Id {
+			if held {
+				service.release(areaId)
+			}
+			continue
+		}
+		if !held {
+			if leased && lease.ExpiresAt.After(now) {
+				continue
+			}
+			ok, err := service.repository.Acquire(
+				areaId,
+				service.node.Id,
+				time.Second*schemas.AreaLeaseDuration,
+			)
+			if err != nil {
+				println("error acquire area lease: " + err.Error())
+				continue
+			}
+			if !ok {
+				continue
+			}
+		}
+		owned[areaId] = expiresAt
+	}
+	// the leases left are the ones of the areas deleted or disabled since
+	for areaId, lease := range leasesByArea {
+		if lease.NodeId == service.node.Id {
+			service.release(areaId)
+		}
+	}
+
+	service.mutex.Lock()
+	defer service.mutex.Unlock()
+	for areaId := range owned {
+		if _, ok := service.owned[areaId]; !ok {
+			acquired = append(acquired, areaId)
+		}
+	}
+	for areaId := range service.owned {
+		if _, ok := owned[areaId]; !ok {
+			released = append(released, areaId)
+		}
+	}
+	service.owned = owned
+	slices.Sort(acquired)
+	slices.Sort(released)
+	return acquired, released, nil
+}
+
+// release gives up the lease of the area held by the node.
+func (service *areaLeaseService) release(areaId uint64) {
+	err := service.repository.Release(areaId, service.node.Id)
+	if err != nil {
+		println("error release area lease: " + err.Error())
+	}
+}
+
+// Leave releases all the leases of the node and removes it from the live nodes, so that the
+// other nodes take its areas over on their next rebalance, without waiting for the leases
+// to expire.
+//
+// Returns:
+//   - error: An error if the node can not be removed.
+func (service *areaLeaseService) Leave() error {
+	service.mutex.Lock()
+	service.owned = make(map[uint64]time.Time)
+	service.mutex.Unlock()
+	err := service.repository.DeleteNode(service.node.Id)
+	if err != nil {
+		return fmt.Errorf("can't leave the scheduler nodes: %w", err)
+	}
+	return nil
+}
+
+// GetAreaOwnership returns the known scheduler nodes with the areas whose lease they hold,
+// and the enabled areas that no node owns, because their lease expired or was released and
+// their new owner did not acquire it yet.
+//
+// Returns:
+//   - schemas.AreaOwnership: The distribution of the areas over the nodes.
+//   - error: An error if the nodes, the leases or the areas can not be found.
+func (service *areaLeaseService) GetAreaOwnership() (schemas.AreaOwnership, error) {
+	nodes, err := service.repository.FindNodes()
+	if err != nil {
+		return schemas.AreaOwnership{}, fmt.Errorf("can't find scheduler nodes: %w", err)
+	}
+	leases, err := service.repository.FindLeases()
+	if err != nil {
+		return schemas.AreaOwnership{}, fmt.Errorf("can't find area leases: %w", err)
+	}
+	areaIds, err := service.areaRepository.FindEnabledIds()
+	if err != nil {
+		return schemas.AreaOwnership{}, fmt.Errorf("can't find enabled areas: %w", err)
+	}
+
+	now, err := service.repository.Now()
+	if err != nil {
+		return schemas.AreaOwnership{}, fmt.Errorf("can't find database time: %w", err)
+	}
+	ownership := schemas.AreaOwnership{
+		NodeId:         service.node.Id,
+		Nodes:          make([]schemas.SchedulerNodeOwnership, 0, len(nodes)),
+		UnownedAreaIds: []uint64{},
+	}
+	nodeIndex := make(map[string]int, len(nodes))
+	for index, node := range nodes {
+		nodeIndex[node.Id] = index
+		ownership.Nodes = append(ownership.Nodes, schemas.SchedulerNodeOwnership{
+			Node:    node,
+			Alive:   isAlive(node, now),
+			AreaIds: []uint64{},
+		})
+	}
+	owned := make(map[uint64]bool, len(leases))
+	for _, lease := range leases {
+		if !lease.ExpiresAt.After(now) {
+			continue
+		}
+		index, ok := nodeIndex[lease.NodeId]
+		if !ok {
+			continue
+		}
+		ownership.Nodes[index].AreaIds = append(ownership.Nodes[index].AreaIds, lease.AreaId)
+		owned[lease.AreaId] = true
+	}
+	for _, areaId := range areaIds {
+		if !owned[areaId] {
+			ownership.UnownedAreaIds = append(ownership.UnownedAreaIds, areaId)
+		}
+	}
+	return ownership, nil
+}
@@ -0,0 +1,76 @@
+package service_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/repository"
+	"area/service"
+	"area/test"
+)
+
+func newSharedAreaLeaseRepository(t *testing.T) repository.AreaLeaseRepository {
+	t.Helper()
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+	// every connection to an in-memory database opens a new database
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	return repository.NewAreaLeaseRepository(db)
+}
+
+func TestAreaLeaseServiceSharesAreasBetweenNodes(t *testing.T) {
+	// with random node ids, enough areas for both nodes to get some
+	areaIds := []uint64{}
+	for areaId := range uint64(40) {
+		areaIds = append(areaIds, areaId+1)
+	}
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindEnabledIds").Return(areaIds, nil)
+	leaseRepository := newSharedAreaLeaseRepository(t)
+	first := service.NewAreaLeaseService(leaseRepository, mockRepo)
+	second := service.NewAreaLeaseService(leaseRepository, mockRepo)
+
+	acquired, released, err := first.Rebalance()
+	assert.NoError(t, err)
+	assert.Equal(t, areaIds, acquired)
+	assert.Empty(t, released)
+
+	// the second node waits for the first one to release its share of the areas
+	acquired, _, err = second.Rebalance()
+	assert.NoError(t, err)
+	assert.Empty(t, acquired)
+	_, released, err = first.Rebalance()
+	assert.NoError(t, err)
+	assert.NotEmpty(t, released)
+	acquired, _, err = second.Rebalance()
+	assert.NoError(t, err)
+	assert.Equal(t, released, acquired)
+
+	for _, areaId := range areaIds {
+		assert.NotEqual(t, first.Owns(areaId), second.Owns(areaId), "area %d", areaId)
+	}
+	ownership, err := first.GetAreaOwnership()
+	assert.NoError(t, err)
+	assert.Equal(t, first.NodeId(), ownership.NodeId)
+	assert.Len(t, ownership.Nodes, 2)
+	assert.Empty(t, ownership.UnownedAreaIds)
+	for _, node := range ownership.Nodes {
+		assert.True(t, node.Alive)
+		if node.Node.Id == second.NodeId() {
+			assert.Equal(t, acquired, node.AreaIds)
+		}
+	}
+
+	// the first node takes the areas of the second one over once it left
+	assert.NoError(t, second.Leave())
+	acquired, _, err = first.Rebalance()
+	assert.NoError(t, err)
+	assert.Equal(t, released, acquired)
+	for _, areaId := range areaIds {
+		assert.True(t, first.Owns(areaId))
+		assert.False(t, second.Owns(areaId))
+	}
+}
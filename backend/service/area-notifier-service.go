@@ -0,0 +1,143 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"area/repository"
+	"area/schemas"
+)
+
+// areaNotifier is the AreaScheduler of the API replicas, which do not check the areas.
+// The area changes and the queued triggers are only persisted and notified to the workers
+// through the database.
+//
+// Fields:
+//   - triggerQueueRepository: Repository of the persistent queue of the triggers.
+//   - notificationRepository: Repository used to notify the workers.
+//   - areaResultService: Service used to save the triggers filtered out.
+type areaNotifier struct {
+	triggerQueueRepository repository.TriggerQueueRepository
+	notificationRepository repository.NotificationRepository
+	areaResultService      AreaResultService
+}
+
+// NewAreaNotifier creates the AreaScheduler of an API replica. Scheduling, rescheduling or
+// stopping an area notifies the workers of the change of the area, and a queued trigger
+// wakes their reaction workers up. Start and Stop do nothing, and no area is ever scheduled
+// by the API replica itself.
+//
+// Parameters:
+//   - triggerQueueRepository: an instance of TriggerQueueRepository to queue the triggers.
+//   - notificationRepository: an instance of NotificationRepository to notify the workers.
+//   - areaResultService: an instance of AreaResultService to save the triggers filtered out.
+//
+// Returns:
+//   - AreaScheduler: a new instance of AreaScheduler notifying the workers.
+func NewAreaNotifier(
+	triggerQueueRepository repository.TriggerQueueRepository,
+	notificationRepository repository.NotificationRepository,
+	areaResultService AreaResultService,
+) AreaScheduler {
+	return &areaNotifier{
+		triggerQueueRepository: triggerQueueRepository,
+		notificationRepository: notificationRepository,
+		areaResultService:      areaResultService,
+	}
+}
+
+// publishAreaChange notifies the workers that the area changed.
+// A failed notification is only logged, the workers applying the change on their next
+// check or rebalance of the area.
+func publishAreaChange(
+	notificationRepository repository.NotificationRepository,
+	nodeId string,
+	areaId uint64,
+) {
+	payload, err := json.Marshal(schemas.AreaChange{AreaId: areaId, NodeId: nodeId})
+	if err != nil {
+		println("error encode area change: " + err.Error())
+		return
+	}
+	err = notificationRepository.Notify(schemas.AreaChangeChannel, string(payload))
+	if err != nil {
+		println("error notify area change: " + err.Error())
+	}
+}
+
+// publishQueuedTrigger wakes the reaction workers of the workers up for a queued trigger.
+// A failed notification is only logged, the reaction workers claiming the trigger on their
+// next poll of the queue.
+func publishQueuedTrigger(
+	notificationRepository repository.NotificationRepository,
+	trigger schemas.QueuedTrigger,
+) {
+	payload := strconv.FormatUint(trigger.AreaId, 10)
+	err := notificationRepository.Notify(schemas.TriggerQueueChannel, payload)
+	if err != nil {
+		println("error notify queued trigger: " + err.Error())
+	}
+}
+
+// Start does nothing, the API replica does not check the areas.
+func (notifier *areaNotifier) Start() {}
+
+// Stop does nothing, the API replica does not check the areas.
+func (notifier *areaNotifier) Stop(ctx context.Context) error {
+	return nil
+}
+
+// StartArea notifies the workers of the new area.
+func (notifier *areaNotifier) StartArea(area schemas.Area) {
+	publishAreaChange(notifier.notificationRepository, "", area.Id)
+}
+
+// StopArea notifies the workers of the stopped area.
+func (notifier *areaNotifier) StopArea(areaId uint64) {
+	publishAreaChange(notifier.notificationRepository, "", areaId)
+}
+
+// RescheduleArea notifies the workers of the updated area.
+func (notifier *areaNotifier) RescheduleArea(area schemas.Area) {
+	publishAreaChange(notifier.notificationRepository, "", area.Id)
+}
+
+// CheckArea does nothing, the API replica does not check the areas.
+func (notifier *areaNotifier) CheckArea(areaId uint64) {}
+
+// IsScheduled always reports false, the areas being scheduled by the workers.
+func (notifier *areaNotifier) IsScheduled(areaId uint64) bool {
+	return false
+}
+
+// EnqueueTrigger saves the trigger in the persistent trigger queue and wakes the reaction
+// workers of the workers up.
+//
+// Parameters:
+//   - trigger: The trigger to queue.
+//
+// Returns:
+//   - error: An error if the trigger can not be queued.
+func (notifier *areaNotifier) EnqueueTrigger(trigger schemas.QueuedTrigger) error {
+	_, err := notifier.triggerQueueRepository.Enqueue(trigger)
+	if err != nil {
+		return fmt.Errorf("unable to enqueue trigger: %w", err)
+	}
+	publishQueuedTrigger(notifier.notificationRepository, trigger)
+	return nil
+}
+
+// TriggerArea queues the trigger of the area for the reaction workers of the workers if it
+// matches the trigger filter of the area.
+//
+// Parameters:
+//   - area: The area whose action reported the trigger.
+//   - trigger: The trigger pushed by the provider of the action.
+//
+// Returns:
+//   - error: An error if the trigger can not be queued.
+func (notifier *areaNotifier) TriggerArea(area schemas.Area, trigger schemas.ActionResult) error {
+	return triggerArea(notifier, notifier.areaResultService, area, trigger)
+}
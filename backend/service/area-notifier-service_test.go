@@ -0,0 +1,48 @@
+package service_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"area/schemas"
+	"area/service"
+	"area/test"
+)
+
+func TestAreaNotifierNotifiesWorkers(t *testing.T) {
+	mockTriggerQueueRepository := new(test.MockTriggerQueueRepository)
+	mockTriggerQueueRepository.On("Enqueue", mock.Anything).Return(uint64(1), nil)
+	mockNotificationRepository := new(test.MockNotificationRepository)
+	mockNotificationRepository.On("Notify", mock.Anything, mock.Anything).Return(nil)
+
+	notifier := service.NewAreaNotifier(
+		mockTriggerQueueRepository,
+		mockNotificationRepository,
+		nil,
+	)
+	notifier.Start()
+	notifier.StartArea(schemas.Area{Id: 1})
+	notifier.RescheduleArea(schemas.Area{Id: 2})
+	notifier.StopArea(3)
+	err := notifier.EnqueueTrigger(schemas.QueuedTrigger{AreaId: 4, Message: "retried"})
+	assert.NoError(t, err)
+	err = notifier.TriggerArea(schemas.Area{Id: 5}, schemas.ActionResult{Message: "pushed"})
+	assert.NoError(t, err)
+	assert.NoError(t, notifier.Stop(context.Background()))
+
+	assert.False(t, notifier.IsScheduled(1))
+	mockNotificationRepository.AssertCalled(t, "Notify",
+		schemas.AreaChangeChannel, `{"area_id":1,"node_id":""}`)
+	mockNotificationRepository.AssertCalled(t, "Notify",
+		schemas.AreaChangeChannel, `{"area_id":2,"node_id":""}`)
+	mockNotificationRepository.AssertCalled(t, "Notify",
+		schemas.AreaChangeChannel, `{"area_id":3,"node_id":""}`)
+	mockTriggerQueueRepository.AssertCalled(t, "Enqueue",
+		schemas.QueuedTrigger{AreaId: 4, Message: "retried"})
+	mockNotificationRepository.AssertCalled(t, "Notify", schemas.TriggerQueueChannel, "4")
+	mockTriggerQueueRepository.AssertCalled(t, "Enqueue",
+		schemas.QueuedTrigger{AreaId: 5, Message: "pushed"})
+}
@@ -25,27 +25,57 @@ type AreaResultService interface {
 	// Returns:
 	//   A slice of area results for the specified area ID.
 	FindByAreaID(areaID uint64) []schemas.AreaResult
+
+	// FindByAreaIDAndFilter retrieves a filtered page of the area results of an area, newest first.
+	// Parameters:
+	//   areaID - the ID of the area to find results for.
+	//   filter - the status, creation time range and page of the results.
+	// Returns:
+	//   The area results of the page, the number of results matching the filter,
+	//   and an error if the query fails.
+	FindByAreaIDAndFilter(
+		areaID uint64,
+		filter schemas.AreaResultFilter,
+	) (results []schemas.AreaResult, total int64, err error)
+
+	// FindWorkflowRunsByAreaIDAndFilter retrieves a filtered page of the workflow runs of an
+	// area, newest first, with the state of their steps.
+	// Parameters:
+	//   areaID - the ID of the area to find workflow runs for.
+	//   filter - the status and page of the runs.
+	// Returns:
+	//   The workflow runs of the page, the number of runs matching the filter,
+	//   and an error if the query fails.
+	FindWorkflowRunsByAreaIDAndFilter(
+		areaID uint64,
+		filter schemas.WorkflowRunFilter,
+	) (runs []schemas.WorkflowRun, total int64, err error)
 }
 
 // areaResultService is a service that provides operations related to area results.
-// It interacts with the AreaResultRepository to perform CRUD operations and business logic.
+// It interacts with the AreaResultRepository to perform CRUD operations and business logic,
+// and with the WorkflowRunRepository to read the workflow runs.
 type areaResultService struct {
-	repository repository.AreaResultRepository
+	repository            repository.AreaResultRepository
+	workflowRunRepository repository.WorkflowRunRepository
 }
 
-// NewAreaResultService creates a new instance of AreaResultService with the provided repository.
-// It initializes an areaResultService struct with the given repository and returns a pointer to it.
+// NewAreaResultService creates a new instance of AreaResultService with the provided repositories.
+// It initializes an areaResultService struct with the given repositories and returns a pointer to it.
 //
 // Parameters:
 //   - repository: an instance of AreaResultRepository that will be used by the service.
+//   - workflowRunRepository: an instance of WorkflowRunRepository to read the workflow runs.
 //
 // Returns:
 //   - AreaResultService: a new instance of AreaResultService.
 func NewAreaResultService(
 	repository repository.AreaResultRepository,
+	workflowRunRepository repository.WorkflowRunRepository,
 ) AreaResultService {
 	newService := areaResultService{
-		repository: repository,
+		repository:            repository,
+		workflowRunRepository: workflowRunRepository,
 	}
 	return &newService
 }
@@ -75,3 +105,58 @@ func (service *areaResultService) FindAll() []schemas.AreaResult {
 func (service *areaResultService) FindByAreaID(areaID uint64) []schemas.AreaResult {
 	return service.repository.FindByAreaId(areaID)
 }
+
+// FindByAreaIDAndFilter retrieves a filtered page of the area results of the given area, newest first.
+// The first page is returned when no page is given, with schemas.AreaResultPageSize results
+// when no page size is given. The page size is capped at schemas.AreaResultMaxPageSize.
+//
+// Parameters:
+//   - areaID: The unique identifier of the area.
+//   - filter: The status, creation time range and page of the results.
+//
+// Returns:
+//   - results: The area results of the requested page.
+//   - total: The number of area results matching the filter.
+//   - err: An error if the query fails.
+func (service *areaResultService) FindByAreaIDAndFilter(
+	areaID uint64,
+	filter schemas.AreaResultFilter,
+) (results []schemas.AreaResult, total int64, err error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = schemas.AreaResultPageSize
+	}
+	if filter.PageSize > schemas.AreaResultMaxPageSize {
+		filter.PageSize = schemas.AreaResultMaxPageSize
+	}
+	return service.repository.FindByAreaIdAndFilter(areaID, filter)
+}
+
+// FindWorkflowRunsByAreaIDAndFilter retrieves a filtered page of the workflow runs of the given
+// area, newest first. The pages are sized like the pages of the area results.
+//
+// Parameters:
+//   - areaID: The unique identifier of the area.
+//   - filter: The status and page of the runs.
+//
+// Returns:
+//   - runs: The workflow runs of the requested page.
+//   - total: The number of workflow runs matching the filter.
+//   - err: An error if the query fails.
+func (service *areaResultService) FindWorkflowRunsByAreaIDAndFilter(
+	areaID uint64,
+	filter schemas.WorkflowRunFilter,
+) (runs []schemas.WorkflowRun, total int64, err error) {
+	if filter.Page < 1 {
+		filter.Page = 1
+	}
+	if filter.PageSize < 1 {
+		filter.PageSize = schemas.AreaResultPageSize
+	}
+	if filter.PageSize > schemas.AreaResultMaxPageSize {
+		filter.PageSize = schemas.AreaResultMaxPageSize
+	}
+	return service.workflowRunRepository.FindByAreaIdAndFilter(areaID, filter)
+}
@@ -0,0 +1,1400 @@
+package service
+
+import (
+	"container/heap"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"area/repository"
+	"area/schemas"
+	"area/tools"
+)
+
+// AreaScheduler defines the interface of the subsystem running the areas.
+// Every scheduled area is kept in a timer heap ordered by its next check time, and the
+// due checks are handed to a bounded pool of workers. A worker refreshes the expiring
+// service tokens of the area, runs the area action, and queues every trigger reported by
+// the action in the persistent trigger queue. A pool of reaction workers claims the queued
+// triggers and runs the workflow of their area, its reactions in order, and saves the results.
+// A trigger is acknowledged once its workflow is over, so that the triggers whose workflow
+// was interrupted, even by a crash, are delivered again and resumed.
+// The reactions failing with a transient error are retried following the retry policy of
+// the area, and the triggers whose workflow stops on a failed reaction become dead letters.
+// When several replicas of the backend run, every replica schedules the areas, but an area is
+// only checked by the replica holding its lease, while the queued triggers are run by the
+// reaction workers of every replica. The replicas notify each other of the area changes and
+// of the queued triggers through the database, so that they apply them right away.
+type AreaScheduler interface {
+	// Start launches the dispatch loop and the worker pool.
+	Start()
+
+	// Stop stops taking new checks and triggers and lets the running ones finish until ctx
+	// is done. The ones still running then are cancelled and their triggers are released.
+	Stop(ctx context.Context) error
+
+	// StartArea schedules the area for an immediate first check, and notifies the other
+	// replicas of the new area. It does nothing if the area is already scheduled.
+	StartArea(area schemas.Area)
+
+	// StopArea removes the area from the schedule, cancels its running check, and notifies
+	// the other replicas of the stopped area.
+	StopArea(areaId uint64)
+
+	// RescheduleArea applies the new refresh rate of the area and checks it immediately, and
+	// notifies the other replicas of the updated area.
+	// The area is scheduled if it was not already.
+	RescheduleArea(area schemas.Area)
+
+	// CheckArea checks the area immediately if this replica holds its lease, without notifying
+	// the other replicas, such as when the provider of its action reports a change.
+	CheckArea(areaId uint64)
+
+	// IsScheduled reports whether the area is scheduled.
+	IsScheduled(areaId uint64) bool
+
+	// EnqueueTrigger queues a trigger for the reaction workers.
+	EnqueueTrigger(trigger schemas.QueuedTrigger) error
+
+	// TriggerArea queues a trigger of the area for the reaction workers if it matches the
+	// trigger filter of the area, such as a trigger pushed by the provider of its action.
+	TriggerArea(area schemas.Area, trigger schemas.ActionResult) error
+}
+
+// scheduledArea is an entry of the scheduler timer heap.
+//
+// Fields:
+//   - areaId: The ID of the scheduled area.
+//   - interval: The delay between two checks of the area.
+//   - nextRun: The time of the next check of the area.
+//   - index: The position of the entry in the heap, -1 when it is not queued.
+//   - removed: Whether the area was removed from the schedule.
+//   - cancel: Cancels the running check of the area, nil when the area is not being checked.
+type scheduledArea struct {
+	areaId   uint64
+	interval time.Duration
+	nextRun  time.Time
+	index    int
+	removed  bool
+	cancel   context.CancelFunc
+}
+
+// areaHeap is a min-heap of scheduled areas ordered by their next check time.
+// It implements heap.Interface.
+type areaHeap []*scheduledArea
+
+func (queue areaHeap) Len() int { return len(queue) }
+
+func (queue areaHeap) Less(i, j int) bool { return queue[i].nextRun.Before(queue[j].nextRun) }
+
+func (queue areaHeap) Swap(i, j int) {
+	queue[i], queue[j] = queue[j], queue[i]
+	queue[i].index = i
+	queue[j].index = j
+}
+
+func (queue *areaHeap) Push(value any) {
+	entry := value.(*scheduledArea)
+	entry.index = len(*queue)
+	*queue = append(*queue, entry)
+}
+
+func (queue *areaHeap) Pop() any {
+	old := *queue
+	last := len(old) - 1
+	entry := old[last]
+	old[last] = nil
+	entry.index = -1
+	*queue = old[:last]
+	return entry
+}
+
+// delivery is a queued trigger being run by a reaction worker.
+//
+// Fields:
+//   - areaId: The ID of the area of the trigger.
+//   - cancel: Cancels the workflow run of the trigger.
+type delivery struct {
+	areaId uint64
+	cancel context.CancelFunc
+}
+
+// areaScheduler is the implementation of AreaScheduler.
+//
+// Fields:
+//   - areaRepository: Repository used to load the up to date area before each check.
+//   - serviceService: Service used to find the action and reaction functions.
+//   - tokenService: Service used to refresh the service tokens used by the actions and reactions.
+//   - areaResultService: Service used to save the result of the reactions.
+//   - triggerQueueRepository: Repository of the persistent queue of the triggers.
+//   - workflowRunRepository: Repository used to track the state of the steps of the workflow runs.
+//   - deadLetterRepository: Repository used to record the triggers whose reactions failed for good.
+//   - areaLeaseService: Service deciding which areas are checked by this replica.
+//   - notificationRepository: Repository used to notify the other replicas and to listen to them.
+//   - dropboxLongpoller: Watcher of the Dropbox folders of the areas checked by this replica.
+//   - workerCount: The number of workers checking the areas, and of reaction workers.
+//   - mutex: Guards areas, queue, deliveries and started.
+//   - areas: The scheduled areas by area ID.
+//   - queue: The timer heap of the areas waiting for their next check.
+//   - wake: Signals the dispatch loop that the head of the heap changed.
+//   - jobs: Hands the due areas to the workers.
+//   - queued: Wakes the idle reaction workers up when a trigger is queued.
+//   - rebalanceNow: Wakes the balance loop up when an area changed on another replica.
+//   - deliveries: The triggers being run by the reaction workers by trigger ID.
+//   - stopDispatch: Stops the dispatch loop and the workers from taking new checks, set by Start.
+//   - cancelChecks: Cancels the running checks, set by Start.
+//   - waitGroup: Waits for the dispatch loop and the workers to exit.
+//   - started: Whether the scheduler was started.
+type areaScheduler struct {
+	areaRepository         repository.AreaRepository
+	serviceService         ServiceService
+	tokenService           TokenService
+	areaResultService      AreaResultService
+	triggerQueueRepository repository.TriggerQueueRepository
+	workflowRunRepository  repository.WorkflowRunRepository
+	deadLetterRepository   repository.DeadLetterRepository
+	areaLeaseService       AreaLeaseService
+	notificationRepository repository.NotificationRepository
+	dropboxLongpoller      DropboxLongpoller
+	workerCount            int
+	mutex                  sync.Mutex
+	areas                  map[uint64]*scheduledArea
+	queue                  areaHeap
+	wake                   chan struct{}
+	jobs                   chan *scheduledArea
+	queued                 chan struct{}
+	rebalanceNow           chan struct{}
+	deliveries             map[uint64]*delivery
+	stopDispatch           context.CancelFunc
+	cancelChecks           context.CancelFunc
+	waitGroup              sync.WaitGroup
+	started                bool
+}
+
+// NewAreaScheduler creates a new instance of AreaScheduler with the provided dependencies.
+// The scheduler does nothing until Start is called.
+//
+// Parameters:
+//   - areaRepository: an instance of AreaRepository to load the areas.
+//   - serviceService: an instance of ServiceService to find the actions and reactions.
+//   - tokenService: an instance of TokenService to refresh the service tokens.
+//   - areaResultService: an instance of AreaResultService to save the reaction results.
+//   - triggerQueueRepository: an instance of TriggerQueueRepository to queue the triggers.
+//   - workflowRunRepository: an instance of WorkflowRunRepository to track the workflow runs.
+//   - deadLetterRepository: an instance of DeadLetterRepository to record the failed triggers.
+//   - areaLeaseService: an instance of AreaLeaseService to share the areas with the other replicas.
+//   - notificationRepository: an instance of NotificationRepository to exchange the changes with
+//     the other replicas.
+//   - dropboxLongpoller: an instance of DropboxLongpoller to stop watching the folders of the
+//     areas not checked anymore.
+//   - workerCount: the number of areas that can be checked, and of triggers that can be run,
+//     at the same time.
+//
+// Returns:
+//   - AreaScheduler: a new instance of AreaScheduler.
+func NewAreaScheduler(
+	areaRepository repository.AreaRepository,
+	serviceService ServiceService,
+	tokenService TokenService,
+	areaResultService AreaResultService,
+	triggerQueueRepository repository.TriggerQueueRepository,
+	workflowRunRepository repository.WorkflowRunRepository,
+	deadLetterRepository repository.DeadLetterRepository,
+	areaLeaseService AreaLeaseService,
+	notificationRepository repository.NotificationRepository,
+	dropboxLongpoller DropboxLongpoller,
+	workerCount int,
+) AreaScheduler {
+	if workerCount < 1 {
+		workerCount = 1
+	}
+	return &areaScheduler{
+		areaRepository:         areaRepository,
+		serviceService:         serviceService,
+		tokenService:           tokenService,
+		areaResultService:      areaResultService,
+		triggerQueueRepository: triggerQueueRepository,
+		workflowRunRepository:  workflowRunRepository,
+		deadLetterRepository:   deadLetterRepository,
+		areaLeaseService:       areaLeaseService,
+		notificationRepository: notificationRepository,
+		dropboxLongpoller:      dropboxLongpoller,
+		workerCount:            workerCount,
+		areas:                  make(map[uint64]*scheduledArea),
+		wake:                   make(chan struct{}, 1),
+		jobs:                   make(chan *scheduledArea),
+		queued:                 make(chan struct{}, workerCount),
+		rebalanceNow:           make(chan struct{}, 1),
+		deliveries:             make(map[uint64]*delivery),
+	}
+}
+
+// refreshInterval returns the delay between two checks of the area.
+// It is the area refresh rate, bounded by the minimum refresh rate of its action.
+func refreshInterval(area schemas.Area) time.Duration {
+	refreshRate := area.ActionRefreshRate
+	if area.Action.MinimumRefreshRate > refreshRate {
+		refreshRate = area.Action.MinimumRefreshRate
+	}
+	if refreshRate == 0 {
+		refreshRate = 1
+	}
+	return time.Second * time.Duration(refreshRate)
+}
+
+// Start launches the dispatch loop, the worker pool, the reaction worker pool, the loop
+// balancing the areas with the other replicas and the loop listening to their notifications.
+// Calling Start on a started scheduler does nothing.
+func (scheduler *areaScheduler) Start() {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	if scheduler.started {
+		return
+	}
+	scheduler.started = true
+	dispatchCtx, stopDispatch := context.WithCancel(context.Background())
+	checkCtx, cancelChecks := context.WithCancel(context.Background())
+	scheduler.stopDispatch = stopDispatch
+	scheduler.cancelChecks = cancelChecks
+	scheduler.waitGroup.Add(2*scheduler.workerCount + 3)
+	go scheduler.dispatch(dispatchCtx)
+	go scheduler.balance(dispatchCtx)
+	go scheduler.listen(dispatchCtx)
+	for range scheduler.workerCount {
+		go scheduler.work(dispatchCtx, checkCtx)
+		go scheduler.consume(dispatchCtx, checkCtx)
+	}
+}
+
+// Stop stops the dispatch loop, so the workers do not take new checks and the reaction
+// workers do not claim new triggers, and waits for the running checks and workflow runs to
+// finish. When ctx is done before, they are cancelled: the triggers whose reactions did not
+// complete are released to the queue and delivered again on the next start.
+// The scheduled areas are kept, but they are not checked anymore, and their leases are
+// released for the other replicas to take them over.
+//
+// Parameters:
+//   - ctx: The context bounding the drain window of the running checks.
+//
+// Returns:
+//   - error: An error wrapping the ctx error if running checks had to be cancelled.
+func (scheduler *areaScheduler) Stop(ctx context.Context) error {
+	scheduler.mutex.Lock()
+	if !scheduler.started {
+		scheduler.mutex.Unlock()
+		return nil
+	}
+	scheduler.started = false
+	stopDispatch := scheduler.stopDispatch
+	cancelChecks := scheduler.cancelChecks
+	scheduler.mutex.Unlock()
+
+	stopDispatch()
+	done := make(chan struct{})
+	go func() {
+		scheduler.waitGroup.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		cancelChecks()
+	case <-ctx.Done():
+		cancelChecks()
+		<-done
+	}
+	err := scheduler.areaLeaseService.Leave()
+	if err != nil {
+		println("error release area leases: " + err.Error())
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("running area checks and triggers were interrupted: %w", ctx.Err())
+	}
+	return nil
+}
+
+// notify wakes the dispatch loop up without blocking.
+func (scheduler *areaScheduler) notify() {
+	select {
+	case scheduler.wake <- struct{}{}:
+	default:
+	}
+}
+
+// StartArea schedules the area for an immediate first check, and notifies the other replicas.
+// It does nothing if the area is already scheduled, so an area never gets two workers.
+func (scheduler *areaScheduler) StartArea(area schemas.Area) {
+	scheduler.scheduleArea(area)
+	scheduler.publishAreaChange(area.Id)
+}
+
+// scheduleArea schedules the area for an immediate first check, if it is not already.
+func (scheduler *areaScheduler) scheduleArea(area schemas.Area) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	if _, ok := scheduler.areas[area.Id]; ok {
+		return
+	}
+	entry := &scheduledArea{
+		areaId:   area.Id,
+		interval: refreshInterval(area),
+		nextRun:  time.Now(),
+		index:    -1,
+	}
+	scheduler.areas[area.Id] = entry
+	heap.Push(&scheduler.queue, entry)
+	scheduler.notify()
+}
+
+// StopArea removes the area from the schedule, and notifies the other replicas.
+// A check already running for the area is cancelled, and the area is not checked again.
+// The workflow runs of the area are cancelled too, their triggers being released.
+func (scheduler *areaScheduler) StopArea(areaId uint64) {
+	scheduler.unscheduleArea(areaId)
+	scheduler.publishAreaChange(areaId)
+}
+
+// unscheduleArea removes the area from the schedule, cancelling its running check and
+// workflow runs, and stops watching its Dropbox folder.
+func (scheduler *areaScheduler) unscheduleArea(areaId uint64) {
+	scheduler.dropboxLongpoller.Unwatch(areaId)
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	for _, running := range scheduler.deliveries {
+		if running.areaId == areaId {
+			running.cancel()
+		}
+	}
+	entry, ok := scheduler.areas[areaId]
+	if !ok {
+		return
+	}
+	delete(scheduler.areas, areaId)
+	entry.removed = true
+	if entry.cancel != nil {
+		entry.cancel()
+	}
+	if entry.index >= 0 {
+		heap.Remove(&scheduler.queue, entry.index)
+		scheduler.notify()
+	}
+}
+
+// RescheduleArea applies the new refresh rate of the area and checks it immediately, and
+// notifies the other replicas.
+// If the area is being checked, the new refresh rate applies once the check is done.
+// The area is scheduled if it was not already.
+func (scheduler *areaScheduler) RescheduleArea(area schemas.Area) {
+	scheduler.rescheduleArea(area)
+	scheduler.publishAreaChange(area.Id)
+}
+
+// rescheduleArea applies the new refresh rate of the area and checks it immediately,
+// scheduling it if it was not already.
+func (scheduler *areaScheduler) rescheduleArea(area schemas.Area) {
+	scheduler.mutex.Lock()
+	entry, ok := scheduler.areas[area.Id]
+	if !ok {
+		scheduler.mutex.Unlock()
+		scheduler.scheduleArea(area)
+		return
+	}
+	defer scheduler.mutex.Unlock()
+	entry.interval = refreshInterval(area)
+	if entry.index >= 0 {
+		entry.nextRun = time.Now()
+		heap.Fix(&scheduler.queue, entry.index)
+		scheduler.notify()
+	}
+}
+
+// CheckArea moves the next check of the area to now, if the area is scheduled and this
+// replica holds its lease. The area itself did not change, so the other replicas are not
+// notified and its refresh rate is kept.
+// An area being checked is left as is.
+func (scheduler *areaScheduler) CheckArea(areaId uint64) {
+	if !scheduler.areaLeaseService.Owns(areaId) {
+		return
+	}
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	entry, ok := scheduler.areas[areaId]
+	if ok && entry.index >= 0 {
+		entry.nextRun = time.Now()
+		heap.Fix(&scheduler.queue, entry.index)
+		scheduler.notify()
+	}
+}
+
+// publishAreaChange notifies the other replicas that the area changed.
+func (scheduler *areaScheduler) publishAreaChange(areaId uint64) {
+	publishAreaChange(scheduler.notificationRepository, scheduler.areaLeaseService.NodeId(), areaId)
+}
+
+// IsScheduled reports whether the area is scheduled.
+func (scheduler *areaScheduler) IsScheduled(areaId uint64) bool {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	_, ok := scheduler.areas[areaId]
+	return ok
+}
+
+// nextDueArea pops the head of the heap if its check is due.
+// Otherwise it returns the delay until the next check, or -1 if no area is scheduled.
+func (scheduler *areaScheduler) nextDueArea() (entry *scheduledArea, delay time.Duration) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	if scheduler.queue.Len() == 0 {
+		return nil, -1
+	}
+	delay = time.Until(scheduler.queue[0].nextRun)
+	if delay > 0 {
+		return nil, delay
+	}
+	entry = heap.Pop(&scheduler.queue).(*scheduledArea)
+	return entry, 0
+}
+
+// dispatch is the loop handing the due areas to the workers.
+// It sleeps until the next check is due or until the heap changes.
+func (scheduler *areaScheduler) dispatch(ctx context.Context) {
+	defer scheduler.waitGroup.Done()
+	timer := time.NewTimer(time.Hour)
+	defer timer.Stop()
+	for {
+		entry, delay := scheduler.nextDueArea()
+		if entry != nil {
+			select {
+			case scheduler.jobs <- entry:
+			case <-ctx.Done():
+				scheduler.requeue(entry)
+				return
+			}
+			continue
+		}
+		if delay < 0 {
+			select {
+			case <-scheduler.wake:
+			case <-ctx.Done():
+				return
+			}
+			continue
+		}
+		timer.Reset(delay)
+		select {
+		case <-timer.C:
+		case <-scheduler.wake:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// balance is the loop sharing the areas with the other replicas. Every AreaLeaseRenew
+// seconds, and when an area changed on another replica, until ctx is done, it rebalances the
+// leases of the areas, checks the areas this replica acquired right away, and cancels the
+// running checks of the areas it released.
+func (scheduler *areaScheduler) balance(ctx context.Context) {
+	defer scheduler.waitGroup.Done()
+	ticker := time.NewTicker(time.Second * schemas.AreaLeaseRenew)
+	defer ticker.Stop()
+	for {
+		scheduler.rebalance()
+		select {
+		case <-ticker.C:
+		case <-scheduler.rebalanceNow:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// rebalance applies one rebalance of the area leases to the schedule.
+// The areas acquired are scheduled if they were created on another replica.
+func (scheduler *areaScheduler) rebalance() {
+	acquired, released, err := scheduler.areaLeaseService.Rebalance()
+	if err != nil {
+		println("error rebalance areas: " + err.Error())
+		return
+	}
+	for _, areaId := range released {
+		scheduler.mutex.Lock()
+		if entry, ok := scheduler.areas[areaId]; ok && entry.cancel != nil {
+			entry.cancel()
+		}
+		scheduler.mutex.Unlock()
+		// the replica acquiring the area watches its folder from its own checks
+		scheduler.dropboxLongpoller.Unwatch(areaId)
+	}
+	for _, areaId := range acquired {
+		area, err := scheduler.areaRepository.FindById(areaId)
+		if err != nil {
+			println("error find area: " + err.Error())
+			continue
+		}
+		scheduler.rescheduleArea(area)
+	}
+}
+
+// listen is the loop receiving the notifications of the other replicas until ctx is done.
+// The listening starts again after NotificationRetry seconds when the connection fails;
+// the changes notified in the meantime are applied on the next rebalance and the next poll of
+// the trigger queue.
+func (scheduler *areaScheduler) listen(ctx context.Context) {
+	defer scheduler.waitGroup.Done()
+	channels := []string{schemas.AreaChangeChannel, schemas.TriggerQueueChannel}
+	for {
+		err := scheduler.notificationRepository.Listen(ctx, channels, scheduler.handleNotification)
+		if ctx.Err() != nil {
+			return
+		}
+		println("error listen to notifications: " + err.Error())
+		if !waitRetry(ctx, time.Second*schemas.NotificationRetry) {
+			return
+		}
+	}
+}
+
+// handleNotification applies a notification of another replica: a queued trigger wakes an
+// idle reaction worker up, and a changed area is synchronized with the database and the
+// areas are rebalanced.
+func (scheduler *areaScheduler) handleNotification(channel string, payload string) {
+	switch channel {
+	case schemas.TriggerQueueChannel:
+		scheduler.wakeConsumer()
+	case schemas.AreaChangeChannel:
+		var change schemas.AreaChange
+		err := json.Unmarshal([]byte(payload), &change)
+		if err != nil {
+			println("error decode area change: " + err.Error())
+			return
+		}
+		if change.NodeId == scheduler.areaLeaseService.NodeId() {
+			return
+		}
+		scheduler.syncArea(change.AreaId)
+		select {
+		case scheduler.rebalanceNow <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// syncArea applies the state of the area in the database to the schedule: the deleted and
+// disabled areas are removed from it, the others are checked right away with their
+// refresh rate.
+func (scheduler *areaScheduler) syncArea(areaId uint64) {
+	area, err := scheduler.areaRepository.FindById(areaId)
+	if err != nil && !errors.Is(err, schemas.ErrAreaNotFound) {
+		println("error find area: " + err.Error())
+		return
+	}
+	if err != nil || !area.Enable {
+		scheduler.unscheduleArea(areaId)
+		return
+	}
+	scheduler.rescheduleArea(area)
+}
+
+// work is the loop of a worker. It checks the areas handed by the dispatch loop
+// until dispatchCtx is done. The checks run with checkCtx.
+func (scheduler *areaScheduler) work(dispatchCtx context.Context, checkCtx context.Context) {
+	defer scheduler.waitGroup.Done()
+	for {
+		select {
+		case entry := <-scheduler.jobs:
+			scheduler.runArea(checkCtx, entry)
+			scheduler.requeue(entry)
+		case <-dispatchCtx.Done():
+			return
+		}
+	}
+}
+
+// requeue puts the area back in the heap once its check is done,
+// unless it was removed from the schedule in the meantime.
+func (scheduler *areaScheduler) requeue(entry *scheduledArea) {
+	scheduler.mutex.Lock()
+	defer scheduler.mutex.Unlock()
+	if entry.removed {
+		return
+	}
+	entry.nextRun = time.Now().Add(entry.interval)
+	heap.Push(&scheduler.queue, entry)
+	scheduler.notify()
+}
+
+// boundReaction is a reaction of an area with the function running it.
+type boundReaction struct {
+	areaReaction schemas.AreaReaction
+	reaction     ReactionFunc
+}
+
+// runArea performs one check of the area, if this replica holds the lease of the area, without
+// reading the area from the database otherwise. It loads the up to date area, refreshes the
+// service tokens expiring soon, runs its action, then queues every trigger returned by the
+// action that matches the trigger filter of the area, for the reaction workers to run the
+// workflow of the area.
+// Every check stopped by a failing action or by a token that can not be refreshed is saved
+// as an execution record.
+// The check runs with its own context, cancelled when the area is removed from the
+// schedule, when the replica releases the lease of the area, or when the scheduler drain
+// window is over. The triggers returned by the action are queued even then, since the
+// action already advanced its storage past them.
+// Areas that do not exist anymore are removed from the schedule.
+func (scheduler *areaScheduler) runArea(ctx context.Context, entry *scheduledArea) {
+	if !scheduler.areaLeaseService.Owns(entry.areaId) {
+		return
+	}
+	area, err := scheduler.areaRepository.FindById(entry.areaId)
+	if err != nil {
+		if errors.Is(err, schemas.ErrAreaNotFound) {
+			scheduler.unscheduleArea(entry.areaId)
+			return
+		}
+		println("error find area: " + err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	scheduler.mutex.Lock()
+	if entry.removed {
+		scheduler.mutex.Unlock()
+		return
+	}
+	entry.interval = refreshInterval(area)
+	entry.cancel = cancel
+	scheduler.mutex.Unlock()
+	defer func() {
+		scheduler.mutex.Lock()
+		entry.cancel = nil
+		scheduler.mutex.Unlock()
+	}()
+
+	if !area.Enable {
+		return
+	}
+
+	action := scheduler.serviceService.FindActionByName(area.Action.Name)
+	if action == nil {
+		println("action not found: " + area.Action.Name)
+		return
+	}
+
+	startedAt := time.Now()
+	err = scheduler.refreshTokens(ctx, area)
+	if err != nil {
+		scheduler.saveCheckResult(area, schemas.AreaResultSkipped, err, startedAt)
+		return
+	}
+
+	startedAt = time.Now()
+	resultActions, err := scheduler.checkAction(ctx, area, action)
+	if err != nil {
+		if ctx.Err() == nil {
+			scheduler.saveCheckResult(area, schemas.AreaResultFailure, err, startedAt)
+		}
+		return
+	}
+
+	for _, resultAction := range resultActions {
+		err = scheduler.TriggerArea(area, resultAction)
+		if err != nil {
+			println("error enqueue trigger: " + err.Error())
+		}
+	}
+}
+
+// TriggerArea queues the trigger for the reaction workers if it matches the trigger filter of
+// the area, like the triggers reported by the checks of the area.
+//
+// Parameters:
+//   - area: The area whose action reported the trigger.
+//   - trigger: The trigger reported by the action, or pushed by the provider of the action.
+//
+// Returns:
+//   - error: An error if the trigger can not be queued.
+func (scheduler *areaScheduler) TriggerArea(area schemas.Area, trigger schemas.ActionResult) error {
+	return triggerArea(scheduler, scheduler.areaResultService, area, trigger)
+}
+
+// triggerArea queues the trigger of the area through the scheduler if it matches the trigger
+// filter of the area, saving an execution record of the trigger otherwise.
+func triggerArea(
+	scheduler AreaScheduler,
+	areaResultService AreaResultService,
+	area schemas.Area,
+	trigger schemas.ActionResult,
+) error {
+	if !matchFilter(areaResultService, area, trigger) {
+		return nil
+	}
+	return scheduler.EnqueueTrigger(schemas.QueuedTrigger{
+		AreaId:  area.Id,
+		Message: trigger.Message,
+		Payload: trigger.Payload,
+	})
+}
+
+// EnqueueTrigger saves the trigger in the persistent trigger queue and wakes an idle reaction
+// worker up to run it, of this replica and of the other ones.
+//
+// Parameters:
+//   - trigger: The trigger to queue, with the position of its first reaction to run and its
+//     workflow run when it resumes one.
+//
+// Returns:
+//   - error: An error if the trigger can not be queued.
+func (scheduler *areaScheduler) EnqueueTrigger(trigger schemas.QueuedTrigger) error {
+	_, err := scheduler.triggerQueueRepository.Enqueue(trigger)
+	if err != nil {
+		return fmt.Errorf("unable to enqueue trigger: %w", err)
+	}
+	scheduler.wakeConsumer()
+	publishQueuedTrigger(scheduler.notificationRepository, trigger)
+	return nil
+}
+
+// wakeConsumer wakes an idle reaction worker up without blocking.
+func (scheduler *areaScheduler) wakeConsumer() {
+	select {
+	case scheduler.queued <- struct{}{}:
+	default:
+	}
+}
+
+// consume is the loop of a reaction worker. It claims the queued triggers and runs their
+// workflow until dispatchCtx is done, waiting for a trigger to be queued, or for the poll
+// interval, when the queue is empty. The workflow runs run with runCtx.
+func (scheduler *areaScheduler) consume(dispatchCtx context.Context, runCtx context.Context) {
+	defer scheduler.waitGroup.Done()
+	lease := time.Second * schemas.TriggerQueueLease
+	timer := time.NewTimer(time.Second * schemas.TriggerQueuePoll)
+	defer timer.Stop()
+	for dispatchCtx.Err() == nil {
+		trigger, claimed, err := scheduler.triggerQueueRepository.Claim(lease)
+		if err != nil {
+			println("error claim trigger: " + err.Error())
+		}
+		if claimed {
+			scheduler.deliver(runCtx, trigger)
+			continue
+		}
+		timer.Reset(time.Second * schemas.TriggerQueuePoll)
+		select {
+		case <-scheduler.queued:
+		case <-timer.C:
+		case <-dispatchCtx.Done():
+			return
+		}
+	}
+}
+
+// deliver runs the workflow of the area of a claimed trigger, resuming its workflow run from
+// the first reaction that did not complete, and acknowledges the trigger once the run is over.
+// The lease of the trigger is renewed every TriggerQueueRenew seconds while the run lasts, and
+// the run is interrupted if the lease was lost to another delivery.
+// A run interrupted by ctx, or by the removal of the area from the schedule, releases the
+// trigger to be delivered again. The triggers of a disabled area, or of an area whose action
+// or reactions can not be found, are released to be delivered again later, and the triggers
+// of a deleted area are acknowledged.
+func (scheduler *areaScheduler) deliver(ctx context.Context, trigger schemas.QueuedTrigger) {
+	area, err := scheduler.areaRepository.FindById(trigger.AreaId)
+	if errors.Is(err, schemas.ErrAreaNotFound) {
+		scheduler.ackTrigger(trigger)
+		return
+	}
+	if err != nil {
+		println("error find area: " + err.Error())
+		scheduler.releaseTrigger(trigger, time.Second*schemas.TriggerQueueRetry)
+		return
+	}
+	reactions := scheduler.bindReactions(area)
+	if !area.Enable || reactions == nil {
+		scheduler.releaseTrigger(trigger, time.Second*schemas.TriggerQueueRetry)
+		return
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	scheduler.mutex.Lock()
+	scheduler.deliveries[trigger.Id] = &delivery{areaId: area.Id, cancel: cancel}
+	scheduler.mutex.Unlock()
+	defer func() {
+		scheduler.mutex.Lock()
+		delete(scheduler.deliveries, trigger.Id)
+		scheduler.mutex.Unlock()
+	}()
+
+	var run schemas.WorkflowRun
+	if trigger.WorkflowRunId != 0 || trigger.ReactionPosition > 0 {
+		run = scheduler.resumeWorkflowRun(area, reactions, trigger)
+	} else {
+		run = scheduler.startWorkflowRun(area, reactions, schemas.ActionResult{
+			Message: trigger.Message,
+			Payload: trigger.Payload,
+		})
+	}
+	stopRenewal := scheduler.renewTrigger(trigger, cancel)
+	if run.Id != trigger.WorkflowRunId {
+		scheduler.saveProgress(trigger, run.Id, trigger.ReactionPosition)
+	}
+	completed := scheduler.reactAll(ctx, area, reactions, trigger, &run)
+	stopRenewal()
+	if !completed {
+		scheduler.releaseTrigger(trigger, 0)
+		println("trigger of area " + strconv.FormatUint(area.Id, 10) + " interrupted, released")
+		return
+	}
+	scheduler.ackTrigger(trigger)
+}
+
+// renewTrigger renews the lease of a claimed trigger every TriggerQueueRenew seconds, so that
+// a workflow run outlasting the lease, because of the retries of its reactions, is not
+// delivered again meanwhile. When the lease was lost, interrupt is called to stop the run.
+//
+// Returns:
+//   - func(): A function stopping the renewals, returning once they are over.
+func (scheduler *areaScheduler) renewTrigger(
+	trigger schemas.QueuedTrigger,
+	interrupt context.CancelFunc,
+) func() {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(time.Second * schemas.TriggerQueueRenew)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+			case <-done:
+				return
+			}
+			lease := time.Second * schemas.TriggerQueueLease
+			err := scheduler.triggerQueueRepository.Renew(trigger, lease)
+			if errors.Is(err, schemas.ErrTriggerLeaseLost) {
+				println("lease of trigger " + strconv.FormatUint(trigger.Id, 10) + " lost")
+				interrupt()
+				return
+			}
+			if err != nil {
+				println("error renew trigger lease: " + err.Error())
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// bindReactions returns the reactions of the area with the functions running them, or nil
+// if one of them can not be found.
+func (scheduler *areaScheduler) bindReactions(area schemas.Area) []boundReaction {
+	reactions := []boundReaction{}
+	for _, areaReaction := range areaReactions(area) {
+		reaction := scheduler.serviceService.FindReactionByName(areaReaction.Reaction.Name)
+		if reaction == nil {
+			println("reaction not found: " + areaReaction.Reaction.Name)
+			return nil
+		}
+		reactions = append(reactions, boundReaction{areaReaction: areaReaction, reaction: reaction})
+	}
+	return reactions
+}
+
+// saveProgress saves the workflow run of a claimed trigger and the position of its first
+// reaction that did not complete, renewing the lease of the trigger.
+func (scheduler *areaScheduler) saveProgress(
+	trigger schemas.QueuedTrigger,
+	runId uint64,
+	position int,
+) {
+	err := scheduler.triggerQueueRepository.Progress(
+		trigger,
+		runId,
+		position,
+		time.Second*schemas.TriggerQueueLease,
+	)
+	if err != nil {
+		println("error save trigger progress: " + err.Error())
+	}
+}
+
+// releaseTrigger makes a claimed trigger available to the reaction workers again after delay.
+func (scheduler *areaScheduler) releaseTrigger(trigger schemas.QueuedTrigger, delay time.Duration) {
+	err := scheduler.triggerQueueRepository.Release(trigger, delay)
+	if err != nil {
+		println("error release trigger: " + err.Error())
+	}
+}
+
+// ackTrigger removes a claimed trigger whose workflow run is over from the queue.
+func (scheduler *areaScheduler) ackTrigger(trigger schemas.QueuedTrigger) {
+	err := scheduler.triggerQueueRepository.Ack(trigger)
+	if err != nil {
+		println("error acknowledge trigger: " + err.Error())
+	}
+}
+
+// refreshTokens refreshes the tokens of the action and reaction services of the area
+// when they expire soon.
+//
+// Returns:
+//   - error: An error if a token is missing or can not be refreshed.
+func (scheduler *areaScheduler) refreshTokens(ctx context.Context, area schemas.Area) error {
+	serviceIds := []uint64{area.Action.ServiceId}
+	for _, areaReaction := range areaReactions(area) {
+		if !slices.Contains(serviceIds, areaReaction.Reaction.ServiceId) {
+			serviceIds = append(serviceIds, areaReaction.Reaction.ServiceId)
+		}
+	}
+	for _, serviceId := range serviceIds {
+		err := scheduler.tokenService.RefreshTokenIfExpiring(ctx, area.UserId, serviceId)
+		if err != nil {
+			return fmt.Errorf("unable to refresh token: %w", err)
+		}
+	}
+	return nil
+}
+
+// checkAction runs the action of the area. When the service rejects the token of the
+// action, the token is refreshed and the action is run a second time.
+func (scheduler *areaScheduler) checkAction(
+	ctx context.Context,
+	area schemas.Area,
+	action ActionFunc,
+) ([]schemas.ActionResult, error) {
+	resultActions, err := action(ctx, area.ActionOption, area)
+	if scheduler.refreshUnauthorized(ctx, area.UserId, area.Action.ServiceId, err) {
+		resultActions, err = action(ctx, area.ActionOption, area)
+	}
+	return resultActions, err
+}
+
+// refreshUnauthorized refreshes the token of the user for the service when err reports
+// that the service rejected it with a 401 status.
+//
+// Returns:
+//   - bool: Whether the token was refreshed, so the failed call should be retried.
+func (scheduler *areaScheduler) refreshUnauthorized(
+	ctx context.Context,
+	userId uint64,
+	serviceId uint64,
+	err error,
+) bool {
+	if !errors.Is(err, schemas.ErrUnauthorized) {
+		return false
+	}
+	refreshErr := scheduler.tokenService.RefreshToken(ctx, userId, serviceId)
+	if refreshErr != nil {
+		println("error refresh token: " + refreshErr.Error())
+		return false
+	}
+	return true
+}
+
+// matchFilter reports whether the trigger matches the trigger filter of the area.
+// A trigger filtered out is saved as a skipped execution record, and a filter that can not be
+// evaluated as a failed one.
+func matchFilter(
+	areaResultService AreaResultService,
+	area schemas.Area,
+	trigger schemas.ActionResult,
+) bool {
+	startedAt := time.Now()
+	matched, err := tools.MatchTriggerFilter(area.Filter, trigger.Payload)
+	if err != nil {
+		saveTriggerResult(
+			areaResultService,
+			area,
+			trigger,
+			schemas.AreaResultFailure,
+			err.Error(),
+			startedAt,
+		)
+		return false
+	}
+	if !matched {
+		saveTriggerResult(
+			areaResultService,
+			area,
+			trigger,
+			schemas.AreaResultSkipped,
+			"trigger filtered out",
+			startedAt,
+		)
+	}
+	return matched
+}
+
+// saveTriggerResult saves the execution record of a trigger of the area whose reactions
+// did not run.
+func saveTriggerResult(
+	areaResultService AreaResultService,
+	area schemas.Area,
+	trigger schemas.ActionResult,
+	status schemas.AreaResultStatus,
+	message string,
+	startedAt time.Time,
+) {
+	result := schemas.AreaResult{
+		Area:           area,
+		Status:         status,
+		Result:         message,
+		TriggerMessage: trigger.Message,
+		TriggerPayload: trigger.Payload,
+		StartedAt:      startedAt,
+	}
+	if status == schemas.AreaResultFailure {
+		result.Error = message
+	}
+	saveAreaResult(areaResultService, result)
+}
+
+// startWorkflowRun saves a new workflow run of the reactions of the area for one trigger,
+// with every step pending. A run that can not be saved still runs, without being tracked.
+func (scheduler *areaScheduler) startWorkflowRun(
+	area schemas.Area,
+	reactions []boundReaction,
+	trigger schemas.ActionResult,
+) schemas.WorkflowRun {
+	run := schemas.WorkflowRun{
+		AreaId:         area.Id,
+		TriggerMessage: trigger.Message,
+		TriggerPayload: trigger.Payload,
+		Status:         schemas.WorkflowRunRunning,
+		Steps:          make(schemas.WorkflowSteps, 0, len(reactions)),
+		StartedAt:      time.Now(),
+	}
+	for position, bound := range reactions {
+		run.Steps = append(run.Steps, schemas.WorkflowStep{
+			Position:     position,
+			ReactionName: bound.areaReaction.Reaction.Name,
+			Status:       schemas.WorkflowStepPending,
+		})
+	}
+	runId, err := scheduler.workflowRunRepository.Save(run)
+	if err != nil {
+		println("error save workflow run: " + err.Error())
+	}
+	run.Id = runId
+	return run
+}
+
+// resumeWorkflowRun returns the workflow run of a queued trigger, running again, holding the
+// outputs of the steps that completed before the interruption. A new run is started when the
+// run can not be found or when the reactions of the area changed since, the steps before the
+// first step to run being skipped.
+func (scheduler *areaScheduler) resumeWorkflowRun(
+	area schemas.Area,
+	reactions []boundReaction,
+	trigger schemas.QueuedTrigger,
+) schemas.WorkflowRun {
+	if trigger.WorkflowRunId != 0 {
+		run, err := scheduler.workflowRunRepository.FindById(trigger.WorkflowRunId)
+		if err == nil && len(run.Steps) == len(reactions) {
+			run.Status = schemas.WorkflowRunRunning
+			return run
+		}
+		if err != nil && !errors.Is(err, schemas.ErrWorkflowRunNotFound) {
+			println("error find workflow run: " + err.Error())
+		}
+	}
+	run := scheduler.startWorkflowRun(area, reactions, schemas.ActionResult{
+		Message: trigger.Message,
+		Payload: trigger.Payload,
+	})
+	for position := 0; position < trigger.ReactionPosition && position < len(run.Steps); position++ {
+		run.Steps[position].Status = schemas.WorkflowStepSkipped
+	}
+	return run
+}
+
+// finishWorkflowRun marks the steps of the run that did not run as skipped, sets its status,
+// a failure if one of its steps failed, and saves it.
+func (scheduler *areaScheduler) finishWorkflowRun(run *schemas.WorkflowRun) {
+	run.Status = schemas.WorkflowRunSuccess
+	for position := range run.Steps {
+		step := &run.Steps[position]
+		if step.Status == schemas.WorkflowStepPending {
+			step.Status = schemas.WorkflowStepSkipped
+		}
+		if step.Status == schemas.WorkflowStepFailure {
+			run.Status = schemas.WorkflowRunFailure
+		}
+	}
+	run.FinishedAt = time.Now()
+	scheduler.updateWorkflowRun(*run)
+}
+
+// updateWorkflowRun saves the state of the steps of a tracked workflow run.
+func (scheduler *areaScheduler) updateWorkflowRun(run schemas.WorkflowRun) {
+	if run.Id == 0 {
+		return
+	}
+	err := scheduler.workflowRunRepository.Update(run)
+	if err != nil {
+		println("error update workflow run: " + err.Error())
+	}
+}
+
+// reactAll runs the steps of the workflow run of a queued trigger, the reactions of the area
+// in order, starting with the first reaction that did not complete for the trigger. The
+// run, and its progress on the trigger, are saved after every step. A failing reaction stops the
+// reactions after it, unless it is set to continue on failure. The run is finished once its
+// reactions completed, and saved as running when one was interrupted.
+//
+// Returns:
+//   - bool: Whether the reactions completed, false if one was interrupted.
+func (scheduler *areaScheduler) reactAll(
+	ctx context.Context,
+	area schemas.Area,
+	reactions []boundReaction,
+	queued schemas.QueuedTrigger,
+	run *schemas.WorkflowRun,
+) bool {
+	trigger := schemas.ActionResult{Message: queued.Message, Payload: queued.Payload}
+	for position := queued.ReactionPosition; position < len(reactions); position++ {
+		succeeded, completed := scheduler.react(
+			ctx,
+			area,
+			reactions[position],
+			trigger,
+			run,
+			position,
+		)
+		if !completed {
+			scheduler.updateWorkflowRun(*run)
+			return false
+		}
+		scheduler.updateWorkflowRun(*run)
+		scheduler.saveProgress(queued, run.Id, position+1)
+		if !succeeded && !reactions[position].areaReaction.ContinueOnFailure {
+			break
+		}
+	}
+	scheduler.finishWorkflowRun(run)
+	return true
+}
+
+// react runs the step at position of a workflow run, one reaction of the area for one
+// trigger, and saves its execution record. The {{action.field}} placeholders of the reaction
+// option are rendered from the trigger payload, and the {{steps.N.field}} ones from the
+// outputs of the previous steps, before the reaction runs. When the service rejects the token
+// of the reaction, the token is refreshed and the reaction is run a second time. A reaction
+// failing with a transient error is run again after the backoff of the retry policy of the
+// area, until it succeeds or runs the maximum number of attempts. A reaction failing for good
+// that stops the workflow records the trigger as a dead letter.
+// A reaction failing because ctx was cancelled is not saved, and its step stays pending.
+//
+// Returns:
+//   - bool: Whether the reaction succeeded.
+//   - bool: Whether the reaction completed, false if it was interrupted.
+func (scheduler *areaScheduler) react(
+	ctx context.Context,
+	area schemas.Area,
+	bound boundReaction,
+	trigger schemas.ActionResult,
+	run *schemas.WorkflowRun,
+	position int,
+) (bool, bool) {
+	if ctx.Err() != nil {
+		return false, false
+	}
+	areaReaction := bound.areaReaction
+	result := schemas.AreaResult{
+		Area:             area,
+		ReactionName:     areaReaction.Reaction.Name,
+		ReactionPosition: areaReaction.Position,
+		WorkflowRunId:    run.Id,
+		TriggerMessage:   trigger.Message,
+		TriggerPayload:   trigger.Payload,
+		StartedAt:        time.Now(),
+	}
+	step := &run.Steps[position]
+	step.StartedAt = result.StartedAt
+	option, err := tools.RenderWorkflowTemplate(
+		areaReaction.ReactionOption,
+		trigger.Payload,
+		run.Steps.Outputs(),
+	)
+	if err != nil {
+		result.Status = schemas.AreaResultFailure
+		result.Error = err.Error()
+		result.Result = err.Error()
+		scheduler.saveStepResult(step, result, nil)
+		scheduler.saveDeadLetter(area, bound, trigger, run, position, result, false)
+		return false, true
+	}
+	area.ReactionId = areaReaction.ReactionId
+	area.Reaction = areaReaction.Reaction
+	area.ReactionOption = option
+	result.ReactionInput = option
+	result.Attempt = 1
+	serviceId := areaReaction.Reaction.ServiceId
+	policy := area.RetryPolicy.WithDefaults()
+	refreshed := false
+	retries := 0
+	var resultReaction schemas.ReactionResult
+	for {
+		resultReaction, err = bound.reaction(ctx, option, area)
+		if err == nil || ctx.Err() != nil {
+			break
+		}
+		if !refreshed && scheduler.refreshUnauthorized(ctx, area.UserId, serviceId, err) {
+			refreshed = true
+			result.Attempt++
+			continue
+		}
+		if !tools.IsTransientError(err) || result.Attempt >= policy.MaxAttempts {
+			break
+		}
+		retries++
+		if !waitRetry(ctx, tools.RetryDelay(policy, retries)) {
+			break
+		}
+		result.Attempt++
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			step.StartedAt = time.Time{}
+			return false, false
+		}
+		result.Status = schemas.AreaResultFailure
+		result.Error = err.Error()
+		result.Result = err.Error()
+		scheduler.saveStepResult(step, result, nil)
+		transient := tools.IsTransientError(err)
+		scheduler.saveDeadLetter(area, bound, trigger, run, position, result, transient)
+		return false, true
+	}
+	result.Status = schemas.AreaResultSuccess
+	result.ReactionOutput = resultReaction.Message
+	result.Result = resultReaction.Message
+	scheduler.saveStepResult(step, result, stepOutput(resultReaction))
+	return true, true
+}
+
+// waitRetry waits for the backoff before the retry of a reaction.
+//
+// Returns:
+//   - bool: Whether the backoff elapsed, false if ctx was cancelled first.
+func waitRetry(ctx context.Context, delay time.Duration) bool {
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// saveDeadLetter records the trigger of a workflow run that stops on the failed reaction at
+// position as a dead letter of the owner of the area, so that it can be retried or discarded.
+// The reactions set to continue on failure do not stop the workflow and are not recorded.
+func (scheduler *areaScheduler) saveDeadLetter(
+	area schemas.Area,
+	bound boundReaction,
+	trigger schemas.ActionResult,
+	run *schemas.WorkflowRun,
+	position int,
+	result schemas.AreaResult,
+	transient bool,
+) {
+	if bound.areaReaction.ContinueOnFailure {
+		return
+	}
+	err := scheduler.deadLetterRepository.Save(schemas.DeadLetter{
+		UserId:           area.UserId,
+		AreaId:           area.Id,
+		WorkflowRunId:    run.Id,
+		ReactionPosition: position,
+		ReactionName:     bound.areaReaction.Reaction.Name,
+		TriggerMessage:   trigger.Message,
+		TriggerPayload:   trigger.Payload,
+		Error:            result.Error,
+		Transient:        transient,
+		Attempts:         result.Attempt,
+	})
+	if err != nil {
+		println("error save dead letter: " + err.Error())
+	}
+}
+
+// saveStepResult saves the execution record of the reaction of a workflow step and reports
+// its outcome and output on the step.
+func (scheduler *areaScheduler) saveStepResult(
+	step *schemas.WorkflowStep,
+	result schemas.AreaResult,
+	output json.RawMessage,
+) {
+	scheduler.saveResult(result)
+	step.FinishedAt = time.Now()
+	step.Error = result.Error
+	step.Output = output
+	if result.Status == schemas.AreaResultSuccess {
+		step.Status = schemas.WorkflowStepSuccess
+	} else {
+		step.Status = schemas.WorkflowStepFailure
+	}
+}
+
+// stepOutput returns the output of a workflow step: the payload of the result of its
+// reaction, with the message of the result as its message field when the payload has none.
+func stepOutput(result schemas.ReactionResult) json.RawMessage {
+	fields := map[string]any{}
+	if len(result.Payload) != 0 {
+		err := json.Unmarshal(result.Payload, &fields)
+		if err != nil {
+			println("error unmarshal reaction payload: " + err.Error())
+			fields = map[string]any{}
+		}
+	}
+	if _, ok := fields["message"]; !ok {
+		fields["message"] = result.Message
+	}
+	output, err := json.Marshal(fields)
+	if err != nil {
+		println("error marshal step output: " + err.Error())
+		return nil
+	}
+	return output
+}
+
+// saveCheckResult saves the execution record of a check of the area that stopped before
+// running any reaction, because the check was skipped or the action failed.
+func (scheduler *areaScheduler) saveCheckResult(
+	area schemas.Area,
+	status schemas.AreaResultStatus,
+	err error,
+	startedAt time.Time,
+) {
+	scheduler.saveResult(schemas.AreaResult{
+		Area:      area,
+		Status:    status,
+		Error:     err.Error(),
+		Result:    err.Error(),
+		StartedAt: startedAt,
+	})
+}
+
+// saveResult completes the timing of the execution record of a run and saves it.
+func (scheduler *areaScheduler) saveResult(result schemas.AreaResult) {
+	saveAreaResult(scheduler.areaResultService, result)
+}
+
+// saveAreaResult completes the timing of the execution record of a run and saves it with the
+// area result service.
+func saveAreaResult(areaResultService AreaResultService, result schemas.AreaResult) {
+	result.FinishedAt = time.Now()
+	result.Duration = result.FinishedAt.Sub(result.StartedAt).Milliseconds()
+	areaResultService.Save(result)
+}
@@ -0,0 +1,1515 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"area/repository"
+	"area/schemas"
+	"area/service"
+	"area/test"
+)
+
+// MockAreaResultService is a mock implementation of the AreaResultService interface.
+type MockAreaResultService struct {
+	mock.Mock
+}
+
+func (m *MockAreaResultService) Save(newAreaResult schemas.AreaResult) {
+	m.Called(newAreaResult)
+}
+
+func (m *MockAreaResultService) FindAll() []schemas.AreaResult {
+	args := m.Called()
+	return args.Get(0).([]schemas.AreaResult)
+}
+
+func (m *MockAreaResultService) FindByAreaID(areaID uint64) []schemas.AreaResult {
+	args := m.Called(areaID)
+	return args.Get(0).([]schemas.AreaResult)
+}
+
+func (m *MockAreaResultService) FindByAreaIDAndFilter(
+	areaID uint64,
+	filter schemas.AreaResultFilter,
+) ([]schemas.AreaResult, int64, error) {
+	args := m.Called(areaID, filter)
+	return args.Get(0).([]schemas.AreaResult), args.Get(1).(int64), args.Error(2)
+}
+
+func (m *MockAreaResultService) FindWorkflowRunsByAreaIDAndFilter(
+	areaID uint64,
+	filter schemas.WorkflowRunFilter,
+) ([]schemas.WorkflowRun, int64, error) {
+	args := m.Called(areaID, filter)
+	return args.Get(0).([]schemas.WorkflowRun), args.Get(1).(int64), args.Error(2)
+}
+
+func newSchedulerArea(id uint64) schemas.Area {
+	return schemas.Area{
+		Id:                id,
+		Enable:            true,
+		ActionRefreshRate: 1,
+		Action:            schemas.Action{Name: "test action"},
+		Reaction:          schemas.Reaction{Name: "test reaction"},
+	}
+}
+
+func newTokenService() *test.MockTokenService {
+	mockTokenService := new(test.MockTokenService)
+	mockTokenService.On("RefreshTokenIfExpiring", mock.Anything, mock.Anything).Return(nil)
+	mockTokenService.On("RefreshToken", mock.Anything, mock.Anything).Return(nil)
+	return mockTokenService
+}
+
+func newTriggerQueueRepository(t *testing.T) repository.TriggerQueueRepository {
+	t.Helper()
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+	// every connection to an in-memory database opens a new database
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	return repository.NewTriggerQueueRepository(db)
+}
+
+func newWorkflowRunRepository() *test.MockWorkflowRunRepository {
+	mockWorkflowRunRepository := new(test.MockWorkflowRunRepository)
+	mockWorkflowRunRepository.On("Save", mock.Anything).Return(uint64(1), nil)
+	mockWorkflowRunRepository.On("Update", mock.Anything).Return(nil)
+	mockWorkflowRunRepository.On("FindById", mock.Anything).
+		Return(schemas.WorkflowRun{}, schemas.ErrWorkflowRunNotFound)
+	return mockWorkflowRunRepository
+}
+
+func newDeadLetterRepository() *test.MockDeadLetterRepository {
+	mockDeadLetterRepository := new(test.MockDeadLetterRepository)
+	mockDeadLetterRepository.On("Save", mock.Anything).Return(nil)
+	return mockDeadLetterRepository
+}
+
+// newAreaLeaseService returns the lease service of a replica running alone, owning every area.
+func newAreaLeaseService() *test.MockAreaLeaseService {
+	mockAreaLeaseService := new(test.MockAreaLeaseService)
+	mockAreaLeaseService.On("NodeId").Return("node")
+	mockAreaLeaseService.On("Owns", mock.Anything).Return(true)
+	mockAreaLeaseService.On("Rebalance").Return([]uint64(nil), []uint64(nil), nil)
+	mockAreaLeaseService.On("Leave").Return(nil)
+	return mockAreaLeaseService
+}
+
+// newNotificationRepository returns a notification repository receiving no notification.
+func newNotificationRepository() *test.MockNotificationRepository {
+	mockNotificationRepository := new(test.MockNotificationRepository)
+	mockNotificationRepository.On("Notify", mock.Anything, mock.Anything).Return(nil)
+	mockNotificationRepository.On("Listen", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			<-args.Get(0).(context.Context).Done()
+		}).
+		Return(context.Canceled)
+	return mockNotificationRepository
+}
+
+// newDropboxLongpoller returns a watcher of the Dropbox folders that is never started.
+func newDropboxLongpoller() service.DropboxLongpoller {
+	return service.NewDropboxLongpoller(schemas.DropboxNotifyURL)
+}
+
+func TestAreaSchedulerRunsActionAndReaction(t *testing.T) {
+	area := newSchedulerArea(1)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	actionCalls := atomic.Int32{}
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			actionCalls.Add(1)
+			return []schemas.ActionResult{{Message: "triggered"}}, nil
+		}))
+	mockServiceService.On("FindReactionByName", "test reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			return schemas.ReactionResult{Message: "reacted"}, nil
+		}))
+
+	saved := make(chan schemas.AreaResult, 1)
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		saved <- args.Get(0).(schemas.AreaResult)
+	}).Once()
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		mockAreaResultService,
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		2,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+	scheduler.StartArea(area)
+	scheduler.StartArea(area)
+
+	select {
+	case result := <-saved:
+		assert.Equal(t, "reacted", result.Result)
+		assert.Equal(t, area.Id, result.Area.Id)
+		assert.Equal(t, schemas.AreaResultSuccess, result.Status)
+		assert.Equal(t, "triggered", result.TriggerMessage)
+		assert.Equal(t, "reacted", result.ReactionOutput)
+		assert.Equal(t, uint(1), result.Attempt)
+		assert.Empty(t, result.Error)
+		assert.False(t, result.FinishedAt.Before(result.StartedAt))
+	case <-time.After(time.Second):
+		t.Fatal("reaction result was not saved")
+	}
+	assert.Equal(t, int32(1), actionCalls.Load())
+	assert.True(t, scheduler.IsScheduled(area.Id))
+}
+
+func TestAreaSchedulerStopArea(t *testing.T) {
+	area := newSchedulerArea(2)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	called := make(chan struct{}, 10)
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			called <- struct{}{}
+			return nil, nil
+		}))
+	mockServiceService.On("FindReactionByName", "test reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			return schemas.ReactionResult{}, nil
+		}))
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		new(MockAreaResultService),
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+	scheduler.StartArea(area)
+
+	select {
+	case <-called:
+	case <-time.After(time.Second):
+		t.Fatal("action was not called")
+	}
+	scheduler.StopArea(area.Id)
+	assert.False(t, scheduler.IsScheduled(area.Id))
+
+	select {
+	case <-called:
+		t.Fatal("action was called after the area was stopped")
+	case <-time.After(1500 * time.Millisecond):
+	}
+}
+
+func TestAreaSchedulerStopAreaCancelsRunningCheck(t *testing.T) {
+	area := newSchedulerArea(4)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	started := make(chan struct{})
+	cancelled := make(chan error, 1)
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			close(started)
+			<-ctx.Done()
+			cancelled <- ctx.Err()
+			return nil, ctx.Err()
+		}))
+	mockServiceService.On("FindReactionByName", "test reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			return schemas.ReactionResult{}, nil
+		}))
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		new(MockAreaResultService),
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+	scheduler.StartArea(area)
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("action was not called")
+	}
+	scheduler.StopArea(area.Id)
+
+	select {
+	case err := <-cancelled:
+		assert.True(t, errors.Is(err, context.Canceled))
+	case <-time.After(time.Second):
+		t.Fatal("running check was not cancelled")
+	}
+}
+
+func TestAreaSchedulerSavesReactionError(t *testing.T) {
+	area := newSchedulerArea(5)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			return []schemas.ActionResult{{Message: "triggered"}}, nil
+		}))
+	mockServiceService.On("FindReactionByName", "test reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			return schemas.ReactionResult{}, schemas.ErrTokenNotFound
+		}))
+
+	saved := make(chan schemas.AreaResult, 1)
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		select {
+		case saved <- args.Get(0).(schemas.AreaResult):
+		default:
+		}
+	})
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		mockAreaResultService,
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+	scheduler.StartArea(area)
+
+	select {
+	case result := <-saved:
+		assert.Equal(t, schemas.ErrTokenNotFound.Error(), result.Result)
+		assert.Equal(t, schemas.AreaResultFailure, result.Status)
+		assert.Equal(t, "triggered", result.TriggerMessage)
+		assert.Equal(t, schemas.ErrTokenNotFound.Error(), result.Error)
+	case <-time.After(time.Second):
+		t.Fatal("reaction error was not saved")
+	}
+}
+
+func TestAreaSchedulerSavesActionError(t *testing.T) {
+	area := newSchedulerArea(11)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			return nil, schemas.ErrTokenNotFound
+		}))
+	mockServiceService.On("FindReactionByName", "test reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			return schemas.ReactionResult{}, nil
+		}))
+
+	saved := make(chan schemas.AreaResult, 10)
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		saved <- args.Get(0).(schemas.AreaResult)
+	})
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		mockAreaResultService,
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+	scheduler.StartArea(area)
+
+	select {
+	case result := <-saved:
+		assert.Equal(t, schemas.AreaResultFailure, result.Status)
+		assert.Equal(t, schemas.ErrTokenNotFound.Error(), result.Error)
+		assert.Empty(t, result.TriggerMessage)
+		assert.Equal(t, uint(0), result.Attempt)
+	case <-time.After(time.Second):
+		t.Fatal("action error was not saved")
+	}
+}
+
+func TestAreaSchedulerRemovesDeletedArea(t *testing.T) {
+	area := newSchedulerArea(3)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).
+		Return(schemas.Area{}, fmt.Errorf("failed to find area by id: %w", schemas.ErrAreaNotFound))
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		new(test.MockServiceService),
+		newTokenService(),
+		new(MockAreaResultService),
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+	scheduler.StartArea(area)
+
+	assert.Eventually(t, func() bool {
+		return !scheduler.IsScheduled(area.Id)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func newBlockingReactionServiceService(
+	started chan struct{},
+	release chan struct{},
+) *test.MockServiceService {
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			return []schemas.ActionResult{{Message: "first"}, {Message: "second"}}, nil
+		}))
+	mockServiceService.On("FindReactionByName", "test reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			select {
+			case started <- struct{}{}:
+			default:
+			}
+			select {
+			case <-release:
+				return schemas.ReactionResult{Message: "reacted"}, nil
+			case <-ctx.Done():
+				return schemas.ReactionResult{}, ctx.Err()
+			}
+		}))
+	return mockServiceService
+}
+
+func TestAreaSchedulerStopDrainsRunningCheck(t *testing.T) {
+	area := newSchedulerArea(6)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	started := make(chan struct{}, 1)
+	release := make(chan struct{})
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Return()
+	triggerQueueRepository := newTriggerQueueRepository(t)
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		newBlockingReactionServiceService(started, release),
+		newTokenService(),
+		mockAreaResultService,
+		triggerQueueRepository,
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	scheduler.StartArea(area)
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("reaction was not called")
+	}
+	close(release)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, scheduler.Stop(ctx))
+	// the second trigger is either run before the stop or left unclaimed in the queue
+	triggers, err := triggerQueueRepository.FindByAreaId(area.Id)
+	assert.NoError(t, err)
+	for _, trigger := range triggers {
+		assert.Equal(t, "second", trigger.Message)
+		assert.Zero(t, trigger.Deliveries)
+	}
+	mockAreaResultService.AssertNumberOfCalls(t, "Save", 2-len(triggers))
+}
+
+func TestAreaSchedulerStopReleasesInterruptedTriggers(t *testing.T) {
+	area := newSchedulerArea(7)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	started := make(chan struct{}, 1)
+	mockAreaResultService := new(MockAreaResultService)
+	triggerQueueRepository := newTriggerQueueRepository(t)
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		newBlockingReactionServiceService(started, make(chan struct{})),
+		newTokenService(),
+		mockAreaResultService,
+		triggerQueueRepository,
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	scheduler.StartArea(area)
+
+	select {
+	case <-started:
+	case <-time.After(time.Second):
+		t.Fatal("reaction was not called")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err := scheduler.Stop(ctx)
+	assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	mockAreaResultService.AssertNotCalled(t, "Save", mock.Anything)
+	triggers, err := triggerQueueRepository.FindByAreaId(area.Id)
+	assert.NoError(t, err)
+	assert.Len(t, triggers, 2)
+	assert.Equal(t, "first", triggers[0].Message)
+	assert.Equal(t, uint64(1), triggers[0].WorkflowRunId)
+	assert.Equal(t, 0, triggers[0].ReactionPosition)
+	assert.Equal(t, uint(1), triggers[0].Deliveries)
+	assert.True(t, triggers[0].LockedUntil.Before(time.Now()))
+	assert.Equal(t, "second", triggers[1].Message)
+	assert.Zero(t, triggers[1].Deliveries)
+}
+
+func TestAreaSchedulerDeliversQueuedTriggers(t *testing.T) {
+	area := newSchedulerArea(8)
+	area.Reactions = []schemas.AreaReaction{
+		{
+			Position:       0,
+			Reaction:       schemas.Reaction{Name: "first reaction"},
+			ReactionOption: json.RawMessage(`{}`),
+		},
+		{
+			Position:       1,
+			Reaction:       schemas.Reaction{Name: "test reaction"},
+			ReactionOption: json.RawMessage(`{"body":"{{steps.0.message}}"}`),
+		},
+	}
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	firstCalls := atomic.Int32{}
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			return nil, nil
+		}))
+	mockServiceService.On("FindReactionByName", "first reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			firstCalls.Add(1)
+			return schemas.ReactionResult{Message: "first reacted"}, nil
+		}))
+	mockServiceService.On("FindReactionByName", "test reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			return schemas.ReactionResult{Message: "reacted"}, nil
+		}))
+
+	saved := make(chan schemas.AreaResult, 1)
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		saved <- args.Get(0).(schemas.AreaResult)
+	}).Once()
+
+	// the first step of the run completed before the previous delivery was interrupted
+	mockWorkflowRunRepository := new(test.MockWorkflowRunRepository)
+	mockWorkflowRunRepository.On("FindById", uint64(3)).Return(schemas.WorkflowRun{
+		Id:     3,
+		AreaId: area.Id,
+		Status: schemas.WorkflowRunRunning,
+		Steps: schemas.WorkflowSteps{
+			{
+				Position: 0,
+				Status:   schemas.WorkflowStepSuccess,
+				Output:   json.RawMessage(`{"message":"first reacted"}`),
+			},
+			{Position: 1, Status: schemas.WorkflowStepPending},
+		},
+	}, nil)
+	mockWorkflowRunRepository.On("Update", mock.Anything).Return(nil)
+
+	triggerQueueRepository := newTriggerQueueRepository(t)
+	_, err := triggerQueueRepository.Enqueue(schemas.QueuedTrigger{
+		AreaId:           area.Id,
+		Message:          "queued",
+		ReactionPosition: 1,
+		WorkflowRunId:    3,
+	})
+	assert.NoError(t, err)
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		mockAreaResultService,
+		triggerQueueRepository,
+		mockWorkflowRunRepository,
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+
+	select {
+	case result := <-saved:
+		assert.Equal(t, "reacted", result.Result)
+		assert.Equal(t, "queued", result.TriggerMessage)
+		assert.Equal(t, uint64(3), result.WorkflowRunId)
+		assert.JSONEq(t, `{"body":"first reacted"}`, string(result.ReactionInput))
+	case <-time.After(time.Second):
+		t.Fatal("queued trigger was not delivered")
+	}
+	assert.Eventually(t, func() bool {
+		triggers, err := triggerQueueRepository.FindByAreaId(area.Id)
+		return err == nil && len(triggers) == 0
+	}, time.Second, 10*time.Millisecond, "queued trigger was not acknowledged")
+	assert.Zero(t, firstCalls.Load())
+}
+
+func TestAreaSchedulerRetriesActionAfterTokenRefresh(t *testing.T) {
+	area := newSchedulerArea(9)
+	area.UserId = 4
+	area.Action.ServiceId = 2
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	actionCalls := atomic.Int32{}
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			if actionCalls.Add(1) == 1 {
+				return nil, fmt.Errorf("error status code 401: %w", schemas.ErrUnauthorized)
+			}
+			return []schemas.ActionResult{{Message: "triggered"}}, nil
+		}))
+	mockServiceService.On("FindReactionByName", "test reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			return schemas.ReactionResult{Message: "reacted"}, nil
+		}))
+
+	mockTokenService := newTokenService()
+
+	saved := make(chan schemas.AreaResult, 1)
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		select {
+		case saved <- args.Get(0).(schemas.AreaResult):
+		default:
+		}
+	})
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		mockTokenService,
+		mockAreaResultService,
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+	scheduler.StartArea(area)
+
+	select {
+	case result := <-saved:
+		assert.Equal(t, "reacted", result.Result)
+	case <-time.After(time.Second):
+		t.Fatal("action was not retried")
+	}
+	assert.Equal(t, int32(2), actionCalls.Load())
+	mockTokenService.AssertCalled(t, "RefreshToken", area.UserId, area.Action.ServiceId)
+}
+
+func TestAreaSchedulerSkipsAreaWhenTokenNeedsReauth(t *testing.T) {
+	area := newSchedulerArea(10)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	called := make(chan struct{}, 10)
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			called <- struct{}{}
+			return nil, nil
+		}))
+	mockServiceService.On("FindReactionByName", "test reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			return schemas.ReactionResult{}, nil
+		}))
+
+	mockTokenService := new(test.MockTokenService)
+	mockTokenService.On("RefreshTokenIfExpiring", mock.Anything, mock.Anything).
+		Return(schemas.ErrTokenNeedsReauth)
+
+	saved := make(chan schemas.AreaResult, 10)
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		saved <- args.Get(0).(schemas.AreaResult)
+	})
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		mockTokenService,
+		mockAreaResultService,
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+	scheduler.StartArea(area)
+
+	select {
+	case result := <-saved:
+		assert.Equal(t, schemas.AreaResultSkipped, result.Status)
+		assert.Contains(t, result.Error, schemas.ErrTokenNeedsReauth.Error())
+		assert.Empty(t, result.TriggerMessage)
+	case <-time.After(time.Second):
+		t.Fatal("skipped run was not saved")
+	}
+	select {
+	case <-called:
+		t.Fatal("action ran with a token needing to be authorized again")
+	case <-time.After(100 * time.Millisecond):
+	}
+}
+
+func TestAreaSchedulerRendersActionPlaceholders(t *testing.T) {
+	area := newSchedulerArea(12)
+	area.ReactionOption = json.RawMessage(`{"message":"new commit by {{action.author}}"}`)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			return []schemas.ActionResult{{
+				Message: "triggered",
+				Payload: json.RawMessage(`{"author":"octocat"}`),
+			}}, nil
+		}))
+	options := make(chan json.RawMessage, 1)
+	mockServiceService.On("FindReactionByName", "test reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			options <- option
+			return schemas.ReactionResult{Message: "reacted"}, nil
+		}))
+
+	saved := make(chan schemas.AreaResult, 1)
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		saved <- args.Get(0).(schemas.AreaResult)
+	}).Once()
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		mockAreaResultService,
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+	scheduler.StartArea(area)
+
+	select {
+	case result := <-saved:
+		assert.JSONEq(t, `{"message":"new commit by octocat"}`, string(<-options))
+		assert.JSONEq(t, `{"message":"new commit by octocat"}`, string(result.ReactionInput))
+		assert.JSONEq(t, `{"author":"octocat"}`, string(result.TriggerPayload))
+	case <-time.After(time.Second):
+		t.Fatal("reaction result was not saved")
+	}
+}
+
+func newFanOutArea(id uint64, continueOnFailure bool) schemas.Area {
+	area := newSchedulerArea(id)
+	area.Reactions = []schemas.AreaReaction{
+		{
+			Position:          0,
+			Reaction:          schemas.Reaction{Name: "failing reaction"},
+			ReactionOption:    json.RawMessage(`{}`),
+			ContinueOnFailure: continueOnFailure,
+		},
+		{
+			Position:       1,
+			Reaction:       schemas.Reaction{Name: "test reaction"},
+			ReactionOption: json.RawMessage(`{}`),
+		},
+	}
+	return area
+}
+
+func runFanOutArea(t *testing.T, area schemas.Area) ([]schemas.AreaResult, int32) {
+	t.Helper()
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	secondCalls := atomic.Int32{}
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			return []schemas.ActionResult{{Message: "triggered"}}, nil
+		}))
+	mockServiceService.On("FindReactionByName", "failing reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			return schemas.ReactionResult{}, errors.New("mail not sent")
+		}))
+	mockServiceService.On("FindReactionByName", "test reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			secondCalls.Add(1)
+			return schemas.ReactionResult{Message: "reacted"}, nil
+		}))
+
+	saved := make(chan schemas.AreaResult, 2)
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		saved <- args.Get(0).(schemas.AreaResult)
+	})
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		mockAreaResultService,
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	scheduler.StartArea(area)
+	time.Sleep(200 * time.Millisecond)
+	assert.NoError(t, scheduler.Stop(context.Background()))
+	close(saved)
+
+	results := []schemas.AreaResult{}
+	for result := range saved {
+		results = append(results, result)
+	}
+	return results, secondCalls.Load()
+}
+
+func TestAreaSchedulerRunsEveryReactionOfArea(t *testing.T) {
+	results, secondCalls := runFanOutArea(t, newFanOutArea(13, true))
+
+	assert.Equal(t, int32(1), secondCalls)
+	assert.Len(t, results, 2)
+	assert.Equal(t, "failing reaction", results[0].ReactionName)
+	assert.Equal(t, 0, results[0].ReactionPosition)
+	assert.Equal(t, schemas.AreaResultFailure, results[0].Status)
+	assert.Equal(t, "test reaction", results[1].ReactionName)
+	assert.Equal(t, 1, results[1].ReactionPosition)
+	assert.Equal(t, schemas.AreaResultSuccess, results[1].Status)
+}
+
+func TestAreaSchedulerStopsReactionsAfterFailure(t *testing.T) {
+	results, secondCalls := runFanOutArea(t, newFanOutArea(14, false))
+
+	assert.Equal(t, int32(0), secondCalls)
+	assert.Len(t, results, 1)
+	assert.Equal(t, "failing reaction", results[0].ReactionName)
+	assert.Equal(t, schemas.AreaResultFailure, results[0].Status)
+}
+
+func TestAreaSchedulerSkipsFilteredOutTrigger(t *testing.T) {
+	area := newSchedulerArea(15)
+	area.Filter = json.RawMessage(`{"operator":"contains","field":"subject","value":"urgent"}`)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	reactionCalls := atomic.Int32{}
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			return []schemas.ActionResult{
+				{Message: "newsletter", Payload: json.RawMessage(`{"subject":"weekly news"}`)},
+				{Message: "incident", Payload: json.RawMessage(`{"subject":"urgent: down"}`)},
+			}, nil
+		}))
+	mockServiceService.On("FindReactionByName", "test reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			reactionCalls.Add(1)
+			return schemas.ReactionResult{Message: "reacted"}, nil
+		}))
+
+	saved := make(chan schemas.AreaResult, 2)
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		saved <- args.Get(0).(schemas.AreaResult)
+	})
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		mockAreaResultService,
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+	scheduler.StartArea(area)
+
+	for _, expected := range []struct {
+		trigger string
+		status  schemas.AreaResultStatus
+	}{
+		{"newsletter", schemas.AreaResultSkipped},
+		{"incident", schemas.AreaResultSuccess},
+	} {
+		select {
+		case result := <-saved:
+			assert.Equal(t, expected.trigger, result.TriggerMessage)
+			assert.Equal(t, expected.status, result.Status)
+		case <-time.After(time.Second):
+			t.Fatal("trigger result was not saved")
+		}
+	}
+	assert.Equal(t, int32(1), reactionCalls.Load())
+}
+
+func TestAreaSchedulerPassesStepOutputsToNextSteps(t *testing.T) {
+	area := newSchedulerArea(16)
+	area.Reactions = []schemas.AreaReaction{
+		{
+			Position:       0,
+			Reaction:       schemas.Reaction{Name: "latest run"},
+			ReactionOption: json.RawMessage(`{}`),
+		},
+		{
+			Position:       1,
+			Reaction:       schemas.Reaction{Name: "failing reaction"},
+			ReactionOption: json.RawMessage(`{"body":"{{steps.0.name}} {{steps.0.message}}"}`),
+		},
+		{
+			Position:       2,
+			Reaction:       schemas.Reaction{Name: "test reaction"},
+			ReactionOption: json.RawMessage(`{}`),
+		},
+	}
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			return []schemas.ActionResult{{Message: "triggered"}}, nil
+		}))
+	mockServiceService.On("FindReactionByName", "latest run").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			return schemas.ReactionResult{
+				Message: "CI workflow run",
+				Payload: json.RawMessage(`{"name":"CI"}`),
+			}, nil
+		}))
+	mockServiceService.On("FindReactionByName", "failing reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			return schemas.ReactionResult{}, errors.New("mail not sent")
+		}))
+	mockServiceService.On("FindReactionByName", "test reaction").
+		Return(service.ReactionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) (schemas.ReactionResult, error) {
+			return schemas.ReactionResult{Message: "reacted"}, nil
+		}))
+
+	saved := make(chan schemas.AreaResult, 3)
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		saved <- args.Get(0).(schemas.AreaResult)
+	})
+	finished := make(chan schemas.WorkflowRun, 1)
+	mockWorkflowRunRepository := new(test.MockWorkflowRunRepository)
+	mockWorkflowRunRepository.On("Save", mock.Anything).Return(uint64(7), nil)
+	mockWorkflowRunRepository.On("Update", mock.Anything).Run(func(args mock.Arguments) {
+		// the run is also updated after every completed step
+		if run := args.Get(0).(schemas.WorkflowRun); run.Status != schemas.WorkflowRunRunning {
+			finished <- run
+		}
+	}).Return(nil)
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		mockAreaResultService,
+		newTriggerQueueRepository(t),
+		mockWorkflowRunRepository,
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+	scheduler.StartArea(area)
+
+	var run schemas.WorkflowRun
+	select {
+	case run = <-finished:
+	case <-time.After(time.Second):
+		t.Fatal("workflow run was not finished")
+	}
+	assert.Equal(t, uint64(7), run.Id)
+	assert.Equal(t, schemas.WorkflowRunFailure, run.Status)
+	assert.Len(t, run.Steps, 3)
+	assert.Equal(t, schemas.WorkflowStepSuccess, run.Steps[0].Status)
+	assert.JSONEq(t, `{"name":"CI","message":"CI workflow run"}`, string(run.Steps[0].Output))
+	assert.Equal(t, schemas.WorkflowStepFailure, run.Steps[1].Status)
+	assert.Equal(t, "mail not sent", run.Steps[1].Error)
+	assert.Equal(t, schemas.WorkflowStepSkipped, run.Steps[2].Status)
+
+	assert.Len(t, saved, 2)
+	<-saved
+	result := <-saved
+	assert.Equal(t, uint64(7), result.WorkflowRunId)
+	assert.JSONEq(t, `{"body":"CI CI workflow run"}`, string(result.ReactionInput))
+}
+
+func runRetriedArea(
+	t *testing.T,
+	area schemas.Area,
+	reaction service.ReactionFunc,
+	mockDeadLetterRepository *test.MockDeadLetterRepository,
+) schemas.AreaResult {
+	t.Helper()
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			return []schemas.ActionResult{{Message: "triggered"}}, nil
+		}))
+	mockServiceService.On("FindReactionByName", "test reaction").Return(reaction)
+
+	saved := make(chan schemas.AreaResult, 1)
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		select {
+		case saved <- args.Get(0).(schemas.AreaResult):
+		default:
+		}
+	})
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		mockAreaResultService,
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		mockDeadLetterRepository,
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+	scheduler.StartArea(area)
+
+	select {
+	case result := <-saved:
+		return result
+	case <-time.After(time.Second):
+		t.Fatal("reaction result was not saved")
+		return schemas.AreaResult{}
+	}
+}
+
+func TestAreaSchedulerRetriesTransientReactionError(t *testing.T) {
+	area := newSchedulerArea(17)
+	area.RetryPolicy = schemas.RetryPolicy{MaxAttempts: 3, InitialBackoffMs: 1}
+
+	calls := atomic.Int32{}
+	reaction := service.ReactionFunc(func(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) (schemas.ReactionResult, error) {
+		if calls.Add(1) == 1 {
+			return schemas.ReactionResult{}, fmt.Errorf("status 503: %w", schemas.ErrTransient)
+		}
+		return schemas.ReactionResult{Message: "reacted"}, nil
+	})
+	mockDeadLetterRepository := newDeadLetterRepository()
+
+	result := runRetriedArea(t, area, reaction, mockDeadLetterRepository)
+
+	assert.Equal(t, schemas.AreaResultSuccess, result.Status)
+	assert.Equal(t, uint(2), result.Attempt)
+	assert.Equal(t, int32(2), calls.Load())
+	mockDeadLetterRepository.AssertNotCalled(t, "Save", mock.Anything)
+}
+
+func TestAreaSchedulerDeadLettersExhaustedTrigger(t *testing.T) {
+	area := newSchedulerArea(18)
+	area.UserId = 3
+	area.RetryPolicy = schemas.RetryPolicy{MaxAttempts: 3, InitialBackoffMs: 1}
+
+	calls := atomic.Int32{}
+	reaction := service.ReactionFunc(func(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) (schemas.ReactionResult, error) {
+		calls.Add(1)
+		return schemas.ReactionResult{}, fmt.Errorf("status 503: %w", schemas.ErrTransient)
+	})
+	deadLetters := make(chan schemas.DeadLetter, 1)
+	mockDeadLetterRepository := new(test.MockDeadLetterRepository)
+	mockDeadLetterRepository.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		deadLetters <- args.Get(0).(schemas.DeadLetter)
+	}).Return(nil)
+
+	result := runRetriedArea(t, area, reaction, mockDeadLetterRepository)
+
+	assert.Equal(t, schemas.AreaResultFailure, result.Status)
+	assert.Equal(t, uint(3), result.Attempt)
+	assert.Equal(t, int32(3), calls.Load())
+	select {
+	case deadLetter := <-deadLetters:
+		assert.Equal(t, uint64(3), deadLetter.UserId)
+		assert.Equal(t, area.Id, deadLetter.AreaId)
+		assert.Equal(t, uint64(1), deadLetter.WorkflowRunId)
+		assert.Equal(t, 0, deadLetter.ReactionPosition)
+		assert.Equal(t, "test reaction", deadLetter.ReactionName)
+		assert.Equal(t, "triggered", deadLetter.TriggerMessage)
+		assert.True(t, deadLetter.Transient)
+		assert.Equal(t, uint(3), deadLetter.Attempts)
+	case <-time.After(time.Second):
+		t.Fatal("trigger was not dead-lettered")
+	}
+}
+
+func TestAreaSchedulerChecksAreaAcquiredOnRebalance(t *testing.T) {
+	area := newSchedulerArea(19)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			return nil, errors.New("checked")
+		}))
+
+	saved := make(chan schemas.AreaResult, 1)
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		saved <- args.Get(0).(schemas.AreaResult)
+	}).Once()
+
+	// the area was created on another replica, and this replica acquired it
+	mockAreaLeaseService := new(test.MockAreaLeaseService)
+	mockAreaLeaseService.On("NodeId").Return("node")
+	mockAreaLeaseService.On("Owns", area.Id).Return(true)
+	mockAreaLeaseService.On("Rebalance").Return([]uint64{area.Id}, []uint64(nil), nil)
+	mockAreaLeaseService.On("Leave").Return(nil)
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		mockAreaResultService,
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		mockAreaLeaseService,
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+
+	select {
+	case result := <-saved:
+		assert.Equal(t, "checked", result.Error)
+	case <-time.After(time.Second):
+		t.Fatal("acquired area was not checked")
+	}
+	assert.True(t, scheduler.IsScheduled(area.Id))
+	assert.NoError(t, scheduler.Stop(context.Background()))
+	mockAreaLeaseService.AssertCalled(t, "Leave")
+}
+
+func TestAreaSchedulerSkipsAreaOwnedByAnotherReplica(t *testing.T) {
+	area := newSchedulerArea(20)
+	checked := make(chan struct{}, 1)
+	mockRepo := new(test.MockAreaRepository)
+
+	mockServiceService := new(test.MockServiceService)
+	mockAreaLeaseService := new(test.MockAreaLeaseService)
+	mockAreaLeaseService.On("NodeId").Return("node")
+	mockAreaLeaseService.On("Owns", area.Id).Run(func(args mock.Arguments) {
+		select {
+		case checked <- struct{}{}:
+		default:
+		}
+	}).Return(false)
+	mockAreaLeaseService.On("Rebalance").Return([]uint64(nil), []uint64(nil), nil)
+	mockAreaLeaseService.On("Leave").Return(nil)
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		new(MockAreaResultService),
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		mockAreaLeaseService,
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	scheduler.StartArea(area)
+
+	select {
+	case <-checked:
+	case <-time.After(time.Second):
+		t.Fatal("area check was not due")
+	}
+	assert.NoError(t, scheduler.Stop(context.Background()))
+	mockServiceService.AssertNotCalled(t, "FindActionByName", mock.Anything)
+	mockRepo.AssertNotCalled(t, "FindById", mock.Anything)
+	assert.True(t, scheduler.IsScheduled(area.Id))
+}
+
+func TestAreaSchedulerAppliesAreaChangeOfAnotherReplica(t *testing.T) {
+	area := newSchedulerArea(21)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			return nil, errors.New("checked")
+		}))
+
+	saved := make(chan schemas.AreaResult, 1)
+	mockAreaResultService := new(MockAreaResultService)
+	mockAreaResultService.On("Save", mock.Anything).Run(func(args mock.Arguments) {
+		saved <- args.Get(0).(schemas.AreaResult)
+	}).Once()
+
+	// the area was created through another replica
+	mockNotificationRepository := new(test.MockNotificationRepository)
+	mockNotificationRepository.On("Listen", mock.Anything, mock.Anything, mock.Anything).
+		Run(func(args mock.Arguments) {
+			handle := args.Get(2).(func(channel string, payload string))
+			handle(schemas.AreaChangeChannel, `{"area_id":21,"node_id":"other"}`)
+			<-args.Get(0).(context.Context).Done()
+		}).
+		Return(context.Canceled)
+	rebalances := atomic.Int32{}
+	mockAreaLeaseService := new(test.MockAreaLeaseService)
+	mockAreaLeaseService.On("NodeId").Return("node")
+	mockAreaLeaseService.On("Owns", area.Id).Return(true)
+	mockAreaLeaseService.On("Rebalance").Run(func(args mock.Arguments) {
+		rebalances.Add(1)
+	}).Return([]uint64(nil), []uint64(nil), nil)
+	mockAreaLeaseService.On("Leave").Return(nil)
+
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		mockAreaResultService,
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		mockAreaLeaseService,
+		mockNotificationRepository,
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+
+	select {
+	case result := <-saved:
+		assert.Equal(t, "checked", result.Error)
+	case <-time.After(time.Second):
+		t.Fatal("changed area was not checked")
+	}
+	assert.True(t, scheduler.IsScheduled(area.Id))
+	// the change triggers a rebalance on top of the one of the start
+	assert.Eventually(t, func() bool {
+		return rebalances.Load() >= 2
+	}, time.Second, 10*time.Millisecond)
+	mockNotificationRepository.AssertNotCalled(t, "Notify", mock.Anything, mock.Anything)
+}
+
+func TestAreaSchedulerCheckAreaChecksRightAwayWithoutNotifying(t *testing.T) {
+	area := newSchedulerArea(22)
+	area.ActionRefreshRate = 3600
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	checked := make(chan struct{}, 1)
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindActionByName", "test action").
+		Return(service.ActionFunc(func(
+			ctx context.Context,
+			option json.RawMessage,
+			area schemas.Area,
+		) ([]schemas.ActionResult, error) {
+			checked <- struct{}{}
+			return nil, nil
+		}))
+
+	mockNotificationRepository := newNotificationRepository()
+	scheduler := service.NewAreaScheduler(
+		mockRepo,
+		mockServiceService,
+		newTokenService(),
+		new(MockAreaResultService),
+		newTriggerQueueRepository(t),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		mockNotificationRepository,
+		newDropboxLongpoller(),
+		1,
+	)
+	scheduler.Start()
+	defer scheduler.Stop(context.Background())
+	scheduler.StartArea(area)
+
+	select {
+	case <-checked:
+	case <-time.After(time.Second):
+		t.Fatal("area was not checked")
+	}
+	// the next check is an hour away, until the change of the provider is reported
+	assert.Eventually(t, func() bool {
+		scheduler.CheckArea(area.Id)
+		select {
+		case <-checked:
+			return true
+		default:
+			return false
+		}
+	}, time.Second, 10*time.Millisecond)
+	mockNotificationRepository.AssertNumberOfCalls(t, "Notify", 1)
+}
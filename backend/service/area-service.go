@@ -7,15 +7,18 @@ import (
 
 	"area/repository"
 	"area/schemas"
+	"area/tools"
 )
 
 type AreaService interface {
 	FindAll() (areas []schemas.Area, err error)
 	CreateArea(result schemas.AreaMessage, token string) (string, error)
-	InitArea(areaStartValue schemas.Area)
 	AreaExist(id uint64) bool
 	GetUserAreas(token string) ([]schemas.Area, error)
-	UpdateUserArea(token string, areaToUpdate schemas.Area) (updatedArea schemas.Area, err error)
+	UpdateUserArea(
+		token string,
+		update schemas.AreaUpdateMessage,
+	) (updatedArea schemas.Area, err error)
 	DeleteUserArea(
 		token string,
 		areaToDelete struct{ Id uint64 },
@@ -30,6 +33,7 @@ type AreaService interface {
 // - serviceUser: An instance of UserService for user-related operations.
 // - serviceService: An instance of ServiceService for service-related operations.
 // - areaResultService: An instance of AreaResultService for handling area results.
+// - scheduler: An instance of AreaScheduler running the action and reaction of the areas.
 type areaService struct {
 	repository        repository.AreaRepository
 	actionService     ActionService
@@ -37,11 +41,12 @@ type areaService struct {
 	serviceUser       UserService
 	serviceService    ServiceService
 	areaResultService AreaResultService
+	scheduler         AreaScheduler
 }
 
 // NewAreaService creates a new instance of AreaService with the provided dependencies.
 // It initializes the areaService struct with the given repository, actionService,
-// reactionService, serviceUser, serviceService, and areaResultService.
+// reactionService, serviceUser, serviceService, areaResultService, and scheduler.
 //
 // Parameters:
 //   - repository: an instance of AreaRepository for data access.
@@ -50,6 +55,7 @@ type areaService struct {
 //   - reactionService: an instance of ReactionService for reaction-related operations.
 //   - serviceUser: an instance of UserService for user-related operations.
 //   - areaResultService: an instance of AreaResultService for area result-related operations.
+//   - scheduler: an instance of AreaScheduler running the areas.
 //
 // Returns:
 //   - AreaService: a new instance of AreaService initialized with the provided dependencies.
@@ -60,6 +66,7 @@ func NewAreaService(
 	reactionService ReactionService,
 	serviceUser UserService,
 	areaResultService AreaResultService,
+	scheduler AreaScheduler,
 ) AreaService {
 	newService := areaService{
 		repository:        repository,
@@ -68,6 +75,7 @@ func NewAreaService(
 		serviceUser:       serviceUser,
 		serviceService:    serviceService,
 		areaResultService: areaResultService,
+		scheduler:         scheduler,
 	}
 	return &newService
 }
@@ -107,9 +115,117 @@ func compareMaps(map1, map2 map[string]interface{}) bool {
 	return true
 }
 
+// validateOption checks an action or a reaction option against the JSON Schema declared
+// by its service. An action or a reaction saved without a schema falls back to comparing
+// the option with its default option.
+//
+// Parameters:
+//   - name: The name of the option, action or reaction.
+//   - schema: The JSON Schema of the option, empty if the service did not declare one.
+//   - defaultOption: The default option of the action or the reaction.
+//   - option: The option provided by the user.
+//   - templated: Whether the option can hold {{action.field}} or {{steps.N.field}} placeholders.
+//
+// Returns:
+//   - error: A *schemas.OptionValidationError listing the fields that do not match the
+//     schema, or an error if the option can not be decoded.
+func validateOption(
+	name string,
+	schema json.RawMessage,
+	defaultOption json.RawMessage,
+	option json.RawMessage,
+	templated bool,
+) error {
+	if len(schema) != 0 {
+		return tools.ValidateOption(name, schema, option, templated)
+	}
+
+	// check if the json key are the same as default option, json value can be different
+	var defaultOptionMap, providedOptionMap map[string]interface{}
+	if err := json.Unmarshal(defaultOption, &defaultOptionMap); err != nil {
+		return fmt.Errorf("can't unmarshal default %s option: %w", name, err)
+	}
+	if err := json.Unmarshal(option, &providedOptionMap); err != nil {
+		return fmt.Errorf("can't unmarshal provided %s option: %w", name, err)
+	}
+	if !compareMaps(defaultOptionMap, providedOptionMap) {
+		return fmt.Errorf("%s option does not match default option type", name)
+	}
+	return nil
+}
+
+// newAreaReactions builds the ordered reactions of an area from the reactions of an area message.
+// Every reaction option is validated against the JSON Schema of its reaction, and its
+// {{steps.N.field}} placeholders must name the reactions running before it.
+//
+// Parameters:
+//   - messages: The reactions of the area message, in order.
+//
+// Returns:
+//   - []schemas.AreaReaction: The reactions of the area.
+//   - error: An error if a reaction can not be found or if its option does not match its schema.
+func (service *areaService) newAreaReactions(
+	messages []schemas.AreaReactionMessage,
+) ([]schemas.AreaReaction, error) {
+	reactions := make([]schemas.AreaReaction, 0, len(messages))
+	for index, message := range messages {
+		reaction, err := service.reactionService.FindById(message.ReactionId)
+		if err != nil {
+			return nil, fmt.Errorf("can't find reaction by id: %w", err)
+		}
+		name := "reaction"
+		if len(messages) > 1 {
+			name = fmt.Sprintf("reactions[%d]", index)
+		}
+		err = validateOption(
+			name,
+			reaction.OptionSchema,
+			reaction.Option,
+			message.ReactionOption,
+			true,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("can't validate %s option: %w", name, err)
+		}
+		err = tools.ValidateStepPlaceholders(name, message.ReactionOption, index)
+		if err != nil {
+			return nil, fmt.Errorf("can't validate %s option: %w", name, err)
+		}
+		reactions = append(reactions, schemas.AreaReaction{
+			Position:          index,
+			ReactionId:        reaction.Id,
+			Reaction:          reaction,
+			ReactionOption:    message.ReactionOption,
+			ContinueOnFailure: message.ContinueOnFailure,
+		})
+	}
+	return reactions, nil
+}
+
+// areaReactions returns the ordered reactions of an area. An area created before it could
+// have several reactions has no reaction list and runs its single reaction.
+//
+// Parameters:
+//   - area: The area whose reactions are returned.
+//
+// Returns:
+//   - []schemas.AreaReaction: The reactions of the area, in order.
+func areaReactions(area schemas.Area) []schemas.AreaReaction {
+	if len(area.Reactions) > 0 {
+		return area.Reactions
+	}
+	return []schemas.AreaReaction{{
+		AreaId:         area.Id,
+		ReactionId:     area.ReactionId,
+		Reaction:       area.Reaction,
+		ReactionOption: area.ReactionOption,
+	}}
+}
+
 // CreateArea creates a new area with the provided action and reaction options.
-// It validates the provided options against the default options for the specified action and reaction.
-// If the options are valid, it saves the new area to the repository and initializes it.
+// It validates the provided options against the JSON Schema of the specified action and reactions,
+// and validates the trigger filter of the area.
+// If the options are valid, it saves the new area to the repository and schedules it.
 //
 // Parameters:
 //   - result: schemas.AreaMessage containing the action and reaction options, title, and description.
@@ -119,16 +235,12 @@ func compareMaps(map1, map2 map[string]interface{}) bool {
 //   - string: A success message if the area is created successfully.
 //   - error: An error message if any step in the process fails.
 func (service *areaService) CreateArea(result schemas.AreaMessage, token string) (string, error) {
-	var actionOption, reactionOption json.RawMessage
+	var actionOption json.RawMessage
 
 	if err := json.Unmarshal(result.ActionOption, &actionOption); err != nil {
 		return "", fmt.Errorf("can't unmarshal action option: %w", err)
 	}
 
-	if err := json.Unmarshal(result.ReactionOption, &reactionOption); err != nil {
-		return "", fmt.Errorf("can't unmarshal reaction option: %w", err)
-	}
-
 	user, err := service.serviceUser.GetUserInfo(token)
 	if err != nil {
 		return "", fmt.Errorf("can't get user info: %w", err)
@@ -138,32 +250,31 @@ func (service *areaService) CreateArea(result schemas.AreaMessage, token string)
 	if err != nil {
 		return "", fmt.Errorf("can't find action by id: %w", err)
 	}
-	areaReaction, err := service.reactionService.FindById(result.ReactionId)
-	if err != nil {
-		return "", fmt.Errorf("can't find reaction by id: %w", err)
-	}
-
-	// check if the json key are the same as default areaAction.Option, json value can be different
-	var defaultActionOption, providedActionOption map[string]interface{}
-	if err := json.Unmarshal(areaAction.Option, &defaultActionOption); err != nil {
-		return "", fmt.Errorf("can't unmarshal default action option: %w", err)
-	}
-	if err := json.Unmarshal(result.ActionOption, &providedActionOption); err != nil {
-		return "", fmt.Errorf("can't unmarshal provided action option: %w", err)
+	reactionMessages := result.Reactions
+	if len(reactionMessages) == 0 {
+		reactionMessages = []schemas.AreaReactionMessage{{
+			ReactionId:     result.ReactionId,
+			ReactionOption: result.ReactionOption,
+		}}
 	}
-	if !compareMaps(defaultActionOption, providedActionOption) {
-		return "", fmt.Errorf("action option does not match default option type")
+	reactions, err := service.newAreaReactions(reactionMessages)
+	if err != nil {
+		return "", err
 	}
 
-	var defaultReactionOption, providedReactionOption map[string]interface{}
-	if err := json.Unmarshal(areaReaction.Option, &defaultReactionOption); err != nil {
-		return "", fmt.Errorf("can't unmarshal default reaction option: %w", err)
-	}
-	if err := json.Unmarshal(result.ReactionOption, &providedReactionOption); err != nil {
-		return "", fmt.Errorf("can't unmarshal provided reaction option: %w", err)
+	err = validateOption(
+		"action",
+		areaAction.OptionSchema,
+		areaAction.Option,
+		result.ActionOption,
+		false,
+	)
+	if err != nil {
+		return "", fmt.Errorf("can't validate action option: %w", err)
 	}
-	if !compareMaps(defaultReactionOption, providedReactionOption) {
-		return "", fmt.Errorf("reaction option does not match default option type")
+	err = tools.ValidateTriggerFilter(result.Filter)
+	if err != nil {
+		return "", fmt.Errorf("can't validate trigger filter: %w", err)
 	}
 
 	defaultVariavle := struct{}{}
@@ -172,15 +283,23 @@ func (service *areaService) CreateArea(result schemas.AreaMessage, token string)
 		return "", fmt.Errorf("can't marshal default storage variable: %w", err)
 	}
 
+	retryPolicy := schemas.DefaultRetryPolicy
+	if result.RetryPolicy != nil {
+		retryPolicy = *result.RetryPolicy
+	}
+
 	newArea := schemas.Area{
 		User:              user,
 		ActionOption:      result.ActionOption,
-		ReactionOption:    result.ReactionOption,
+		ReactionOption:    reactions[0].ReactionOption,
 		Title:             result.Title,
 		Description:       result.Description,
 		Enable:            true,
 		Action:            areaAction,
-		Reaction:          areaReaction,
+		Reaction:          reactions[0].Reaction,
+		Reactions:         reactions,
+		Filter:            result.Filter,
+		RetryPolicy:       retryPolicy,
 		ActionRefreshRate: uint64(result.ActionRefreshRate),
 		StorageVariable:   defaultStorageVariable,
 	}
@@ -191,7 +310,7 @@ func (service *areaService) CreateArea(result schemas.AreaMessage, token string)
 	}
 
 	newArea.Id = id
-	service.InitArea(newArea)
+	service.scheduler.StartArea(newArea)
 	return "Area created successfully", nil
 }
 
@@ -210,71 +329,6 @@ func (service *areaService) AreaExist(id uint64) bool {
 	return err == nil
 }
 
-// InitArea initializes the area with the given start value and starts two goroutines.
-// The first goroutine continuously checks if the area exists and is enabled, then performs the action associated with the area.
-// The second goroutine waits for the action result from the first goroutine, performs the reaction associated with the area, and saves the result.
-//
-// Parameters:
-//   - areaStartValue: The initial value of the area to be initialized.
-//
-// The function uses a channel to communicate between the two goroutines.
-func (service *areaService) InitArea(areaStartValue schemas.Area) {
-	channelArea := make(chan string)
-	println("go routine action " + areaStartValue.Action.Name)
-	println("reaction " + areaStartValue.Reaction.Name)
-	go func(areaStartValue schemas.Area, channelArea chan string) {
-		// get the action with the id
-		for service.AreaExist(areaStartValue.Id) {
-			area, err := service.repository.FindById(areaStartValue.Id)
-			if err != nil {
-				println("error")
-				return
-			}
-			// println(area.Action.Name)
-			action := service.serviceService.FindActionByName(area.Action.Name)
-			if action == nil {
-				println("action not found")
-				return
-			}
-
-			if area.Enable {
-				action(channelArea, area.ActionOption, area)
-			}
-		}
-		channelArea <- "response to clear"
-	}(areaStartValue, channelArea)
-	// area
-	fmt.Printf("go routine area %+v\n", areaStartValue)
-	go func(areaStartValue schemas.Area, channelArea chan string) {
-		// check if the area is in the databse
-		for service.AreaExist(areaStartValue.Id) {
-			// check if the area is enable in the databse
-			area, err := service.repository.FindById(areaStartValue.Id)
-			if err != nil {
-				return
-			}
-
-			reaction := service.serviceService.FindReactionByName(area.Reaction.Name)
-
-			if area.Enable {
-				resultAction := <-channelArea
-				if resultAction == "response to clear" {
-					return
-				}
-				resultReaction := reaction(area.ReactionOption, area)
-				service.areaResultService.Save(schemas.AreaResult{
-					Area:   area,
-					Result: resultReaction,
-				})
-				println("result action")
-				println(resultAction)
-				println("result reaction")
-				println(resultReaction)
-			}
-		}
-	}(areaStartValue, channelArea)
-}
-
 // containsArea checks if a given area is present in a list of areas.
 // It takes a slice of schemas.Area and a single schemas.Area as input parameters.
 // It returns true if the area is found in the list, otherwise it returns false.
@@ -309,12 +363,36 @@ func (service *areaService) GetUserAreas(token string) ([]schemas.Area, error) {
 	return areas, nil
 }
 
-// UpdateUserArea updates the area information for a user based on the provided token and area details.
-// It retrieves the user information using the token, finds the user's areas, and updates the specified area if it exists.
+// hasOption reports whether an option is present in an update, a missing or null option
+// keeping the current one.
+func hasOption(option json.RawMessage) bool {
+	return len(option) != 0 && string(option) != "null"
+}
+
+// optionChanged reports whether the provided option differs from the current one,
+// ignoring the formatting and the order of the fields.
+func optionChanged(current json.RawMessage, provided json.RawMessage) bool {
+	var currentValue, providedValue interface{}
+	if json.Unmarshal(current, &currentValue) != nil ||
+		json.Unmarshal(provided, &providedValue) != nil {
+		return true
+	}
+	return !reflect.DeepEqual(currentValue, providedValue)
+}
+
+// UpdateUserArea partially updates an area of a user based on the provided token and update.
+// Only the fields present in the update are changed. The provided action and reaction options
+// are validated against the JSON Schema of the action and reactions of the area, the same way
+// they are on creation. The reactions of the area are replaced when the update lists them,
+// while a single reaction option only changes the option of the first reaction. A null trigger
+// filter removes the filter of the area. When the action option changes, the storage variable
+// of the area is reset so that the action starts again from a clean state. Only the columns of
+// the fields present in the update are written. The running area is then rescheduled with its
+// new refresh rate, or removed from the schedule if it was disabled.
 //
 // Parameters:
 //   - token: A string representing the user's authentication token.
-//   - areaToUpdate: A schemas.Area object containing the details of the area to be updated.
+//   - update: A schemas.AreaUpdateMessage object containing the fields of the area to update.
 //
 // Returns:
 //   - updatedArea: A schemas.Area object representing the updated area.
@@ -322,41 +400,134 @@ func (service *areaService) GetUserAreas(token string) ([]schemas.Area, error) {
 //
 // Possible errors:
 //   - If the user information cannot be retrieved using the token.
-//   - If the user's areas cannot be found by user ID.
 //   - If the area to be updated cannot be found by its ID.
 //   - If the area to be updated does not belong to the user.
+//   - If the action or reaction option does not match its schema.
 //   - If the area update operation fails.
 func (service *areaService) UpdateUserArea(
 	token string,
-	areaToUpdate schemas.Area,
+	update schemas.AreaUpdateMessage,
 ) (updatedArea schemas.Area, err error) {
 	user, err := service.serviceUser.GetUserInfo(token)
 	if err != nil {
 		return updatedArea, fmt.Errorf("can't get user info: %w", err)
 	}
-	userArea, err := service.repository.FindByUserId(user.Id)
+	area, err := service.repository.FindById(update.Id)
 	if err != nil {
-		return updatedArea, fmt.Errorf("can't find areas by user id: %w", err)
+		return updatedArea, fmt.Errorf("can't find area by id: %w", err)
+	}
+	if area.UserId != user.Id {
+		return updatedArea, schemas.ErrAreaNotFound
+	}
+
+	columns := map[string]any{}
+	if hasOption(update.ActionOption) {
+		err = validateOption(
+			"action",
+			area.Action.OptionSchema,
+			area.Action.Option,
+			update.ActionOption,
+			false,
+		)
+		if err != nil {
+			return updatedArea, fmt.Errorf("can't validate action option: %w", err)
+		}
+		if optionChanged(area.ActionOption, update.ActionOption) {
+			columns["storage_variable"] = json.RawMessage("{}")
+		}
+		columns["action_option"] = update.ActionOption
+	}
+	reactions := areaReactions(area)
+	reactionsChanged := false
+	if update.Reactions != nil {
+		if len(update.Reactions) == 0 {
+			return updatedArea, schemas.ErrAreaWithoutReaction
+		}
+		reactions, err = service.newAreaReactions(update.Reactions)
+		if err != nil {
+			return updatedArea, err
+		}
+		reactionsChanged = true
+	}
+	if hasOption(update.ReactionOption) {
+		err = validateOption(
+			"reaction",
+			reactions[0].Reaction.OptionSchema,
+			reactions[0].Reaction.Option,
+			update.ReactionOption,
+			true,
+		)
+		if err != nil {
+			return updatedArea, fmt.Errorf("can't validate reaction option: %w", err)
+		}
+		err = tools.ValidateStepPlaceholders("reaction", update.ReactionOption, 0)
+		if err != nil {
+			return updatedArea, fmt.Errorf("can't validate reaction option: %w", err)
+		}
+		reactions[0].ReactionOption = update.ReactionOption
+		reactionsChanged = true
+	}
+	if reactionsChanged {
+		columns["reaction_id"] = reactions[0].ReactionId
+		columns["reaction_option"] = reactions[0].ReactionOption
+	}
+	if len(update.Filter) != 0 {
+		err = tools.ValidateTriggerFilter(update.Filter)
+		if err != nil {
+			return updatedArea, fmt.Errorf("can't validate trigger filter: %w", err)
+		}
+		columns["filter"] = update.Filter
+		if !hasOption(update.Filter) {
+			columns["filter"] = nil
+		}
+	}
+	if update.RetryPolicy != nil {
+		columns["retry_max_attempts"] = update.RetryPolicy.MaxAttempts
+		columns["retry_initial_backoff_ms"] = update.RetryPolicy.InitialBackoffMs
+		columns["retry_max_backoff_ms"] = update.RetryPolicy.MaxBackoffMs
+		columns["retry_multiplier"] = update.RetryPolicy.Multiplier
+		columns["retry_jitter"] = update.RetryPolicy.Jitter
+	}
+	if update.Title != nil {
+		columns["title"] = *update.Title
+	}
+	if update.Description != nil {
+		columns["description"] = *update.Description
+	}
+	if update.Enable != nil {
+		columns["enable"] = *update.Enable
 	}
-	areaToUpdateDatabase, err := service.repository.FindById(areaToUpdate.Id)
+	if update.ActionRefreshRate != nil {
+		columns["action_refresh_rate"] = *update.ActionRefreshRate
+	}
+
+	// a check of the area running meanwhile only writes the storage variable of the area
+	err = service.repository.UpdateColumns(area.Id, columns)
 	if err != nil {
-		return updatedArea, fmt.Errorf("can't find areas by user id: %w", err)
+		return updatedArea, fmt.Errorf("can't update area: %w", err)
 	}
-	if containsArea(userArea, areaToUpdateDatabase) {
-		err = service.repository.Update(areaToUpdate)
+	if reactionsChanged {
+		err = service.repository.ReplaceReactions(area.Id, reactions)
 		if err != nil {
-			return updatedArea, fmt.Errorf("can't update area: %w", err)
+			return updatedArea, fmt.Errorf("can't update area reactions: %w", err)
 		}
-		return areaToUpdateDatabase, nil
+	}
+	updatedArea, err = service.repository.FindById(area.Id)
+	if err != nil {
+		return updatedArea, fmt.Errorf("can't find updated area: %w", err)
+	}
+	if updatedArea.Enable {
+		service.scheduler.RescheduleArea(updatedArea)
 	} else {
-		return updatedArea, fmt.Errorf("area not found")
+		service.scheduler.StopArea(updatedArea.Id)
 	}
+	return updatedArea, nil
 }
 
 // DeleteUserArea deletes a user area based on the provided token and area ID.
 // It first retrieves the user information using the provided token, then fetches
 // the areas associated with the user. If the area to be deleted is found within
-// the user's areas, it deletes the area from the repository.
+// the user's areas, it removes the area from the schedule and deletes it from the repository.
 //
 // Parameters:
 //   - token: A string representing the user's authentication token.
@@ -383,6 +554,7 @@ func (service *areaService) DeleteUserArea(
 		return deletedArea, fmt.Errorf("can't find areas by user id: %w", err)
 	}
 	if containsArea(userAreas, areaToDeleteDatabase) {
+		service.scheduler.StopArea(areaToDeleteDatabase.Id)
 		err = service.repository.Delete(areaToDeleteDatabase)
 		if err != nil {
 			return deletedArea, fmt.Errorf("can't update area: %w", err)
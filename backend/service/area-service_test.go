@@ -0,0 +1,250 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"area/repository"
+	"area/schemas"
+	"area/service"
+	"area/test"
+)
+
+func newUpdatableArea(t *testing.T) schemas.Area {
+	t.Helper()
+	actionSchema, err := json.Marshal(schemas.TimerActionSpecificHourSchema)
+	assert.NoError(t, err)
+	reactionSchema, err := json.Marshal(schemas.TimerReactionGiveTimeSchema)
+	assert.NoError(t, err)
+	return schemas.Area{
+		Id:                1,
+		UserId:            1,
+		Title:             "Morning",
+		Description:       "Wake up",
+		Enable:            true,
+		ActionRefreshRate: 10,
+		ActionOption:      json.RawMessage(`{"hour":7,"minute":0}`),
+		ReactionOption:    json.RawMessage(`{}`),
+		StorageVariable:   json.RawMessage(`{"time":"2025-01-12T07:00:00Z"}`),
+		Action:            schemas.Action{OptionSchema: actionSchema},
+		Reaction:          schemas.Reaction{OptionSchema: reactionSchema},
+	}
+}
+
+func newUpdateAreaService(
+	mockRepo *test.MockAreaRepository,
+	scheduler service.AreaScheduler,
+) service.AreaService {
+	return service.NewAreaService(
+		mockRepo,
+		nil,
+		nil,
+		nil,
+		newUpdateAreaUserService(),
+		nil,
+		scheduler,
+	)
+}
+
+func newUpdateAreaUserService() *test.MockUserService {
+	mockUserService := new(test.MockUserService)
+	mockUserService.On("GetUserInfo", "token").Return(schemas.User{Id: 1}, nil)
+	return mockUserService
+}
+
+func newIdleScheduler() service.AreaScheduler {
+	return service.NewAreaScheduler(
+		new(test.MockAreaRepository),
+		new(test.MockServiceService),
+		newTokenService(),
+		new(MockAreaResultService),
+		new(test.MockTriggerQueueRepository),
+		newWorkflowRunRepository(),
+		newDeadLetterRepository(),
+		newAreaLeaseService(),
+		newNotificationRepository(),
+		newDropboxLongpoller(),
+		1,
+	)
+}
+
+func TestUpdateUserAreaAppliesPresentFields(t *testing.T) {
+	area := newUpdatableArea(t)
+	var columns map[string]any
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+	mockRepo.On("UpdateColumns", area.Id, mock.Anything).Run(func(args mock.Arguments) {
+		columns = args.Get(1).(map[string]any)
+	}).Return(nil)
+
+	scheduler := newIdleScheduler()
+	areaService := newUpdateAreaService(mockRepo, scheduler)
+	refreshRate := uint64(30)
+	_, err := areaService.UpdateUserArea("token", schemas.AreaUpdateMessage{
+		Id:                area.Id,
+		ActionOption:      json.RawMessage(`{"hour":8,"minute":30}`),
+		ActionRefreshRate: &refreshRate,
+	})
+
+	assert.NoError(t, err)
+	assert.Len(t, columns, 3)
+	assert.Equal(t, uint64(30), columns["action_refresh_rate"])
+	assert.JSONEq(t, `{"hour":8,"minute":30}`, string(columns["action_option"].(json.RawMessage)))
+	assert.JSONEq(t, `{}`, string(columns["storage_variable"].(json.RawMessage)))
+	assert.True(t, scheduler.IsScheduled(area.Id))
+}
+
+func TestUpdateUserAreaKeepsStorageWhenActionOptionIsUnchanged(t *testing.T) {
+	area := newUpdatableArea(t)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+	mockRepo.On("UpdateColumns", area.Id, mock.MatchedBy(func(columns map[string]any) bool {
+		_, reset := columns["storage_variable"]
+		return !reset && columns["enable"] == false
+	})).Return(nil)
+
+	areaService := newUpdateAreaService(mockRepo, newIdleScheduler())
+	enable := false
+	_, err := areaService.UpdateUserArea("token", schemas.AreaUpdateMessage{
+		Id:           area.Id,
+		ActionOption: json.RawMessage(`{"minute":0, "hour":7}`),
+		Enable:       &enable,
+	})
+
+	assert.NoError(t, err)
+	mockRepo.AssertExpectations(t)
+}
+
+func TestUpdateUserAreaRejectsInvalidOption(t *testing.T) {
+	area := newUpdatableArea(t)
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	areaService := newUpdateAreaService(mockRepo, newIdleScheduler())
+	_, err := areaService.UpdateUserArea("token", schemas.AreaUpdateMessage{
+		Id:           area.Id,
+		ActionOption: json.RawMessage(`{"hour":25,"minute":0}`),
+	})
+
+	var validationErr *schemas.OptionValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "hour", validationErr.Fields[0].Field)
+	mockRepo.AssertNotCalled(t, "UpdateColumns", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUserAreaRejectsAreaOfAnotherUser(t *testing.T) {
+	area := newUpdatableArea(t)
+	area.UserId = 2
+	mockRepo := new(test.MockAreaRepository)
+	mockRepo.On("FindById", area.Id).Return(area, nil)
+
+	areaService := newUpdateAreaService(mockRepo, newIdleScheduler())
+	title := "Stolen"
+	_, err := areaService.UpdateUserArea("token", schemas.AreaUpdateMessage{
+		Id:    area.Id,
+		Title: &title,
+	})
+
+	assert.ErrorIs(t, err, schemas.ErrAreaNotFound)
+	mockRepo.AssertNotCalled(t, "UpdateColumns", mock.Anything, mock.Anything)
+}
+
+func TestUpdateUserAreaSurvivesRunningCheck(t *testing.T) {
+	db, err := test.SetupTestDB()
+	assert.NoError(t, err)
+	// every connection to an in-memory database opens a new database
+	sqlDB, err := db.DB()
+	assert.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	areaRepository := repository.NewAreaRepository(db)
+	actionSchema, err := json.Marshal(schemas.TimerActionSpecificHourSchema)
+	assert.NoError(t, err)
+	action := schemas.Action{
+		Name:         string(schemas.SpecificTime),
+		Option:       json.RawMessage(`{"hour":0,"minute":0}`),
+		OptionSchema: actionSchema,
+	}
+	assert.NoError(t, db.Create(&action).Error)
+	areaId, err := areaRepository.SaveArea(schemas.Area{
+		UserId:            1,
+		ActionId:          action.Id,
+		Title:             "Morning",
+		Enable:            true,
+		ActionRefreshRate: 10,
+		ActionOption:      json.RawMessage(`{"hour":7,"minute":0}`),
+		StorageVariable:   json.RawMessage(`{"time":"2025-01-12T07:00:00Z"}`),
+	})
+	assert.NoError(t, err)
+
+	requested := make(chan struct{})
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(requested)
+		<-release
+		_ = json.NewEncoder(w).Encode(schemas.TimeApiResponse{
+			Year:  2025,
+			Month: 1,
+			Day:   13,
+			Hour:  7,
+		})
+	}))
+	defer server.Close()
+	timerService := service.NewTimerService(
+		nil,
+		nil,
+		areaRepository,
+		&test.FakeProviderClient{URL: server.URL},
+	)
+
+	checked, err := areaRepository.FindById(areaId)
+	assert.NoError(t, err)
+	done := make(chan error)
+	go func() {
+		_, err := timerService.TimerActionSpecificHour(
+			context.Background(),
+			checked.ActionOption,
+			checked,
+		)
+		done <- err
+	}()
+	<-requested
+
+	title := "Evening"
+	refreshRate := uint64(30)
+	areaService := service.NewAreaService(
+		areaRepository,
+		nil,
+		nil,
+		nil,
+		newUpdateAreaUserService(),
+		nil,
+		newIdleScheduler(),
+	)
+	_, err = areaService.UpdateUserArea("token", schemas.AreaUpdateMessage{
+		Id:                areaId,
+		ActionOption:      json.RawMessage(`{"hour":19,"minute":0}`),
+		Filter:            json.RawMessage(`{"operator":"equals","field":"time","value":"07:00"}`),
+		RetryPolicy:       &schemas.RetryPolicy{MaxAttempts: 3, Multiplier: 2},
+		Title:             &title,
+		ActionRefreshRate: &refreshRate,
+	})
+	assert.NoError(t, err)
+	close(release)
+	assert.NoError(t, <-done)
+
+	area, err := areaRepository.FindById(areaId)
+	assert.NoError(t, err)
+	assert.Equal(t, "Evening", area.Title)
+	assert.Equal(t, uint64(30), area.ActionRefreshRate)
+	assert.Equal(t, uint(3), area.RetryPolicy.MaxAttempts)
+	assert.JSONEq(t, `{"operator":"equals","field":"time","value":"07:00"}`, string(area.Filter))
+	assert.JSONEq(t, `{"hour":19,"minute":0}`, string(area.ActionOption))
+	assert.JSONEq(t, `{}`, string(area.StorageVariable))
+}
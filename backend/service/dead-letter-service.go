@@ -0,0 +1,175 @@
+package service
+
+import (
+	"fmt"
+
+	"area/repository"
+	"area/schemas"
+)
+
+// DeadLetterService defines the interface for managing the dead letters of the users.
+// A dead letter is a trigger whose workflow run stopped on a reaction that failed for good.
+type DeadLetterService interface {
+	// GetUserDeadLetters retrieves the dead letters of the areas of the user, newest first.
+	// Parameters:
+	//   token - the authentication token of the user.
+	// Returns:
+	//   The dead letters of the user, and an error if the user or the query fails.
+	GetUserDeadLetters(token string) (deadLetters []schemas.DeadLetter, err error)
+
+	// RetryUserDeadLetter queues the trigger of a dead letter of the user again, for the
+	// reaction workers to resume its workflow run from the failed reaction.
+	// Parameters:
+	//   token - the authentication token of the user.
+	//   id - the ID of the dead letter.
+	// Returns:
+	//   The retried dead letter, and schemas.ErrDeadLetterNotFound if the user has no such
+	//   dead letter.
+	RetryUserDeadLetter(token string, id uint64) (deadLetter schemas.DeadLetter, err error)
+
+	// DiscardUserDeadLetter deletes a dead letter of the user without replaying its trigger.
+	// Parameters:
+	//   token - the authentication token of the user.
+	//   id - the ID of the dead letter.
+	// Returns:
+	//   The discarded dead letter, and schemas.ErrDeadLetterNotFound if the user has no such
+	//   dead letter.
+	DiscardUserDeadLetter(token string, id uint64) (deadLetter schemas.DeadLetter, err error)
+}
+
+// deadLetterService is the implementation of DeadLetterService.
+// It includes the following fields:
+// - repository: An instance of DeadLetterRepository storing the dead letters.
+// - serviceUser: An instance of UserService to find the user of a token.
+// - scheduler: An instance of AreaScheduler queuing the retried triggers.
+type deadLetterService struct {
+	repository  repository.DeadLetterRepository
+	serviceUser UserService
+	scheduler   AreaScheduler
+}
+
+// NewDeadLetterService creates a new instance of DeadLetterService with the provided dependencies.
+//
+// Parameters:
+//   - repository: an instance of DeadLetterRepository storing the dead letters.
+//   - serviceUser: an instance of UserService for user-related operations.
+//   - scheduler: an instance of AreaScheduler running the areas.
+//
+// Returns:
+//   - DeadLetterService: a new instance of DeadLetterService.
+func NewDeadLetterService(
+	repository repository.DeadLetterRepository,
+	serviceUser UserService,
+	scheduler AreaScheduler,
+) DeadLetterService {
+	newService := deadLetterService{
+		repository:  repository,
+		serviceUser: serviceUser,
+		scheduler:   scheduler,
+	}
+	return &newService
+}
+
+// GetUserDeadLetters retrieves the dead letters of the user of the given token, newest first.
+//
+// Parameters:
+//   - token: A string representing the user's authentication token.
+//
+// Returns:
+//   - deadLetters: The dead letters of the user.
+//   - err: An error if the user information cannot be retrieved or the query fails.
+func (service *deadLetterService) GetUserDeadLetters(
+	token string,
+) (deadLetters []schemas.DeadLetter, err error) {
+	user, err := service.serviceUser.GetUserInfo(token)
+	if err != nil {
+		return deadLetters, fmt.Errorf("can't get user info: %w", err)
+	}
+	deadLetters, err = service.repository.FindByUserId(user.Id)
+	if err != nil {
+		return deadLetters, fmt.Errorf("can't find dead letters by user id: %w", err)
+	}
+	return deadLetters, nil
+}
+
+// findUserDeadLetter retrieves the dead letter with the given ID if it belongs to the user of
+// the given token.
+func (service *deadLetterService) findUserDeadLetter(
+	token string,
+	id uint64,
+) (deadLetter schemas.DeadLetter, err error) {
+	user, err := service.serviceUser.GetUserInfo(token)
+	if err != nil {
+		return deadLetter, fmt.Errorf("can't get user info: %w", err)
+	}
+	deadLetter, err = service.repository.FindById(id)
+	if err != nil {
+		return deadLetter, fmt.Errorf("can't find dead letter by id: %w", err)
+	}
+	if deadLetter.UserId != user.Id {
+		return schemas.DeadLetter{}, schemas.ErrDeadLetterNotFound
+	}
+	return deadLetter, nil
+}
+
+// RetryUserDeadLetter queues the trigger of the dead letter again, to be resumed from the
+// failed reaction by the reaction workers, and deletes the dead letter. The trigger of a
+// disabled area waits in the queue until the area is enabled again.
+//
+// Parameters:
+//   - token: A string representing the user's authentication token.
+//   - id: The ID of the dead letter.
+//
+// Returns:
+//   - deadLetter: The retried dead letter.
+//   - err: schemas.ErrDeadLetterNotFound if the user has no such dead letter, or an error
+//     if the trigger cannot be queued.
+func (service *deadLetterService) RetryUserDeadLetter(
+	token string,
+	id uint64,
+) (deadLetter schemas.DeadLetter, err error) {
+	deadLetter, err = service.findUserDeadLetter(token, id)
+	if err != nil {
+		return deadLetter, err
+	}
+	err = service.scheduler.EnqueueTrigger(schemas.QueuedTrigger{
+		AreaId:           deadLetter.AreaId,
+		Message:          deadLetter.TriggerMessage,
+		Payload:          deadLetter.TriggerPayload,
+		ReactionPosition: deadLetter.ReactionPosition,
+		WorkflowRunId:    deadLetter.WorkflowRunId,
+	})
+	if err != nil {
+		return deadLetter, fmt.Errorf("can't enqueue trigger: %w", err)
+	}
+	err = service.repository.Delete(deadLetter)
+	if err != nil {
+		return deadLetter, fmt.Errorf("can't delete dead letter: %w", err)
+	}
+	return deadLetter, nil
+}
+
+// DiscardUserDeadLetter deletes the dead letter without replaying its trigger.
+//
+// Parameters:
+//   - token: A string representing the user's authentication token.
+//   - id: The ID of the dead letter.
+//
+// Returns:
+//   - deadLetter: The discarded dead letter.
+//   - err: schemas.ErrDeadLetterNotFound if the user has no such dead letter, or an error
+//     if the dead letter cannot be deleted.
+func (service *deadLetterService) DiscardUserDeadLetter(
+	token string,
+	id uint64,
+) (deadLetter schemas.DeadLetter, err error) {
+	deadLetter, err = service.findUserDeadLetter(token, id)
+	if err != nil {
+		return deadLetter, err
+	}
+	err = service.repository.Delete(deadLetter)
+	if err != nil {
+		return deadLetter, fmt.Errorf("can't delete dead letter: %w", err)
+	}
+	return deadLetter, nil
+}
@@ -0,0 +1,210 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"area/schemas"
+)
+
+// DropboxLongpoller defines the interface of the watcher of the Dropbox folders of the areas.
+// It long polls the cursor of the last check of an area, so that the area is checked right
+// away when its folder changes instead of at its next check.
+type DropboxLongpoller interface {
+	// Start makes the watcher report the changed folders through onChange. Watch does nothing
+	// before.
+	Start(onChange func(areaId uint64))
+	// Watch long polls the cursor of the area, replacing the one it was long polling.
+	Watch(areaId uint64, cursor string)
+	// Unwatch stops the long poll of the cursor of the area.
+	Unwatch(areaId uint64)
+}
+
+// dropboxWatch is the long poll of the cursor of an area.
+//
+// Fields:
+//   - cursor: The cursor long polled.
+//   - cancel: Stops the long poll.
+type dropboxWatch struct {
+	cursor string
+	cancel context.CancelFunc
+}
+
+// dropboxLongpoller is the implementation of DropboxLongpoller.
+//
+// Fields:
+//   - notifyURL: The base URL of the Dropbox long polls.
+//   - client: The HTTP client sending the long polls, whose timeout covers their wait.
+//   - mutex: Guards onChange and watches.
+//   - onChange: Reports the areas whose folder changed, nil until Start.
+//   - watches: The running long polls, by ID of the area.
+type dropboxLongpoller struct {
+	notifyURL string
+	client    *http.Client
+	mutex     sync.Mutex
+	onChange  func(areaId uint64)
+	watches   map[uint64]*dropboxWatch
+}
+
+// NewDropboxLongpoller creates the watcher of the Dropbox folders of the areas. The long polls
+// are not sent through the ProviderClient, whose timeout is shorter than their wait: they
+// carry no token and do not count in the rate limits of Dropbox.
+//
+// Parameters:
+//   - notifyURL: The base URL of the Dropbox long polls, schemas.DropboxNotifyURL outside tests.
+//
+// Returns:
+//   - DropboxLongpoller: a new instance of DropboxLongpoller.
+func NewDropboxLongpoller(notifyURL string) DropboxLongpoller {
+	return &dropboxLongpoller{
+		notifyURL: notifyURL,
+		client: &http.Client{
+			Timeout: time.Second * (schemas.DropboxLongpollTimeout +
+				schemas.DropboxLongpollJitter + schemas.ProviderTimeout),
+		},
+		watches: make(map[uint64]*dropboxWatch),
+	}
+}
+
+// Start makes the watcher report the areas whose folder changed through onChange, which
+// checks them.
+//
+// Parameters:
+//   - onChange: Called with the ID of an area once its folder changed since its cursor.
+func (poller *dropboxLongpoller) Start(onChange func(areaId uint64)) {
+	poller.mutex.Lock()
+	defer poller.mutex.Unlock()
+	poller.onChange = onChange
+}
+
+// Watch long polls the cursor of the last check of the area. The long poll of a previous
+// cursor of the area is stopped. The long poll ends once it reports a change, the check of
+// the area it triggers watching its new cursor, or once it fails, the next check of the area
+// watching its cursor again.
+//
+// Parameters:
+//   - areaId: The ID of the area.
+//   - cursor: The cursor of the folder of the area after its last check.
+func (poller *dropboxLongpoller) Watch(areaId uint64, cursor string) {
+	poller.mutex.Lock()
+	defer poller.mutex.Unlock()
+	if poller.onChange == nil {
+		return
+	}
+	watch, ok := poller.watches[areaId]
+	if ok {
+		if watch.cursor == cursor {
+			return
+		}
+		watch.cancel()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	watch = &dropboxWatch{cursor: cursor, cancel: cancel}
+	poller.watches[areaId] = watch
+	go poller.poll(ctx, areaId, watch, poller.onChange)
+}
+
+// Unwatch stops the long poll of the cursor of the area, which is not checked by this replica
+// anymore, as it was stopped or its lease was released to another replica.
+//
+// Parameters:
+//   - areaId: The ID of the area.
+func (poller *dropboxLongpoller) Unwatch(areaId uint64) {
+	poller.mutex.Lock()
+	defer poller.mutex.Unlock()
+	watch, ok := poller.watches[areaId]
+	if !ok {
+		return
+	}
+	watch.cancel()
+	delete(poller.watches, areaId)
+}
+
+// poll long polls the cursor of the watch until the folder changes, waiting the backoff asked
+// by Dropbox between two long polls, then reports the change through onChange.
+func (poller *dropboxLongpoller) poll(
+	ctx context.Context,
+	areaId uint64,
+	watch *dropboxWatch,
+	onChange func(areaId uint64),
+) {
+	defer poller.unwatch(areaId, watch)
+	for {
+		result, err := poller.longpoll(ctx, watch.cursor)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			println("error dropbox longpoll: " + err.Error())
+			return
+		}
+		if result.Changes {
+			onChange(areaId)
+			return
+		}
+		if !waitRetry(ctx, time.Second*time.Duration(result.Backoff)) {
+			return
+		}
+	}
+}
+
+// unwatch forgets the watch of the area once its long poll ended, unless it was replaced.
+func (poller *dropboxLongpoller) unwatch(areaId uint64, watch *dropboxWatch) {
+	poller.mutex.Lock()
+	defer poller.mutex.Unlock()
+	watch.cancel()
+	if poller.watches[areaId] == watch {
+		delete(poller.watches, areaId)
+	}
+}
+
+// longpoll waits for the changes of the folder since the cursor, for up to
+// DropboxLongpollTimeout seconds.
+//
+// Parameters:
+//   - ctx: The context of the long poll, cancelling it when done.
+//   - cursor: The cursor of the folder.
+//
+// Returns:
+//   - schemas.DropboxLongpollResult: Whether the folder changed and the backoff to follow.
+//   - error: An error if the request fails, such as a cursor Dropbox reset.
+func (poller *dropboxLongpoller) longpoll(
+	ctx context.Context,
+	cursor string,
+) (result schemas.DropboxLongpollResult, err error) {
+	body, err := json.Marshal(schemas.DropboxCursorRequest{
+		Cursor:  cursor,
+		Timeout: schemas.DropboxLongpollTimeout,
+	})
+	if err != nil {
+		return result, fmt.Errorf("unable to marshal request: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		poller.notifyURL+"/2/files/list_folder/longpoll",
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return result, fmt.Errorf("unable to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := poller.client.Do(req)
+	if err != nil {
+		return result, fmt.Errorf("unable to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return result, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return result, fmt.Errorf("unable to decode response: %w", err)
+	}
+	return result, nil
+}
@@ -1,13 +1,19 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"maps"
 	"net/http"
 	"net/url"
 	"os"
+	"path"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -41,16 +47,20 @@ type DropboxService interface {
 	// Service interface functions
 	GetServiceActionInfo() []schemas.Action
 	GetServiceReactionInfo() []schemas.Reaction
-	FindActionByName(name string) func(c chan string, option json.RawMessage, area schemas.Area)
-	FindReactionByName(name string) func(option json.RawMessage, area schemas.Area) string
+	FindActionByName(name string) ActionFunc
+	FindReactionByName(name string) ReactionFunc
 	// Service specific functions
 	AuthGetServiceAccessToken(code string) (token schemas.Token, err error)
+	RefreshServiceAccessToken(
+		ctx context.Context,
+		refreshToken string,
+	) (token schemas.Token, err error)
 	GetUserInfo(accessToken string) (user schemas.User, err error)
 	GetUserAllFolderAndFileList(
 		userDropboxToken string,
 	) (fileList []schemas.DropboxEntry, err error)
 	GetUserFolderAndFileList(
-		userDropboxToken string, path string,
+		ctx context.Context, userDropboxToken string, path string,
 	) (folderAndFileList []schemas.DropboxEntry, err error)
 	GetUserFileList(
 		folderAndFileList []schemas.DropboxEntry,
@@ -65,9 +75,17 @@ type DropboxService interface {
 		folderAndFileList []schemas.DropboxEntry,
 	) (pathDisplay []string)
 	// Actions functions
-	DropboxActionUpdateInFolder(channel chan string, option json.RawMessage, area schemas.Area)
+	DropboxActionUpdateInFolder(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) ([]schemas.ActionResult, error)
 	// Reactions functions
-	DropboxReactionSaveUrl(option json.RawMessage, area schemas.Area) string
+	DropboxReactionSaveUrl(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) (schemas.ReactionResult, error)
 }
 
 // dropboxService is a struct that provides methods to interact with Dropbox services.
@@ -84,6 +102,9 @@ type dropboxService struct {
 	serviceRepository repository.ServiceRepository
 	areaRepository    repository.AreaRepository
 	tokenRepository   repository.TokenRepository
+	providerClient    ProviderClient
+	pollCache         PollCache
+	longpoller        DropboxLongpoller
 	serviceInfo       schemas.Service
 }
 
@@ -95,6 +116,9 @@ type dropboxService struct {
 //   - serviceRepository: repository.ServiceRepository
 //   - areaRepository: repository.AreaRepository
 //   - tokenRepository: repository.TokenRepository
+//   - providerClient: ProviderClient
+//   - pollCache: PollCache
+//   - longpoller: DropboxLongpoller
 //
 // Returns:
 //   - DropboxService: a new instance of DropboxService
@@ -103,12 +127,18 @@ func NewDropboxService(
 	serviceRepository repository.ServiceRepository,
 	areaRepository repository.AreaRepository,
 	tokenRepository repository.TokenRepository,
+	providerClient ProviderClient,
+	pollCache PollCache,
+	longpoller DropboxLongpoller,
 ) DropboxService {
 	return &dropboxService{
 		repository:        githubTokenRepository,
 		serviceRepository: serviceRepository,
 		areaRepository:    areaRepository,
 		tokenRepository:   tokenRepository,
+		providerClient:    providerClient,
+		pollCache:         pollCache,
+		longpoller:        longpoller,
 		serviceInfo: schemas.Service{
 			Name:        schemas.Dropbox,
 			Description: "This service is a file storage service",
@@ -140,7 +170,8 @@ func (service *dropboxService) GetServiceInfo() schemas.Service {
 //	[]schemas.Action: A slice containing the Dropbox action details.
 func (service *dropboxService) GetServiceActionInfo() []schemas.Action {
 	defaultValue := schemas.DropboxActionUpdateInFolder{
-		Path: "folder/subfolder",
+		Path:       "folder/subfolder",
+		Extensions: []string{},
 	}
 	actionUpdateInFolder, err := json.Marshal(defaultValue)
 	if err != nil {
@@ -158,6 +189,7 @@ func (service *dropboxService) GetServiceActionInfo() []schemas.Action {
 			Description:        "This action triggers when there is an update in a folder",
 			Service:            service.serviceInfo,
 			Option:             actionUpdateInFolder,
+			OptionSchema:       newOptionSchema(schemas.DropboxActionUpdateInFolderSchema),
 			MinimumRefreshRate: 10,
 		},
 	}
@@ -186,16 +218,17 @@ func (service *dropboxService) GetServiceReactionInfo() []schemas.Reaction {
 	}
 	return []schemas.Reaction{
 		{
-			Name:        string(schemas.SaveUrl),
-			Description: "This reaction save content from a URL to a file in Dropbox",
-			Service:     service.serviceInfo,
-			Option:      saveUrlReactionOption,
+			Name:         string(schemas.SaveUrl),
+			Description:  "This reaction save content from a URL to a file in Dropbox",
+			Service:      service.serviceInfo,
+			Option:       saveUrlReactionOption,
+			OptionSchema: newOptionSchema(schemas.DropboxSaveUrlReactionOptionSchema),
 		},
 	}
 }
 
 // FindActionByName returns a function that performs a specific action based on the provided name.
-// The returned function takes a channel, a JSON raw message, and an area schema as parameters.
+// The returned ActionFunc takes a context, a JSON raw message, and an area schema as parameters.
 //
 // Parameters:
 //   - name: The name of the action to find.
@@ -204,7 +237,7 @@ func (service *dropboxService) GetServiceReactionInfo() []schemas.Reaction {
 //   - A function that performs the specified action, or nil if the action name is not recognized.
 func (service *dropboxService) FindActionByName(
 	name string,
-) func(c chan string, option json.RawMessage, area schemas.Area) {
+) ActionFunc {
 	switch name {
 	case string(schemas.UpdateInFolder):
 		return service.DropboxActionUpdateInFolder
@@ -214,7 +247,7 @@ func (service *dropboxService) FindActionByName(
 }
 
 // FindReactionByName returns a function that corresponds to the given reaction name.
-// The returned function takes a JSON raw message and an Area schema as parameters and returns a string.
+// The returned ReactionFunc takes a context, a JSON raw message and an Area schema as parameters.
 // If the reaction name matches a known reaction, the corresponding function is returned.
 // If the reaction name does not match any known reactions, nil is returned.
 //
@@ -222,11 +255,11 @@ func (service *dropboxService) FindActionByName(
 //   - name: The name of the reaction to find.
 //
 // Returns:
-//   - A function that takes a JSON raw message and an Area schema as parameters and returns a string,
+//   - The ReactionFunc of the reaction,
 //     or nil if the reaction name does not match any known reactions.
 func (service *dropboxService) FindReactionByName(
 	name string,
-) func(option json.RawMessage, area schemas.Area) string {
+) ReactionFunc {
 	switch name {
 	case string(schemas.SaveUrl):
 		return service.DropboxReactionSaveUrl
@@ -283,8 +316,7 @@ func (service *dropboxService) AuthGetServiceAccessToken(
 	req.URL.RawQuery = data.Encode()
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Dropbox, req)
 	if err != nil {
 		return schemas.Token{}, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -312,6 +344,81 @@ func (service *dropboxService) AuthGetServiceAccessToken(
 	return token, nil
 }
 
+// RefreshServiceAccessToken exchanges a Dropbox refresh token for a new short-lived access token.
+// Dropbox refresh tokens do not expire, so the returned token has no refresh token.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - refreshToken: The refresh token received when the user authorized Dropbox.
+//
+// Returns:
+//   - token: A Token schema containing the new access token and its expiration time.
+//   - err: An error if the environment variables are missing or the request fails.
+//     It wraps schemas.ErrRefreshTokenRejected when Dropbox rejects the refresh token.
+func (service *dropboxService) RefreshServiceAccessToken(
+	ctx context.Context,
+	refreshToken string,
+) (token schemas.Token, err error) {
+	clientID := os.Getenv("DROPBOX_CLIENT_ID")
+	if clientID == "" {
+		return schemas.Token{}, schemas.ErrDropboxClientIdNotSet
+	}
+
+	clientSecret := os.Getenv("DROPBOX_SECRET")
+	if clientSecret == "" {
+		return schemas.Token{}, schemas.ErrDropboxSecretNotSet
+	}
+
+	apiURL := "https://api.dropboxapi.com/oauth2/token"
+
+	data := url.Values{}
+	data.Set("client_id", clientID)
+	data.Set("client_secret", clientSecret)
+	data.Set("refresh_token", refreshToken)
+	data.Set("grant_type", "refresh_token")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, nil)
+	if err != nil {
+		return schemas.Token{}, fmt.Errorf("unable to create request because %w", err)
+	}
+
+	req.URL.RawQuery = data.Encode()
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := service.providerClient.Do(schemas.Dropbox, req)
+	if err != nil {
+		return schemas.Token{}, fmt.Errorf("unable to make request because %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+		return schemas.Token{}, fmt.Errorf(
+			"unable to refresh token because %w",
+			schemas.ErrRefreshTokenRejected,
+		)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return schemas.Token{}, fmt.Errorf("unable to refresh token because %v", resp.Status)
+	}
+
+	var result schemas.DropboxTokenResponse
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return schemas.Token{}, fmt.Errorf("unable to decode response because %w", err)
+	}
+
+	if result.AccessToken == "" {
+		return schemas.Token{}, schemas.ErrAccessTokenNotFoundInResponse
+	}
+
+	token = schemas.Token{
+		Token:        result.AccessToken,
+		RefreshToken: result.RefreshToken,
+		ExpireAt:     time.Now().Add(time.Duration(result.ExpiresIn) * time.Second),
+	}
+	return token, nil
+}
+
 // GetUserInfo retrieves the current user's information from Dropbox using the provided access token.
 // It sends a POST request to the Dropbox API endpoint for getting the current account information.
 //
@@ -338,9 +445,8 @@ func (service *dropboxService) GetUserInfo(
 	// Add the Authorization header
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	// Make the request using the default HTTP client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Make the request through the shared provider client
+	resp, err := service.providerClient.Do(schemas.Dropbox, req)
 	if err != nil {
 		return schemas.User{}, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -373,76 +479,165 @@ func (service *dropboxService) GetUserInfo(
 func (service *dropboxService) GetUserAllFolderAndFileList(
 	userDropboxToken string,
 ) (folderAndFileList []schemas.DropboxEntry, err error) {
-	return service.GetUserFolderAndFileList(userDropboxToken, "")
+	return service.GetUserFolderAndFileList(context.Background(), userDropboxToken, "")
 }
 
 // GetUserFolderAndFileList retrieves the list of folders and files from the user's Dropbox account
-// for the specified path.
+// for the specified path, and its subfolders.
 //
 // Parameters:
+//   - ctx: The context of the requests, cancelling them when done.
 //   - userDropboxToken: The OAuth token for the user's Dropbox account.
 //   - path: The path in the Dropbox account to list the folders and files from.
 //
 // Returns:
 //   - folderAndFileList: A slice of DropboxEntry structs representing the folders and files.
-//   - err: An error if the request fails or the response cannot be decoded.
-//
-// The function makes an HTTP POST request to the Dropbox API to list the folders and files
-// at the specified path. It sets the necessary headers, including the Authorization header
-// with the user's Dropbox token. The response is decoded into a DropboxListFolderResult struct,
-// and the entries are returned as a slice of DropboxEntry structs.
+//   - err: An error if a request fails or a response cannot be decoded.
 func (service *dropboxService) GetUserFolderAndFileList(
-	userDropboxToken string, path string,
+	ctx context.Context, userDropboxToken string, path string,
 ) (folderAndFileList []schemas.DropboxEntry, err error) {
-	ctx := context.Background()
+	folderAndFileList, _, err = service.listFolder(ctx, userDropboxToken,
+		schemas.DropboxListFolderRequest{Path: path, Recursive: true},
+	)
+	return folderAndFileList, err
+}
 
-	// Prepare the request body
-	reqBody := `{"path": "` + path + `","recursive": true}`
+// listFolder lists the entries of a folder through all the pages of the listing.
+//
+// Parameters:
+//   - ctx: The context of the requests, cancelling them when done.
+//   - userDropboxToken: The OAuth token for the user's Dropbox account.
+//   - request: The folder to list, and whether its subfolders are listed.
+//
+// Returns:
+//   - entries: The entries of the folder.
+//   - cursor: The cursor of the changes of the folder following the listing.
+//   - err: An error if a request fails.
+func (service *dropboxService) listFolder(
+	ctx context.Context,
+	userDropboxToken string,
+	request schemas.DropboxListFolderRequest,
+) (entries []schemas.DropboxEntry, cursor string, err error) {
+	result := schemas.DropboxListFolderResult{}
+	err = service.sendListFolderRequest(ctx, userDropboxToken, "list_folder", request, &result)
+	if err != nil {
+		return nil, "", err
+	}
+	entries = result.Entries
+	if result.HasMore {
+		more, cursor, err := service.listFolderChanges(ctx, userDropboxToken, result.Cursor)
+		return append(entries, more...), cursor, err
+	}
+	return entries, result.Cursor, nil
+}
+
+// listFolderChanges lists the changes of a folder since a cursor, through all their pages.
+//
+// Parameters:
+//   - ctx: The context of the requests, cancelling them when done.
+//   - userDropboxToken: The OAuth token for the user's Dropbox account.
+//   - cursor: The cursor of a previous listing of the folder.
+//
+// Returns:
+//   - changes: The entries changed since the cursor, the deleted ones being of tag "deleted".
+//   - newCursor: The cursor of the changes of the folder following these ones.
+//   - err: schemas.ErrDropboxCursorReset if Dropbox no longer accepts the cursor, or an error
+//     if a request fails.
+func (service *dropboxService) listFolderChanges(
+	ctx context.Context,
+	userDropboxToken string,
+	cursor string,
+) (changes []schemas.DropboxEntry, newCursor string, err error) {
+	for {
+		result := schemas.DropboxListFolderResult{}
+		err = service.sendListFolderRequest(ctx, userDropboxToken, "list_folder/continue",
+			schemas.DropboxCursorRequest{Cursor: cursor},
+			&result,
+		)
+		if err != nil {
+			return nil, "", err
+		}
+		changes = append(changes, result.Entries...)
+		cursor = result.Cursor
+		if !result.HasMore {
+			return changes, cursor, nil
+		}
+	}
+}
+
+// sendListFolderRequest sends a request to a list_folder endpoint of the Dropbox API.
+//
+// Parameters:
+//   - ctx: The context of the request, cancelling it when done.
+//   - userDropboxToken: The OAuth token for the user's Dropbox account.
+//   - endpoint: The endpoint, relative to the files endpoints of the Dropbox API.
+//   - request: The body of the request.
+//   - result: The value the response is decoded into.
+//
+// Returns:
+//   - error: schemas.ErrDropboxCursorReset if Dropbox reset the cursor of the request, or an
+//     error if the request fails or the response cannot be decoded.
+func (service *dropboxService) sendListFolderRequest(
+	ctx context.Context,
+	userDropboxToken string,
+	endpoint string,
+	request any,
+	result *schemas.DropboxListFolderResult,
+) error {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("unable to marshal request: %w", err)
+	}
 
 	// Create the HTTP request
 	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
-		"https://api.dropboxapi.com/2/files/list_folder",
-		strings.NewReader(reqBody),
+		"https://api.dropboxapi.com/2/files/"+endpoint,
+		bytes.NewReader(body),
 	)
 	if err != nil {
-		return nil, fmt.Errorf("unable to create request: %w", err)
+		return fmt.Errorf("unable to create request: %w", err)
 	}
 
 	// Set the Authorization header
 	req.Header.Set("Authorization", "Bearer "+userDropboxToken)
 	req.Header.Set("Content-Type", "application/json")
 
-	// Make the request using the default HTTP client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Make the request through the shared provider client
+	resp, err := service.providerClient.Do(schemas.Dropbox, req)
 	if err != nil {
-		return nil, fmt.Errorf("unable to make request: %w", err)
+		return fmt.Errorf("unable to make request: %w", err)
 	}
 	defer resp.Body.Close() // Ensure the response body is closed to avoid resource leaks
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fmt.Errorf(
+			"error status code %d: %w",
+			resp.StatusCode,
+			schemas.ErrUnauthorized,
+		)
+	}
 	if resp.StatusCode != http.StatusOK {
 		// Read and log the error response for debugging
 		errorBody, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf(
+		errorResponse := schemas.DropboxErrorResponse{}
+		if resp.StatusCode == http.StatusConflict &&
+			json.Unmarshal(errorBody, &errorResponse) == nil &&
+			errorResponse.Error.Tag == "reset" {
+			return schemas.ErrDropboxCursorReset
+		}
+		return statusError(resp.StatusCode, fmt.Errorf(
 			"unexpected status code: %d, response: %s",
 			resp.StatusCode,
 			string(errorBody),
-		)
+		))
 	}
 
-	println("Response status code: ", resp.StatusCode)
-
 	// Decode the JSON response into the result struct
-	result := schemas.DropboxListFolderResult{}
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	err = json.NewDecoder(resp.Body).Decode(result)
 	if err != nil {
-		return nil, fmt.Errorf("unable to decode response: %w", err)
+		return fmt.Errorf("unable to decode response: %w", err)
 	}
-
-	// Append the retrieved files to the file list
-	folderAndFileList = result.Entries
-
-	return folderAndFileList, nil
+	return nil
 }
 
 // GetUserFileList filters the provided list of Dropbox entries and returns only the entries that are files.
@@ -525,6 +720,7 @@ func (service *dropboxService) GetPathDisplayDropboxEntry(
 // SaveUrl saves a file from a given URL to a specified path in the user's Dropbox.
 //
 // Parameters:
+//   - ctx: The context of the request, cancelling it when done.
 //   - userDropboxToken: The OAuth token for the user's Dropbox account.
 //   - path: The path in the Dropbox where the file should be saved. Must not be empty and should start with '/'.
 //   - url: The URL of the file to be saved. Must not be empty and should start with "http://" or "https://".
@@ -539,10 +735,8 @@ func (service *dropboxService) GetPathDisplayDropboxEntry(
 //   - Returns an error if the response status code is not 200 OK.
 //   - Returns an error if the response cannot be decoded into the result struct.
 func (service *dropboxService) SaveUrl(
-	userDropboxToken string, path string, url string,
+	ctx context.Context, userDropboxToken string, path string, url string,
 ) (fileJobStatus schemas.DropboxSaveUrlResult, err error) {
-	ctx := context.Background()
-
 	if path == "" {
 		return fileJobStatus, fmt.Errorf("path cannot be empty")
 	}
@@ -575,22 +769,28 @@ func (service *dropboxService) SaveUrl(
 	req.Header.Set("Authorization", "Bearer "+userDropboxToken)
 	req.Header.Set("Content-Type", "application/json")
 
-	// Make the request using the default HTTP client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Make the request through the shared provider client
+	resp, err := service.providerClient.Do(schemas.Dropbox, req)
 	if err != nil {
 		return fileJobStatus, fmt.Errorf("unable to make request: %w", err)
 	}
 	defer resp.Body.Close() // Ensure the response body is closed to avoid resource leaks
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return fileJobStatus, fmt.Errorf(
+			"error status code %d: %w",
+			resp.StatusCode,
+			schemas.ErrUnauthorized,
+		)
+	}
 	if resp.StatusCode != http.StatusOK {
 		// Read and log the error response for debugging
 		errorBody, _ := io.ReadAll(resp.Body)
-		return fileJobStatus, fmt.Errorf(
+		return fileJobStatus, statusError(resp.StatusCode, fmt.Errorf(
 			"unexpected status code: %d, response: %s",
 			resp.StatusCode,
 			string(errorBody),
-		)
+		))
 	}
 
 	// Decode the JSON response into the result struct
@@ -605,6 +805,7 @@ func (service *dropboxService) SaveUrl(
 // SaveUrlCheckJobStatus checks the status of a save URL job in Dropbox.
 //
 // Parameters:
+//   - ctx: The context of the request, cancelling it when done.
 //   - userDropboxToken: The OAuth token for the user's Dropbox account.
 //   - saveUrlResult: The result of the save URL operation containing the async job ID.
 //
@@ -617,10 +818,8 @@ func (service *dropboxService) SaveUrl(
 // makes the HTTP request. If the request is successful, it decodes the JSON response
 // into the saveUrlFile struct. If there is an error at any step, it returns the error.
 func (service *dropboxService) SaveUrlCheckJobStatus(
-	userDropboxToken string, saveUrlResult schemas.DropboxSaveUrlResult,
+	ctx context.Context, userDropboxToken string, saveUrlResult schemas.DropboxSaveUrlResult,
 ) (saveUrlFile schemas.DropboxEntry, err error) {
-	ctx := context.Background()
-
 	// Prepare the request body
 	reqBody := `{"async_job_id":"` + saveUrlResult.AsyncJobID + `"}`
 
@@ -637,22 +836,28 @@ func (service *dropboxService) SaveUrlCheckJobStatus(
 	req.Header.Set("Authorization", "Bearer "+userDropboxToken)
 	req.Header.Set("Content-Type", "application/json")
 
-	// Make the request using the default HTTP client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Make the request through the shared provider client
+	resp, err := service.providerClient.Do(schemas.Dropbox, req)
 	if err != nil {
 		return saveUrlFile, fmt.Errorf("unable to make request: %w", err)
 	}
 	defer resp.Body.Close() // Ensure the response body is closed to avoid resource leaks
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return saveUrlFile, fmt.Errorf(
+			"error status code %d: %w",
+			resp.StatusCode,
+			schemas.ErrUnauthorized,
+		)
+	}
 	if resp.StatusCode != http.StatusOK {
 		// Read and log the error response for debugging
 		errorBody, _ := io.ReadAll(resp.Body)
-		return saveUrlFile, fmt.Errorf(
+		return saveUrlFile, statusError(resp.StatusCode, fmt.Errorf(
 			"unexpected status code: %d, response: %s",
 			resp.StatusCode,
 			string(errorBody),
-		)
+		))
 	}
 
 	// Decode the JSON response into the result struct
@@ -664,154 +869,276 @@ func (service *dropboxService) SaveUrlCheckJobStatus(
 	return saveUrlFile, nil
 }
 
-// IsEntryUpdate checks if any entry in the provided list of Dropbox entries
-// has been modified after the specified date.
+// dropboxFolderPath returns the path of a folder as expected by the Dropbox API: "" for the
+// root of the Dropbox, the path starting with a slash otherwise.
+func dropboxFolderPath(folder string) string {
+	folder = strings.Trim(folder, "/")
+	if folder == "" {
+		return ""
+	}
+	return "/" + folder
+}
+
+// matchDropboxEntry reports whether an entry of the watched folder matches the filters of an
+// UpdateInFolder action. The glob is matched against the path of the entry relative to the
+// folder, or against its name when the glob has no slash. The extensions are compared without
+// their leading dot. Both ignore the case, like the paths of Dropbox.
 //
 // Parameters:
-//
-//	folderAndFileList - a slice of DropboxEntry objects to check for updates
-//	date - the time to compare each entry's modification date against
+//   - options: The options of the action, whose path is the one of the Dropbox API.
+//   - pathLower: The lowercased path of the entry.
 //
 // Returns:
+//   - bool: Whether the entry matches, the folder itself never matching.
+func matchDropboxEntry(options schemas.DropboxActionUpdateInFolder, pathLower string) bool {
+	relative, ok := strings.CutPrefix(pathLower, strings.ToLower(options.Path)+"/")
+	if !ok || relative == "" {
+		return false
+	}
+	if options.Glob != "" {
+		name := relative
+		if !strings.Contains(options.Glob, "/") {
+			name = path.Base(relative)
+		}
+		matched, _ := path.Match(strings.ToLower(options.Glob), name)
+		if !matched {
+			return false
+		}
+	}
+	if len(options.Extensions) == 0 {
+		return true
+	}
+	extension := strings.TrimPrefix(path.Ext(relative), ".")
+	return slices.ContainsFunc(options.Extensions, func(wanted string) bool {
+		return strings.EqualFold(strings.TrimPrefix(wanted, "."), extension)
+	})
+}
+
+// dropboxEntryRevision returns the revision of an entry kept by an UpdateInFolder action: the
+// revision of a file, "" for a folder.
+func dropboxEntryRevision(entry schemas.DropboxEntry) string {
+	if entry.Tag == "file" {
+		return entry.Rev
+	}
+	return ""
+}
+
+// dropboxChanges is the outcome of a listing of the changes of a folder since a cursor.
 //
-//	true if any entry in the list has a ClientModified date after the specified date,
-//	false otherwise.
-func (service *dropboxService) IsEntryUpdate(
-	folderAndFileList []schemas.DropboxEntry,
-	date time.Time,
-) bool {
-	for _, entry := range folderAndFileList {
-		if entry.ClientModified.After(date) {
-			return true
+// Fields:
+//   - entries: The entries changed since the cursor.
+//   - cursor: The cursor following the changes.
+type dropboxChanges struct {
+	entries []schemas.DropboxEntry
+	cursor  string
+}
+
+// listWatchedFolder lists the folder watched by an UpdateInFolder action, and returns the
+// storage variable watching it from now on.
+//
+// Parameters:
+//   - ctx: The context of the requests, cancelling them when done.
+//   - userDropboxToken: The OAuth token for the user's Dropbox account.
+//   - options: The options of the action.
+//
+// Returns:
+//   - schemas.DropboxActionUpdateInFolderStorage: The storage variable, with the cursor
+//     following the listing and the entries matching the filters.
+//   - []schemas.DropboxEntry: The entries of the folder matching the filters.
+//   - error: An error if a request fails.
+func (service *dropboxService) listWatchedFolder(
+	ctx context.Context,
+	userDropboxToken string,
+	options schemas.DropboxActionUpdateInFolder,
+) (schemas.DropboxActionUpdateInFolderStorage, []schemas.DropboxEntry, error) {
+	storage := schemas.DropboxActionUpdateInFolderStorage{
+		Options: options,
+		Entries: make(map[string]string),
+	}
+	entries, cursor, err := service.listFolder(ctx, userDropboxToken,
+		schemas.DropboxListFolderRequest{Path: options.Path, Recursive: options.Recursive},
+	)
+	if err != nil {
+		return storage, nil, err
+	}
+	storage.Cursor = cursor
+
+	var matched []schemas.DropboxEntry
+	for _, entry := range entries {
+		if entry.Tag == "deleted" || !matchDropboxEntry(options, entry.PathLower) {
+			continue
 		}
+		storage.Entries[entry.PathLower] = dropboxEntryRevision(entry)
+		matched = append(matched, entry)
 	}
+	return storage, matched, nil
+}
 
-	return false
+// applyDropboxChanges applies the changes of the folder watched by an UpdateInFolder action to
+// the entries kept in its storage variable, and adds the ones matching its filters to the
+// payload. The deletion of a folder deletes the entries under it.
+//
+// Parameters:
+//   - storage: The storage variable of the action.
+//   - changes: The entries changed since the cursor of the storage variable.
+//   - payload: The payload of the trigger of the changes.
+func applyDropboxChanges(
+	storage *schemas.DropboxActionUpdateInFolderStorage,
+	changes []schemas.DropboxEntry,
+	payload *schemas.DropboxUpdateInFolderPayload,
+) {
+	for _, entry := range changes {
+		if entry.Tag == "deleted" {
+			for _, known := range slices.Sorted(maps.Keys(storage.Entries)) {
+				if known == entry.PathLower || strings.HasPrefix(known, entry.PathLower+"/") {
+					delete(storage.Entries, known)
+					payload.Deleted = append(payload.Deleted, known)
+				}
+			}
+			continue
+		}
+		if !matchDropboxEntry(storage.Options, entry.PathLower) {
+			continue
+		}
+		revision := dropboxEntryRevision(entry)
+		known, ok := storage.Entries[entry.PathLower]
+		if !ok {
+			payload.Added = append(payload.Added, entry.PathDisplay)
+		} else if known != revision {
+			payload.Modified = append(payload.Modified, entry.PathDisplay)
+		}
+		storage.Entries[entry.PathLower] = revision
+	}
 }
 
 // Actions functions
 
-// DropboxActionUpdateInFolder performs an update action in a specified Dropbox folder.
-// It checks for updates in the folder and sends a notification if any updates are found.
+// DropboxActionUpdateInFolder reports the entries added, modified or deleted in a Dropbox
+// folder, and in its subfolders in recursive mode, since the last check. The first check lists
+// the folder, then every check lists the changes since the cursor of the previous one. The
+// entries of the folder matching the filters of the action are kept in its storage variable,
+// so that the added entries are told from the modified ones. A rename is reported as the
+// deletion of the old path and the addition of the new one.
+// The cursor of the last check is long polled, so that a change of the folder checks the area
+// right away.
 //
 // Parameters:
-// - channel: A channel to send update notifications.
+// - ctx: The context of the check, cancelling the Dropbox requests when done.
 // - option: A JSON raw message containing the options for the action.
 // - area: The area schema containing user and action information.
 //
 // The function performs the following steps:
-// 1. Retrieves the user's token for the Dropbox service.
-// 2. Initializes or retrieves the storage variable for the action.
-// 3. Unmarshals the options for the action.
-// 4. Retrieves the list of files and folders in the specified Dropbox path.
-// 5. Checks if there are any updates in the folder since the last check.
-// 6. Sends a notification if updates are found and updates the storage variable.
-// 7. Sleeps for the specified refresh rate before the next check.
-//
-// If any errors occur during these steps, appropriate error messages are printed and the function returns.
+// 1. Unmarshals the options for the action and retrieves the user's token.
+// 2. Lists the folder when the storage variable has no cursor, or one for other options.
+// 3. Otherwise lists the changes since the cursor through the poll cache, or lists the
+// folder again and compares it with the kept entries when Dropbox reset the cursor.
+// 4. Updates the storage variable and long polls its new cursor.
+// 5. Returns a trigger listing the changes, if any.
+//
+// If any errors occur during these steps, the function returns the error.
 func (service *dropboxService) DropboxActionUpdateInFolder(
-	channel chan string,
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) {
+) ([]schemas.ActionResult, error) {
+	// Unmarshal the option
+	options := schemas.DropboxActionUpdateInFolder{}
+	err := json.Unmarshal([]byte(option), &options)
+	if err != nil {
+		return nil, fmt.Errorf("error unmarshal dropbox option: %w", err)
+	}
+	options.Path = dropboxFolderPath(options.Path)
+	_, err = path.Match(options.Glob, "")
+	if err != nil {
+		return nil, fmt.Errorf("error invalid glob: %w", err)
+	}
+
 	// Find the token of the user
 	token, err := service.tokenRepository.FindByUserIdAndServiceId(
 		area.UserId,
 		area.Action.ServiceId,
 	)
 	if err != nil {
-		fmt.Println("Error finding token:", err)
-		return
+		return nil, fmt.Errorf("error finding token: %w", err)
 	}
 	if token.Token == "" {
-		fmt.Println("Error: Token not found")
-		return
+		return nil, schemas.ErrTokenNotFound
 	}
 
-	databaseStored := schemas.DropboxActionUpdateInFolderStorage{}
-	err = json.Unmarshal(area.StorageVariable, &databaseStored)
-	if err != nil {
-		toto := struct{}{}
-		err = json.Unmarshal(area.StorageVariable, &toto)
+	// a storage variable of a previous version lists the folder again
+	storage := schemas.DropboxActionUpdateInFolderStorage{}
+	_ = json.Unmarshal(area.StorageVariable, &storage)
+
+	payload := schemas.DropboxUpdateInFolderPayload{
+		Path:     options.Path,
+		Added:    []string{},
+		Modified: []string{},
+		Deleted:  []string{},
+	}
+	if storage.Cursor == "" || storage.Options.Path != options.Path ||
+		storage.Options.Recursive != options.Recursive ||
+		storage.Options.Glob != options.Glob ||
+		!slices.Equal(storage.Options.Extensions, options.Extensions) {
+		// the changes are reported from this listing on
+		storage, _, err = service.listWatchedFolder(ctx, token.Token, options)
 		if err != nil {
-			println("error unmarshalling storage variable: " + err.Error())
-			return
-		} else {
-			println("initializing storage variable")
-			databaseStored = schemas.DropboxActionUpdateInFolderStorage{
-				Time: time.Now(),
-			}
-			area.StorageVariable, err = json.Marshal(databaseStored)
+			return nil, fmt.Errorf("error list folder: %w", err)
+		}
+	} else {
+		cursor := storage.Cursor
+		changes, err := cachedPoll(ctx, service.pollCache, area, schemas.PollKey{
+			Provider:   schemas.Dropbox,
+			Resource:   "files/list_folder/continue?cursor=" + cursor,
+			Credential: token.Token,
+		}, func(ctx context.Context) (dropboxChanges, error) {
+			entries, cursor, err := service.listFolderChanges(ctx, token.Token, cursor)
+			return dropboxChanges{entries: entries, cursor: cursor}, err
+		})
+		switch {
+		case errors.Is(err, schemas.ErrDropboxCursorReset):
+			println("resyncing dropbox folder of area " + strconv.FormatUint(area.Id, 10))
+			listed, entries, err := service.listWatchedFolder(ctx, token.Token, options)
 			if err != nil {
-				println("error marshalling storage variable: " + err.Error())
-				return
+				return nil, fmt.Errorf("error list folder: %w", err)
 			}
-			err = service.areaRepository.Update(area)
-			if err != nil {
-				println("error updating area: " + err.Error())
-				return
+			for _, entry := range entries {
+				known, ok := storage.Entries[entry.PathLower]
+				if !ok {
+					payload.Added = append(payload.Added, entry.PathDisplay)
+				} else if known != dropboxEntryRevision(entry) {
+					payload.Modified = append(payload.Modified, entry.PathDisplay)
+				}
 			}
+			for _, known := range slices.Sorted(maps.Keys(storage.Entries)) {
+				if _, ok := listed.Entries[known]; !ok {
+					payload.Deleted = append(payload.Deleted, known)
+				}
+			}
+			storage = listed
+		case err != nil:
+			return nil, fmt.Errorf("error list folder changes: %w", err)
+		default:
+			applyDropboxChanges(&storage, changes.entries, &payload)
+			storage.Cursor = changes.cursor
 		}
 	}
 
-	if databaseStored.Time.IsZero() {
-		println("initializing storage variable")
-		databaseStored = schemas.DropboxActionUpdateInFolderStorage{
-			Time: time.Now(),
-		}
-		area.StorageVariable, err = json.Marshal(databaseStored)
-		if err != nil {
-			println("error marshalling storage variable: " + err.Error())
-			return
-		}
-		err = service.areaRepository.Update(area)
-		if err != nil {
-			println("error updating area: " + err.Error())
-			return
-		}
-	}
-
-	// Unmarshal the option
-	optionJSON := schemas.DropboxActionUpdateInFolder{}
-
-	err = json.Unmarshal([]byte(option), &optionJSON)
-	if err != nil {
-		println("error unmarshal weather option: " + err.Error())
-		time.Sleep(time.Second)
-		return
-	}
-
-	if optionJSON.Path[0] == '/' {
-		optionJSON.Path = optionJSON.Path[1:]
-	}
-
-	fileAndFolder, err := service.GetUserFolderAndFileList(token.Token, optionJSON.Path)
+	err = updateStorageVariable(service.areaRepository, &area, storage)
 	if err != nil {
-		fmt.Println(err)
-	}
-
-	if service.IsEntryUpdate(fileAndFolder, databaseStored.Time) {
-		response := "new update in " + optionJSON.Path + " folder"
-		databaseStored.Time = time.Now()
-		area.StorageVariable, err = json.Marshal(databaseStored)
-		if err != nil {
-			println("error marshalling storage variable: " + err.Error())
-			return
-		}
-		err = service.areaRepository.Update(area)
-		if err != nil {
-			println("error updating area: " + err.Error())
-			return
-		}
-		println(response)
-		channel <- response
+		return nil, err
 	}
+	service.longpoller.Watch(area.Id, storage.Cursor)
 
-	if (area.Action.MinimumRefreshRate) > area.ActionRefreshRate {
-		time.Sleep(time.Second * time.Duration(area.Action.MinimumRefreshRate))
-	} else {
-		time.Sleep(time.Second * time.Duration(area.ActionRefreshRate))
+	if len(payload.Added) == 0 && len(payload.Modified) == 0 && len(payload.Deleted) == 0 {
+		return nil, nil
 	}
+	response := fmt.Sprintf("new update in %s folder: %d added, %d modified, %d deleted",
+		options.Path,
+		len(payload.Added),
+		len(payload.Modified),
+		len(payload.Deleted),
+	)
+	return newTrigger(response, payload)
 }
 
 // Reactions functions
@@ -819,12 +1146,13 @@ func (service *dropboxService) DropboxActionUpdateInFolder(
 // DropboxReactionSaveUrl saves a file from a given URL to Dropbox.
 //
 // Parameters:
+//   - ctx: The context of the reaction, cancelling the Dropbox requests when done.
 //   - option: A JSON raw message containing the options for the Dropbox save URL reaction.
 //   - area: An Area schema containing user and reaction information.
 //
 // Returns:
 //
-//	A string message indicating the result of the operation.
+//	The result of the operation, or an error if the file could not be saved.
 //
 // The function performs the following steps:
 //  1. Unmarshals the JSON options into a DropboxSaveUrlReactionOption struct.
@@ -833,18 +1161,17 @@ func (service *dropboxService) DropboxActionUpdateInFolder(
 //  4. Checks the job status of the save operation.
 //  5. Returns a message indicating the path of the saved file and the source URL.
 //
-// If any error occurs during these steps, an appropriate error message is returned.
+// If any error occurs during these steps, the error is returned.
 func (service *dropboxService) DropboxReactionSaveUrl(
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) string {
+) (schemas.ReactionResult, error) {
 	optionJSON := schemas.DropboxSaveUrlReactionOption{}
 
 	err := json.Unmarshal([]byte(option), &optionJSON)
 	if err != nil {
-		println("error unmarshal temperature option: " + err.Error())
-		time.Sleep(time.Second)
-		return "error unmarshal temperature option: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error unmarshal dropbox option: %w", err)
 	}
 
 	// Find the token of the user
@@ -853,23 +1180,23 @@ func (service *dropboxService) DropboxReactionSaveUrl(
 		area.Reaction.ServiceId,
 	)
 	if err != nil {
-		fmt.Println("Error finding token:", err)
-		return "Error finding token" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error finding token: %w", err)
 	}
 	if token.Token == "" {
-		fmt.Println("Error: Token not found")
-		return "Error: Token not found"
+		return schemas.ReactionResult{}, schemas.ErrTokenNotFound
 	}
 
-	fileJobStatus, err := service.SaveUrl(token.Token, optionJSON.Path, optionJSON.URL)
+	fileJobStatus, err := service.SaveUrl(ctx, token.Token, optionJSON.Path, optionJSON.URL)
 	if err != nil {
-		fmt.Println(err)
+		return schemas.ReactionResult{}, fmt.Errorf("error save url: %w", err)
 	}
 
-	saveFile, err := service.SaveUrlCheckJobStatus(token.Token, fileJobStatus)
+	saveFile, err := service.SaveUrlCheckJobStatus(ctx, token.Token, fileJobStatus)
 	if err != nil {
-		fmt.Println(err)
+		return schemas.ReactionResult{}, fmt.Errorf("error check save url job status: %w", err)
 	}
 
-	return "create file " + saveFile.PathDisplay + " that save content from " + optionJSON.URL
+	return schemas.ReactionResult{
+		Message: "create file " + saveFile.PathDisplay + " that save content from " + optionJSON.URL,
+	}, nil
 }
@@ -0,0 +1,229 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"area/schemas"
+	"area/service"
+	"area/test"
+)
+
+const dropboxOptions = `{"path":"Docs","recursive":true,"glob":"","extensions":["pdf"]}`
+
+var watchedDropboxOptions = schemas.DropboxActionUpdateInFolder{
+	Path:       "/Docs",
+	Recursive:  true,
+	Extensions: []string{"pdf"},
+}
+
+// newFakeDropbox starts a fake Dropbox API whose folder /Docs holds a.pdf, notes.txt and
+// old/c.pdf when listed, on two pages ending with the cursor c1. Since c1, b.pdf was added,
+// a.pdf modified, notes.txt modified and the folder old deleted. The cursor gone was reset.
+func newFakeDropbox(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/2/files/list_folder":
+			request := schemas.DropboxListFolderRequest{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			assert.Equal(t, "/Docs", request.Path)
+			assert.True(t, request.Recursive)
+			_, _ = w.Write([]byte(`{"entries": [
+				{".tag": "folder", "path_lower": "/docs", "path_display": "/Docs"},
+				{".tag": "file", "path_lower": "/docs/a.pdf", "path_display": "/Docs/a.pdf",
+					"rev": "1"},
+				{".tag": "file", "path_lower": "/docs/notes.txt", "path_display": "/Docs/notes.txt",
+					"rev": "1"}
+			], "cursor": "c0", "has_more": true}`))
+		case "/2/files/list_folder/continue":
+			request := schemas.DropboxCursorRequest{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			switch request.Cursor {
+			case "c0":
+				_, _ = w.Write([]byte(`{"entries": [
+					{".tag": "folder", "path_lower": "/docs/old", "path_display": "/Docs/old"},
+					{".tag": "file", "path_lower": "/docs/old/c.pdf",
+						"path_display": "/Docs/old/c.pdf", "rev": "1"}
+				], "cursor": "c1", "has_more": false}`))
+			case "c1":
+				_, _ = w.Write([]byte(`{"entries": [
+					{".tag": "file", "path_lower": "/docs/b.pdf", "path_display": "/Docs/b.pdf",
+						"rev": "1"},
+					{".tag": "file", "path_lower": "/docs/a.pdf", "path_display": "/Docs/a.pdf",
+						"rev": "2"},
+					{".tag": "file", "path_lower": "/docs/notes.txt",
+						"path_display": "/Docs/notes.txt", "rev": "2"},
+					{".tag": "deleted", "path_lower": "/docs/old", "path_display": "/Docs/old"}
+				], "cursor": "c2", "has_more": false}`))
+			default:
+				w.WriteHeader(http.StatusConflict)
+				_, _ = w.Write([]byte(`{"error_summary": "reset/..", "error": {".tag": "reset"}}`))
+			}
+		default:
+			t.Errorf("unexpected request %s", r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func checkDropboxFolder(
+	t *testing.T,
+	storage schemas.DropboxActionUpdateInFolderStorage,
+) ([]schemas.ActionResult, *test.MockAreaRepository) {
+	mockAreaRepository := new(test.MockAreaRepository)
+	mockAreaRepository.On("SwapStorageVariable", mock.Anything, mock.Anything).Return(true, nil)
+	mockTokenRepository := new(test.MockTokenRepository)
+	mockTokenRepository.On("FindByUserIdAndServiceId", uint64(7), uint64(4)).
+		Return(schemas.Token{Token: "token"}, nil)
+	dropboxService := service.NewDropboxService(
+		nil,
+		nil,
+		mockAreaRepository,
+		mockTokenRepository,
+		&test.FakeProviderClient{URL: newFakeDropbox(t).URL},
+		service.NewPollCache(),
+		service.NewDropboxLongpoller(""),
+	)
+	storageVariable, err := json.Marshal(storage)
+	assert.NoError(t, err)
+	area := schemas.Area{
+		Id:              1,
+		UserId:          7,
+		Action:          schemas.Action{Name: string(schemas.UpdateInFolder), ServiceId: 4},
+		ActionOption:    json.RawMessage(dropboxOptions),
+		StorageVariable: storageVariable,
+	}
+
+	triggers, err := dropboxService.DropboxActionUpdateInFolder(
+		context.Background(),
+		area.ActionOption,
+		area,
+	)
+	assert.NoError(t, err)
+	return triggers, mockAreaRepository
+}
+
+func storedDropboxVariable(
+	t *testing.T,
+	mockAreaRepository *test.MockAreaRepository,
+) schemas.DropboxActionUpdateInFolderStorage {
+	variable := schemas.DropboxActionUpdateInFolderStorage{}
+	assert.NoError(t, json.Unmarshal(storedVariable(mockAreaRepository), &variable))
+	return variable
+}
+
+func TestDropboxFirstCheckListsFolder(t *testing.T) {
+	triggers, mockAreaRepository := checkDropboxFolder(
+		t,
+		schemas.DropboxActionUpdateInFolderStorage{},
+	)
+
+	assert.Empty(t, triggers)
+	variable := storedDropboxVariable(t, mockAreaRepository)
+	assert.Equal(t, "c1", variable.Cursor)
+	assert.Equal(t, watchedDropboxOptions, variable.Options)
+	assert.Equal(t, map[string]string{
+		"/docs/a.pdf":     "1",
+		"/docs/old/c.pdf": "1",
+	}, variable.Entries)
+}
+
+func TestDropboxReportsFolderChanges(t *testing.T) {
+	triggers, mockAreaRepository := checkDropboxFolder(t, schemas.DropboxActionUpdateInFolderStorage{
+		Cursor:  "c1",
+		Options: watchedDropboxOptions,
+		Entries: map[string]string{"/docs/a.pdf": "1", "/docs/old/c.pdf": "1"},
+	})
+
+	// notes.txt does not match the extensions of the action
+	assert.Len(t, triggers, 1)
+	payload := schemas.DropboxUpdateInFolderPayload{}
+	assert.NoError(t, json.Unmarshal(triggers[0].Payload, &payload))
+	assert.Equal(t, []string{"/Docs/b.pdf"}, payload.Added)
+	assert.Equal(t, []string{"/Docs/a.pdf"}, payload.Modified)
+	assert.Equal(t, []string{"/docs/old/c.pdf"}, payload.Deleted)
+
+	variable := storedDropboxVariable(t, mockAreaRepository)
+	assert.Equal(t, "c2", variable.Cursor)
+	assert.Equal(t, map[string]string{"/docs/a.pdf": "2", "/docs/b.pdf": "1"}, variable.Entries)
+}
+
+func TestDropboxResyncsResetCursor(t *testing.T) {
+	triggers, mockAreaRepository := checkDropboxFolder(t, schemas.DropboxActionUpdateInFolderStorage{
+		Cursor:  "gone",
+		Options: watchedDropboxOptions,
+		Entries: map[string]string{"/docs/a.pdf": "0", "/docs/z.pdf": "1"},
+	})
+
+	// the listing of the folder is compared with the entries known before the reset
+	assert.Len(t, triggers, 1)
+	payload := schemas.DropboxUpdateInFolderPayload{}
+	assert.NoError(t, json.Unmarshal(triggers[0].Payload, &payload))
+	assert.Equal(t, []string{"/Docs/old/c.pdf"}, payload.Added)
+	assert.Equal(t, []string{"/Docs/a.pdf"}, payload.Modified)
+	assert.Equal(t, []string{"/docs/z.pdf"}, payload.Deleted)
+	assert.Equal(t, "c1", storedDropboxVariable(t, mockAreaRepository).Cursor)
+}
+
+func TestDropboxLongpollWakesArea(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2/files/list_folder/longpoll", r.URL.Path)
+		request := schemas.DropboxCursorRequest{}
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "c1", request.Cursor)
+		assert.Equal(t, uint64(schemas.DropboxLongpollTimeout), request.Timeout)
+		_, _ = w.Write([]byte(`{"changes": true}`))
+	}))
+	t.Cleanup(server.Close)
+
+	changed := make(chan uint64, 1)
+	longpoller := service.NewDropboxLongpoller(server.URL)
+	longpoller.Start(func(areaId uint64) {
+		changed <- areaId
+	})
+	longpoller.Watch(1, "c1")
+
+	select {
+	case areaId := <-changed:
+		assert.Equal(t, uint64(1), areaId)
+	case <-time.After(5 * time.Second):
+		t.Fatal("the change of the folder was not reported")
+	}
+}
+
+func TestDropboxUnwatchStopsLongpoll(t *testing.T) {
+	polled := make(chan struct{}, 1)
+	stopped := make(chan struct{}, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// the cancel of the long poll is only seen once its body is read
+		_, _ = io.Copy(io.Discard, r.Body)
+		polled <- struct{}{}
+		<-r.Context().Done()
+		stopped <- struct{}{}
+	}))
+	t.Cleanup(server.Close)
+
+	longpoller := service.NewDropboxLongpoller(server.URL)
+	longpoller.Start(func(areaId uint64) {
+		t.Error("the folder of an unwatched area was reported")
+	})
+	longpoller.Watch(1, "c1")
+	<-polled
+	longpoller.Unwatch(1)
+
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		t.Fatal("the long poll of the unwatched area was not stopped")
+	}
+}
@@ -1,17 +1,22 @@
 package service
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	"area/repository"
 	"area/schemas"
+	"area/tools"
 )
 
 // Constructor
@@ -38,19 +43,43 @@ import (
 // GithubActionUpdateCommitInRepo performs an action to update a commit in a repository.
 //
 // GithubReactionGetLatestCommitInRepo performs a reaction to get the latest commit in a repository.
+//
+// RegisterWebhook registers a webhook on a repository of the user, pushing its events.
+//
+// HandleWebhook turns a delivery of a webhook into triggers of the areas of its user.
 type GithubService interface {
 	// Service interface functions
 	GetServiceActionInfo() []schemas.Action
 	GetServiceReactionInfo() []schemas.Reaction
-	FindActionByName(name string) func(c chan string, option json.RawMessage, area schemas.Area)
-	FindReactionByName(name string) func(option json.RawMessage, area schemas.Area) string
+	FindActionByName(name string) ActionFunc
+	FindReactionByName(name string) ReactionFunc
 	// Service specific functions
 	AuthGetServiceAccessToken(code string) (token schemas.Token, err error)
 	GetUserInfo(accessToken string) (user schemas.User, err error)
+	// Webhook functions
+	RegisterWebhook(
+		ctx context.Context,
+		userId uint64,
+		repoName string,
+	) (webhook schemas.GithubWebhook, err error)
+	HandleWebhook(
+		webhookId uint64,
+		event string,
+		signature string,
+		body []byte,
+	) (triggers []schemas.AreaTrigger, err error)
 	// Actions functions
-	GithubActionUpdateCommitInRepo(channel chan string, option json.RawMessage, area schemas.Area)
+	GithubActionUpdateCommitInRepo(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) ([]schemas.ActionResult, error)
 	// Reactions functions
-	GithubReactionGetLatestCommitInRepo(option json.RawMessage, area schemas.Area) string
+	GithubReactionGetLatestCommitInRepo(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) (schemas.ReactionResult, error)
 }
 
 type githubService struct {
@@ -58,6 +87,8 @@ type githubService struct {
 	serviceRepository repository.ServiceRepository
 	areaRepository    repository.AreaRepository
 	tokenRepository   repository.TokenRepository
+	providerClient    ProviderClient
+	pollCache         PollCache
 	serviceInfo       schemas.Service
 }
 
@@ -66,12 +97,16 @@ func NewGithubService(
 	serviceRepository repository.ServiceRepository,
 	areaRepository repository.AreaRepository,
 	tokenRepository repository.TokenRepository,
+	providerClient ProviderClient,
+	pollCache PollCache,
 ) GithubService {
 	return &githubService{
 		repository:        repository,
 		serviceRepository: serviceRepository,
 		areaRepository:    areaRepository,
 		tokenRepository:   tokenRepository,
+		providerClient:    providerClient,
+		pollCache:         pollCache,
 		serviceInfo: schemas.Service{
 			Name:        schemas.Github,
 			Description: "This service is a code repository service",
@@ -127,6 +162,7 @@ func (service *githubService) GetServiceActionInfo() []schemas.Action {
 			Description:        "This action trigger when a new commit is pushed to a repository",
 			Service:            service.serviceInfo,
 			Option:             actionOption,
+			OptionSchema:       newOptionSchema(schemas.GithubActionOptionSchema),
 			MinimumRefreshRate: 10,
 		},
 		{
@@ -134,6 +170,7 @@ func (service *githubService) GetServiceActionInfo() []schemas.Action {
 			Description:        "This action trigger when a new pullrequest is open to a repository",
 			Service:            service.serviceInfo,
 			Option:             actionOption,
+			OptionSchema:       newOptionSchema(schemas.GithubActionOptionSchema),
 			MinimumRefreshRate: 10,
 		},
 		{
@@ -141,6 +178,7 @@ func (service *githubService) GetServiceActionInfo() []schemas.Action {
 			Description:        "This action trigger when a new workflow is run in a repository",
 			Service:            service.serviceInfo,
 			Option:             actionOption,
+			OptionSchema:       newOptionSchema(schemas.GithubActionOptionSchema),
 			MinimumRefreshRate: 10,
 		},
 	}
@@ -165,22 +203,24 @@ func (service *githubService) GetServiceReactionInfo() []schemas.Reaction {
 	}
 	return []schemas.Reaction{
 		{
-			Name:        string(schemas.GetLatestCommitInRepo),
-			Description: "This reaction get the latest commit in a repository",
-			Service:     service.serviceInfo,
-			Option:      actionOption,
+			Name:         string(schemas.GetLatestCommitInRepo),
+			Description:  "This reaction get the latest commit in a repository",
+			Service:      service.serviceInfo,
+			Option:       actionOption,
+			OptionSchema: newOptionSchema(schemas.GithubActionOptionSchema),
 		},
 		{
-			Name:        string(schemas.GetLatestWorkflowRunInRepo),
-			Description: "This reaction get the latest workflow run in a repository",
-			Service:     service.serviceInfo,
-			Option:      actionOption,
+			Name:         string(schemas.GetLatestWorkflowRunInRepo),
+			Description:  "This reaction get the latest workflow run in a repository",
+			Service:      service.serviceInfo,
+			Option:       actionOption,
+			OptionSchema: newOptionSchema(schemas.GithubActionOptionSchema),
 		},
 	}
 }
 
 // FindActionByName returns a function that matches the given action name.
-// The returned function takes a channel, a JSON raw message, and an area schema as parameters.
+// The returned ActionFunc takes a context, a JSON raw message, and an area schema as parameters.
 // If the action name does not match any predefined actions, it returns nil.
 //
 // Parameters:
@@ -190,7 +230,7 @@ func (service *githubService) GetServiceReactionInfo() []schemas.Reaction {
 //   - A function that matches the given action name, or nil if no match is found.
 func (service *githubService) FindActionByName(
 	name string,
-) func(c chan string, option json.RawMessage, area schemas.Area) {
+) ActionFunc {
 	switch name {
 	case string(schemas.UpdateCommitInRepo):
 		return service.GithubActionUpdateCommitInRepo
@@ -204,18 +244,17 @@ func (service *githubService) FindActionByName(
 }
 
 // FindReactionByName returns a function that matches the given reaction name.
-// The returned function takes a json.RawMessage option and a schemas.Area as parameters,
-// and returns a string. If the reaction name does not match any known reactions, it returns nil.
+// The returned ReactionFunc takes a context, a json.RawMessage option and a schemas.Area as parameters.
+// If the reaction name does not match any known reactions, it returns nil.
 //
 // Parameters:
 //   - name: The name of the reaction to find.
 //
 // Returns:
-//   - A function that takes a json.RawMessage option and a schemas.Area as parameters,
-//     and returns a string, or nil if the reaction name does not match any known reactions.
+//   - The ReactionFunc of the reaction, or nil if the reaction name does not match any known reactions.
 func (service *githubService) FindReactionByName(
 	name string,
-) func(option json.RawMessage, area schemas.Area) string {
+) ReactionFunc {
 	switch name {
 	case string(schemas.GetLatestCommitInRepo):
 		return service.GithubReactionGetLatestCommitInRepo
@@ -297,8 +336,7 @@ func (service *githubService) AuthGetServiceAccessToken(
 	req.URL.RawQuery = data.Encode()
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Github, req)
 	if err != nil {
 		return token, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -351,9 +389,8 @@ func (service *githubService) GetUserEmail(accessToken string) (email string, er
 	req.Header.Set("Accept", "application/vnd.github+json")
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
 
-	// Make the request using the default HTTP client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Make the request through the shared provider client
+	resp, err := service.providerClient.Do(schemas.Github, req)
 	if err != nil {
 		return email, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -408,9 +445,8 @@ func (service *githubService) GetUserInfoAccount(
 	// Add the Authorization header
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	// Make the request using the default HTTP client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Make the request through the shared provider client
+	resp, err := service.providerClient.Do(schemas.Github, req)
 	if err != nil {
 		return schemas.User{}, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -487,12 +523,16 @@ func (service *githubService) IsCommitUpdate(
 // CommitList retrieves the list of commits from a specified GitHub repository.
 //
 // Parameters:
+//   - ctx: The context of the request, cancelling it when done.
 //   - userGithubToken: A string containing the GitHub token for authentication.
 //   - repo: A string specifying the repository in the format "owner/repo".
+//   - validators: The validators of the last response seen by the area, if any.
 //
 // Returns:
 //   - commitList: A slice of GithubCommit structs containing the commit details.
-//   - err: An error object if an error occurred during the request or response processing.
+//   - newValidators: The ETag and Last-Modified headers of the response.
+//   - err: An error object if an error occurred during the request or response processing,
+//     or schemas.ErrNotModified if the commits did not change since the validators.
 //
 // The function makes an HTTP GET request to the GitHub API to fetch the commits
 // of the specified repository. It sets the necessary headers for authorization
@@ -500,49 +540,56 @@ func (service *githubService) IsCommitUpdate(
 // into a slice of GithubCommit structs. If any error occurs during the process,
 // it returns an appropriate error message.
 func (service *githubService) CommitList(
-	userGithubToken string, repo string,
-) (commitList []schemas.GithubCommit, err error) {
-	ctx := context.Background()
-
+	ctx context.Context, userGithubToken string, repo string,
+	validators schemas.ConditionalState,
+) (
+	commitList []schemas.GithubCommit,
+	newValidators schemas.ConditionalState,
+	err error,
+) {
 	// Create the HTTP request
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
 		"https://api.github.com/repos/"+repo+"/commits",
 		nil,
 	)
 	if err != nil {
-		return commitList, fmt.Errorf("unable to create request: %w", err)
+		return commitList, newValidators, fmt.Errorf("unable to create request: %w", err)
 	}
 
 	// Set the Authorization header
 	req.Header.Set("Authorization", "Bearer "+userGithubToken)
 	req.Header.Set("Content-Type", "application/vnd.github+json")
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	setConditionalHeaders(req, validators)
 
-	// Make the request using the default HTTP client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Make the request through the shared provider client
+	resp, err := service.providerClient.Do(schemas.Github, req)
 	if err != nil {
-		return commitList, fmt.Errorf("unable to make request: %w", err)
+		return commitList, newValidators, fmt.Errorf("unable to make request: %w", err)
 	}
 	defer resp.Body.Close() // Ensure the response body is closed to avoid resource leaks
 
+	if resp.StatusCode == http.StatusNotModified {
+		return commitList, validators, schemas.ErrNotModified
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		// Read and log the error response for debugging
 		errorBody, _ := io.ReadAll(resp.Body)
-		return commitList, fmt.Errorf(
+		return commitList, newValidators, statusError(resp.StatusCode, fmt.Errorf(
 			"unexpected status code: %d, response: %s",
 			resp.StatusCode,
 			string(errorBody),
-		)
+		))
 	}
 
 	// Decode the JSON response into the result struct
 	err = json.NewDecoder(resp.Body).Decode(&commitList)
 	if err != nil {
-		return commitList, fmt.Errorf("unable to decode response: %w", err)
+		return commitList, newValidators, fmt.Errorf("unable to decode response: %w", err)
 	}
 
-	return commitList, nil
+	return commitList, responseValidators(resp), nil
 }
 
 // IsPullRequestUpdate checks if there are any pull requests in the provided list
@@ -571,64 +618,75 @@ func (service *githubService) IsPullRequestUpdate(
 // PullRequestList retrieves a list of pull requests for a given repository from the GitHub API.
 //
 // Parameters:
+//   - ctx: The context of the request, cancelling it when done.
 //   - userGithubToken: A string containing the GitHub token for authentication.
 //   - repo: A string specifying the repository in the format "owner/repo".
+//   - validators: The validators of the last response seen by the area, if any.
 //
 // Returns:
 //   - pullRequestList: A slice of GithubPullRequest structs containing the details of each pull request.
-//   - err: An error object if an error occurred during the request or response processing.
+//   - newValidators: The ETag and Last-Modified headers of the response.
+//   - err: An error object if an error occurred during the request or response processing,
+//     or schemas.ErrNotModified if the pull requests did not change since the validators.
 //
 // The function performs the following steps:
 //  1. Creates an HTTP GET request to the GitHub API endpoint for listing pull requests.
 //  2. Sets the necessary headers, including the Authorization header with the provided GitHub token.
-//  3. Sends the request using the default HTTP client.
+//  3. Sends the request through the shared provider client.
 //  4. Checks the response status code and reads the response body if the status code is not 200 OK.
 //  5. Decodes the JSON response into a slice of GithubPullRequest structs.
 //  6. Returns the list of pull requests and any error encountered.
 func (service *githubService) PullRequestList(
-	userGithubToken string, repo string,
-) (pullRequestList []schemas.GithubPullRequest, err error) {
-	ctx := context.Background()
-
+	ctx context.Context, userGithubToken string, repo string,
+	validators schemas.ConditionalState,
+) (
+	pullRequestList []schemas.GithubPullRequest,
+	newValidators schemas.ConditionalState,
+	err error,
+) {
 	// Create the HTTP request
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
 		"https://api.github.com/repos/"+repo+"/pulls",
 		nil,
 	)
 	if err != nil {
-		return pullRequestList, fmt.Errorf("unable to create request: %w", err)
+		return pullRequestList, newValidators, fmt.Errorf("unable to create request: %w", err)
 	}
 
 	// Set the Authorization header
 	req.Header.Set("Authorization", "Bearer "+userGithubToken)
 	req.Header.Set("Content-Type", "application/vnd.github+json")
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	setConditionalHeaders(req, validators)
 
-	// Make the request using the default HTTP client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Make the request through the shared provider client
+	resp, err := service.providerClient.Do(schemas.Github, req)
 	if err != nil {
-		return pullRequestList, fmt.Errorf("unable to make request: %w", err)
+		return pullRequestList, newValidators, fmt.Errorf("unable to make request: %w", err)
 	}
 	defer resp.Body.Close() // Ensure the response body is closed to avoid resource leaks
 
+	if resp.StatusCode == http.StatusNotModified {
+		return pullRequestList, validators, schemas.ErrNotModified
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		// Read and log the error response for debugging
 		errorBody, _ := io.ReadAll(resp.Body)
-		return pullRequestList, fmt.Errorf(
+		return pullRequestList, newValidators, statusError(resp.StatusCode, fmt.Errorf(
 			"unexpected status code: %d, response: %s",
 			resp.StatusCode,
 			string(errorBody),
-		)
+		))
 	}
 
 	// Decode the JSON response into the result struct
 	err = json.NewDecoder(resp.Body).Decode(&pullRequestList)
 	if err != nil {
-		return pullRequestList, fmt.Errorf("unable to decode response: %w", err)
+		return pullRequestList, newValidators, fmt.Errorf("unable to decode response: %w", err)
 	}
 
-	return pullRequestList, nil
+	return pullRequestList, responseValidators(resp), nil
 }
 
 // IsWorkflowRunUpdate checks if there is any workflow run in the provided list
@@ -662,65 +720,397 @@ func (service *githubService) IsWorkflowRunUpdate(
 // a list of workflow runs or an error if the request fails.
 //
 // Parameters:
+//   - ctx: The context of the request, cancelling it when done.
 //   - userGithubToken: A string containing the GitHub token of the user.
 //   - repo: A string containing the name of the repository in the format "owner/repo".
+//   - validators: The validators of the last response seen by the area, if any.
 //
 // Returns:
 //   - workflowRunList: A struct containing the list of workflow runs.
-//   - err: An error if the request fails or the response cannot be decoded.
+//   - newValidators: The ETag and Last-Modified headers of the response.
+//   - err: An error if the request fails or the response cannot be decoded,
+//     or schemas.ErrNotModified if the workflow runs did not change since the validators.
 func (service *githubService) WorkflowRunList(
-	userGithubToken string, repo string,
-) (workflowRunList schemas.GithubWorkflowRunsList, err error) {
-	ctx := context.Background()
-
+	ctx context.Context, userGithubToken string, repo string,
+	validators schemas.ConditionalState,
+) (
+	workflowRunList schemas.GithubWorkflowRunsList,
+	newValidators schemas.ConditionalState,
+	err error,
+) {
 	// Create the HTTP request
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
 		"https://api.github.com/repos/"+repo+"/actions/runs",
 		nil,
 	)
 	if err != nil {
-		return workflowRunList, fmt.Errorf("unable to create request: %w", err)
+		return workflowRunList, newValidators, fmt.Errorf("unable to create request: %w", err)
 	}
 
 	// Set the Authorization header
 	req.Header.Set("Authorization", "Bearer "+userGithubToken)
 	req.Header.Set("Content-Type", "application/vnd.github+json")
 	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	setConditionalHeaders(req, validators)
 
-	// Make the request using the default HTTP client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Make the request through the shared provider client
+	resp, err := service.providerClient.Do(schemas.Github, req)
 	if err != nil {
-		return workflowRunList, fmt.Errorf("unable to make request: %w", err)
+		return workflowRunList, newValidators, fmt.Errorf("unable to make request: %w", err)
 	}
 	defer resp.Body.Close() // Ensure the response body is closed to avoid resource leaks
 
+	if resp.StatusCode == http.StatusNotModified {
+		return workflowRunList, validators, schemas.ErrNotModified
+	}
+
 	if resp.StatusCode != http.StatusOK {
 		// Read and log the error response for debugging
 		errorBody, _ := io.ReadAll(resp.Body)
-		return workflowRunList, fmt.Errorf(
+		return workflowRunList, newValidators, statusError(resp.StatusCode, fmt.Errorf(
 			"unexpected status code: %d, response: %s",
 			resp.StatusCode,
 			string(errorBody),
-		)
+		))
 	}
 
 	// Decode the JSON response into the result struct
 	err = json.NewDecoder(resp.Body).Decode(&workflowRunList)
 	if err != nil {
-		return workflowRunList, fmt.Errorf("unable to decode response: %w", err)
+		return workflowRunList, newValidators, fmt.Errorf("unable to decode response: %w", err)
+	}
+
+	return workflowRunList, responseValidators(resp), nil
+}
+
+// Webhook functions
+
+// RegisterWebhook registers a webhook on the repository with the GitHub token of the user,
+// sending the push, pull_request and workflow_run events of the repository to the backend.
+// The deliveries are signed with a secret generated for the webhook. A webhook already
+// registered by the user on the repository is returned as is.
+//
+// Parameters:
+//   - ctx: The context of the request, cancelling the GitHub request when done.
+//   - userId: The ID of the user registering the webhook.
+//   - repoName: The repository, in the format "owner/repo". The user must be allowed to
+//     manage its webhooks.
+//
+// Returns:
+//   - webhook: The webhook registered on the repository.
+//   - err: An error if the user has no GitHub token, or if the webhook can not be created on
+//     GitHub or saved.
+func (service *githubService) RegisterWebhook(
+	ctx context.Context,
+	userId uint64,
+	repoName string,
+) (webhook schemas.GithubWebhook, err error) {
+	webhook, err = service.repository.FindWebhookByUserIdAndRepoName(userId, repoName)
+	if err == nil {
+		return webhook, nil
+	}
+	if !errors.Is(err, schemas.ErrGithubWebhookNotFound) {
+		return webhook, fmt.Errorf("unable to find webhook: %w", err)
+	}
+
+	githubInfo, err := service.serviceRepository.FindByName(schemas.Github)
+	if err != nil {
+		return webhook, fmt.Errorf("unable to find service: %w", err)
+	}
+	token, err := service.tokenRepository.FindByUserIdAndServiceId(userId, githubInfo.Id)
+	if err != nil {
+		return webhook, fmt.Errorf("unable to find token: %w", err)
+	}
+	if token.Token == "" {
+		return webhook, schemas.ErrTokenNotFound
+	}
+	webhookURL, err := getWebhookURL(schemas.Github)
+	if err != nil {
+		return webhook, fmt.Errorf("unable to get webhook url: %w", err)
+	}
+	secret, err := tools.GenerateWebhookSecret()
+	if err != nil {
+		return webhook, err
+	}
+
+	// the webhook is saved first, its ID being part of the URL of its deliveries
+	webhook = schemas.GithubWebhook{UserId: userId, RepoName: repoName, Secret: secret}
+	webhook.Id, err = service.repository.SaveWebhook(webhook)
+	if err != nil {
+		return webhook, fmt.Errorf("unable to save webhook: %w", err)
+	}
+	hook, err := service.createRepositoryHook(
+		ctx,
+		token.Token,
+		repoName,
+		webhookURL+"/"+strconv.FormatUint(webhook.Id, 10),
+		secret,
+	)
+	if err != nil {
+		deleteErr := service.repository.DeleteWebhook(webhook)
+		if deleteErr != nil {
+			println("error delete webhook: " + deleteErr.Error())
+		}
+		return schemas.GithubWebhook{}, fmt.Errorf("unable to create webhook: %w", err)
+	}
+	webhook.HookId = hook.Id
+	err = service.repository.UpdateWebhook(webhook)
+	if err != nil {
+		return webhook, fmt.Errorf("unable to update webhook: %w", err)
+	}
+	return webhook, nil
+}
+
+// createRepositoryHook creates a webhook on the repository through the GitHub API.
+//
+// Parameters:
+//   - ctx: The context of the request, cancelling it when done.
+//   - userGithubToken: A string containing the GitHub token for authentication.
+//   - repo: A string specifying the repository in the format "owner/repo".
+//   - webhookURL: The URL the deliveries of the webhook are sent to.
+//   - secret: The secret signing the deliveries of the webhook.
+//
+// Returns:
+//   - hook: The webhook created on GitHub.
+//   - err: An error if the request fails or GitHub refuses to create the webhook.
+func (service *githubService) createRepositoryHook(
+	ctx context.Context,
+	userGithubToken string,
+	repo string,
+	webhookURL string,
+	secret string,
+) (hook schemas.GithubHook, err error) {
+	body, err := json.Marshal(schemas.GithubHookRequest{
+		Name:   "web",
+		Active: true,
+		Events: []schemas.GithubWebhookEvent{
+			schemas.GithubEventPush,
+			schemas.GithubEventPullRequest,
+			schemas.GithubEventWorkflowRun,
+		},
+		Config: schemas.GithubHookConfig{
+			URL:         webhookURL,
+			ContentType: "json",
+			Secret:      secret,
+		},
+	})
+	if err != nil {
+		return hook, fmt.Errorf("unable to marshal request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost,
+		"https://api.github.com/repos/"+repo+"/hooks",
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return hook, fmt.Errorf("unable to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+userGithubToken)
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	resp, err := service.providerClient.Do(schemas.Github, req)
+	if err != nil {
+		return hook, fmt.Errorf("unable to make request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		errorBody, _ := io.ReadAll(resp.Body)
+		return hook, statusError(resp.StatusCode, fmt.Errorf(
+			"unexpected status code: %d, response: %s",
+			resp.StatusCode,
+			string(errorBody),
+		))
+	}
+
+	err = json.NewDecoder(resp.Body).Decode(&hook)
+	if err != nil {
+		return hook, fmt.Errorf("unable to decode response: %w", err)
 	}
+	return hook, nil
+}
 
-	return workflowRunList, nil
+// HandleWebhook checks the signature of a delivery of a webhook and maps its event onto the
+// GitHub action detecting it: a push with a head commit onto UpdateCommitInRepo, an opened
+// pull request onto UpdatePullRequestInRepo, and a requested workflow run onto
+// UpdateWorkflowRunInRepo. The other events, such as the ping sent when the webhook is
+// created, trigger nothing.
+// Every enabled area of the user of the webhook whose action is the mapped action on the
+// repository of the event gets a trigger with the payload its check would report, and its
+// storage variable moves past the event, so that its next check does not report it again.
+//
+// Parameters:
+//   - webhookId: The ID of the webhook, from the URL of the delivery.
+//   - event: The name of the event, from the X-GitHub-Event header.
+//   - signature: The signature of the delivery, from the X-Hub-Signature-256 header.
+//   - body: The raw body of the delivery.
+//
+// Returns:
+//   - triggers: The triggers of the areas, to queue for their reaction workers.
+//   - err: schemas.ErrGithubWebhookNotFound if the webhook does not exist,
+//     schemas.ErrInvalidWebhookSignature if the signature does not match the body, or an
+//     error if the event can not be decoded or the areas updated.
+func (service *githubService) HandleWebhook(
+	webhookId uint64,
+	event string,
+	signature string,
+	body []byte,
+) (triggers []schemas.AreaTrigger, err error) {
+	webhook, err := service.repository.FindWebhookById(webhookId)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find webhook: %w", err)
+	}
+	if !strings.HasPrefix(signature, schemas.GithubSignaturePrefix) ||
+		!tools.VerifySignature(
+			webhook.Secret,
+			body,
+			strings.TrimPrefix(signature, schemas.GithubSignaturePrefix),
+		) {
+		return nil, schemas.ErrInvalidWebhookSignature
+	}
+
+	var action schemas.GithubAction
+	var repoName string
+	var trigger func(repoName string) ([]schemas.ActionResult, error)
+	switch schemas.GithubWebhookEvent(event) {
+	case schemas.GithubEventPush:
+		push := schemas.GithubPushEvent{}
+		err = json.Unmarshal(body, &push)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode push event: %w", err)
+		}
+		if push.HeadCommit == nil {
+			return nil, nil
+		}
+		action, repoName = schemas.UpdateCommitInRepo, push.Repository.FullName
+		trigger = func(repoName string) ([]schemas.ActionResult, error) {
+			return newTrigger(
+				"new commit update in "+repoName+" repository",
+				schemas.GithubCommitPayload{
+					Repository: repoName,
+					Sha:        push.HeadCommit.Id,
+					Author:     push.HeadCommit.Author.Name,
+					Message:    push.HeadCommit.Message,
+					Url:        push.HeadCommit.URL,
+					Date:       push.HeadCommit.Timestamp,
+				},
+			)
+		}
+	case schemas.GithubEventPullRequest:
+		pullRequest := schemas.GithubPullRequestEvent{}
+		err = json.Unmarshal(body, &pullRequest)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode pull request event: %w", err)
+		}
+		if pullRequest.Action != "opened" {
+			return nil, nil
+		}
+		action, repoName = schemas.UpdatePullRequestInRepo, pullRequest.Repository.FullName
+		trigger = func(repoName string) ([]schemas.ActionResult, error) {
+			return newTrigger(
+				"new pull request update in "+repoName+" repository",
+				schemas.GithubPullRequestPayload{
+					Repository: repoName,
+					Number:     pullRequest.PullRequest.Number,
+					Title:      pullRequest.PullRequest.Title,
+					Author:     pullRequest.PullRequest.User.Login,
+					Url:        pullRequest.PullRequest.HTMLURL,
+				},
+			)
+		}
+	case schemas.GithubEventWorkflowRun:
+		workflowRun := schemas.GithubWorkflowRunEvent{}
+		err = json.Unmarshal(body, &workflowRun)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode workflow run event: %w", err)
+		}
+		if workflowRun.Action != "requested" {
+			return nil, nil
+		}
+		action, repoName = schemas.UpdateWorkflowRunInRepo, workflowRun.Repository.FullName
+		trigger = func(repoName string) ([]schemas.ActionResult, error) {
+			return newTrigger(
+				"new workflow run in "+repoName+" repository",
+				schemas.GithubWorkflowRunPayload{
+					Repository: repoName,
+					Name:       workflowRun.WorkflowRun.Name,
+					Status:     workflowRun.WorkflowRun.Status,
+					Branch:     workflowRun.WorkflowRun.HeadBranch,
+					Url:        workflowRun.WorkflowRun.HTMLURL,
+				},
+			)
+		}
+	default:
+		return nil, nil
+	}
+	return service.triggerAreas(webhook.UserId, action, repoName, trigger)
+}
+
+// triggerAreas builds the triggers of the enabled areas of the user whose action is the given
+// GitHub action on the repository, and moves their storage variable past the event.
+//
+// Parameters:
+//   - userId: The ID of the user owning the areas.
+//   - action: The GitHub action detecting the event.
+//   - repoName: The repository of the event, in the format "owner/repo".
+//   - trigger: Builds the triggers of the event, with the repository name of the area.
+//
+// Returns:
+//   - triggers: The triggers of the areas.
+//   - err: An error if the areas can not be updated.
+func (service *githubService) triggerAreas(
+	userId uint64,
+	action schemas.GithubAction,
+	repoName string,
+	trigger func(repoName string) ([]schemas.ActionResult, error),
+) (triggers []schemas.AreaTrigger, err error) {
+	areas, err := service.areaRepository.FindByUserId(userId)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find areas: %w", err)
+	}
+	for _, area := range areas {
+		if !area.Enable || area.Action.Name != string(action) {
+			continue
+		}
+		option := schemas.GithubActionOption{}
+		err = json.Unmarshal(area.ActionOption, &option)
+		if err != nil || !strings.EqualFold(option.RepoName, repoName) {
+			continue
+		}
+		results, err := trigger(option.RepoName)
+		if err != nil {
+			return triggers, err
+		}
+
+		// the checks of the area only report the events following this one
+		databaseStored := schemas.GithubActionOptionStorage{}
+		_ = json.Unmarshal(area.StorageVariable, &databaseStored)
+		databaseStored.Time = time.Now()
+		area.StorageVariable, err = json.Marshal(databaseStored)
+		if err != nil {
+			return triggers, fmt.Errorf("unable to marshal storage variable: %w", err)
+		}
+		err = service.areaRepository.UpdateStorageVariable(area)
+		if err != nil {
+			return triggers, fmt.Errorf("unable to update area: %w", err)
+		}
+
+		for _, result := range results {
+			triggers = append(triggers, schemas.AreaTrigger{Area: area, Trigger: result})
+		}
+	}
+	return triggers, nil
 }
 
 // Actions functions
 
 // GithubActionUpdateCommitInRepo checks for new commits in a GitHub repository and updates the area storage variable with the latest commit time.
-// If a new commit is found, it sends a message to the provided channel.
+// If a new commit is found, it returns a trigger describing the update.
 //
 // Parameters:
-//   - channel: A channel to send messages about new commits.
+//   - ctx: The context of the check, cancelling the GitHub requests when done.
 //   - option: A JSON raw message containing the options for the GitHub action.
 //   - area: The area schema containing user and action information.
 //
@@ -730,25 +1120,22 @@ func (service *githubService) WorkflowRunList(
 //  3. Unmarshals the provided option JSON into a GithubActionOption struct.
 //  4. Retrieves the list of commits from the specified repository.
 //  5. Checks if there are new commits since the last stored commit time.
-//  6. If a new commit is found, updates the storage variable and sends a message to the channel.
-//  7. Sleeps for the specified refresh rate before checking again.
+//  6. If a new commit is found, updates the storage variable and returns a trigger.
 func (service *githubService) GithubActionUpdateCommitInRepo(
-	channel chan string,
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) {
+) ([]schemas.ActionResult, error) {
 	// Find the token of the user
 	token, err := service.tokenRepository.FindByUserIdAndServiceId(
 		area.UserId,
 		area.Action.ServiceId,
 	)
 	if err != nil {
-		fmt.Println("Error finding token:", err)
-		return
+		return nil, fmt.Errorf("error finding token: %w", err)
 	}
 	if token.Token == "" {
-		fmt.Println("Error: Token not found")
-		return
+		return nil, schemas.ErrTokenNotFound
 	}
 
 	databaseStored := schemas.GithubActionOptionStorage{}
@@ -757,8 +1144,7 @@ func (service *githubService) GithubActionUpdateCommitInRepo(
 		toto := struct{}{}
 		err = json.Unmarshal(area.StorageVariable, &toto)
 		if err != nil {
-			println("error unmarshalling storage variable: " + err.Error())
-			return
+			return nil, fmt.Errorf("error unmarshalling storage variable: %w", err)
 		} else {
 			println("initializing storage variable")
 			databaseStored = schemas.GithubActionOptionStorage{
@@ -766,13 +1152,11 @@ func (service *githubService) GithubActionUpdateCommitInRepo(
 			}
 			area.StorageVariable, err = json.Marshal(databaseStored)
 			if err != nil {
-				println("error marshalling storage variable: " + err.Error())
-				return
+				return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 			}
-			err = service.areaRepository.Update(area)
+			err = service.areaRepository.UpdateStorageVariable(area)
 			if err != nil {
-				println("error updating area: " + err.Error())
-				return
+				return nil, fmt.Errorf("error updating area: %w", err)
 			}
 		}
 	}
@@ -784,13 +1168,11 @@ func (service *githubService) GithubActionUpdateCommitInRepo(
 		}
 		area.StorageVariable, err = json.Marshal(databaseStored)
 		if err != nil {
-			println("error marshalling storage variable: " + err.Error())
-			return
+			return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 		}
-		err = service.areaRepository.Update(area)
+		err = service.areaRepository.UpdateStorageVariable(area)
 		if err != nil {
-			println("error updating area: " + err.Error())
-			return
+			return nil, fmt.Errorf("error updating area: %w", err)
 		}
 	}
 
@@ -799,44 +1181,72 @@ func (service *githubService) GithubActionUpdateCommitInRepo(
 
 	err = json.Unmarshal([]byte(option), &optionJSON)
 	if err != nil {
-		println("error unmarshal weather option: " + err.Error())
-		time.Sleep(time.Second)
-		return
+		return nil, fmt.Errorf("error unmarshal github option: %w", err)
 	}
 
-	commitList, err := service.CommitList(token.Token, optionJSON.RepoName)
+	commitList, validators, err := cachedConditionalPoll(ctx, service.pollCache, area,
+		schemas.PollKey{
+			Provider:   schemas.Github,
+			Resource:   "repos/" + optionJSON.RepoName + "/commits",
+			Credential: token.Token,
+		},
+		databaseStored.ConditionalState,
+		func(
+			ctx context.Context,
+			validators schemas.ConditionalState,
+		) ([]schemas.GithubCommit, schemas.ConditionalState, error) {
+			return service.CommitList(ctx, token.Token, optionJSON.RepoName, validators)
+		},
+	)
+	if errors.Is(err, schemas.ErrNotModified) {
+		// the commits did not change since the last poll of the area
+		return nil, nil
+	}
 	if err != nil {
-		fmt.Println(err)
+		return nil, fmt.Errorf("error get commit list: %w", err)
 	}
+	validatorsChanged := validators != databaseStored.ConditionalState
+	databaseStored.ConditionalState = validators
 
 	if service.IsCommitUpdate(commitList, databaseStored.Time) {
 		response := "new commit update in " + optionJSON.RepoName + " repository"
 		databaseStored.Time = time.Now()
 		area.StorageVariable, err = json.Marshal(databaseStored)
 		if err != nil {
-			println("error marshalling storage variable: " + err.Error())
-			return
+			return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 		}
-		err = service.areaRepository.Update(area)
+		err = service.areaRepository.UpdateStorageVariable(area)
 		if err != nil {
-			println("error updating area: " + err.Error())
-			return
+			return nil, fmt.Errorf("error updating area: %w", err)
 		}
-		println(response)
-		channel <- response
+		return newTrigger(response, schemas.GithubCommitPayload{
+			Repository: optionJSON.RepoName,
+			Sha:        commitList[0].Sha,
+			Author:     commitList[0].Commit.Author.Name,
+			Message:    commitList[0].Commit.Message,
+			Url:        commitList[0].HTMLURL,
+			Date:       commitList[0].Commit.Author.Date,
+		})
 	}
 
-	if (area.Action.MinimumRefreshRate) > area.ActionRefreshRate {
-		time.Sleep(time.Second * time.Duration(area.Action.MinimumRefreshRate))
-	} else {
-		time.Sleep(time.Second * time.Duration(area.ActionRefreshRate))
+	if validatorsChanged {
+		area.StorageVariable, err = json.Marshal(databaseStored)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling storage variable: %w", err)
+		}
+		err = service.areaRepository.UpdateStorageVariable(area)
+		if err != nil {
+			return nil, fmt.Errorf("error updating area: %w", err)
+		}
 	}
+
+	return nil, nil
 }
 
-// GithubActionUpdatePullRequestInRepo checks for updates in a GitHub repository's pull requests and sends a notification if there are new updates.
+// GithubActionUpdatePullRequestInRepo checks for updates in a GitHub repository's pull requests and returns a trigger if there are new updates.
 //
 // Parameters:
-//   - channel: A channel to send the update message.
+//   - ctx: The context of the check, cancelling the GitHub requests when done.
 //   - option: A JSON raw message containing the options for the GitHub action.
 //   - area: A schema containing user and action information.
 //
@@ -846,25 +1256,22 @@ func (service *githubService) GithubActionUpdateCommitInRepo(
 //  3. Unmarshals the options for the GitHub action.
 //  4. Retrieves the list of pull requests for the specified repository.
 //  5. Checks if there are any updates to the pull requests since the last stored time.
-//  6. Sends a notification message if there are updates and updates the storage variable.
-//  7. Sleeps for the specified refresh rate before the next check.
+//  6. Returns a trigger if there are updates and updates the storage variable.
 func (service *githubService) GithubActionUpdatePullRequestInRepo(
-	channel chan string,
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) {
+) ([]schemas.ActionResult, error) {
 	// Find the token of the user
 	token, err := service.tokenRepository.FindByUserIdAndServiceId(
 		area.UserId,
 		area.Action.ServiceId,
 	)
 	if err != nil {
-		fmt.Println("Error finding token:", err)
-		return
+		return nil, fmt.Errorf("error finding token: %w", err)
 	}
 	if token.Token == "" {
-		fmt.Println("Error: Token not found")
-		return
+		return nil, schemas.ErrTokenNotFound
 	}
 
 	databaseStored := schemas.GithubActionOptionStorage{}
@@ -873,8 +1280,7 @@ func (service *githubService) GithubActionUpdatePullRequestInRepo(
 		toto := struct{}{}
 		err = json.Unmarshal(area.StorageVariable, &toto)
 		if err != nil {
-			println("error unmarshalling storage variable: " + err.Error())
-			return
+			return nil, fmt.Errorf("error unmarshalling storage variable: %w", err)
 		} else {
 			println("initializing storage variable")
 			databaseStored = schemas.GithubActionOptionStorage{
@@ -882,13 +1288,11 @@ func (service *githubService) GithubActionUpdatePullRequestInRepo(
 			}
 			area.StorageVariable, err = json.Marshal(databaseStored)
 			if err != nil {
-				println("error marshalling storage variable: " + err.Error())
-				return
+				return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 			}
-			err = service.areaRepository.Update(area)
+			err = service.areaRepository.UpdateStorageVariable(area)
 			if err != nil {
-				println("error updating area: " + err.Error())
-				return
+				return nil, fmt.Errorf("error updating area: %w", err)
 			}
 		}
 	}
@@ -900,13 +1304,11 @@ func (service *githubService) GithubActionUpdatePullRequestInRepo(
 		}
 		area.StorageVariable, err = json.Marshal(databaseStored)
 		if err != nil {
-			println("error marshalling storage variable: " + err.Error())
-			return
+			return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 		}
-		err = service.areaRepository.Update(area)
+		err = service.areaRepository.UpdateStorageVariable(area)
 		if err != nil {
-			println("error updating area: " + err.Error())
-			return
+			return nil, fmt.Errorf("error updating area: %w", err)
 		}
 	}
 
@@ -915,46 +1317,73 @@ func (service *githubService) GithubActionUpdatePullRequestInRepo(
 
 	err = json.Unmarshal([]byte(option), &optionJSON)
 	if err != nil {
-		println("error unmarshal weather option: " + err.Error())
-		time.Sleep(time.Second)
-		return
+		return nil, fmt.Errorf("error unmarshal github option: %w", err)
 	}
 
-	pullRequestList, err := service.PullRequestList(token.Token, optionJSON.RepoName)
+	pullRequestList, validators, err := cachedConditionalPoll(ctx, service.pollCache, area,
+		schemas.PollKey{
+			Provider:   schemas.Github,
+			Resource:   "repos/" + optionJSON.RepoName + "/pulls",
+			Credential: token.Token,
+		},
+		databaseStored.ConditionalState,
+		func(
+			ctx context.Context,
+			validators schemas.ConditionalState,
+		) ([]schemas.GithubPullRequest, schemas.ConditionalState, error) {
+			return service.PullRequestList(ctx, token.Token, optionJSON.RepoName, validators)
+		},
+	)
+	if errors.Is(err, schemas.ErrNotModified) {
+		// the pull requests did not change since the last poll of the area
+		return nil, nil
+	}
 	if err != nil {
-		fmt.Println(err)
+		return nil, fmt.Errorf("error get pull request list: %w", err)
 	}
+	validatorsChanged := validators != databaseStored.ConditionalState
+	databaseStored.ConditionalState = validators
 
 	if service.IsPullRequestUpdate(pullRequestList, databaseStored.Time) {
 		response := "new pull request update in " + optionJSON.RepoName + " repository"
 		databaseStored.Time = time.Now()
 		area.StorageVariable, err = json.Marshal(databaseStored)
 		if err != nil {
-			println("error marshalling storage variable: " + err.Error())
-			return
+			return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 		}
-		err = service.areaRepository.Update(area)
+		err = service.areaRepository.UpdateStorageVariable(area)
 		if err != nil {
-			println("error updating area: " + err.Error())
-			return
+			return nil, fmt.Errorf("error updating area: %w", err)
 		}
-		println(response)
-		channel <- response
+		return newTrigger(response, schemas.GithubPullRequestPayload{
+			Repository: optionJSON.RepoName,
+			Number:     pullRequestList[0].Number,
+			Title:      pullRequestList[0].Title,
+			Author:     pullRequestList[0].User.Login,
+			Url:        pullRequestList[0].HTMLURL,
+		})
 	}
 
-	if (area.Action.MinimumRefreshRate) > area.ActionRefreshRate {
-		time.Sleep(time.Second * time.Duration(area.Action.MinimumRefreshRate))
-	} else {
-		time.Sleep(time.Second * time.Duration(area.ActionRefreshRate))
+	if validatorsChanged {
+		area.StorageVariable, err = json.Marshal(databaseStored)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling storage variable: %w", err)
+		}
+		err = service.areaRepository.UpdateStorageVariable(area)
+		if err != nil {
+			return nil, fmt.Errorf("error updating area: %w", err)
+		}
 	}
+
+	return nil, nil
 }
 
 // GithubActionUpdateWorkflowRunInRepo checks for updates in GitHub workflow runs for a specified repository.
 // It retrieves the user's token, unmarshals the storage variable, and updates the storage variable if necessary.
-// If a new workflow run is detected, it sends a response message to the provided channel.
+// If a new workflow run is detected, it returns a trigger describing it.
 //
 // Parameters:
-//   - channel: A channel to send response messages.
+//   - ctx: The context of the check, cancelling the GitHub requests when done.
 //   - option: A JSON raw message containing the options for the GitHub action.
 //   - area: A schema containing user and action information.
 //
@@ -966,27 +1395,24 @@ func (service *githubService) GithubActionUpdatePullRequestInRepo(
 //  5. Retrieves the list of workflow runs from the GitHub repository.
 //  6. Checks if there are any new workflow runs since the last stored time.
 //  7. Updates the storage variable with the current time if a new workflow run is detected.
-//  8. Sends a response message to the channel if a new workflow run is detected.
-//  9. Sleeps for the specified refresh rate before the next check.
+//  8. Returns a trigger if a new workflow run is detected.
 //
-// Errors are printed to the console, and the function returns early if any errors occur during the process.
+// The function returns early with an error if any step of the process fails.
 func (service *githubService) GithubActionUpdateWorkflowRunInRepo(
-	channel chan string,
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) {
+) ([]schemas.ActionResult, error) {
 	// Find the token of the user
 	token, err := service.tokenRepository.FindByUserIdAndServiceId(
 		area.UserId,
 		area.Action.ServiceId,
 	)
 	if err != nil {
-		fmt.Println("Error finding token:", err)
-		return
+		return nil, fmt.Errorf("error finding token: %w", err)
 	}
 	if token.Token == "" {
-		fmt.Println("Error: Token not found")
-		return
+		return nil, schemas.ErrTokenNotFound
 	}
 
 	databaseStored := schemas.GithubActionOptionStorage{}
@@ -995,8 +1421,7 @@ func (service *githubService) GithubActionUpdateWorkflowRunInRepo(
 		toto := struct{}{}
 		err = json.Unmarshal(area.StorageVariable, &toto)
 		if err != nil {
-			println("error unmarshalling storage variable: " + err.Error())
-			return
+			return nil, fmt.Errorf("error unmarshalling storage variable: %w", err)
 		} else {
 			println("initializing storage variable")
 			databaseStored = schemas.GithubActionOptionStorage{
@@ -1004,13 +1429,11 @@ func (service *githubService) GithubActionUpdateWorkflowRunInRepo(
 			}
 			area.StorageVariable, err = json.Marshal(databaseStored)
 			if err != nil {
-				println("error marshalling storage variable: " + err.Error())
-				return
+				return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 			}
-			err = service.areaRepository.Update(area)
+			err = service.areaRepository.UpdateStorageVariable(area)
 			if err != nil {
-				println("error updating area: " + err.Error())
-				return
+				return nil, fmt.Errorf("error updating area: %w", err)
 			}
 		}
 	}
@@ -1022,13 +1445,11 @@ func (service *githubService) GithubActionUpdateWorkflowRunInRepo(
 		}
 		area.StorageVariable, err = json.Marshal(databaseStored)
 		if err != nil {
-			println("error marshalling storage variable: " + err.Error())
-			return
+			return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 		}
-		err = service.areaRepository.Update(area)
+		err = service.areaRepository.UpdateStorageVariable(area)
 		if err != nil {
-			println("error updating area: " + err.Error())
-			return
+			return nil, fmt.Errorf("error updating area: %w", err)
 		}
 	}
 
@@ -1037,38 +1458,65 @@ func (service *githubService) GithubActionUpdateWorkflowRunInRepo(
 
 	err = json.Unmarshal([]byte(option), &optionJSON)
 	if err != nil {
-		println("error unmarshal weather option: " + err.Error())
-		time.Sleep(time.Second)
-		return
+		return nil, fmt.Errorf("error unmarshal github option: %w", err)
 	}
 
-	workflowRunList, err := service.WorkflowRunList(token.Token, optionJSON.RepoName)
+	workflowRunList, validators, err := cachedConditionalPoll(ctx, service.pollCache, area,
+		schemas.PollKey{
+			Provider:   schemas.Github,
+			Resource:   "repos/" + optionJSON.RepoName + "/actions/runs",
+			Credential: token.Token,
+		},
+		databaseStored.ConditionalState,
+		func(
+			ctx context.Context,
+			validators schemas.ConditionalState,
+		) (schemas.GithubWorkflowRunsList, schemas.ConditionalState, error) {
+			return service.WorkflowRunList(ctx, token.Token, optionJSON.RepoName, validators)
+		},
+	)
+	if errors.Is(err, schemas.ErrNotModified) {
+		// the workflow runs did not change since the last poll of the area
+		return nil, nil
+	}
 	if err != nil {
-		fmt.Println(err)
+		return nil, fmt.Errorf("error get workflow run list: %w", err)
 	}
+	validatorsChanged := validators != databaseStored.ConditionalState
+	databaseStored.ConditionalState = validators
 
 	if service.IsWorkflowRunUpdate(workflowRunList.WorkflowRuns, databaseStored.Time) {
 		response := "new workflow run in " + optionJSON.RepoName + " repository"
 		databaseStored.Time = time.Now()
 		area.StorageVariable, err = json.Marshal(databaseStored)
 		if err != nil {
-			println("error marshalling storage variable: " + err.Error())
-			return
+			return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 		}
-		err = service.areaRepository.Update(area)
+		err = service.areaRepository.UpdateStorageVariable(area)
 		if err != nil {
-			println("error updating area: " + err.Error())
-			return
+			return nil, fmt.Errorf("error updating area: %w", err)
 		}
-		println(response)
-		channel <- response
+		return newTrigger(response, schemas.GithubWorkflowRunPayload{
+			Repository: optionJSON.RepoName,
+			Name:       workflowRunList.WorkflowRuns[0].Name,
+			Status:     workflowRunList.WorkflowRuns[0].Status,
+			Branch:     workflowRunList.WorkflowRuns[0].HeadBranch,
+			Url:        workflowRunList.WorkflowRuns[0].HTMLURL,
+		})
 	}
 
-	if (area.Action.MinimumRefreshRate) > area.ActionRefreshRate {
-		time.Sleep(time.Second * time.Duration(area.Action.MinimumRefreshRate))
-	} else {
-		time.Sleep(time.Second * time.Duration(area.ActionRefreshRate))
+	if validatorsChanged {
+		area.StorageVariable, err = json.Marshal(databaseStored)
+		if err != nil {
+			return nil, fmt.Errorf("error marshalling storage variable: %w", err)
+		}
+		err = service.areaRepository.UpdateStorageVariable(area)
+		if err != nil {
+			return nil, fmt.Errorf("error updating area: %w", err)
+		}
 	}
+
+	return nil, nil
 }
 
 // Reactions functions
@@ -1076,26 +1524,29 @@ func (service *githubService) GithubActionUpdateWorkflowRunInRepo(
 // GithubReactionGetLatestCommitInRepo retrieves the latest commit in a specified GitHub repository.
 //
 // Parameters:
+//   - ctx: The context of the reaction, cancelling the GitHub request when done.
 //   - option: A JSON raw message containing the repository name.
 //   - area: An Area schema containing user and service information.
 //
 // Returns:
-//   - A string describing the latest commit, including the author's name, commit message, repository name, and commit date.
-//   - An error message if any step in the process fails, such as token retrieval, JSON unmarshalling, or commit list retrieval.
+//   - The result describing the latest commit, including the author's name, commit message, repository name, and commit date,
+//     with the commit as its payload.
+//   - An error if any step in the process fails, such as token retrieval, JSON unmarshalling, or commit list retrieval.
 func (service *githubService) GithubReactionGetLatestCommitInRepo(
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) string {
+) (schemas.ReactionResult, error) {
 	// Find the token of the user
 	token, err := service.tokenRepository.FindByUserIdAndServiceId(
 		area.UserId,
 		area.Reaction.ServiceId,
 	)
 	if err != nil {
-		return "Error finding token:" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error finding token: %w", err)
 	}
 	if token.Token == "" {
-		return "Error: Token not found"
+		return schemas.ReactionResult{}, schemas.ErrTokenNotFound
 	}
 
 	// Unmarshal the option
@@ -1103,50 +1554,70 @@ func (service *githubService) GithubReactionGetLatestCommitInRepo(
 
 	err = json.Unmarshal([]byte(option), &optionJSON)
 	if err != nil {
-		return "error unmarshal weather option: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error unmarshal github option: %w", err)
 	}
 
-	commitList, err := service.CommitList(token.Token, optionJSON.RepoName)
+	commitList, _, err := service.CommitList(
+		ctx,
+		token.Token,
+		optionJSON.RepoName,
+		schemas.ConditionalState{},
+	)
 	if err != nil {
-		return err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error get commit list: %w", err)
 	}
 
 	if (len(commitList)) == 0 {
-		return "No commit found in " + optionJSON.RepoName + " repository"
+		return schemas.ReactionResult{
+			Message: "No commit found in " + optionJSON.RepoName + " repository",
+		}, nil
 	} else {
-		return commitList[0].Commit.Author.Name + " commit " + commitList[0].Commit.Message + " in " + optionJSON.RepoName + " repository, at " + commitList[0].Commit.Author.Date.String()
+		commit := commitList[0]
+		response := commit.Commit.Author.Name + " commit " + commit.Commit.Message +
+			" in " + optionJSON.RepoName + " repository, at " + commit.Commit.Author.Date.String()
+		return newReactionResult(response, schemas.GithubCommitPayload{
+			Repository: optionJSON.RepoName,
+			Sha:        commit.Sha,
+			Author:     commit.Commit.Author.Name,
+			Message:    commit.Commit.Message,
+			Url:        commit.HTMLURL,
+			Date:       commit.Commit.Author.Date,
+		})
 	}
 }
 
 // GithubReactionGetLatestWorkflowRunInRepo retrieves the latest workflow run in a specified GitHub repository.
 //
 // Parameters:
+//   - ctx: The context of the reaction, cancelling the GitHub request when done.
 //   - option: A JSON raw message containing the repository name.
 //   - area: An Area struct containing user and service information.
 //
 // Returns:
 //
-//	A string describing the latest workflow run in the specified repository, or an error message if any issues occur.
+//	The result describing the latest workflow run in the specified repository, with the workflow
+//	run as its payload, or an error if any issues occur.
 //
 // The function performs the following steps:
 //  1. Finds the token of the user for the specified service.
 //  2. Unmarshals the option JSON to extract the repository name.
 //  3. Retrieves the list of workflow runs for the specified repository.
-//  4. Returns the name and creation date of the latest workflow run.
+//  4. Returns the name and creation date of the latest workflow run, and the run as payload.
 func (service *githubService) GithubReactionGetLatestWorkflowRunInRepo(
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) string {
+) (schemas.ReactionResult, error) {
 	// Find the token of the user
 	token, err := service.tokenRepository.FindByUserIdAndServiceId(
 		area.UserId,
 		area.Reaction.ServiceId,
 	)
 	if err != nil {
-		return "Error finding token:" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error finding token: %w", err)
 	}
 	if token.Token == "" {
-		return "Error: Token not found"
+		return schemas.ReactionResult{}, schemas.ErrTokenNotFound
 	}
 
 	// Unmarshal the option
@@ -1154,17 +1625,33 @@ func (service *githubService) GithubReactionGetLatestWorkflowRunInRepo(
 
 	err = json.Unmarshal([]byte(option), &optionJSON)
 	if err != nil {
-		return "error unmarshal weather option: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error unmarshal github option: %w", err)
 	}
 
-	workflowList, err := service.WorkflowRunList(token.Token, optionJSON.RepoName)
+	workflowList, _, err := service.WorkflowRunList(
+		ctx,
+		token.Token,
+		optionJSON.RepoName,
+		schemas.ConditionalState{},
+	)
 	if err != nil {
-		return err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error get workflow run list: %w", err)
 	}
 
 	if workflowList.TotalCount == 0 {
-		return "No workflow run found in " + optionJSON.RepoName + " repository"
+		return schemas.ReactionResult{
+			Message: "No workflow run found in " + optionJSON.RepoName + " repository",
+		}, nil
 	} else {
-		return workflowList.WorkflowRuns[0].Name + " workflow run in " + optionJSON.RepoName + " repository, at " + workflowList.WorkflowRuns[0].CreatedAt.String()
+		workflowRun := workflowList.WorkflowRuns[0]
+		response := workflowRun.Name + " workflow run in " + optionJSON.RepoName +
+			" repository, at " + workflowRun.CreatedAt.String()
+		return newReactionResult(response, schemas.GithubWorkflowRunPayload{
+			Repository: optionJSON.RepoName,
+			Name:       workflowRun.Name,
+			Status:     workflowRun.Status,
+			Branch:     workflowRun.HeadBranch,
+			Url:        workflowRun.HTMLURL,
+		})
 	}
 }
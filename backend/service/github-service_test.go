@@ -0,0 +1,232 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"area/schemas"
+	"area/service"
+	"area/test"
+	"area/tools"
+)
+
+const githubPushEvent = `{
+	"ref": "refs/heads/main",
+	"repository": {"full_name": "Owner/Repo"},
+	"head_commit": {
+		"id": "6dcb09b",
+		"message": "Fix all the bugs",
+		"timestamp": "2024-01-02T03:04:05Z",
+		"url": "https://github.com/Owner/Repo/commit/6dcb09b",
+		"author": {"name": "Monalisa Octocat"}
+	}
+}`
+
+func newGithubWebhookService(
+	areas []schemas.Area,
+) (service.GithubService, *test.MockAreaRepository) {
+	mockGithubRepository := new(test.MockGithubRepository)
+	webhook := schemas.GithubWebhook{Id: 1, UserId: 7, RepoName: "owner/repo", Secret: "secret"}
+	mockGithubRepository.On("FindWebhookById", uint64(1)).Return(webhook, nil)
+	mockGithubRepository.On("FindWebhookById", mock.Anything).
+		Return(schemas.GithubWebhook{}, schemas.ErrGithubWebhookNotFound)
+	mockAreaRepository := new(test.MockAreaRepository)
+	mockAreaRepository.On("FindByUserId", uint64(7)).Return(areas, nil)
+	mockAreaRepository.On("UpdateStorageVariable", mock.Anything).Return(nil)
+	githubService := service.NewGithubService(
+		mockGithubRepository,
+		nil,
+		mockAreaRepository,
+		nil,
+		service.NewProviderClient(),
+		service.NewPollCache(),
+	)
+	return githubService, mockAreaRepository
+}
+
+func githubArea(id uint64, action schemas.GithubAction, repoName string) schemas.Area {
+	return schemas.Area{
+		Id:              id,
+		UserId:          7,
+		Enable:          true,
+		Action:          schemas.Action{Name: string(action)},
+		ActionOption:    json.RawMessage(`{"repo_name":"` + repoName + `"}`),
+		StorageVariable: json.RawMessage(`{}`),
+	}
+}
+
+func TestGithubWebhookTriggersMatchingAreas(t *testing.T) {
+	disabled := githubArea(4, schemas.UpdateCommitInRepo, "owner/repo")
+	disabled.Enable = false
+	githubService, mockAreaRepository := newGithubWebhookService([]schemas.Area{
+		githubArea(1, schemas.UpdateCommitInRepo, "owner/repo"),
+		githubArea(2, schemas.UpdateCommitInRepo, "owner/other"),
+		githubArea(3, schemas.UpdatePullRequestInRepo, "owner/repo"),
+		disabled,
+	})
+	body := []byte(githubPushEvent)
+
+	triggers, err := githubService.HandleWebhook(
+		1,
+		string(schemas.GithubEventPush),
+		schemas.GithubSignaturePrefix+tools.SignPayload("secret", body),
+		body,
+	)
+	assert.NoError(t, err)
+	assert.Len(t, triggers, 1)
+	assert.Equal(t, uint64(1), triggers[0].Area.Id)
+	assert.Equal(t, "new commit update in owner/repo repository", triggers[0].Trigger.Message)
+	payload := schemas.GithubCommitPayload{}
+	assert.NoError(t, json.Unmarshal(triggers[0].Trigger.Payload, &payload))
+	assert.Equal(t, "6dcb09b", payload.Sha)
+	assert.Equal(t, "Monalisa Octocat", payload.Author)
+
+	// the next check of the area does not report the pushed commit again
+	mockAreaRepository.AssertNumberOfCalls(t, "UpdateStorageVariable", 1)
+	storage := schemas.GithubActionOptionStorage{}
+	assert.NoError(t, json.Unmarshal(storedVariable(mockAreaRepository), &storage))
+	assert.True(t, storage.Time.After(payload.Date))
+}
+
+func TestGithubWebhookRejectsInvalidSignature(t *testing.T) {
+	githubService, mockAreaRepository := newGithubWebhookService([]schemas.Area{
+		githubArea(1, schemas.UpdateCommitInRepo, "owner/repo"),
+	})
+	body := []byte(githubPushEvent)
+
+	for _, signature := range []string{
+		"",
+		tools.SignPayload("secret", body),
+		schemas.GithubSignaturePrefix + tools.SignPayload("other", body),
+	} {
+		_, err := githubService.HandleWebhook(1, string(schemas.GithubEventPush), signature, body)
+		assert.ErrorIs(t, err, schemas.ErrInvalidWebhookSignature)
+	}
+	_, err := githubService.HandleWebhook(
+		2,
+		string(schemas.GithubEventPush),
+		schemas.GithubSignaturePrefix+tools.SignPayload("secret", body),
+		body,
+	)
+	assert.ErrorIs(t, err, schemas.ErrGithubWebhookNotFound)
+	mockAreaRepository.AssertNotCalled(t, "FindByUserId", mock.Anything)
+}
+
+func TestGithubWebhookIgnoresOtherEvents(t *testing.T) {
+	githubService, _ := newGithubWebhookService([]schemas.Area{
+		githubArea(1, schemas.UpdatePullRequestInRepo, "owner/repo"),
+	})
+
+	for event, body := range map[schemas.GithubWebhookEvent]string{
+		schemas.GithubEventPing:        `{"zen":"Non-blocking is better than blocking."}`,
+		schemas.GithubEventPullRequest: `{"action":"closed","repository":{"full_name":"owner/repo"}}`,
+		"issues":                       `{"action":"opened"}`,
+	} {
+		triggers, err := githubService.HandleWebhook(
+			1,
+			string(event),
+			schemas.GithubSignaturePrefix+tools.SignPayload("secret", []byte(body)),
+			[]byte(body),
+		)
+		assert.NoError(t, err, event)
+		assert.Empty(t, triggers, event)
+	}
+}
+
+func checkGithubPullRequests(
+	t *testing.T,
+	handler http.HandlerFunc,
+	storage schemas.GithubActionOptionStorage,
+) ([]schemas.ActionResult, *test.MockAreaRepository, error) {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	mockAreaRepository := new(test.MockAreaRepository)
+	mockAreaRepository.On("UpdateStorageVariable", mock.Anything).Return(nil)
+	mockTokenRepository := new(test.MockTokenRepository)
+	mockTokenRepository.On("FindByUserIdAndServiceId", uint64(7), uint64(3)).
+		Return(schemas.Token{Token: "token"}, nil)
+	githubService := service.NewGithubService(
+		nil,
+		nil,
+		mockAreaRepository,
+		mockTokenRepository,
+		&test.FakeProviderClient{URL: server.URL},
+		service.NewPollCache(),
+	)
+	area := githubArea(1, schemas.UpdatePullRequestInRepo, "owner/repo")
+	area.Action.ServiceId = 3
+	storageVariable, err := json.Marshal(storage)
+	assert.NoError(t, err)
+	area.StorageVariable = storageVariable
+	action := githubService.FindActionByName(string(schemas.UpdatePullRequestInRepo))
+	triggers, err := action(context.Background(), area.ActionOption, area)
+	return triggers, mockAreaRepository, err
+}
+
+func TestGithubPullRequestsSendsValidators(t *testing.T) {
+	storage := schemas.GithubActionOptionStorage{
+		Time: time.Now(),
+		ConditionalState: schemas.ConditionalState{
+			ETag:         `"pulls"`,
+			LastModified: "Mon, 02 Jan 2006 15:04:05 GMT",
+		},
+	}
+	triggers, mockAreaRepository, err := checkGithubPullRequests(t, func(
+		w http.ResponseWriter,
+		r *http.Request,
+	) {
+		assert.Equal(t, "/repos/owner/repo/pulls", r.URL.Path)
+		assert.Equal(t, `"pulls"`, r.Header.Get("If-None-Match"))
+		assert.Equal(t, "Mon, 02 Jan 2006 15:04:05 GMT", r.Header.Get("If-Modified-Since"))
+		// the empty body of the response fails to decode as pull requests
+		w.WriteHeader(http.StatusNotModified)
+	}, storage)
+
+	assert.NoError(t, err)
+	assert.Empty(t, triggers)
+	mockAreaRepository.AssertNotCalled(t, "UpdateStorageVariable", mock.Anything)
+}
+
+func TestGithubPullRequestsSavesValidators(t *testing.T) {
+	storage := schemas.GithubActionOptionStorage{Time: time.Now()}
+	triggers, mockAreaRepository, err := checkGithubPullRequests(t, func(
+		w http.ResponseWriter,
+		r *http.Request,
+	) {
+		assert.Empty(t, r.Header.Get("If-None-Match"))
+		assert.Empty(t, r.Header.Get("If-Modified-Since"))
+		w.Header().Set("ETag", `"pulls"`)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		_, err := w.Write([]byte(`[]`))
+		assert.NoError(t, err)
+	}, storage)
+
+	assert.NoError(t, err)
+	assert.Empty(t, triggers)
+	saved := schemas.GithubActionOptionStorage{}
+	assert.NoError(t, json.Unmarshal(storedVariable(mockAreaRepository), &saved))
+	assert.Equal(t, `"pulls"`, saved.ETag)
+	assert.Equal(t, "Mon, 02 Jan 2006 15:04:05 GMT", saved.LastModified)
+}
+
+func TestGithubPullRequestsRetriesUnavailableGithub(t *testing.T) {
+	for _, status := range []int{http.StatusTooManyRequests, http.StatusBadGateway} {
+		_, _, err := checkGithubPullRequests(t, func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(status)
+		}, schemas.GithubActionOptionStorage{Time: time.Now()})
+
+		assert.Error(t, err, status)
+		assert.True(t, tools.IsTransientError(err), status)
+	}
+	_, _, err := checkGithubPullRequests(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}, schemas.GithubActionOptionStorage{Time: time.Now()})
+	assert.False(t, tools.IsTransientError(err))
+}
@@ -5,11 +5,13 @@ import (
 	"context"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"time"
 
 	"area/repository"
@@ -22,16 +24,28 @@ type GoogleService interface {
 	// Service interface functions
 	GetServiceActionInfo() []schemas.Action
 	GetServiceReactionInfo() []schemas.Reaction
-	FindActionByName(name string) func(c chan string, option json.RawMessage, area schemas.Area)
-	FindReactionByName(name string) func(option json.RawMessage, area schemas.Area) string
+	FindActionByName(name string) ActionFunc
+	FindReactionByName(name string) ReactionFunc
 	// Token operations
 	// Service specific functions
 	AuthGetServiceAccessToken(code string) (token schemas.Token, err error)
+	RefreshServiceAccessToken(
+		ctx context.Context,
+		refreshToken string,
+	) (token schemas.Token, err error)
 	GetUserInfo(accessToken string) (user schemas.User, err error)
 	// Actions functions
-	GoogleActionReceiveMail(channel chan string, option json.RawMessage, area schemas.Area)
+	GoogleActionReceiveMail(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) ([]schemas.ActionResult, error)
 	// Reactions functions
-	GoogleReactionSendMail(option json.RawMessage, area schemas.Area) string
+	GoogleReactionSendMail(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) (schemas.ReactionResult, error)
 }
 
 // googleService is a struct that encapsulates various repositories and service information
@@ -48,6 +62,8 @@ type googleService struct {
 	serviceRepository repository.ServiceRepository
 	areaRepository    repository.AreaRepository
 	tokenRepository   repository.TokenRepository
+	providerClient    ProviderClient
+	pollCache         PollCache
 	serviceInfo       schemas.Service
 }
 
@@ -60,6 +76,8 @@ type googleService struct {
 //   - serviceRepository: an instance of ServiceRepository for accessing general service data.
 //   - areaRepository: an instance of AreaRepository for accessing area-related data.
 //   - tokenRepository: an instance of TokenRepository for accessing token-related data.
+//   - providerClient: an instance of ProviderClient for sending the requests to Google.
+//   - pollCache: an instance of PollCache for sharing the polls of Gmail between the areas.
 //
 // Returns:
 //
@@ -69,12 +87,16 @@ func NewGoogleService(
 	serviceRepository repository.ServiceRepository,
 	areaRepository repository.AreaRepository,
 	tokenRepository repository.TokenRepository,
+	providerClient ProviderClient,
+	pollCache PollCache,
 ) GoogleService {
 	return &googleService{
 		repository:        repository,
 		serviceRepository: serviceRepository,
 		areaRepository:    areaRepository,
 		tokenRepository:   tokenRepository,
+		providerClient:    providerClient,
+		pollCache:         pollCache,
 		serviceInfo: schemas.Service{
 			Name:        schemas.Google,
 			Description: "This service is a google service",
@@ -94,7 +116,7 @@ func (service *googleService) GetServiceInfo() schemas.Service {
 }
 
 // FindActionByName returns a function that matches the given action name.
-// The returned function takes a channel, a JSON raw message, and an area schema as parameters.
+// The returned ActionFunc takes a context, a JSON raw message, and an area schema as parameters.
 // If the action name matches a predefined action, the corresponding function is returned.
 // If the action name does not match any predefined actions, nil is returned.
 //
@@ -102,11 +124,10 @@ func (service *googleService) GetServiceInfo() schemas.Service {
 // - name: The name of the action to find.
 //
 // Returns:
-//   - A function that takes a channel, a JSON raw message, and an area schema as parameters,
-//     or nil if the action name does not match any predefined actions.
+//   - The ActionFunc of the action, or nil if the action name does not match any predefined actions.
 func (service *googleService) FindActionByName(
 	name string,
-) func(c chan string, option json.RawMessage, area schemas.Area) {
+) ActionFunc {
 	switch name {
 	case string(schemas.ReceiveGoogleMail):
 		return service.GoogleActionReceiveMail
@@ -116,7 +137,7 @@ func (service *googleService) FindActionByName(
 }
 
 // FindReactionByName returns a function that corresponds to the given reaction name.
-// The returned function takes a JSON raw message and an area schema as parameters and returns a string.
+// The returned ReactionFunc takes a context, a JSON raw message and an area schema as parameters.
 // If the reaction name matches a predefined case, the corresponding function is returned.
 // If the reaction name does not match any predefined cases, nil is returned.
 //
@@ -124,11 +145,11 @@ func (service *googleService) FindActionByName(
 //   - name: The name of the reaction to find.
 //
 // Returns:
-//   - A function that takes a JSON raw message and an area schema, and returns a string.
+//   - The ReactionFunc of the reaction.
 //     If the reaction name does not match any predefined cases, nil is returned.
 func (service *googleService) FindReactionByName(
 	name string,
-) func(option json.RawMessage, area schemas.Area) string {
+) ReactionFunc {
 	switch name {
 	case string(schemas.SendMail):
 		return service.GoogleReactionSendMail
@@ -159,6 +180,7 @@ func (service *googleService) GetServiceActionInfo() []schemas.Action {
 			Description:        "Receive an email with google service",
 			Service:            service.serviceInfo,
 			Option:             option,
+			OptionSchema:       newOptionSchema(schemas.GmailReceiveMailSchema),
 			MinimumRefreshRate: 10,
 		},
 	}
@@ -191,10 +213,11 @@ func (service *googleService) GetServiceReactionInfo() []schemas.Reaction {
 	}
 	return []schemas.Reaction{
 		{
-			Name:        string(schemas.SendMail),
-			Description: "Send an email with google service",
-			Service:     service.serviceInfo,
-			Option:      option,
+			Name:         string(schemas.SendMail),
+			Description:  "Send an email with google service",
+			Service:      service.serviceInfo,
+			Option:       option,
+			OptionSchema: newOptionSchema(schemas.GmailReactionSendMailOptionSchema),
 		},
 	}
 }
@@ -251,8 +274,7 @@ func (service *googleService) AuthGetServiceAccessToken(
 	req.URL.RawQuery = data.Encode()
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Google, req)
 	if err != nil {
 		return schemas.Token{}, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -280,6 +302,81 @@ func (service *googleService) AuthGetServiceAccessToken(
 	return token, nil
 }
 
+// RefreshServiceAccessToken exchanges a Google refresh token for a new access token.
+// Google keeps the refresh token of the user, so the returned token has no refresh token.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - refreshToken: The refresh token received when the user authorized Google.
+//
+// Returns:
+//   - token: A Token schema containing the new access token and its expiration time.
+//   - err: An error if the environment variables are missing or the request fails.
+//     It wraps schemas.ErrRefreshTokenRejected when Google rejects the refresh token.
+func (service *googleService) RefreshServiceAccessToken(
+	ctx context.Context,
+	refreshToken string,
+) (token schemas.Token, err error) {
+	clientID := os.Getenv("GOOGLE_CLIENT_ID")
+	if clientID == "" {
+		return schemas.Token{}, schemas.ErrGoogleClientIdNotSet
+	}
+
+	clientSecret := os.Getenv("GOOGLE_SECRET")
+	if clientSecret == "" {
+		return schemas.Token{}, schemas.ErrGoogleSecretNotSet
+	}
+
+	apiURL := "https://oauth2.googleapis.com/token"
+
+	data := url.Values{}
+	data.Set("client_id", clientID)
+	data.Set("client_secret", clientSecret)
+	data.Set("refresh_token", refreshToken)
+	data.Set("grant_type", "refresh_token")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, nil)
+	if err != nil {
+		return schemas.Token{}, fmt.Errorf("unable to create request because %w", err)
+	}
+
+	req.URL.RawQuery = data.Encode()
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := service.providerClient.Do(schemas.Google, req)
+	if err != nil {
+		return schemas.Token{}, fmt.Errorf("unable to make request because %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+		return schemas.Token{}, fmt.Errorf(
+			"unable to refresh token because %w",
+			schemas.ErrRefreshTokenRejected,
+		)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return schemas.Token{}, fmt.Errorf("unable to refresh token because %v", resp.Status)
+	}
+
+	var result schemas.GoogleTokenResponse
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return schemas.Token{}, fmt.Errorf("unable to decode response because %w", err)
+	}
+
+	if result.AccessToken == "" {
+		return schemas.Token{}, schemas.ErrAccessTokenNotFoundInResponse
+	}
+
+	token = schemas.Token{
+		Token:        result.AccessToken,
+		RefreshToken: result.RefreshToken,
+		ExpireAt:     time.Now().Add(time.Duration(result.ExpiresIn) * time.Second),
+	}
+	return token, nil
+}
+
 // GetUserGmailProfile retrieves the Gmail profile of the authenticated user using the provided access token.
 // It sends a GET request to the Gmail API and decodes the response into a GmailProfile schema.
 //
@@ -289,7 +386,9 @@ func (service *googleService) AuthGetServiceAccessToken(
 // Returns:
 //   - result: A GmailProfile struct containing the user's Gmail profile information.
 //   - err: An error if the request or decoding fails, otherwise nil.
-func GetUserGmailProfile(accessToken string) (result schemas.GmailProfile, err error) {
+func (service *googleService) GetUserGmailProfile(
+	accessToken string,
+) (result schemas.GmailProfile, err error) {
 	ctx := context.Background()
 
 	// Create a new HTTP request
@@ -305,9 +404,8 @@ func GetUserGmailProfile(accessToken string) (result schemas.GmailProfile, err e
 	// Add the Authorization header
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	// Make the request using the default HTTP client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Make the request through the shared provider client
+	resp, err := service.providerClient.Do(schemas.Google, req)
 	if err != nil {
 		return schemas.GmailProfile{}, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -330,7 +428,9 @@ func GetUserGmailProfile(accessToken string) (result schemas.GmailProfile, err e
 // Returns:
 //   - result: A schemas.GoogleProfile struct containing the user's profile information.
 //   - err: An error if the request or decoding fails, otherwise nil.
-func GetUserGoogleProfile(accessToken string) (result schemas.GoogleProfile, err error) {
+func (service *googleService) GetUserGoogleProfile(
+	accessToken string,
+) (result schemas.GoogleProfile, err error) {
 	ctx := context.Background()
 	// Create a new HTTP request
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet,
@@ -344,9 +444,8 @@ func GetUserGoogleProfile(accessToken string) (result schemas.GoogleProfile, err
 	// Add the Authorization header
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	// Make the request using the default HTTP client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Make the request through the shared provider client
+	resp, err := service.providerClient.Do(schemas.Google, req)
 	if err != nil {
 		return schemas.GoogleProfile{}, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -373,12 +472,12 @@ func GetUserGoogleProfile(accessToken string) (result schemas.GoogleProfile, err
 func (service *googleService) GetUserInfo(
 	accessToken string,
 ) (user schemas.User, err error) {
-	gmailProfile, err := GetUserGmailProfile(accessToken)
+	gmailProfile, err := service.GetUserGmailProfile(accessToken)
 	if err != nil {
 		return schemas.User{}, fmt.Errorf("unable to get gmail profile because %w", err)
 	}
 
-	googleProfile, err := GetUserGoogleProfile(accessToken)
+	googleProfile, err := service.GetUserGoogleProfile(accessToken)
 	if err != nil {
 		return schemas.User{}, fmt.Errorf("unable to get google profile because %w", err)
 	}
@@ -425,7 +524,7 @@ func initializedGoogleStorageVariable(
 				println("error marshalling storage variable: " + err.Error())
 				return variable, err
 			}
-			err = service.areaRepository.Update(area)
+			err = service.areaRepository.UpdateStorageVariable(area)
 			if err != nil {
 				println("error updating area: " + err.Error())
 				return variable, err
@@ -442,7 +541,7 @@ func initializedGoogleStorageVariable(
 			println("error marshalling storage variable: " + err.Error())
 			return variable, err
 		}
-		err = service.areaRepository.Update(area)
+		err = service.areaRepository.UpdateStorageVariable(area)
 		if err != nil {
 			println("error updating area: " + err.Error())
 			return variable, err
@@ -451,101 +550,197 @@ func initializedGoogleStorageVariable(
 	return variable, nil
 }
 
-// getLastEmailId retrieves the most recent email ID from the user's Gmail inbox after a specified time.
-// It takes a token for authorization and a GoogleVariableReceiveMail struct containing the time query.
-// It returns a GmailEmailResponse struct and an error if any occurred during the process.
+// getGmail sends a GET request to the Gmail API and decodes its response.
 //
 // Parameters:
+//   - ctx: The context of the request, cancelling it when done.
 //   - token: schemas.Token containing the authorization token.
-//   - variable: schemas.GoogleVariableReceiveMail containing the time query.
+//   - apiURL: The URL of the Gmail resource.
+//   - result: The value the response is decoded into.
 //
 // Returns:
-//   - schemas.GmailEmailResponse: Struct containing the email response.
-//   - error: Error if any occurred during the process.
-func getLastEmailId(
+//   - error: An error if the request fails or the response can not be decoded, wrapping
+//     schemas.ErrGmailNotFound if the resource does not exist.
+func (service *googleService) getGmail(
+	ctx context.Context,
 	token schemas.Token,
-	variable schemas.GoogleVariableReceiveMail,
-) (schemas.GmailEmailResponse, error) {
-	emailResponse := schemas.GmailEmailResponse{}
-	timeQuery := variable.Time.Format("2006/01/02")
-	apiURL := fmt.Sprintf(
-		"https://gmail.googleapis.com/gmail/v1/users/me/messages?maxResults=1&labelIds=INBOX&q=after:%s",
-		timeQuery,
-	)
-
-	ctx := context.Background()
+	apiURL string,
+	result any,
+) error {
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
-		println("error creating request: " + err.Error())
-		return emailResponse, err
+		return fmt.Errorf("error creating request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+token.Token)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Google, req)
 	if err != nil {
-		println("error making request: " + err.Error())
-		return emailResponse, err
+		return fmt.Errorf("error making request: %w", err)
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		println("error status code: " + fmt.Sprint(resp.StatusCode))
-		return emailResponse, err
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return fmt.Errorf(
+			"error status code %d: %w",
+			resp.StatusCode,
+			schemas.ErrUnauthorized,
+		)
+	case http.StatusNotFound:
+		return fmt.Errorf(
+			"error status code %d: %w",
+			resp.StatusCode,
+			schemas.ErrGmailNotFound,
+		)
+	default:
+		return statusError(resp.StatusCode, fmt.Errorf("error status code %d", resp.StatusCode))
 	}
 
-	err = json.NewDecoder(resp.Body).Decode(&emailResponse)
+	err = json.NewDecoder(resp.Body).Decode(result)
 	if err != nil {
-		println("error decoding response: " + err.Error())
-		return emailResponse, err
+		return fmt.Errorf("error decoding response: %w", err)
 	}
-	return emailResponse, nil
+	return nil
 }
 
-// getLastEmailDetails retrieves the details of the last email for a given message ID.
-// It makes a request to the Gmail API to fetch the email headers and extracts the Date, From, and Subject fields.
+// gmailChanges is the outcome of a listing of the emails added to the inbox of a user.
+//
+// Fields:
+//   - messageIds: The IDs of the emails added, oldest first.
+//   - historyId: The history ID of the mailbox once the emails added.
+type gmailChanges struct {
+	messageIds []string
+	historyId  string
+}
+
+// getAddedEmails lists the emails added to the inbox of the user since a history ID, through
+// all the pages of users.history.list.
 //
 // Parameters:
-//   - id: The ID of the email message to retrieve.
-//   - token: The authentication token required to access the Gmail API.
+//   - ctx: The context of the requests, cancelling them when done.
+//   - token: schemas.Token containing the authorization token.
+//   - startHistoryId: The history ID of the mailbox after the last listing.
 //
 // Returns:
-//   - schemas.EmailDetails: A struct containing the Date, From, and Subject of the email.
-//   - error: An error if the request fails or if the email details cannot be found.
-func getLastEmailDetails(id string, token schemas.Token) (schemas.EmailDetails, error) {
-	var emailDetails schemas.EmailDetails
-	ctx := context.Background()
-	client := &http.Client{}
+//   - gmailChanges: The emails added since the history ID and the new history ID.
+//   - error: schemas.ErrGmailHistoryExpired if Gmail no longer keeps the history since the
+//     history ID, or an error if a request fails.
+func (service *googleService) getAddedEmails(
+	ctx context.Context,
+	token schemas.Token,
+	startHistoryId string,
+) (gmailChanges, error) {
+	changes := gmailChanges{historyId: startHistoryId}
+	seen := make(map[string]bool)
+	query := url.Values{}
+	query.Set("startHistoryId", startHistoryId)
+	query.Set("historyTypes", "messageAdded")
+	query.Set("labelId", "INBOX")
+	for {
+		history := schemas.GmailHistoryResponse{}
+		err := service.getGmail(ctx, token,
+			"https://gmail.googleapis.com/gmail/v1/users/me/history?"+query.Encode(),
+			&history,
+		)
+		if errors.Is(err, schemas.ErrGmailNotFound) {
+			return changes, schemas.ErrGmailHistoryExpired
+		}
+		if err != nil {
+			return changes, err
+		}
 
-	apiURL := fmt.Sprintf(
-		"https://gmail.googleapis.com/gmail/v1/users/me/messages/%s?fields=payload(headers),id",
-		id,
-	)
+		for _, record := range history.History {
+			for _, added := range record.MessagesAdded {
+				if !seen[added.Message.Id] {
+					seen[added.Message.Id] = true
+					changes.messageIds = append(changes.messageIds, added.Message.Id)
+				}
+			}
+		}
+		if history.HistoryId != "" {
+			changes.historyId = history.HistoryId
+		}
+		if history.NextPageToken == "" {
+			return changes, nil
+		}
+		query.Set("pageToken", history.NextPageToken)
+	}
+}
 
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+// resyncEmails lists the emails of the inbox of the user received after a time, for a full
+// resync of the mailbox when its history can not be listed. The history ID is read before the
+// emails, so that no email added during the resync is missed by the next listing.
+//
+// Parameters:
+//   - ctx: The context of the requests, cancelling them when done.
+//   - token: schemas.Token containing the authorization token.
+//   - after: The reception time of the last email reported.
+//
+// Returns:
+//   - gmailChanges: The newest GmailResyncMaxMessages emails received after the time, and the
+//     history ID of the mailbox.
+//   - error: An error if a request fails.
+func (service *googleService) resyncEmails(
+	ctx context.Context,
+	token schemas.Token,
+	after time.Time,
+) (gmailChanges, error) {
+	changes := gmailChanges{}
+	profile := schemas.GmailProfile{}
+	err := service.getGmail(ctx, token,
+		"https://gmail.googleapis.com/gmail/v1/users/me/profile",
+		&profile,
+	)
 	if err != nil {
-		println("error creating request: " + err.Error())
-		return emailDetails, err
+		return changes, err
 	}
+	changes.historyId = profile.HistoryId
 
-	req.Header.Set("Authorization", "Bearer "+token.Token)
-
-	resp, err := client.Do(req)
+	query := url.Values{}
+	query.Set("labelIds", "INBOX")
+	query.Set("maxResults", strconv.Itoa(schemas.GmailResyncMaxMessages))
+	query.Set("q", "after:"+strconv.FormatInt(after.Unix(), 10))
+	emailResponse := schemas.GmailEmailResponse{}
+	err = service.getGmail(ctx, token,
+		"https://gmail.googleapis.com/gmail/v1/users/me/messages?"+query.Encode(),
+		&emailResponse,
+	)
 	if err != nil {
-		println("error making request: " + err.Error())
-		return emailDetails, err
+		return changes, err
 	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		println("error status code: " + fmt.Sprint(resp.StatusCode))
-		return emailDetails, err
+	// the emails are listed newest first
+	for i := len(emailResponse.Messages) - 1; i >= 0; i-- {
+		changes.messageIds = append(changes.messageIds, emailResponse.Messages[i].Id)
 	}
+	return changes, nil
+}
+
+// getEmailDetails retrieves the details of an email.
+// It makes a request to the Gmail API to fetch the email headers and reception time and
+// extracts the Date, From, and Subject fields.
+//
+// Parameters:
+//   - ctx: The context of the request, cancelling it when done.
+//   - id: The ID of the email message to retrieve.
+//   - token: The authentication token required to access the Gmail API.
+//
+// Returns:
+//   - schemas.EmailDetails: A struct containing the Date, From, Subject and reception time of the email.
+//   - error: An error if the request fails, wrapping schemas.ErrGmailNotFound if the email was deleted.
+func (service *googleService) getEmailDetails(
+	ctx context.Context,
+	id string,
+	token schemas.Token,
+) (schemas.EmailDetails, error) {
+	var emailDetails schemas.EmailDetails
 	var emailAllDetails schemas.GmailMessageResponse
-	err = json.NewDecoder(resp.Body).Decode(&emailAllDetails)
+	err := service.getGmail(ctx, token, fmt.Sprintf(
+		"https://gmail.googleapis.com/gmail/v1/users/me/messages/%s?fields=id,internalDate,payload(headers)",
+		url.PathEscape(id),
+	), &emailAllDetails)
 	if err != nil {
-		println("error decoding response: " + err.Error())
 		return emailDetails, err
 	}
 
@@ -560,115 +755,130 @@ func getLastEmailDetails(id string, token schemas.Token) (schemas.EmailDetails,
 		}
 	}
 
-	if emailDetails.Date == "" || emailDetails.From == "" || emailDetails.Subject == "" {
-		println("error: email details not found")
-		return emailDetails, err
+	// the reception time of Gmail does not depend on the clock of the sender
+	internalDate, err := strconv.ParseInt(emailAllDetails.InternalDate, 10, 64)
+	if err != nil {
+		return emailDetails, fmt.Errorf("error parsing internal date: %w", err)
 	}
-
+	emailDetails.ReceivedAt = time.UnixMilli(internalDate).UTC()
 	return emailDetails, nil
 }
 
 // Actions functions
 
 // GoogleActionReceiveMail handles the process of receiving emails from a Google account.
-// It initializes the Google storage variable, retrieves the token, gets the last email ID,
-// fetches the email details, and checks if there are any new emails. If a new email is found,
-// it updates the storage variable and sends a response through the provided channel.
+// It lists the emails added to the inbox since the history ID stored in the storage variable
+// of the area, and returns one trigger per email, oldest first. When Gmail no longer keeps the
+// history since the stored history ID, or on the first check of an area storing a time only,
+// a full resync reports the emails received after the stored time instead.
 //
 // Parameters:
-//   - channel: A channel to send the response string when a new email is received.
+//   - ctx: The context of the check, cancelling the Gmail requests when done.
 //   - option: A JSON raw message containing additional options (currently unused).
 //   - area: A schemas.Area object containing user and action details.
 //
+// Returns:
+//   - []schemas.ActionResult: The triggers, or nil if no new email was received.
+//   - error: An error if any step of the process fails.
+//
 // The function performs the following steps:
 //  1. Initializes the Google storage variable.
 //  2. Retrieves the token for the user and service.
-//  3. Gets the last email ID.
-//  4. Fetches the email details.
-//  5. Parses the email time and checks if it is a new email.
-//  6. Updates the storage variable and sends a response if a new email is found.
-//  7. Sleeps for the specified refresh rate before returning.
+//  3. Lists the emails added since the history ID through the poll cache, or resyncs the mailbox.
+//  4. Fetches the details of every email added.
+//  5. Updates the storage variable with the new history ID and the reception time of the
+//     last email, and returns a trigger per email.
 func (service *googleService) GoogleActionReceiveMail(
-	channel chan string,
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) {
+) ([]schemas.ActionResult, error) {
 	variable, err := initializedGoogleStorageVariable(area, *service)
 	if err != nil {
-		println("error initializing storage variable: " + err.Error())
-		return
+		return nil, fmt.Errorf("error initializing storage variable: %w", err)
 	}
 
 	token, err := service.tokenRepository.FindByUserIdAndServiceId(
 		area.UserId,
 		area.Action.ServiceId,
 	)
-	if err != nil || token.Token == "" {
-		println("error retrieving token or token not found")
-		return
+	if err != nil {
+		return nil, fmt.Errorf("error finding token: %w", err)
+	}
+	if token.Token == "" {
+		return nil, schemas.ErrTokenNotFound
+	}
+
+	err = schemas.ErrGmailHistoryExpired
+	changes := gmailChanges{}
+	resync := false
+	if variable.HistoryId != "" {
+		changes, err = cachedPoll(ctx, service.pollCache, area, schemas.PollKey{
+			Provider:   schemas.Google,
+			Resource:   "users/me/history?startHistoryId=" + variable.HistoryId,
+			Credential: token.Token,
+		}, func(ctx context.Context) (gmailChanges, error) {
+			return service.getAddedEmails(ctx, token, variable.HistoryId)
+		})
+	}
+	if errors.Is(err, schemas.ErrGmailHistoryExpired) {
+		println("resyncing gmail mailbox of area " + strconv.FormatUint(area.Id, 10))
+		resync = true
+		changes, err = service.resyncEmails(ctx, token, variable.Time)
 	}
-
-	emailResponse, err := getLastEmailId(token, variable)
 	if err != nil {
-		println("error getting last email id: " + err.Error())
-		return
+		return nil, fmt.Errorf("error listing new emails: %w", err)
 	}
 
-	if len(emailResponse.Messages) > 0 {
-		id := emailResponse.Messages[0].Id
-		emailDetails, err := getLastEmailDetails(id, token)
-		if err != nil {
-			println("error getting last email details: " + err.Error())
-			return
+	var triggers []schemas.ActionResult
+	lastTime := variable.Time
+	for _, id := range changes.messageIds {
+		emailDetails, err := service.getEmailDetails(ctx, id, token)
+		if errors.Is(err, schemas.ErrGmailNotFound) {
+			// the email was deleted since it was added
+			continue
 		}
-
-		emailTime, err := time.Parse(time.RFC1123Z, emailDetails.Date)
 		if err != nil {
-			emailTime, err = time.Parse(time.RFC1123, emailDetails.Date)
-			if err != nil {
-				println("error parsing time: " + err.Error())
-				return
-			}
+			return nil, fmt.Errorf("error getting email details: %w", err)
 		}
-		if variable.Time.After(emailTime) {
-			println("no new emails")
-			if (area.Action.MinimumRefreshRate) > area.ActionRefreshRate {
-				time.Sleep(time.Second * time.Duration(area.Action.MinimumRefreshRate))
-			} else {
-				time.Sleep(time.Second * time.Duration(area.ActionRefreshRate))
-			}
-			return
+		// the emails of the resync were already reported up to the stored time
+		if resync && !emailDetails.ReceivedAt.After(variable.Time) {
+			continue
 		}
-		response := fmt.Sprintf("New email received from %s: object: %s",
-			emailDetails.From,
-			emailDetails.Subject,
-		)
-		variable.Time, err = time.Parse(time.RFC1123Z, emailDetails.Date)
-		if err != nil {
-			println("error parsing time: " + err.Error())
-			return
+		if emailDetails.ReceivedAt.After(lastTime) {
+			lastTime = emailDetails.ReceivedAt
 		}
-		variable.Time = variable.Time.Add(time.Second)
-		area.StorageVariable, err = json.Marshal(variable)
+		trigger, err := newTrigger(
+			fmt.Sprintf("New email received from %s: object: %s",
+				emailDetails.From,
+				emailDetails.Subject,
+			),
+			schemas.GmailMailPayload{
+				Sender:  emailDetails.From,
+				Subject: emailDetails.Subject,
+				Date:    emailDetails.ReceivedAt,
+			},
+		)
 		if err != nil {
-			println("error marshalling storage variable: " + err.Error())
-			return
+			return nil, err
 		}
-		err = service.areaRepository.Update(area)
-		if err != nil {
-			println("error updating area: " + err.Error())
-			return
-		}
-		channel <- response
-	} else {
-		println("No new emails")
+		triggers = append(triggers, trigger...)
 	}
 
-	if (area.Action.MinimumRefreshRate) > area.ActionRefreshRate {
-		time.Sleep(time.Second * time.Duration(area.Action.MinimumRefreshRate))
-	} else {
-		time.Sleep(time.Second * time.Duration(area.ActionRefreshRate))
+	if changes.historyId == variable.HistoryId && lastTime.Equal(variable.Time) {
+		return triggers, nil
+	}
+	variable.HistoryId = changes.historyId
+	variable.Time = lastTime
+	area.StorageVariable, err = json.Marshal(variable)
+	if err != nil {
+		return nil, fmt.Errorf("error marshalling storage variable: %w", err)
+	}
+	err = service.areaRepository.UpdateStorageVariable(area)
+	if err != nil {
+		return nil, fmt.Errorf("error updating area: %w", err)
 	}
+	return triggers, nil
 }
 
 // Reactions functions
@@ -678,23 +888,24 @@ func (service *googleService) GoogleActionReceiveMail(
 // constructs the email message, and sends it via the Gmail API.
 //
 // Parameters:
+//   - ctx: The context of the reaction, cancelling the Gmail request when done.
 //   - option: A JSON raw message containing the email options (To, Subject, Body).
 //   - area: An Area struct containing user and reaction information.
 //
 // Returns:
 //
-//	A string indicating the result of the operation, either an error message or a success message.
+//	The result of the operation, with the sent email as its payload, or an error if the email
+//	could not be sent.
 func (service *googleService) GoogleReactionSendMail(
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) string {
+) (schemas.ReactionResult, error) {
 	optionJSON := schemas.GmailReactionSendMailOption{}
 
 	err := json.Unmarshal(option, &optionJSON)
 	if err != nil {
-		println("error unmarshal gmail option: " + err.Error())
-		time.Sleep(time.Second)
-		return "Error unmarshal gmail option" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error unmarshal gmail option: %w", err)
 	}
 
 	token, err := service.tokenRepository.FindByUserIdAndServiceId(
@@ -702,12 +913,10 @@ func (service *googleService) GoogleReactionSendMail(
 		area.Reaction.ServiceId,
 	)
 	if err != nil {
-		fmt.Println("Error finding token:", err)
-		return "Error finding token" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error finding token: %w", err)
 	}
 	if token.Token == "" {
-		fmt.Println("Error: Token not found")
-		return "Error: Token not found"
+		return schemas.ReactionResult{}, schemas.ErrTokenNotFound
 	}
 
 	apiURL := "https://gmail.googleapis.com/gmail/v1/users/me/messages/send"
@@ -723,8 +932,6 @@ func (service *googleService) GoogleReactionSendMail(
 
 	body := fmt.Sprintf(`{"raw": "%s"}`, raw)
 
-	ctx := context.Background()
-
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
@@ -732,32 +939,49 @@ func (service *googleService) GoogleReactionSendMail(
 		bytes.NewBuffer([]byte(body)),
 	)
 	if err != nil {
-		fmt.Println("Error creating request:", err)
-		return "Error creating request" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error creating request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+token.Token)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Google, req)
 	if err != nil {
-		fmt.Println("Error making request:", err)
-		return "Error making request:" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error making request: %w", err)
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return schemas.ReactionResult{}, fmt.Errorf(
+			"error status code %d: %w",
+			resp.StatusCode,
+			schemas.ErrUnauthorized,
+		)
+	}
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
 		respBody, _ := io.ReadAll(resp.Body)
-		fmt.Printf(
-			"Failed to send email. Status: %s, Response: %s\n",
+		return schemas.ReactionResult{}, statusError(resp.StatusCode, fmt.Errorf(
+			"failed to send email, status: %s, response: %s",
 			resp.Status,
 			string(respBody),
-		)
-		return "Failed to send email"
+		))
+	}
+
+	sentMail := struct {
+		Id       string `json:"id"`
+		ThreadId string `json:"threadId"`
+	}{}
+	// the email is sent, a response that can not be decoded only leaves the payload incomplete
+	err = json.NewDecoder(resp.Body).Decode(&sentMail)
+	if err != nil {
+		println("error decoding sent email: " + err.Error())
 	}
 
-	fmt.Println("Email sent successfully!")
-	return "Email sent successfully!"
+	return newReactionResult("Email sent successfully!", schemas.GmailSentMailPayload{
+		Id:       sentMail.Id,
+		ThreadId: sentMail.ThreadId,
+		To:       optionJSON.To,
+		Subject:  optionJSON.Subject,
+	})
 }
@@ -0,0 +1,164 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"area/schemas"
+	"area/service"
+	"area/test"
+)
+
+var gmailMessages = map[string]time.Time{
+	"m1": time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+	"m3": time.Date(2024, 1, 2, 3, 5, 0, 0, time.UTC),
+}
+
+// newFakeGmail starts a fake Gmail API whose mailbox history is 100, then m1 and m2 added in
+// 101 and 102, then m3 added in 110, m2 being deleted since. The history before 100 expired.
+func newFakeGmail(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/gmail/v1/users/me/history":
+			assert.Equal(t, "messageAdded", query.Get("historyTypes"))
+			switch {
+			case query.Get("startHistoryId") == "110":
+				_, _ = w.Write([]byte(`{"historyId": "110"}`))
+			case query.Get("startHistoryId") != "100":
+				w.WriteHeader(http.StatusNotFound)
+			case query.Get("pageToken") == "":
+				_, _ = w.Write([]byte(`{
+					"history": [
+						{"id": "101", "messagesAdded": [{"message": {"id": "m1"}}]},
+						{"id": "102", "messagesAdded": [
+							{"message": {"id": "m1"}},
+							{"message": {"id": "m2"}}
+						]}
+					],
+					"nextPageToken": "page-2",
+					"historyId": "110"
+				}`))
+			default:
+				_, _ = w.Write([]byte(`{
+					"history": [{"id": "110", "messagesAdded": [{"message": {"id": "m3"}}]}],
+					"historyId": "110"
+				}`))
+			}
+		case "/gmail/v1/users/me/profile":
+			_, _ = w.Write([]byte(`{"historyId": "500"}`))
+		case "/gmail/v1/users/me/messages":
+			assert.Equal(t, "INBOX", query.Get("labelIds"))
+			_, _ = w.Write([]byte(`{"messages": [{"id": "m3"}, {"id": "m1"}]}`))
+		default:
+			received, ok := gmailMessages[r.URL.Path[len("/gmail/v1/users/me/messages/"):]]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{
+				"internalDate": "%d",
+				"payload": {"headers": [
+					{"name": "From", "value": "friend@example.com"},
+					{"name": "Subject", "value": "Hello"},
+					{"name": "Date", "value": "Thu, 1 Jan 1970 00:00:00 +0000"}
+				]}
+			}`, received.UnixMilli())
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func receiveGmail(
+	t *testing.T,
+	storage string,
+) ([]schemas.ActionResult, *test.MockAreaRepository) {
+	mockAreaRepository := new(test.MockAreaRepository)
+	mockAreaRepository.On("UpdateStorageVariable", mock.Anything).Return(nil)
+	mockTokenRepository := new(test.MockTokenRepository)
+	mockTokenRepository.On("FindByUserIdAndServiceId", uint64(7), uint64(2)).
+		Return(schemas.Token{Token: "token"}, nil)
+	googleService := service.NewGoogleService(
+		nil,
+		nil,
+		mockAreaRepository,
+		mockTokenRepository,
+		&test.FakeProviderClient{URL: newFakeGmail(t).URL},
+		service.NewPollCache(),
+	)
+	area := schemas.Area{
+		Id:              1,
+		UserId:          7,
+		Action:          schemas.Action{Name: string(schemas.ReceiveGoogleMail), ServiceId: 2},
+		StorageVariable: json.RawMessage(storage),
+	}
+
+	triggers, err := googleService.GoogleActionReceiveMail(context.Background(), nil, area)
+	assert.NoError(t, err)
+	return triggers, mockAreaRepository
+}
+
+func storedGmailVariable(
+	t *testing.T,
+	mockAreaRepository *test.MockAreaRepository,
+) schemas.GoogleVariableReceiveMail {
+	variable := schemas.GoogleVariableReceiveMail{}
+	assert.NoError(t, json.Unmarshal(storedVariable(mockAreaRepository), &variable))
+	return variable
+}
+
+func TestGmailReportsEveryAddedEmail(t *testing.T) {
+	triggers, mockAreaRepository := receiveGmail(
+		t,
+		`{"time":"2024-01-01T00:00:00Z","history_id":"100"}`,
+	)
+
+	// the emails are reported once each, oldest first, by their reception time in Gmail
+	assert.Len(t, triggers, 2)
+	for i, id := range []string{"m1", "m3"} {
+		payload := schemas.GmailMailPayload{}
+		assert.NoError(t, json.Unmarshal(triggers[i].Payload, &payload))
+		assert.Equal(t, gmailMessages[id], payload.Date)
+		assert.Equal(t, "friend@example.com", payload.Sender)
+	}
+
+	variable := storedGmailVariable(t, mockAreaRepository)
+	assert.Equal(t, "110", variable.HistoryId)
+	assert.Equal(t, gmailMessages["m3"], variable.Time)
+}
+
+func TestGmailWithoutChangeKeepsStorage(t *testing.T) {
+	triggers, mockAreaRepository := receiveGmail(
+		t,
+		`{"time":"2024-01-02T03:05:00Z","history_id":"110"}`,
+	)
+
+	assert.Empty(t, triggers)
+	mockAreaRepository.AssertNotCalled(t, "UpdateStorageVariable", mock.Anything)
+}
+
+func TestGmailResyncsExpiredHistory(t *testing.T) {
+	triggers, mockAreaRepository := receiveGmail(
+		t,
+		`{"time":"2024-01-02T03:04:05Z","history_id":"1"}`,
+	)
+
+	// m1 was reported before the history expired
+	assert.Len(t, triggers, 1)
+	payload := schemas.GmailMailPayload{}
+	assert.NoError(t, json.Unmarshal(triggers[0].Payload, &payload))
+	assert.Equal(t, gmailMessages["m3"], payload.Date)
+
+	variable := storedGmailVariable(t, mockAreaRepository)
+	assert.Equal(t, "500", variable.HistoryId)
+	assert.Equal(t, gmailMessages["m3"], variable.Time)
+}
@@ -4,11 +4,14 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 
@@ -22,31 +25,39 @@ type MicrosoftService interface {
 	// Service interface functions
 	GetServiceActionInfo() []schemas.Action
 	GetServiceReactionInfo() []schemas.Reaction
-	FindActionByName(name string) func(c chan string, option json.RawMessage, area schemas.Area)
-	FindReactionByName(name string) func(option json.RawMessage, area schemas.Area) string
+	FindActionByName(name string) ActionFunc
+	FindReactionByName(name string) ReactionFunc
 	// Service specific functions
 	AuthGetServiceAccessToken(code string) (token schemas.Token, err error)
+	RefreshServiceAccessToken(
+		ctx context.Context,
+		refreshToken string,
+	) (token schemas.Token, err error)
 	GetUserInfo(accessToken string) (user schemas.User, err error)
+	// HandleNotifications turns a delivery of Graph notifications into triggers of the areas.
+	HandleNotifications(ctx context.Context, body []byte) ([]schemas.AreaTrigger, error)
 	// Actions functions
 	MicrosoftActionReceiveMail(
-		channel chan string,
+		ctx context.Context,
 		option json.RawMessage,
 		area schemas.Area,
-	)
+	) ([]schemas.ActionResult, error)
 	MicrosoftActionEventStarting(
-		channel chan string,
+		ctx context.Context,
 		option json.RawMessage,
 		area schemas.Area,
-	)
+	) ([]schemas.ActionResult, error)
 	// Reactions functions
 	MicrosoftReactionSendMail(
+		ctx context.Context,
 		option json.RawMessage,
 		area schemas.Area,
-	) string
+	) (schemas.ReactionResult, error)
 	MicrosoftReactionCreateEvent(
+		ctx context.Context,
 		option json.RawMessage,
 		area schemas.Area,
-	) string
+	) (schemas.ReactionResult, error)
 }
 
 // microsoftService is a struct that encapsulates various repositories and service information
@@ -57,13 +68,17 @@ type MicrosoftService interface {
 // - serviceRepository: ServiceRepository interface for interacting with service data.
 // - areaRepository: AreaRepository interface for interacting with area data.
 // - tokenRepository: TokenRepository interface for managing tokens.
+// - subscriptionManager: MicrosoftSubscriptionManager managing the Graph subscriptions of the areas.
 // - serviceInfo: Service schema containing information about the service.
 type microsoftService struct {
-	repository        repository.MicrosoftRepository // Microsoft repository
-	serviceRepository repository.ServiceRepository   // Service repository
-	areaRepository    repository.AreaRepository      // Area repository
-	tokenRepository   repository.TokenRepository     // Token repository
-	serviceInfo       schemas.Service                // Service information
+	repository          repository.MicrosoftRepository // Microsoft repository
+	serviceRepository   repository.ServiceRepository   // Service repository
+	areaRepository      repository.AreaRepository      // Area repository
+	tokenRepository     repository.TokenRepository     // Token repository
+	providerClient      ProviderClient                 // Shared HTTP client of the providers
+	pollCache           PollCache                      // Cache of the polls shared by the areas
+	subscriptionManager MicrosoftSubscriptionManager   // Graph subscriptions of the areas
+	serviceInfo         schemas.Service                // Service information
 }
 
 // NewMicrosoftService creates a new instance of MicrosoftService with the provided repositories.
@@ -74,6 +89,9 @@ type microsoftService struct {
 //   - serviceRepository: repository.ServiceRepository - Repository for handling service-related operations.
 //   - areaRepository: repository.AreaRepository - Repository for handling area-related operations.
 //   - tokenRepository: repository.TokenRepository - Repository for handling token-related operations.
+//   - providerClient: ProviderClient - Shared HTTP client sending the requests to Microsoft Graph.
+//   - pollCache: PollCache - Cache of the polls of Microsoft Graph shared by the areas.
+//   - subscriptionManager: MicrosoftSubscriptionManager - Manager of the Graph subscriptions of the areas.
 //
 // Returns:
 //   - MicrosoftService: A new instance of MicrosoftService.
@@ -82,12 +100,18 @@ func NewMicrosoftService(
 	serviceRepository repository.ServiceRepository,
 	areaRepository repository.AreaRepository,
 	tokenRepository repository.TokenRepository,
+	providerClient ProviderClient,
+	pollCache PollCache,
+	subscriptionManager MicrosoftSubscriptionManager,
 ) MicrosoftService {
 	return &microsoftService{
-		repository:        githubTokenRepository,
-		serviceRepository: serviceRepository,
-		areaRepository:    areaRepository,
-		tokenRepository:   tokenRepository,
+		repository:          githubTokenRepository,
+		serviceRepository:   serviceRepository,
+		areaRepository:      areaRepository,
+		tokenRepository:     tokenRepository,
+		providerClient:      providerClient,
+		pollCache:           pollCache,
+		subscriptionManager: subscriptionManager,
 		serviceInfo: schemas.Service{
 			Name:        schemas.Microsoft,
 			Description: "This service is used to interact with Microsoft services",
@@ -140,13 +164,15 @@ func (service *microsoftService) GetServiceActionInfo() []schemas.Action {
 			Description:        "Receive a mail using Microsoft services",
 			Service:            service.serviceInfo,
 			Option:             option,
+			OptionSchema:       newOptionSchema(schemas.MicrosoftReceiveMailSchema),
 			MinimumRefreshRate: 10,
 		},
 		{
-			Name:        string(schemas.EventStarting),
-			Description: "Event starting using Microsoft services",
-			Service:     service.serviceInfo,
-			Option:      optionEventIncoming,
+			Name:         string(schemas.EventStarting),
+			Description:  "Event starting using Microsoft services",
+			Service:      service.serviceInfo,
+			Option:       optionEventIncoming,
+			OptionSchema: newOptionSchema(schemas.MicrosoftEventIncomingOptionsSchema),
 		},
 	}
 }
@@ -186,7 +212,7 @@ func (service *microsoftService) GetServiceReactionInfo() []schemas.Reaction {
 		Body:     "Weekly meeting",
 		Location: "Bordeaux",
 		Start:    "2025-01-12T16:06:00",
-		End:      "YYYY-MM-DDTHH:MM:SS",
+		End:      "2025-01-12T17:06:00",
 	}
 	optionCreateEvent, err := json.Marshal(defaultValueCreateEvent)
 	if err != nil {
@@ -194,22 +220,24 @@ func (service *microsoftService) GetServiceReactionInfo() []schemas.Reaction {
 	}
 	return []schemas.Reaction{
 		{
-			Name:        string(schemas.SendMicrosoftMail),
-			Description: "Send a mail using Microsoft services",
-			Service:     service.serviceInfo,
-			Option:      option,
+			Name:         string(schemas.SendMicrosoftMail),
+			Description:  "Send a mail using Microsoft services",
+			Service:      service.serviceInfo,
+			Option:       option,
+			OptionSchema: newOptionSchema(schemas.MicrosoftReactionSendMailOptionsSchema),
 		},
 		{
-			Name:        string(schemas.CreateEvent),
-			Description: "Create an event using Microsoft services",
-			Service:     service.serviceInfo,
-			Option:      optionCreateEvent,
+			Name:         string(schemas.CreateEvent),
+			Description:  "Create an event using Microsoft services",
+			Service:      service.serviceInfo,
+			Option:       optionCreateEvent,
+			OptionSchema: newOptionSchema(schemas.MicrosoftCreateEventOptionsSchema),
 		},
 	}
 }
 
 // FindActionByName returns a function that matches the provided action name.
-// The returned function takes a channel, a JSON raw message, and an area schema as parameters.
+// The returned ActionFunc takes a context, a JSON raw message, and an area schema as parameters.
 //
 // Parameters:
 //   - name: The name of the action to find.
@@ -218,7 +246,7 @@ func (service *microsoftService) GetServiceReactionInfo() []schemas.Reaction {
 //   - A function that matches the provided action name, or nil if no match is found.
 func (service *microsoftService) FindActionByName(
 	name string,
-) func(c chan string, option json.RawMessage, area schemas.Area) {
+) ActionFunc {
 	switch name {
 	case string(schemas.ReceiveMicrosoftMail):
 		return service.MicrosoftActionReceiveMail
@@ -230,18 +258,18 @@ func (service *microsoftService) FindActionByName(
 }
 
 // FindReactionByName returns a function that performs a specific Microsoft service reaction
-// based on the provided name. The returned function takes a JSON raw message and an area schema
-// as parameters and returns a string.
+// based on the provided name. The returned ReactionFunc takes a context, a JSON raw message
+// and an area schema as parameters.
 //
 // Parameters:
 //   - name: The name of the reaction to find.
 //
 // Returns:
-//   - A function that takes a JSON raw message and an area schema, and returns a string.
+//   - The ReactionFunc of the reaction.
 //     If the name does not match any known reactions, it returns nil.
 func (service *microsoftService) FindReactionByName(
 	name string,
-) func(option json.RawMessage, area schemas.Area) string {
+) ReactionFunc {
 	switch name {
 	case string(schemas.SendMicrosoftMail):
 		return service.MicrosoftReactionSendMail
@@ -297,8 +325,7 @@ func (service *microsoftService) AuthGetServiceAccessToken(
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Microsoft, req)
 	if err != nil {
 		return schemas.Token{}, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -325,6 +352,80 @@ func (service *microsoftService) AuthGetServiceAccessToken(
 	return token, nil
 }
 
+// RefreshServiceAccessToken exchanges a Microsoft refresh token for a new access token.
+// Microsoft rotates the refresh token, so the returned token holds the refresh token to use next time.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - refreshToken: The last refresh token received from Microsoft.
+//
+// Returns:
+//   - token: A Token schema containing the new access token, refresh token and expiration time.
+//   - err: An error if the environment variables are missing or the request fails.
+//     It wraps schemas.ErrRefreshTokenRejected when Microsoft rejects the refresh token.
+func (service *microsoftService) RefreshServiceAccessToken(
+	ctx context.Context,
+	refreshToken string,
+) (token schemas.Token, err error) {
+	clientID := os.Getenv("MICROSOFT_CLIENT_ID")
+	if clientID == "" {
+		return schemas.Token{}, schemas.ErrMicrosoftClientIdNotSet
+	}
+
+	apiURL := "https://login.microsoftonline.com/common/oauth2/v2.0/token"
+
+	data := url.Values{}
+	data.Set("client_id", clientID)
+	data.Set("refresh_token", refreshToken)
+	data.Set("grant_type", "refresh_token")
+
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		apiURL,
+		strings.NewReader(data.Encode()),
+	)
+	if err != nil {
+		return schemas.Token{}, fmt.Errorf("unable to create request because %w", err)
+	}
+
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := service.providerClient.Do(schemas.Microsoft, req)
+	if err != nil {
+		return schemas.Token{}, fmt.Errorf("unable to make request because %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+		return schemas.Token{}, fmt.Errorf(
+			"unable to refresh token because %w",
+			schemas.ErrRefreshTokenRejected,
+		)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return schemas.Token{}, fmt.Errorf("unable to refresh token because %v", resp.Status)
+	}
+
+	var result schemas.MicrosoftTokenResponse
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return schemas.Token{}, fmt.Errorf("unable to decode response because %w", err)
+	}
+
+	if result.AccessToken == "" {
+		return schemas.Token{}, schemas.ErrAccessTokenNotFoundInResponse
+	}
+
+	token = schemas.Token{
+		Token:        result.AccessToken,
+		RefreshToken: result.RefreshToken,
+		ExpireAt:     time.Now().Add(time.Duration(result.ExpiresIn) * time.Second),
+	}
+	return token, nil
+}
+
 // GetUserInfo retrieves the user information from Microsoft Graph API using the provided access token.
 // It sends a GET request to the "https://graph.microsoft.com/v1.0/me" endpoint and decodes the response
 // into a schemas.User object.
@@ -354,8 +455,7 @@ func (service *microsoftService) GetUserInfo(
 
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Microsoft, req)
 	if err != nil {
 		return schemas.User{}, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -382,119 +482,373 @@ func (service *microsoftService) GetUserInfo(
 	return user, nil
 }
 
-// Actions functions
-
-// MicrosoftActionEventStarting handles the event of a Microsoft action starting.
-// It retrieves the user's Microsoft events, checks if any event matches the specified options,
-// and updates the area storage variable if a matching event is found.
+// HandleNotifications turns a delivery of Graph notifications into triggers of the areas of
+// their subscriptions, through the subscription manager.
 //
 // Parameters:
-//   - channel: A channel to send messages about the event status.
-//   - option: A JSON raw message containing the options for the Microsoft event.
-//   - area: The area schema containing user and action information.
+//   - ctx: The context of the delivery, cancelling the Graph requests when done.
+//   - body: The raw body of the delivery.
 //
-// The function performs the following steps:
-//  1. Unmarshals the options from the JSON raw message.
-//  2. Initializes the Microsoft storage variable.
-//  3. Retrieves the user's token for the Microsoft service.
-//  4. Makes an HTTP GET request to the Microsoft Graph API to fetch the user's events.
-//  5. Checks if any event matches the specified options.
-//  6. Updates the area storage variable and sends a message to the channel if a matching event is found.
-//  7. Sleeps for 10 seconds before returning.
-//
-// If any error occurs during these steps, it prints an error message and returns.
-func (service *microsoftService) MicrosoftActionEventStarting(
-	channel chan string,
-	option json.RawMessage,
+// Returns:
+//   - []schemas.AreaTrigger: The triggers of the areas, to queue for their reaction workers.
+//   - error: schemas.ErrInvalidWebhookSignature if a notification is forged, or an error if
+//     the notifications can not be handled.
+func (service *microsoftService) HandleNotifications(
+	ctx context.Context,
+	body []byte,
+) ([]schemas.AreaTrigger, error) {
+	return service.subscriptionManager.HandleNotifications(ctx, body)
+}
+
+// ensureSubscription makes sure that the area has a Graph subscription notifying its changes.
+// The area keeps polling Graph when it can not, so the error is only logged.
+//
+// Parameters:
+//   - ctx: The context of the check of the area.
+//   - area: The area whose action watches a resource of Graph.
+//   - token: The Microsoft token of the user of the area.
+func (service *microsoftService) ensureSubscription(
+	ctx context.Context,
 	area schemas.Area,
+	token schemas.Token,
 ) {
-	options := schemas.MicrosoftEventIncomingOptions{}
-	err := json.Unmarshal(option, &options)
+	err := service.subscriptionManager.Ensure(ctx, area, token)
 	if err != nil {
-		println("error unmarshalling options: " + err.Error())
-		return
+		println("error ensure microsoft subscription: " + err.Error())
 	}
+}
 
-	variable, err := initializedMicrosoftStorageVariable(area, *service)
-	if err != nil {
-		println("error initializing storage variable: " + err.Error())
+// microsoftDelta is the outcome of a delta query of Microsoft Graph.
+//
+// Fields:
+//   - items: The items changed since the previous query, through all the pages.
+//   - deltaLink: The link of the next delta query.
+type microsoftDelta[T any] struct {
+	items     []T
+	deltaLink string
+}
+
+// getDelta runs a delta query of Microsoft Graph through all its pages.
+//
+// Parameters:
+//   - ctx: The context of the requests, cancelling them when done.
+//   - providerClient: The shared HTTP client sending the requests.
+//   - token: The token of the user for the Microsoft service.
+//   - link: The delta link of the previous query, or the URL of a first query.
+//
+// Returns:
+//   - microsoftDelta[T]: The items changed and the link of the next query.
+//   - error: schemas.ErrMicrosoftDeltaExpired if Graph no longer keeps the changes since the
+//     delta link, or an error if a request fails.
+func getDelta[T any](
+	ctx context.Context,
+	providerClient ProviderClient,
+	token schemas.Token,
+	link string,
+) (delta microsoftDelta[T], err error) {
+	for link != "" {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, link, nil)
+		if err != nil {
+			return delta, fmt.Errorf("error creating request: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token.Token)
+		req.Header.Set("Prefer", `outlook.timezone="UTC"`)
+
+		resp, err := providerClient.Do(schemas.Microsoft, req)
+		if err != nil {
+			return delta, fmt.Errorf("error making request: %w", err)
+		}
+		page := schemas.MicrosoftDeltaResponse[T]{}
+		switch resp.StatusCode {
+		case http.StatusOK:
+			err = json.NewDecoder(resp.Body).Decode(&page)
+		case http.StatusUnauthorized:
+			err = fmt.Errorf("error status code %d: %w", resp.StatusCode, schemas.ErrUnauthorized)
+		case http.StatusGone:
+			err = schemas.ErrMicrosoftDeltaExpired
+		default:
+			err = statusError(resp.StatusCode, fmt.Errorf("error status code %d", resp.StatusCode))
+		}
+		resp.Body.Close()
+		if err != nil {
+			return delta, err
+		}
+
+		delta.items = append(delta.items, page.Value...)
+		link, delta.deltaLink = page.NextLink, page.DeltaLink
+	}
+	return delta, nil
+}
+
+// cachedDelta runs a delta query of Microsoft Graph through the poll cache for the area, the
+// areas holding the same delta link sharing the query. A first query is run again when Graph
+// no longer keeps the changes since the delta link.
+//
+// Parameters:
+//   - ctx: The context of the check of the area.
+//   - service: The Microsoft service of the area.
+//   - area: The area whose action runs the query.
+//   - token: The token of the user for the Microsoft service.
+//   - deltaLink: The delta link stored by the area, or "" before its first query.
+//   - firstLink: The URL of a first query, returning every item of the collection.
+//
+// Returns:
+//   - microsoftDelta[T]: The items changed, shared with the other areas, and the next link.
+//   - bool: Whether the first query was run, every item of the collection being returned.
+//   - error: An error if a request fails.
+func cachedDelta[T any](
+	ctx context.Context,
+	service *microsoftService,
+	area schemas.Area,
+	token schemas.Token,
+	deltaLink string,
+	firstLink string,
+) (microsoftDelta[T], bool, error) {
+	query := func(link string) (microsoftDelta[T], error) {
+		return cachedPoll(ctx, service.pollCache, area, schemas.PollKey{
+			Provider:   schemas.Microsoft,
+			Resource:   link,
+			Credential: token.Token,
+		}, func(ctx context.Context) (microsoftDelta[T], error) {
+			return getDelta[T](ctx, service.providerClient, token, link)
+		})
+	}
+
+	if deltaLink != "" {
+		delta, err := query(deltaLink)
+		if !errors.Is(err, schemas.ErrMicrosoftDeltaExpired) {
+			return delta, false, err
+		}
+		println("resyncing microsoft delta of area " + strconv.FormatUint(area.Id, 10))
+	}
+	delta, err := query(firstLink)
+	return delta, true, err
+}
+
+// applyEventChange applies a change of the calendar view returned by a delta query to the
+// events kept by an EventStarting action: the events removed or whose subject is no longer
+// the watched one are dropped, the others added or updated. An event keeps its reported state
+// unless its start moved.
+//
+// Parameters:
+//   - variable: The storage variable of the action.
+//   - event: The event changed, with its current state.
+//   - subject: The subject of the events watched by the action.
+func applyEventChange(
+	variable *schemas.MicrosoftVariableEvents,
+	event schemas.MicrosoftEvent,
+	subject string,
+) {
+	index := slices.IndexFunc(variable.Events, func(upcoming schemas.MicrosoftUpcomingEvent) bool {
+		return upcoming.Id == event.Id
+	})
+	if event.Removed != nil || event.Subject != subject {
+		if index >= 0 {
+			variable.Events = slices.Delete(variable.Events, index, index+1)
+		}
 		return
 	}
 
-	token, err := service.tokenRepository.FindByUserIdAndServiceId(
-		area.UserId,
-		area.Action.ServiceId,
-	)
-	if err != nil {
-		println("error retrieving token: " + err.Error())
+	upcoming := schemas.MicrosoftUpcomingEvent{
+		Id:      event.Id,
+		Subject: event.Subject,
+		Start:   event.Start.DateTime,
+		End:     event.End.DateTime,
+	}
+	if index < 0 {
+		variable.Events = append(variable.Events, upcoming)
 		return
 	}
+	upcoming.Reported = variable.Events[index].Reported &&
+		variable.Events[index].Start == upcoming.Start
+	variable.Events[index] = upcoming
+}
 
-	apiURL := "https://graph.microsoft.com/v1.0/me/events?$select=subject,start,end"
+// reportStartedEvents returns a trigger per event kept by an EventStarting action that started
+// and is not over, marking it as reported so that it triggers once. The events over are
+// dropped.
+//
+// Parameters:
+//   - variable: The storage variable of the action.
+//   - now: The time of the check.
+//
+// Returns:
+//   - []schemas.ActionResult: The triggers of the started events, by start.
+//   - error: An error if a trigger can not be built.
+func reportStartedEvents(
+	variable *schemas.MicrosoftVariableEvents,
+	now time.Time,
+) ([]schemas.ActionResult, error) {
+	slices.SortStableFunc(variable.Events, func(a, b schemas.MicrosoftUpcomingEvent) int {
+		return strings.Compare(a.Start, b.Start)
+	})
+	var triggers []schemas.ActionResult
+	kept := variable.Events[:0]
+	for _, event := range variable.Events {
+		start, err := time.Parse("2006-01-02T15:04:05.0000000", event.Start)
+		if err != nil {
+			println("error parsing event start time: " + err.Error())
+			continue
+		}
+		end, err := time.Parse("2006-01-02T15:04:05.0000000", event.End)
+		if err != nil {
+			println("error parsing event end time: " + err.Error())
+			continue
+		}
+		if !end.After(now) {
+			continue
+		}
+		if !event.Reported && !start.After(now) {
+			trigger, err := newTrigger(
+				fmt.Sprintf("Event '%s' is starting at %s", event.Subject, event.Start),
+				schemas.MicrosoftEventPayload{
+					Subject: event.Subject,
+					Start:   event.Start,
+					End:     event.End,
+				},
+			)
+			if err != nil {
+				return nil, err
+			}
+			triggers = append(triggers, trigger...)
+			event.Reported = true
+		}
+		kept = append(kept, event)
+	}
+	variable.Events = kept
+	return triggers, nil
+}
 
-	ctx := context.Background()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+// updateStorageVariable stores the storage variable of the area when it changed, unless it
+// was written since the area was loaded: the notifications of the area and its checks both
+// write it, and each must start from what the other stored.
+//
+// Parameters:
+//   - areaRepository: The repository updating the area.
+//   - area: The area, whose storage variable is replaced.
+//   - variable: The new storage variable.
+//
+// Returns:
+//   - error: schemas.ErrStorageVariableChanged if the storage variable was written since the
+//     area was loaded, or an error if the variable can not be marshalled or the area updated.
+func updateStorageVariable(
+	areaRepository repository.AreaRepository,
+	area *schemas.Area,
+	variable any,
+) error {
+	storageVariable, err := json.Marshal(variable)
 	if err != nil {
-		println("error creating request: " + err.Error())
-		return
+		return fmt.Errorf("error marshalling storage variable: %w", err)
+	}
+	if bytes.Equal(storageVariable, area.StorageVariable) {
+		return nil
 	}
+	swapped, err := areaRepository.SwapStorageVariable(*area, storageVariable)
+	if err != nil {
+		return fmt.Errorf("error updating area: %w", err)
+	}
+	if !swapped {
+		return schemas.ErrStorageVariableChanged
+	}
+	area.StorageVariable = storageVariable
+	return nil
+}
 
-	req.Header.Set("Authorization", "Bearer "+token.Token)
+// Actions functions
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+// MicrosoftActionEventStarting handles the event of a Microsoft action starting.
+// It keeps the events of the calendar of the user whose subject is the watched one, from a
+// delta query of the calendar view of the next MicrosoftCalendarWindow days, and triggers once
+// for every event when it starts. The events deleted or moved before their start never
+// trigger. A new window is watched once the current one ends within
+// MicrosoftCalendarWindowRenewal days.
+//
+// Parameters:
+//   - ctx: The context of the check, cancelling the Microsoft Graph requests when done.
+//   - option: A JSON raw message containing the options for the Microsoft event.
+//   - area: The area schema containing user and action information.
+//
+// The function performs the following steps:
+//  1. Unmarshals the options from the JSON raw message and the storage variable of the area.
+//  2. Retrieves the user's token for the Microsoft service, and ensures the Graph subscription
+//     notifying the changes of the events of the user.
+//  3. Fetches the changes of the calendar view since the stored delta link through the poll
+//     cache, or all its events for a new window.
+//  4. Applies the changes to the stored events.
+//  5. Updates the area storage variable and returns a trigger per event started.
+//
+// If any error occurs during these steps, it returns the error.
+func (service *microsoftService) MicrosoftActionEventStarting(
+	ctx context.Context,
+	option json.RawMessage,
+	area schemas.Area,
+) ([]schemas.ActionResult, error) {
+	options := schemas.MicrosoftEventIncomingOptions{}
+	err := json.Unmarshal(option, &options)
 	if err != nil {
-		println("error making request: " + err.Error())
-		return
+		return nil, fmt.Errorf("error unmarshalling options: %w", err)
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		println("error status code: " + fmt.Sprint(resp.StatusCode))
-		return
-	}
+	// a storage variable of a previous version starts a new window
+	variable := schemas.MicrosoftVariableEvents{}
+	_ = json.Unmarshal(area.StorageVariable, &variable)
 
-	var response schemas.MicrosoftEventListResponse
-	err = json.NewDecoder(resp.Body).Decode(&response)
+	token, err := service.tokenRepository.FindByUserIdAndServiceId(
+		area.UserId,
+		area.Action.ServiceId,
+	)
 	if err != nil {
-		println("error decoding response: " + err.Error())
-		return
+		return nil, fmt.Errorf("error retrieving token: %w", err)
+	}
+	service.ensureSubscription(ctx, area, token)
+
+	now := time.Now().UTC()
+	deltaLink := variable.DeltaLink
+	windowEnd := now.AddDate(0, 0, schemas.MicrosoftCalendarWindow)
+	if now.AddDate(0, 0, schemas.MicrosoftCalendarWindowRenewal).After(variable.WindowEnd) {
+		deltaLink = ""
+	}
+	query := url.Values{}
+	query.Set("startDateTime", now.Format(time.RFC3339))
+	query.Set("endDateTime", windowEnd.Format(time.RFC3339))
+	delta, first, err := cachedDelta[schemas.MicrosoftEvent](ctx, service, area, token,
+		deltaLink,
+		"https://graph.microsoft.com/v1.0/me/calendarView/delta?"+query.Encode(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error getting calendar changes: %w", err)
 	}
 
-	for _, event := range response.Value {
-		if event.Subject == options.Name {
-			eventStartTime, err := time.Parse("2006-01-02T15:04:05.0000000", event.Start.DateTime)
-			if err != nil {
-				println("error parsing event start time: " + err.Error())
-				continue
-			}
-			eventEndTime, err := time.Parse("2006-01-02T15:04:05.0000000", event.End.DateTime)
-			if err != nil {
-				println("error parsing event end time: " + err.Error())
-				continue
-			}
-
-			if variable.Time.After(eventStartTime) && variable.Time.Before(eventEndTime) {
-				variable.Time = eventEndTime.Add(time.Second)
-				area.StorageVariable, err = json.Marshal(variable)
-				if err != nil {
-					println("error marshalling storage variable: " + err.Error())
-					return
-				}
-				err = service.areaRepository.Update(area)
-				if err != nil {
-					println("error updating area: " + err.Error())
-					return
+	if first {
+		// the events of the new window replace the kept ones, keeping their reported state
+		previous := variable
+		variable = schemas.MicrosoftVariableEvents{WindowEnd: windowEnd}
+		for _, event := range delta.items {
+			applyEventChange(&variable, event, options.Name)
+		}
+		for i, event := range variable.Events {
+			for _, kept := range previous.Events {
+				if kept.Id == event.Id && kept.Start == event.Start {
+					variable.Events[i].Reported = kept.Reported
 				}
-				channel <- fmt.Sprintf("Event '%s' is starting at %s", event.Subject, event.Start.DateTime)
-				time.Sleep(time.Second * 10)
-				return
 			}
 		}
+	} else {
+		for _, event := range delta.items {
+			applyEventChange(&variable, event, options.Name)
+		}
 	}
+	variable.DeltaLink = delta.deltaLink
 
-	println("no matching events found")
-	time.Sleep(time.Second * 10)
+	triggers, err := reportStartedEvents(&variable, now)
+	if err != nil {
+		return nil, err
+	}
+	err = updateStorageVariable(service.areaRepository, &area, variable)
+	if err != nil {
+		return nil, err
+	}
+	return triggers, nil
 }
 
 // initializedMicrosoftStorageVariable initializes the Microsoft storage variable for a given area.
@@ -503,14 +857,14 @@ func (service *microsoftService) MicrosoftActionEventStarting(
 // If the storage variable's time is zero, it also initializes it with the current UTC time and updates the area in the repository.
 //
 // Parameters:
-//   - area: The area containing the storage variable to be initialized.
+//   - area: The area containing the storage variable to be initialized, which is replaced.
 //   - service: The microsoftService instance used to update the area in the repository.
 //
 // Returns:
 //   - schemas.MicrosoftVariableTime: The initialized Microsoft storage variable.
 //   - error: An error if any occurred during the process.
 func initializedMicrosoftStorageVariable(
-	area schemas.Area,
+	area *schemas.Area,
 	service microsoftService,
 ) (schemas.MicrosoftVariableTime, error) {
 	variable := schemas.MicrosoftVariableTime{}
@@ -531,7 +885,7 @@ func initializedMicrosoftStorageVariable(
 				println("error marshalling storage variable: " + err.Error())
 				return variable, err
 			}
-			err = service.areaRepository.Update(area)
+			err = service.areaRepository.UpdateStorageVariable(*area)
 			if err != nil {
 				println("error updating area: " + err.Error())
 				return variable, err
@@ -548,7 +902,7 @@ func initializedMicrosoftStorageVariable(
 			println("error marshalling storage variable: " + err.Error())
 			return variable, err
 		}
-		err = service.areaRepository.Update(area)
+		err = service.areaRepository.UpdateStorageVariable(*area)
 		if err != nil {
 			println("error updating area: " + err.Error())
 			return variable, err
@@ -557,122 +911,104 @@ func initializedMicrosoftStorageVariable(
 	return variable, nil
 }
 
-func getNewEmails(
-	token schemas.Token,
-	variable schemas.MicrosoftVariableTime,
-) (schemas.MicrosoftEmailResponse, error) {
-	var emailResponse schemas.MicrosoftEmailResponse
-	apiURL := "https://graph.microsoft.com/v1.0/me/messages?$filter=receivedDateTime+gt+" + variable.Time.Format(
-		"2006-01-02T15:04:05",
-	) + "Z"
-	println("apiURL: " + apiURL)
-	ctx := context.Background()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
-	if err != nil {
-		println("error creating request: " + err.Error())
-		return emailResponse, err
-	}
-
-	req.Header.Set("Authorization", "Bearer "+token.Token)
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		println("error making request: " + err.Error())
-		return emailResponse, err
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		println("error status code: " + fmt.Sprint(resp.StatusCode))
-		return emailResponse, nil
-	}
-
-	err = json.NewDecoder(resp.Body).Decode(&emailResponse)
-	if err != nil {
-		println("error decoding response: " + err.Error())
-		return emailResponse, err
-	}
-
-	return emailResponse, nil
-}
-
-// Actions functions
-
 // MicrosoftActionReceiveMail handles the action of receiving emails from Microsoft service.
-// It initializes the storage variable, retrieves the token, fetches new emails, and updates the area repository.
+// It runs a delta query of the inbox of the user from the delta link stored in the storage
+// variable of the area, and returns a trigger per email received after the last one reported,
+// oldest first. The emails deleted, moved or updated never trigger, and neither do the emails
+// moved back to the inbox, received before the last one reported.
 //
 // Parameters:
-//   - channel: A channel to send the response string.
+//   - ctx: The context of the check, cancelling the Microsoft Graph requests when done.
 //   - option: A JSON raw message containing options.
 //   - area: The area schema containing user and action details.
 //
 // The function performs the following steps:
 //  1. Initializes the storage variable using the provided area and service.
-//  2. Retrieves the token associated with the user and service.
-//  3. Fetches new emails using the token and storage variable.
-//  4. If new emails are found, it processes the latest email, updates the storage variable, and sends a response through the channel.
-//  5. Updates the area repository with the new storage variable.
-//  6. Sleeps for a duration based on the action's refresh rate.
+//  2. Retrieves the token associated with the user and service, and ensures the Graph
+//     subscription notifying the new emails of the user.
+//  3. Fetches the changes of the inbox since the stored delta link through the poll cache, or
+//     the emails received since the stored time when there is no delta link or it expired.
+//  4. Returns a trigger per new email, and updates the area with the new delta link and the
+//     reception time of the last email.
 func (service *microsoftService) MicrosoftActionReceiveMail(
-	channel chan string,
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) {
-	variable, err := initializedMicrosoftStorageVariable(area, *service)
+) ([]schemas.ActionResult, error) {
+	variable, err := initializedMicrosoftStorageVariable(&area, *service)
 	if err != nil {
-		println("error initializing storage variable: " + err.Error())
-		return
+		return nil, fmt.Errorf("error initializing storage variable: %w", err)
 	}
 
 	token, err := service.tokenRepository.FindByUserIdAndServiceId(
 		area.UserId,
 		area.Action.ServiceId,
 	)
-	if err != nil || token.Token == "" {
-		println("error retrieving token or token not found")
-		return
+	if err != nil {
+		return nil, fmt.Errorf("error finding token: %w", err)
+	}
+	if token.Token == "" {
+		return nil, schemas.ErrTokenNotFound
 	}
+	service.ensureSubscription(ctx, area, token)
 
-	emailResponse, err := getNewEmails(token, variable)
+	query := url.Values{}
+	query.Set("$select", "subject,from,receivedDateTime")
+	query.Set("$filter", "receivedDateTime ge "+variable.Time.UTC().Format(time.RFC3339))
+	delta, _, err := cachedDelta[schemas.MicrosoftEmail](ctx, service, area, token,
+		variable.DeltaLink,
+		"https://graph.microsoft.com/v1.0/me/mailFolders/inbox/messages/delta?"+query.Encode(),
+	)
 	if err != nil {
-		println("error getting new emails: " + err.Error())
-		return
+		return nil, fmt.Errorf("error getting new emails: %w", err)
 	}
 
-	if len(emailResponse.Value) > 0 {
-		latestEmail := emailResponse.Value[0]
-		response := fmt.Sprintf("New email received from %s: object: %s",
-			latestEmail.From.EmailAddress.Address,
-			latestEmail.Subject,
-		)
-		println(response)
-		variable.Time, err = time.Parse(time.RFC3339, latestEmail.ReceivedDateTime)
+	type receivedEmail struct {
+		email    schemas.MicrosoftEmail
+		received time.Time
+	}
+	var emails []receivedEmail
+	for _, email := range delta.items {
+		if email.Removed != nil {
+			continue
+		}
+		received, err := time.Parse(time.RFC3339, email.ReceivedDateTime)
 		if err != nil {
 			println("error parsing time: " + err.Error())
-			return
+			continue
 		}
-		variable.Time = variable.Time.Add(time.Second)
-		area.StorageVariable, err = json.Marshal(variable)
-		if err != nil {
-			println("error marshalling storage variable: " + err.Error())
-			return
+		if received.After(variable.Time) {
+			emails = append(emails, receivedEmail{email: email, received: received})
 		}
-		err = service.areaRepository.Update(area)
+	}
+	slices.SortStableFunc(emails, func(a, b receivedEmail) int {
+		return a.received.Compare(b.received)
+	})
+
+	var triggers []schemas.ActionResult
+	for _, email := range emails {
+		response := fmt.Sprintf("New email received from %s: object: %s",
+			email.email.From.EmailAddress.Address,
+			email.email.Subject,
+		)
+		trigger, err := newTrigger(response, schemas.MicrosoftMailPayload{
+			Sender:  email.email.From.EmailAddress.Address,
+			Subject: email.email.Subject,
+			Date:    email.email.ReceivedDateTime,
+		})
 		if err != nil {
-			println("error updating area: " + err.Error())
-			return
+			return nil, err
 		}
-		channel <- response
-	} else {
-		println("No new emails")
+		triggers = append(triggers, trigger...)
+		variable.Time = email.received
 	}
+	variable.DeltaLink = delta.deltaLink
 
-	if (area.Action.MinimumRefreshRate) > area.ActionRefreshRate {
-		time.Sleep(time.Second * time.Duration(area.Action.MinimumRefreshRate))
-	} else {
-		time.Sleep(time.Second * time.Duration(area.ActionRefreshRate))
+	err = updateStorageVariable(service.areaRepository, &area, variable)
+	if err != nil {
+		return nil, err
 	}
+	return triggers, nil
 }
 
 // Reactions functions
@@ -680,12 +1016,13 @@ func (service *microsoftService) MicrosoftActionReceiveMail(
 // MicrosoftReactionSendMail sends an email using the Microsoft Graph API.
 //
 // Parameters:
+//   - ctx: The context of the reaction, cancelling the Microsoft Graph request when done.
 //   - option: A JSON raw message containing the email options.
 //   - area: A schemas.Area object containing user and service information.
 //
 // Returns:
 //
-//	A string indicating the result of the email sending operation.
+//	The result of the email sending operation, or an error if any step fails.
 //
 // The function performs the following steps:
 //  1. Unmarshals the email options from the provided JSON raw message.
@@ -694,16 +1031,17 @@ func (service *microsoftService) MicrosoftActionReceiveMail(
 //  4. Creates an HTTP POST request to the Microsoft Graph API to send the email.
 //  5. Sets the necessary headers, including the authorization token.
 //  6. Sends the HTTP request and checks the response status code.
-//  7. Returns a success message if the email is sent successfully, or an error message if any step fails.
+//  7. Returns a success message, with the recipient and the subject as its payload, if the email
+//     is sent successfully, or an error if any step fails.
 func (service *microsoftService) MicrosoftReactionSendMail(
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) string {
+) (schemas.ReactionResult, error) {
 	options := schemas.MicrosoftReactionSendMailOptions{}
 	err := json.Unmarshal(option, &options)
 	if err != nil {
-		fmt.Println("Error unmarshalling options:", err)
-		return "Error unmarshalling options: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error unmarshalling options: %w", err)
 	}
 
 	token, err := service.tokenRepository.FindByUserIdAndServiceId(
@@ -711,12 +1049,10 @@ func (service *microsoftService) MicrosoftReactionSendMail(
 		area.Reaction.ServiceId,
 	)
 	if err != nil {
-		fmt.Println("Error finding token:", err)
-		return "Error finding token: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error finding token: %w", err)
 	}
 	if token.Token == "" {
-		fmt.Println("Error: Token not found")
-		return "Error: Token not found"
+		return schemas.ReactionResult{}, schemas.ErrTokenNotFound
 	}
 
 	apiURL := "https://graph.microsoft.com/v1.0/me/sendMail"
@@ -740,46 +1076,56 @@ func (service *microsoftService) MicrosoftReactionSendMail(
 
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
-		fmt.Println("Error marshalling email payload:", err)
-		return "Error marshalling email payload: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error marshalling email payload: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, apiURL, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewBuffer(payloadBytes))
 	if err != nil {
-		fmt.Println("Error creating HTTP request:", err)
-		return "Error creating HTTP request: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error creating HTTP request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+token.Token)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Microsoft, req)
 	if err != nil {
-		fmt.Println("Error sending email request:", err)
-		return "Error sending email request: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error sending email request: %w", err)
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return schemas.ReactionResult{}, fmt.Errorf(
+			"error status code %d: %w",
+			resp.StatusCode,
+			schemas.ErrUnauthorized,
+		)
+	}
 	if resp.StatusCode != http.StatusAccepted {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		fmt.Println("Error sending email:", string(bodyBytes))
-		return "Error sending email: " + string(bodyBytes)
+		return schemas.ReactionResult{}, statusError(
+			resp.StatusCode,
+			fmt.Errorf("error sending email: %s", string(bodyBytes)),
+		)
 	}
 
-	return "Email sent successfully!"
+	return newReactionResult("Email sent successfully!", schemas.MicrosoftSentMailPayload{
+		Recipient: options.Recipient,
+		Subject:   options.Subject,
+	})
 }
 
 // MicrosoftReactionCreateEvent creates a new event in the Microsoft calendar for the specified user.
 // It takes a JSON raw message containing event options and an Area schema as input parameters.
-// The function returns a string indicating the result of the operation.
+// The function returns the result of the operation, or an error if the event could not be created.
 //
 // Parameters:
+//   - ctx: The context of the reaction, cancelling the Microsoft Graph request when done.
 //   - option: A JSON raw message containing the event options.
 //   - area: An Area schema containing user and service information.
 //
 // Returns:
-//   - A string indicating the result of the event creation operation.
+//   - The result of the event creation operation.
+//   - An error if any step of the event creation fails.
 //
 // The function performs the following steps:
 //  1. Unmarshals the JSON raw message into MicrosoftCreateEventOptions.
@@ -787,16 +1133,17 @@ func (service *microsoftService) MicrosoftReactionSendMail(
 //  3. Validates the retrieved token.
 //  4. Constructs the event payload with the provided options.
 //  5. Sends an HTTP POST request to the Microsoft Graph API to create the event.
-//  6. Handles the response and returns the appropriate result message.
+//  6. Handles the response and returns the appropriate result message, with the created
+//     event as its payload.
 func (service *microsoftService) MicrosoftReactionCreateEvent(
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) string {
+) (schemas.ReactionResult, error) {
 	options := schemas.MicrosoftCreateEventOptions{}
 	err := json.Unmarshal(option, &options)
 	if err != nil {
-		fmt.Println("Error unmarshalling options:", err)
-		return "Error unmarshalling options: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error unmarshalling options: %w", err)
 	}
 
 	token, err := service.tokenRepository.FindByUserIdAndServiceId(
@@ -804,29 +1151,25 @@ func (service *microsoftService) MicrosoftReactionCreateEvent(
 		area.Reaction.ServiceId,
 	)
 	if err != nil {
-		fmt.Println("Error finding token:", err)
-		return "Error finding token: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error finding token: %w", err)
 	}
 
 	if token.Token == "" {
-		fmt.Println("Error: Token not found")
-		return "Error: Token not found"
+		return schemas.ReactionResult{}, schemas.ErrTokenNotFound
 	}
 
 	apiURL := "https://graph.microsoft.com/v1.0/me/events"
 
 	startTime, err := time.Parse("2006-01-02T15:04:05", options.Start)
 	if err != nil {
-		fmt.Println("Error parsing start time:", err)
-		return "Error parsing start time: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error parsing start time: %w", err)
 	}
 
 	startTime = startTime.Add(-time.Hour)
 
 	endTime, err := time.Parse("2006-01-02T15:04:05", options.End)
 	if err != nil {
-		fmt.Println("Error parsing end time:", err)
-		return "Error parsing end time: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error parsing end time: %w", err)
 	}
 
 	endTime = endTime.Add(-time.Hour)
@@ -844,33 +1187,54 @@ func (service *microsoftService) MicrosoftReactionCreateEvent(
 
 	payloadBytes, err := json.Marshal(payload)
 	if err != nil {
-		fmt.Println("Error marshalling event payload:", err)
-		return "Error marshalling event payload: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error marshalling event payload: %w", err)
 	}
 
-	req, err := http.NewRequest(http.MethodPost, apiURL, bytes.NewBuffer(payloadBytes))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewBuffer(payloadBytes))
 	if err != nil {
-		fmt.Println("Error creating HTTP request:", err)
-		return "Error creating HTTP request: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error creating HTTP request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+token.Token)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Microsoft, req)
 	if err != nil {
-		fmt.Println("Error creating event request:", err)
-		return "Error creating event request: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error creating event request: %w", err)
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return schemas.ReactionResult{}, fmt.Errorf(
+			"error status code %d: %w",
+			resp.StatusCode,
+			schemas.ErrUnauthorized,
+		)
+	}
 	if resp.StatusCode != http.StatusCreated {
 		bodyBytes, _ := io.ReadAll(resp.Body)
-		fmt.Println("Error creating event:", string(bodyBytes))
-		return "Error creating event: " + string(bodyBytes)
+		return schemas.ReactionResult{}, statusError(
+			resp.StatusCode,
+			fmt.Errorf("error creating event: %s", string(bodyBytes)),
+		)
+	}
+
+	createdEvent := struct {
+		Id      string `json:"id"`
+		WebLink string `json:"webLink"`
+	}{}
+	// the event is created, a response that can not be decoded only leaves the payload incomplete
+	err = json.NewDecoder(resp.Body).Decode(&createdEvent)
+	if err != nil {
+		println("error decoding created event: " + err.Error())
 	}
 
-	return "Event created successfully!"
+	return newReactionResult("Event created successfully!", schemas.MicrosoftCreatedEventPayload{
+		Id:      createdEvent.Id,
+		Subject: options.Subject,
+		Start:   options.Start,
+		End:     options.End,
+		Url:     createdEvent.WebLink,
+	})
 }
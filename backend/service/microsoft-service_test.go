@@ -0,0 +1,246 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"area/schemas"
+	"area/service"
+	"area/test"
+)
+
+const (
+	inboxDelta    = "https://graph.microsoft.com/v1.0/me/mailFolders/inbox/messages/delta"
+	calendarDelta = "https://graph.microsoft.com/v1.0/me/calendarView/delta"
+)
+
+func microsoftEmail(id string, received string) string {
+	return `{
+		"id": "` + id + `",
+		"subject": "Hello",
+		"from": {"emailAddress": {"address": "friend@example.com"}},
+		"receivedDateTime": "` + received + `"
+	}`
+}
+
+// newFakeInbox starts a fake Microsoft Graph whose inbox changes since the delta token t1 are
+// m1 received, m2 deleted, m0 received long ago and moved back to the inbox, then m3 received,
+// on two pages. The changes since t0 expired, and there is no change since t2.
+func newFakeInbox(t *testing.T) string {
+	delta := func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Get("$skiptoken") == "p2":
+			_, _ = fmt.Fprintf(w, `{"value": [%s, %s, %s], "@odata.deltaLink": "%s"}`,
+				`{"id": "m2", "@removed": {"reason": "deleted"}}`,
+				microsoftEmail("m0", "2023-12-31T00:00:00Z"),
+				microsoftEmail("m3", "2024-01-02T03:05:00Z"),
+				inboxDelta+"?$deltatoken=t2",
+			)
+		case query.Get("$deltatoken") == "t1":
+			_, _ = fmt.Fprintf(w, `{"value": [%s], "@odata.nextLink": "%s"}`,
+				microsoftEmail("m1", "2024-01-02T03:04:05Z"),
+				inboxDelta+"?$skiptoken=p2",
+			)
+		case query.Get("$deltatoken") == "t2":
+			_, _ = fmt.Fprintf(w, `{"value": [], "@odata.deltaLink": "%s"}`,
+				inboxDelta+"?$deltatoken=t2",
+			)
+		case query.Get("$deltatoken") != "":
+			w.WriteHeader(http.StatusGone)
+		default:
+			assert.Equal(t, "receivedDateTime ge 2024-01-01T00:00:00Z", query.Get("$filter"))
+			_, _ = fmt.Fprintf(w, `{"value": [%s], "@odata.deltaLink": "%s"}`,
+				microsoftEmail("m1", "2024-01-02T03:04:05Z"),
+				inboxDelta+"?$deltatoken=t2",
+			)
+		}
+	}
+	return newFakeGraph(t, map[string]http.HandlerFunc{
+		"GET /v1.0/me/mailFolders/inbox/messages/delta": delta,
+	}).URL
+}
+
+func newDeltaMicrosoftService(
+	t *testing.T,
+	graphURL string,
+) (service.MicrosoftService, *test.MockAreaRepository) {
+	t.Setenv("BACKEND_PORT", "8080")
+	t.Setenv("BACKEND_EXTERNAL_HOST", "")
+	t.Setenv("IS_PRODUCTION", "false")
+	mockMicrosoftRepository := new(test.MockMicrosoftRepository)
+	mockMicrosoftRepository.On("FindSubscriptionByAreaId", uint64(1)).
+		Return(schemas.MicrosoftSubscription{}, schemas.ErrMicrosoftSubscriptionNotFound)
+	mockAreaRepository := new(test.MockAreaRepository)
+	mockAreaRepository.On("UpdateStorageVariable", mock.Anything).Return(nil)
+	mockAreaRepository.On("SwapStorageVariable", mock.Anything, mock.Anything).Return(true, nil)
+	mockTokenRepository := new(test.MockTokenRepository)
+	mockTokenRepository.On("FindByUserIdAndServiceId", uint64(7), uint64(3)).
+		Return(schemas.Token{Token: "token"}, nil)
+	providerClient := &test.FakeProviderClient{URL: graphURL}
+	microsoftService := service.NewMicrosoftService(
+		mockMicrosoftRepository,
+		nil,
+		mockAreaRepository,
+		mockTokenRepository,
+		providerClient,
+		service.NewPollCache(),
+		service.NewMicrosoftSubscriptionManager(
+			graphURL,
+			mockMicrosoftRepository,
+			mockAreaRepository,
+			mockTokenRepository,
+			providerClient,
+		),
+	)
+	return microsoftService, mockAreaRepository
+}
+
+func storedVariable(mockAreaRepository *test.MockAreaRepository) json.RawMessage {
+	call := mockAreaRepository.Calls[len(mockAreaRepository.Calls)-1]
+	if call.Method == "SwapStorageVariable" {
+		return call.Arguments.Get(1).(json.RawMessage)
+	}
+	return call.Arguments.Get(0).(schemas.Area).StorageVariable
+}
+
+func receiveMicrosoftMail(
+	t *testing.T,
+	storage string,
+) ([]schemas.ActionResult, *test.MockAreaRepository) {
+	microsoftService, mockAreaRepository := newDeltaMicrosoftService(t, newFakeInbox(t))
+	triggers, err := microsoftService.MicrosoftActionReceiveMail(
+		context.Background(),
+		nil,
+		microsoftMailArea(storage),
+	)
+	assert.NoError(t, err)
+	return triggers, mockAreaRepository
+}
+
+func TestMicrosoftMailReportsAddedEmails(t *testing.T) {
+	triggers, mockAreaRepository := receiveMicrosoftMail(
+		t,
+		`{"time":"2024-01-01T00:00:00Z","delta_link":"`+inboxDelta+`?$deltatoken=t1"}`,
+	)
+
+	// the deleted email and the one moved back to the inbox are not reported
+	assert.Len(t, triggers, 2)
+	for i, date := range []string{"2024-01-02T03:04:05Z", "2024-01-02T03:05:00Z"} {
+		payload := schemas.MicrosoftMailPayload{}
+		assert.NoError(t, json.Unmarshal(triggers[i].Payload, &payload))
+		assert.Equal(t, date, payload.Date)
+		assert.Equal(t, "friend@example.com", payload.Sender)
+	}
+
+	variable := schemas.MicrosoftVariableTime{}
+	assert.NoError(t, json.Unmarshal(storedVariable(mockAreaRepository), &variable))
+	assert.Equal(t, inboxDelta+"?$deltatoken=t2", variable.DeltaLink)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 5, 0, 0, time.UTC), variable.Time)
+}
+
+func TestMicrosoftMailWithoutChangeKeepsStorage(t *testing.T) {
+	triggers, mockAreaRepository := receiveMicrosoftMail(
+		t,
+		`{"time":"2024-01-02T03:05:00Z","delta_link":"`+inboxDelta+`?$deltatoken=t2"}`,
+	)
+
+	assert.Empty(t, triggers)
+	mockAreaRepository.AssertNotCalled(t, "SwapStorageVariable", mock.Anything, mock.Anything)
+}
+
+func TestMicrosoftMailResyncsExpiredDelta(t *testing.T) {
+	triggers, mockAreaRepository := receiveMicrosoftMail(
+		t,
+		`{"time":"2024-01-01T00:00:00Z","delta_link":"`+inboxDelta+`?$deltatoken=t0"}`,
+	)
+
+	assert.Len(t, triggers, 1)
+	variable := schemas.MicrosoftVariableTime{}
+	assert.NoError(t, json.Unmarshal(storedVariable(mockAreaRepository), &variable))
+	assert.Equal(t, inboxDelta+"?$deltatoken=t2", variable.DeltaLink)
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), variable.Time)
+}
+
+func TestMicrosoftEventStartingReportsEachEventOnce(t *testing.T) {
+	now := time.Now().UTC()
+	format := func(date time.Time) string {
+		return date.Format("2006-01-02T15:04:05.0000000")
+	}
+	event := func(id string, subject string, start time.Time) string {
+		return fmt.Sprintf(`{
+			"id": "%s",
+			"subject": "%s",
+			"start": {"dateTime": "%s"},
+			"end": {"dateTime": "%s"}
+		}`, id, subject, format(start), format(start.Add(time.Hour)))
+	}
+	// since t1, e1 started, e2 was deleted before its start, and e3 is not watched
+	graphURL := newFakeGraph(t, map[string]http.HandlerFunc{
+		"GET /v1.0/me/calendarView/delta": func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("$deltatoken") {
+			case "t1":
+				_, _ = fmt.Fprintf(w, `{"value": [%s, %s, %s], "@odata.deltaLink": "%s"}`,
+					event("e1", "Standup", now.Add(-time.Minute)),
+					`{"id": "e2", "@removed": {"reason": "deleted"}}`,
+					event("e3", "Lunch", now.Add(-time.Minute)),
+					calendarDelta+"?$deltatoken=t2",
+				)
+			default:
+				_, _ = fmt.Fprintf(w, `{"value": [], "@odata.deltaLink": "%s"}`,
+					calendarDelta+"?$deltatoken=t2",
+				)
+			}
+		},
+	}).URL
+	microsoftService, mockAreaRepository := newDeltaMicrosoftService(t, graphURL)
+
+	storage, err := json.Marshal(schemas.MicrosoftVariableEvents{
+		DeltaLink: calendarDelta + "?$deltatoken=t1",
+		WindowEnd: now.AddDate(0, 0, schemas.MicrosoftCalendarWindow),
+		Events: []schemas.MicrosoftUpcomingEvent{{
+			Id:      "e2",
+			Subject: "Standup",
+			Start:   format(now.Add(-2 * time.Minute)),
+			End:     format(now.Add(time.Hour)),
+		}},
+	})
+	assert.NoError(t, err)
+	area := schemas.Area{
+		Id:              1,
+		UserId:          7,
+		Action:          schemas.Action{Name: string(schemas.EventStarting), ServiceId: 3},
+		ActionOption:    json.RawMessage(`{"name":"Standup"}`),
+		StorageVariable: storage,
+	}
+
+	triggers, err := microsoftService.MicrosoftActionEventStarting(
+		context.Background(),
+		area.ActionOption,
+		area,
+	)
+	assert.NoError(t, err)
+	assert.Len(t, triggers, 1)
+	payload := schemas.MicrosoftEventPayload{}
+	assert.NoError(t, json.Unmarshal(triggers[0].Payload, &payload))
+	assert.Equal(t, "Standup", payload.Subject)
+	assert.Equal(t, format(now.Add(-time.Minute)), payload.Start)
+
+	// the next check does not report the started event again
+	area.StorageVariable = storedVariable(mockAreaRepository)
+	triggers, err = microsoftService.MicrosoftActionEventStarting(
+		context.Background(),
+		area.ActionOption,
+		area,
+	)
+	assert.NoError(t, err)
+	assert.Empty(t, triggers)
+	mockAreaRepository.AssertNumberOfCalls(t, "SwapStorageVariable", 1)
+}
@@ -0,0 +1,624 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"path"
+	"time"
+
+	"area/repository"
+	"area/schemas"
+	"area/tools"
+)
+
+// MicrosoftSubscriptionManager defines the interface of the manager of the Microsoft Graph
+// subscriptions of the areas. A subscription makes Graph notify the backend of the changes of
+// the resource watched by the action of an area, so that the area is triggered right away
+// instead of at its next check.
+type MicrosoftSubscriptionManager interface {
+	// Ensure creates the subscription of the area, or renews it when it expires soon.
+	Ensure(ctx context.Context, area schemas.Area, token schemas.Token) error
+	// HandleNotifications turns a delivery of notifications into triggers of their areas.
+	HandleNotifications(ctx context.Context, body []byte) ([]schemas.AreaTrigger, error)
+}
+
+// microsoftSubscriptionManager is the implementation of MicrosoftSubscriptionManager.
+//
+// Fields:
+//   - graphURL: The base URL of the Microsoft Graph API.
+//   - repository: MicrosoftRepository storing the subscriptions.
+//   - areaRepository: AreaRepository loading and updating the areas of the notifications.
+//   - tokenRepository: TokenRepository finding the tokens of the users of the areas.
+//   - providerClient: Shared HTTP client sending the requests to Microsoft Graph.
+type microsoftSubscriptionManager struct {
+	graphURL        string
+	repository      repository.MicrosoftRepository
+	areaRepository  repository.AreaRepository
+	tokenRepository repository.TokenRepository
+	providerClient  ProviderClient
+}
+
+// NewMicrosoftSubscriptionManager creates the manager of the Microsoft Graph subscriptions.
+//
+// Parameters:
+//   - graphURL: The base URL of the Microsoft Graph API, schemas.MicrosoftGraphURL outside tests.
+//   - microsoftRepository: Repository storing the subscriptions.
+//   - areaRepository: Repository loading and updating the areas of the notifications.
+//   - tokenRepository: Repository finding the tokens of the users of the areas.
+//   - providerClient: Shared HTTP client sending the requests to Microsoft Graph.
+//
+// Returns:
+//   - MicrosoftSubscriptionManager: a new instance of MicrosoftSubscriptionManager.
+func NewMicrosoftSubscriptionManager(
+	graphURL string,
+	microsoftRepository repository.MicrosoftRepository,
+	areaRepository repository.AreaRepository,
+	tokenRepository repository.TokenRepository,
+	providerClient ProviderClient,
+) MicrosoftSubscriptionManager {
+	return &microsoftSubscriptionManager{
+		graphURL:        graphURL,
+		repository:      microsoftRepository,
+		areaRepository:  areaRepository,
+		tokenRepository: tokenRepository,
+		providerClient:  providerClient,
+	}
+}
+
+// subscriptionResource returns the resource watched for the action of an area and the changes
+// notified for it: the new emails of the mailbox, or the new, updated and deleted events of
+// the calendar.
+//
+// Parameters:
+//   - action: The name of the action of the area.
+//
+// Returns:
+//   - resource: The resource watched, relative to the Graph URL.
+//   - changeType: The changes notified, separated by commas.
+//   - ok: false if the action can not be notified.
+func subscriptionResource(action string) (resource string, changeType string, ok bool) {
+	switch action {
+	case string(schemas.ReceiveMicrosoftMail):
+		return "me/messages", "created", true
+	case string(schemas.EventStarting):
+		return "me/events", "created,updated,deleted", true
+	default:
+		return "", "", false
+	}
+}
+
+// Ensure makes sure that the area has a subscription watching the resource of its action. The
+// subscription is created on its first call, and renewed once it expires within
+// MicrosoftSubscriptionMargin minutes, so that the checks of the area keep it alive. A
+// subscription that Graph no longer knows is created again. Nothing is done when
+// BACKEND_EXTERNAL_HOST is not set, Graph being unable to reach the backend: the area only
+// relies on its checks.
+//
+// Parameters:
+//   - ctx: The context of the check of the area, cancelling the Graph requests when done.
+//   - area: The area whose action watches the resource.
+//   - token: The Microsoft token of the user of the area.
+//
+// Returns:
+//   - error: An error if the subscription can not be created, renewed or saved.
+func (manager *microsoftSubscriptionManager) Ensure(
+	ctx context.Context,
+	area schemas.Area,
+	token schemas.Token,
+) error {
+	resource, changeType, ok := subscriptionResource(area.Action.Name)
+	if !ok {
+		return nil
+	}
+
+	subscription, err := manager.repository.FindSubscriptionByAreaId(area.Id)
+	switch {
+	case err == nil:
+		renewAt := subscription.ExpiresAt.Add(-time.Minute * schemas.MicrosoftSubscriptionMargin)
+		if time.Now().Before(renewAt) {
+			return nil
+		}
+		err = manager.renewSubscription(ctx, token, &subscription)
+		if err == nil {
+			return manager.repository.UpdateSubscription(subscription)
+		}
+		if !errors.Is(err, schemas.ErrMicrosoftSubscriptionNotFound) {
+			return fmt.Errorf("unable to renew subscription: %w", err)
+		}
+		// the subscription expired or was removed from Graph, a new one replaces it
+		err = manager.repository.DeleteSubscription(subscription)
+		if err != nil {
+			return fmt.Errorf("unable to delete subscription: %w", err)
+		}
+	case !errors.Is(err, schemas.ErrMicrosoftSubscriptionNotFound):
+		return fmt.Errorf("unable to find subscription: %w", err)
+	}
+
+	notificationURL, err := getWebhookURL(schemas.Microsoft)
+	if errors.Is(err, schemas.ErrBackendExternalHostNotSet) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("unable to get webhook url: %w", err)
+	}
+	clientState, err := tools.GenerateWebhookSecret()
+	if err != nil {
+		return err
+	}
+
+	created, err := manager.createSubscription(ctx, token, schemas.MicrosoftSubscriptionRequest{
+		ChangeType:         changeType,
+		NotificationURL:    notificationURL,
+		Resource:           resource,
+		ExpirationDateTime: subscriptionExpiration(),
+		ClientState:        clientState,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to create subscription: %w", err)
+	}
+	err = manager.repository.SaveSubscription(schemas.MicrosoftSubscription{
+		AreaId:         area.Id,
+		SubscriptionId: created.Id,
+		Resource:       resource,
+		ClientState:    clientState,
+		ExpiresAt:      created.ExpirationDateTime,
+	})
+	if err != nil {
+		return fmt.Errorf("unable to save subscription: %w", err)
+	}
+	return nil
+}
+
+// subscriptionExpiration returns the expiration of a subscription created or renewed now.
+func subscriptionExpiration() string {
+	return time.Now().UTC().
+		Add(time.Minute * schemas.MicrosoftSubscriptionLifetime).
+		Format(time.RFC3339)
+}
+
+// createSubscription creates a subscription on Graph. Graph checks the notification URL with
+// a validation request before answering.
+//
+// Parameters:
+//   - ctx: The context of the request, cancelling it when done.
+//   - token: The Microsoft token of the user.
+//   - request: The subscription to create.
+//
+// Returns:
+//   - subscription: The subscription created on Graph.
+//   - err: An error if the request fails or Graph refuses to create the subscription.
+func (manager *microsoftSubscriptionManager) createSubscription(
+	ctx context.Context,
+	token schemas.Token,
+	request schemas.MicrosoftSubscriptionRequest,
+) (subscription schemas.MicrosoftGraphSubscription, err error) {
+	body, err := json.Marshal(request)
+	if err != nil {
+		return subscription, fmt.Errorf("unable to marshal request: %w", err)
+	}
+	resp, err := manager.sendRequest(
+		ctx,
+		token,
+		http.MethodPost,
+		"/subscriptions",
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return subscription, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		errorBody, _ := io.ReadAll(resp.Body)
+		return subscription, statusError(resp.StatusCode, fmt.Errorf(
+			"unexpected status code: %d, response: %s",
+			resp.StatusCode,
+			string(errorBody),
+		))
+	}
+	err = json.NewDecoder(resp.Body).Decode(&subscription)
+	if err != nil {
+		return subscription, fmt.Errorf("unable to decode response: %w", err)
+	}
+	return subscription, nil
+}
+
+// renewSubscription pushes back the expiration of a subscription on Graph.
+//
+// Parameters:
+//   - ctx: The context of the request, cancelling it when done.
+//   - token: The Microsoft token of the user.
+//   - subscription: The subscription to renew, whose expiration is updated.
+//
+// Returns:
+//   - error: schemas.ErrMicrosoftSubscriptionNotFound if Graph no longer knows the
+//     subscription, or an error if the request fails.
+func (manager *microsoftSubscriptionManager) renewSubscription(
+	ctx context.Context,
+	token schemas.Token,
+	subscription *schemas.MicrosoftSubscription,
+) error {
+	body, err := json.Marshal(schemas.MicrosoftSubscriptionRenewal{
+		ExpirationDateTime: subscriptionExpiration(),
+	})
+	if err != nil {
+		return fmt.Errorf("unable to marshal request: %w", err)
+	}
+	resp, err := manager.sendRequest(
+		ctx,
+		token,
+		http.MethodPatch,
+		"/subscriptions/"+subscription.SubscriptionId,
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return schemas.ErrMicrosoftSubscriptionNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		errorBody, _ := io.ReadAll(resp.Body)
+		return statusError(resp.StatusCode, fmt.Errorf(
+			"unexpected status code: %d, response: %s",
+			resp.StatusCode,
+			string(errorBody),
+		))
+	}
+	renewed := schemas.MicrosoftGraphSubscription{}
+	err = json.NewDecoder(resp.Body).Decode(&renewed)
+	if err != nil {
+		return fmt.Errorf("unable to decode response: %w", err)
+	}
+	subscription.ExpiresAt = renewed.ExpirationDateTime
+	return nil
+}
+
+// removeSubscription deletes a subscription from Graph and from the database. A subscription
+// Graph no longer knows is only deleted from the database.
+//
+// Parameters:
+//   - ctx: The context of the request, cancelling it when done.
+//   - token: The Microsoft token of the user.
+//   - subscription: The subscription to remove.
+//
+// Returns:
+//   - error: An error if the request fails or the subscription can not be deleted.
+func (manager *microsoftSubscriptionManager) removeSubscription(
+	ctx context.Context,
+	token schemas.Token,
+	subscription schemas.MicrosoftSubscription,
+) error {
+	resp, err := manager.sendRequest(
+		ctx,
+		token,
+		http.MethodDelete,
+		"/subscriptions/"+subscription.SubscriptionId,
+		nil,
+	)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusNotFound {
+		return statusError(resp.StatusCode, fmt.Errorf(
+			"unexpected status code: %d",
+			resp.StatusCode,
+		))
+	}
+	return manager.repository.DeleteSubscription(subscription)
+}
+
+// sendRequest sends a request to Graph with the token of the user.
+//
+// Parameters:
+//   - ctx: The context of the request, cancelling it when done.
+//   - token: The Microsoft token of the user.
+//   - method: The HTTP method of the request.
+//   - path: The path of the request, relative to the Graph URL.
+//   - body: The JSON body of the request, or nil.
+//
+// Returns:
+//   - *http.Response: The response of Graph, whose body must be closed.
+//   - error: An error if the request can not be sent, wrapping schemas.ErrUnauthorized when
+//     Graph refuses the token.
+func (manager *microsoftSubscriptionManager) sendRequest(
+	ctx context.Context,
+	token schemas.Token,
+	method string,
+	path string,
+	body io.Reader,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, manager.graphURL+path, body)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token.Token)
+	// the times of the events are compared with the ones of the checks, in UTC
+	req.Header.Set("Prefer", `outlook.timezone="UTC"`)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := manager.providerClient.Do(schemas.Microsoft, req)
+	if err != nil {
+		return nil, fmt.Errorf("unable to make request: %w", err)
+	}
+	if resp.StatusCode == http.StatusUnauthorized {
+		resp.Body.Close()
+		return nil, fmt.Errorf(
+			"error status code %d: %w",
+			resp.StatusCode,
+			schemas.ErrUnauthorized,
+		)
+	}
+	return resp, nil
+}
+
+// HandleNotifications checks the clientState of every notification of a delivery against the
+// secret of its subscription, then fetches the changed items and turns them into triggers of
+// the areas of the subscriptions, with the payload their checks would report. The storage
+// variable of a triggered area moves past the item, so that its next check does not report
+// it again. The notifications of unknown subscriptions, left by deleted areas until they
+// expire, are ignored, and the subscriptions of the disabled areas are removed.
+//
+// Parameters:
+//   - ctx: The context of the delivery, cancelling the Graph requests when done.
+//   - body: The raw body of the delivery.
+//
+// Returns:
+//   - triggers: The triggers of the areas, to queue for their reaction workers.
+//   - err: schemas.ErrInvalidWebhookSignature if a clientState does not match the secret of
+//     its subscription, or an error if the delivery can not be decoded or the areas updated.
+func (manager *microsoftSubscriptionManager) HandleNotifications(
+	ctx context.Context,
+	body []byte,
+) (triggers []schemas.AreaTrigger, err error) {
+	notifications := schemas.MicrosoftNotificationList{}
+	err = json.Unmarshal(body, &notifications)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode notifications: %w", err)
+	}
+
+	// the whole delivery is refused before any area is updated if one notification is forged
+	subscriptions := make([]schemas.MicrosoftSubscription, len(notifications.Value))
+	for i, notification := range notifications.Value {
+		subscriptions[i], err = manager.repository.FindSubscriptionBySubscriptionId(
+			notification.SubscriptionId,
+		)
+		if errors.Is(err, schemas.ErrMicrosoftSubscriptionNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("unable to find subscription: %w", err)
+		}
+		if subtle.ConstantTimeCompare(
+			[]byte(notification.ClientState),
+			[]byte(subscriptions[i].ClientState),
+		) != 1 {
+			return nil, schemas.ErrInvalidWebhookSignature
+		}
+	}
+
+	for i, notification := range notifications.Value {
+		if subscriptions[i].Id == 0 {
+			continue
+		}
+		results, area, err := manager.handleNotification(ctx, subscriptions[i], notification)
+		if err != nil {
+			return triggers, err
+		}
+		for _, result := range results {
+			triggers = append(triggers, schemas.AreaTrigger{Area: area, Trigger: result})
+		}
+	}
+	return triggers, nil
+}
+
+// handleNotification turns a notification into the triggers of the area of its subscription.
+// The items that can not be fetched, such as an email deleted since its notification, trigger
+// nothing.
+//
+// Parameters:
+//   - ctx: The context of the delivery, cancelling the Graph requests when done.
+//   - subscription: The subscription of the notification.
+//   - notification: The notification.
+//
+// Returns:
+//   - []schemas.ActionResult: The triggers of the area, if any.
+//   - schemas.Area: The area of the subscription, with its updated storage variable.
+//   - error: An error if the area can not be loaded or updated.
+func (manager *microsoftSubscriptionManager) handleNotification(
+	ctx context.Context,
+	subscription schemas.MicrosoftSubscription,
+	notification schemas.MicrosoftNotification,
+) ([]schemas.ActionResult, schemas.Area, error) {
+	area, err := manager.areaRepository.FindById(subscription.AreaId)
+	if err != nil {
+		return nil, area, fmt.Errorf("unable to find area: %w", err)
+	}
+	token, err := manager.tokenRepository.FindByUserIdAndServiceId(
+		area.UserId,
+		area.Action.ServiceId,
+	)
+	if err != nil {
+		return nil, area, fmt.Errorf("unable to find token: %w", err)
+	}
+	if !area.Enable {
+		// the subscription is created again by the checks of the area once enabled
+		err = manager.removeSubscription(ctx, token, subscription)
+		if err != nil {
+			println("error remove microsoft subscription: " + err.Error())
+		}
+		return nil, area, nil
+	}
+
+	var results []schemas.ActionResult
+	switch area.Action.Name {
+	case string(schemas.ReceiveMicrosoftMail):
+		variable := schemas.MicrosoftVariableTime{}
+		err = json.Unmarshal(area.StorageVariable, &variable)
+		if err != nil || variable.Time.IsZero() {
+			// the first check of the area has not set the time its triggers start from yet
+			return nil, area, nil
+		}
+		email := schemas.MicrosoftEmail{}
+		err = manager.fetchItem(ctx, token, notification.Resource,
+			"$select=subject,from,receivedDateTime", &email)
+		if err != nil {
+			println("error fetch microsoft email: " + err.Error())
+			return nil, area, nil
+		}
+		received, err := time.Parse(time.RFC3339, email.ReceivedDateTime)
+		if err != nil {
+			return nil, area, nil
+		}
+		results, err = manager.applyNotification(&area, func(
+			storageVariable json.RawMessage,
+		) ([]schemas.ActionResult, any, error) {
+			variable := schemas.MicrosoftVariableTime{}
+			err := json.Unmarshal(storageVariable, &variable)
+			if err != nil || variable.Time.IsZero() || !received.After(variable.Time) {
+				return nil, nil, nil
+			}
+			// the next delta query of the area reports the email again, but not after this time
+			variable.Time = received
+			results, err := newTrigger(
+				fmt.Sprintf("New email received from %s: object: %s",
+					email.From.EmailAddress.Address,
+					email.Subject,
+				),
+				schemas.MicrosoftMailPayload{
+					Sender:  email.From.EmailAddress.Address,
+					Subject: email.Subject,
+					Date:    email.ReceivedDateTime,
+				},
+			)
+			return results, variable, err
+		})
+	case string(schemas.EventStarting):
+		variable := schemas.MicrosoftVariableEvents{}
+		err = json.Unmarshal(area.StorageVariable, &variable)
+		if err != nil || variable.DeltaLink == "" {
+			// the first check of the area has not listed the events of its window yet
+			return nil, area, nil
+		}
+		options := schemas.MicrosoftEventIncomingOptions{}
+		err = json.Unmarshal(area.ActionOption, &options)
+		if err != nil {
+			return nil, area, fmt.Errorf("unable to unmarshal options: %w", err)
+		}
+		event := schemas.MicrosoftEvent{Id: path.Base(notification.Resource)}
+		if notification.ChangeType == "deleted" {
+			event.Removed = &schemas.MicrosoftRemoved{Reason: "deleted"}
+		} else {
+			err = manager.fetchItem(ctx, token, notification.Resource,
+				"$select=id,subject,start,end", &event)
+			if err != nil {
+				println("error fetch microsoft event: " + err.Error())
+				return nil, area, nil
+			}
+		}
+		results, err = manager.applyNotification(&area, func(
+			storageVariable json.RawMessage,
+		) ([]schemas.ActionResult, any, error) {
+			variable := schemas.MicrosoftVariableEvents{}
+			err := json.Unmarshal(storageVariable, &variable)
+			if err != nil || variable.DeltaLink == "" {
+				return nil, nil, nil
+			}
+			// the same events as the checks of the area, which report each of them only once
+			applyEventChange(&variable, event, options.Name)
+			results, err := reportStartedEvents(&variable, time.Now().UTC())
+			return results, variable, err
+		})
+	default:
+		return nil, area, nil
+	}
+	if err != nil {
+		return nil, area, err
+	}
+	return results, area, nil
+}
+
+// applyNotification applies a notification to the storage variable of its area, and writes
+// it. The notifications are handled by any replica while a worker checks the area, both
+// writing its storage variable: when a check wrote it meanwhile, the area is loaded again and
+// the notification applied to what the check stored, so that an item is reported only once.
+//
+// Parameters:
+//   - area: The area of the notification, whose storage variable is replaced.
+//   - apply: Applies the notification to a storage variable, returning the triggers of the area
+//     and the new storage variable, or no storage variable when the area is not changed.
+//
+// Returns:
+//   - []schemas.ActionResult: The triggers of the area, if any.
+//   - error: An error if the area can not be loaded or updated.
+func (manager *microsoftSubscriptionManager) applyNotification(
+	area *schemas.Area,
+	apply func(storageVariable json.RawMessage) ([]schemas.ActionResult, any, error),
+) ([]schemas.ActionResult, error) {
+	for attempt := 1; ; attempt++ {
+		results, variable, err := apply(area.StorageVariable)
+		if err != nil || variable == nil {
+			return results, err
+		}
+		err = updateStorageVariable(manager.areaRepository, area, variable)
+		if err == nil {
+			return results, nil
+		}
+		if !errors.Is(err, schemas.ErrStorageVariableChanged) ||
+			attempt == schemas.MicrosoftNotificationAttempts {
+			return nil, err
+		}
+		*area, err = manager.areaRepository.FindById(area.Id)
+		if err != nil {
+			return nil, fmt.Errorf("unable to find area: %w", err)
+		}
+	}
+}
+
+// fetchItem fetches the item changed according to a notification.
+//
+// Parameters:
+//   - ctx: The context of the request, cancelling it when done.
+//   - token: The Microsoft token of the user.
+//   - resource: The path of the item, relative to the Graph URL.
+//   - query: The query selecting the fields of the item.
+//   - item: The value the item is decoded into.
+//
+// Returns:
+//   - error: An error if the request fails or the item can not be decoded.
+func (manager *microsoftSubscriptionManager) fetchItem(
+	ctx context.Context,
+	token schemas.Token,
+	resource string,
+	query string,
+	item any,
+) error {
+	resp, err := manager.sendRequest(ctx, token, http.MethodGet, "/"+resource+"?"+query, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return statusError(resp.StatusCode, fmt.Errorf(
+			"unexpected status code: %d",
+			resp.StatusCode,
+		))
+	}
+	err = json.NewDecoder(resp.Body).Decode(item)
+	if err != nil {
+		return fmt.Errorf("unable to decode response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,265 @@
+package service_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"area/schemas"
+	"area/service"
+	"area/test"
+)
+
+// newFakeGraph starts a fake Microsoft Graph serving the given handlers by method and path.
+func newFakeGraph(t *testing.T, handlers map[string]http.HandlerFunc) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		handler, ok := handlers[r.Method+" "+r.URL.Path]
+		if !ok {
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		handler(w, r)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func microsoftMailArea(storage string) schemas.Area {
+	return schemas.Area{
+		Id:              1,
+		UserId:          7,
+		Enable:          true,
+		Action:          schemas.Action{Name: string(schemas.ReceiveMicrosoftMail), ServiceId: 3},
+		ActionOption:    json.RawMessage(`{}`),
+		StorageVariable: json.RawMessage(storage),
+	}
+}
+
+func TestMicrosoftSubscriptionCreatedThenRenewed(t *testing.T) {
+	t.Setenv("BACKEND_PORT", "8080")
+	t.Setenv("BACKEND_EXTERNAL_HOST", "area.example.com")
+	t.Setenv("IS_PRODUCTION", "true")
+	expiration := time.Now().Add(70 * time.Hour).UTC().Truncate(time.Second)
+	graph := newFakeGraph(t, map[string]http.HandlerFunc{
+		"POST /subscriptions": func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+			request := schemas.MicrosoftSubscriptionRequest{}
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+			assert.Equal(t, "me/messages", request.Resource)
+			assert.Equal(t, "created", request.ChangeType)
+			assert.Equal(t,
+				"https://area.example.com/api/v1/microsoft/webhook",
+				request.NotificationURL,
+			)
+			assert.Len(t, request.ClientState, 2*schemas.WebhookSecretLength)
+			w.WriteHeader(http.StatusCreated)
+			_ = json.NewEncoder(w).Encode(schemas.MicrosoftGraphSubscription{
+				Id:                 "sub-1",
+				ExpirationDateTime: expiration,
+			})
+		},
+		"PATCH /subscriptions/sub-1": func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewEncoder(w).Encode(schemas.MicrosoftGraphSubscription{
+				Id:                 "sub-1",
+				ExpirationDateTime: expiration,
+			})
+		},
+	})
+	area := microsoftMailArea(`{}`)
+	token := schemas.Token{Token: "token"}
+
+	mockMicrosoftRepository := new(test.MockMicrosoftRepository)
+	mockMicrosoftRepository.On("FindSubscriptionByAreaId", uint64(1)).
+		Return(schemas.MicrosoftSubscription{}, schemas.ErrMicrosoftSubscriptionNotFound).Once()
+	mockMicrosoftRepository.On("SaveSubscription", mock.MatchedBy(
+		func(subscription schemas.MicrosoftSubscription) bool {
+			return subscription.AreaId == 1 && subscription.SubscriptionId == "sub-1" &&
+				subscription.ExpiresAt.Equal(expiration)
+		},
+	)).Return(nil)
+	manager := service.NewMicrosoftSubscriptionManager(
+		graph.URL,
+		mockMicrosoftRepository,
+		nil,
+		nil,
+		service.NewProviderClient(),
+	)
+	assert.NoError(t, manager.Ensure(context.Background(), area, token))
+	mockMicrosoftRepository.AssertCalled(t, "SaveSubscription", mock.Anything)
+
+	// a subscription far from its expiration is left as is, a close one is renewed
+	subscription := schemas.MicrosoftSubscription{
+		Id:             1,
+		AreaId:         1,
+		SubscriptionId: "sub-1",
+		ExpiresAt:      time.Now().Add(48 * time.Hour),
+	}
+	mockMicrosoftRepository.On("FindSubscriptionByAreaId", uint64(1)).
+		Return(subscription, nil).Once()
+	assert.NoError(t, manager.Ensure(context.Background(), area, token))
+	mockMicrosoftRepository.AssertNotCalled(t, "UpdateSubscription", mock.Anything)
+
+	subscription.ExpiresAt = time.Now().Add(time.Hour)
+	mockMicrosoftRepository.On("FindSubscriptionByAreaId", uint64(1)).
+		Return(subscription, nil).Once()
+	mockMicrosoftRepository.On("UpdateSubscription", mock.MatchedBy(
+		func(subscription schemas.MicrosoftSubscription) bool {
+			return subscription.ExpiresAt.Equal(expiration)
+		},
+	)).Return(nil)
+	assert.NoError(t, manager.Ensure(context.Background(), area, token))
+	mockMicrosoftRepository.AssertCalled(t, "UpdateSubscription", mock.Anything)
+}
+
+func TestMicrosoftSubscriptionNeedsExternalHost(t *testing.T) {
+	t.Setenv("BACKEND_PORT", "8080")
+	t.Setenv("BACKEND_EXTERNAL_HOST", "")
+	t.Setenv("IS_PRODUCTION", "false")
+	graph := newFakeGraph(t, map[string]http.HandlerFunc{})
+
+	mockMicrosoftRepository := new(test.MockMicrosoftRepository)
+	mockMicrosoftRepository.On("FindSubscriptionByAreaId", uint64(1)).
+		Return(schemas.MicrosoftSubscription{}, schemas.ErrMicrosoftSubscriptionNotFound)
+	manager := service.NewMicrosoftSubscriptionManager(
+		graph.URL,
+		mockMicrosoftRepository,
+		nil,
+		nil,
+		service.NewProviderClient(),
+	)
+	err := manager.Ensure(context.Background(), microsoftMailArea(`{}`), schemas.Token{})
+	assert.NoError(t, err)
+	mockMicrosoftRepository.AssertNotCalled(t, "SaveSubscription", mock.Anything)
+}
+
+func newNotifiedMicrosoftManager(
+	t *testing.T,
+	area schemas.Area,
+) (service.MicrosoftSubscriptionManager, *test.MockAreaRepository) {
+	mockAreaRepository := new(test.MockAreaRepository)
+	mockAreaRepository.On("FindById", uint64(1)).Return(area, nil)
+	mockAreaRepository.On("SwapStorageVariable", mock.Anything, mock.Anything).Return(true, nil)
+	return notifiedMicrosoftManager(t, mockAreaRepository), mockAreaRepository
+}
+
+func notifiedMicrosoftManager(
+	t *testing.T,
+	mockAreaRepository *test.MockAreaRepository,
+) service.MicrosoftSubscriptionManager {
+	graph := newFakeGraph(t, map[string]http.HandlerFunc{
+		"GET /Users/u1/Messages/m1": func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{
+				"id": "m1",
+				"subject": "Quarterly report",
+				"from": {"emailAddress": {"address": "boss@example.com"}},
+				"receivedDateTime": "2024-01-02T03:04:05Z"
+			}`))
+		},
+	})
+	mockMicrosoftRepository := new(test.MockMicrosoftRepository)
+	mockMicrosoftRepository.On("FindSubscriptionBySubscriptionId", "sub-1").
+		Return(schemas.MicrosoftSubscription{
+			Id:             1,
+			AreaId:         1,
+			SubscriptionId: "sub-1",
+			ClientState:    "secret",
+		}, nil)
+	mockMicrosoftRepository.On("FindSubscriptionBySubscriptionId", mock.Anything).
+		Return(schemas.MicrosoftSubscription{}, schemas.ErrMicrosoftSubscriptionNotFound)
+	mockTokenRepository := new(test.MockTokenRepository)
+	mockTokenRepository.On("FindByUserIdAndServiceId", uint64(7), uint64(3)).
+		Return(schemas.Token{Token: "token"}, nil)
+	return service.NewMicrosoftSubscriptionManager(
+		graph.URL,
+		mockMicrosoftRepository,
+		mockAreaRepository,
+		mockTokenRepository,
+		service.NewProviderClient(),
+	)
+}
+
+func notificationBody(clientState string) []byte {
+	return []byte(`{"value": [
+		{
+			"subscriptionId": "sub-1",
+			"clientState": "` + clientState + `",
+			"changeType": "created",
+			"resource": "Users/u1/Messages/m1"
+		},
+		{
+			"subscriptionId": "deleted-area",
+			"clientState": "unknown",
+			"changeType": "created",
+			"resource": "Users/u1/Messages/m2"
+		}
+	]}`)
+}
+
+func TestMicrosoftNotificationsTriggerArea(t *testing.T) {
+	manager, mockAreaRepository := newNotifiedMicrosoftManager(
+		t,
+		microsoftMailArea(`{"time":"2024-01-01T00:00:00Z"}`),
+	)
+
+	triggers, err := manager.HandleNotifications(context.Background(), notificationBody("secret"))
+	assert.NoError(t, err)
+	assert.Len(t, triggers, 1)
+	assert.Equal(t, uint64(1), triggers[0].Area.Id)
+	payload := schemas.MicrosoftMailPayload{}
+	assert.NoError(t, json.Unmarshal(triggers[0].Trigger.Payload, &payload))
+	assert.Equal(t, "boss@example.com", payload.Sender)
+	assert.Equal(t, "Quarterly report", payload.Subject)
+
+	// the next check of the area only reports the emails received after this one
+	mockAreaRepository.AssertNumberOfCalls(t, "SwapStorageVariable", 1)
+	variable := schemas.MicrosoftVariableTime{}
+	assert.NoError(t, json.Unmarshal(triggers[0].Area.StorageVariable, &variable))
+	assert.Equal(t, time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC), variable.Time)
+}
+
+func TestMicrosoftNotificationsSkipReportedEmail(t *testing.T) {
+	manager, mockAreaRepository := newNotifiedMicrosoftManager(
+		t,
+		microsoftMailArea(`{"time":"2024-01-02T03:04:05Z"}`),
+	)
+
+	triggers, err := manager.HandleNotifications(context.Background(), notificationBody("secret"))
+	assert.NoError(t, err)
+	assert.Empty(t, triggers)
+	mockAreaRepository.AssertNotCalled(t, "SwapStorageVariable", mock.Anything, mock.Anything)
+}
+
+func TestMicrosoftNotificationsSkipEmailReportedByRunningCheck(t *testing.T) {
+	stale := microsoftMailArea(`{"time":"2024-01-01T00:00:00Z"}`)
+	checked := microsoftMailArea(`{"time":"2024-01-02T03:04:05Z"}`)
+	mockAreaRepository := new(test.MockAreaRepository)
+	mockAreaRepository.On("FindById", uint64(1)).Return(stale, nil).Once()
+	mockAreaRepository.On("FindById", uint64(1)).Return(checked, nil)
+	// a check of the area stored the email between the load of the area and its update
+	mockAreaRepository.On("SwapStorageVariable", stale, mock.Anything).Return(false, nil)
+	manager := notifiedMicrosoftManager(t, mockAreaRepository)
+
+	triggers, err := manager.HandleNotifications(context.Background(), notificationBody("secret"))
+	assert.NoError(t, err)
+	assert.Empty(t, triggers)
+	mockAreaRepository.AssertNumberOfCalls(t, "FindById", 2)
+	mockAreaRepository.AssertNumberOfCalls(t, "SwapStorageVariable", 1)
+}
+
+func TestMicrosoftNotificationsRejectInvalidClientState(t *testing.T) {
+	manager, mockAreaRepository := newNotifiedMicrosoftManager(
+		t,
+		microsoftMailArea(`{"time":"2024-01-01T00:00:00Z"}`),
+	)
+
+	_, err := manager.HandleNotifications(context.Background(), notificationBody("forged"))
+	assert.ErrorIs(t, err, schemas.ErrInvalidWebhookSignature)
+	mockAreaRepository.AssertNotCalled(t, "FindById", mock.Anything)
+}
@@ -8,7 +8,6 @@ import (
 	"net/http"
 	"net/url"
 	"os"
-	"time"
 
 	"area/repository"
 	"area/schemas"
@@ -20,31 +19,31 @@ type OpenWeatherMapService interface {
 	// Service interface functions
 	GetServiceActionInfo() []schemas.Action
 	GetServiceReactionInfo() []schemas.Reaction
-	FindActionByName(
-		name string,
-	) func(channel chan string, option json.RawMessage, area schemas.Area)
-	FindReactionByName(name string) func(option json.RawMessage, area schemas.Area) string
+	FindActionByName(name string) ActionFunc
+	FindReactionByName(name string) ReactionFunc
 	// Service specific functions
 	// Actions functions
 	OpenWeatherMapActionSpecificWeather(
-		channel chan string,
+		ctx context.Context,
 		option json.RawMessage,
 		area schemas.Area,
-	)
+	) ([]schemas.ActionResult, error)
 	OpenWeatherMapActionSpecificTemperature(
-		channel chan string,
+		ctx context.Context,
 		option json.RawMessage,
 		area schemas.Area,
-	)
+	) ([]schemas.ActionResult, error)
 	// Reactions functions
 	OpenWeatherMapReactionCurrentWeather(
+		ctx context.Context,
 		option json.RawMessage,
 		area schemas.Area,
-	) string
+	) (schemas.ReactionResult, error)
 	OpenWeatherMapReactionCurrentTemperature(
+		ctx context.Context,
 		option json.RawMessage,
 		area schemas.Area,
-	) string
+	) (schemas.ReactionResult, error)
 }
 
 // openWeatherMapService provides methods to interact with the OpenWeatherMap API,
@@ -59,6 +58,8 @@ type openWeatherMapService struct {
 	repository        repository.OpenWeatherMapRepository // Repository to access the OpenWeatherMap API
 	serviceRepository repository.ServiceRepository        // Repository to access the Service entity
 	areaRepository    repository.AreaRepository           // Repository to access the Area entity
+	providerClient    ProviderClient                      // Shared HTTP client of the providers
+	pollCache         PollCache                           // Cache of the polls shared by the areas
 	serviceInfo       schemas.Service                     // Information about the OpenWeatherMap service
 }
 
@@ -69,6 +70,8 @@ type openWeatherMapService struct {
 //   - repository: an instance of OpenWeatherMapRepository for accessing weather data.
 //   - serviceRepository: an instance of ServiceRepository for managing service-related data.
 //   - areaRepository: an instance of AreaRepository for managing area-related data.
+//   - providerClient: an instance of ProviderClient for sending the requests to OpenWeatherMap.
+//   - pollCache: an instance of PollCache for sharing the polls of OpenWeatherMap between the areas.
 //
 // Returns:
 //   - OpenWeatherMapService: a new instance of OpenWeatherMapService.
@@ -76,11 +79,15 @@ func NewOpenWeatherMapService(
 	repository repository.OpenWeatherMapRepository,
 	serviceRepository repository.ServiceRepository,
 	areaRepository repository.AreaRepository,
+	providerClient ProviderClient,
+	pollCache PollCache,
 ) OpenWeatherMapService {
 	return &openWeatherMapService{
 		repository:        repository,
 		serviceRepository: serviceRepository,
 		areaRepository:    areaRepository,
+		providerClient:    providerClient,
+		pollCache:         pollCache,
 		serviceInfo: schemas.Service{
 			Name:        schemas.OpenWeatherMap,
 			Description: "This service is a weather service",
@@ -100,17 +107,17 @@ func (service *openWeatherMapService) GetServiceInfo() schemas.Service {
 }
 
 // FindActionByName returns a function that performs a specific action based on the provided name.
-// The returned function takes a channel, an option in the form of json.RawMessage, and an area of type schemas.Area.
+// The returned ActionFunc takes a context, an option in the form of json.RawMessage, and an area of type schemas.Area.
 //
 // Parameters:
 //   - name: A string representing the name of the action.
 //
 // Returns:
-//   - A function that takes a channel, an option, and an area, and performs the corresponding action.
+//   - The ActionFunc performing the corresponding action.
 //   - If the name does not match any known actions, it returns nil.
 func (service *openWeatherMapService) FindActionByName(
 	name string,
-) func(channel chan string, option json.RawMessage, area schemas.Area) {
+) ActionFunc {
 	switch name {
 	case string(schemas.SpecificWeather):
 		return service.OpenWeatherMapActionSpecificWeather
@@ -126,7 +133,7 @@ func (service *openWeatherMapService) FindActionByName(
 }
 
 // FindReactionByName returns a function that corresponds to the given reaction name.
-// The returned function takes a JSON raw message and an area schema as parameters and returns a string.
+// The returned ReactionFunc takes a context, a JSON raw message and an area schema as parameters.
 // If the reaction name matches "CurrentWeather" or "CurrentTemperature", the corresponding function is returned.
 // If the reaction name does not match any known reactions, nil is returned.
 //
@@ -134,11 +141,11 @@ func (service *openWeatherMapService) FindActionByName(
 //   - name: The name of the reaction to find.
 //
 // Returns:
-//   - A function that takes a JSON raw message and an area schema, and returns a string.
+//   - The ReactionFunc of the reaction.
 //   - Nil if the reaction name does not match any known reactions.
 func (service *openWeatherMapService) FindReactionByName(
 	name string,
-) func(option json.RawMessage, area schemas.Area) string {
+) ReactionFunc {
 	switch name {
 
 	case string(schemas.CurrentWeather):
@@ -181,6 +188,9 @@ func (service *openWeatherMapService) GetServiceActionInfo() []schemas.Action {
 	if err != nil {
 		println("error marshal timer option: " + err.Error())
 	}
+	schemaSpecificTemperature := newOptionSchema(
+		schemas.OpenWeatherMapActionSpecificTemperatureSchema,
+	)
 
 	service.serviceInfo, err = service.serviceRepository.FindByName(
 		schemas.OpenWeatherMap,
@@ -194,6 +204,7 @@ func (service *openWeatherMapService) GetServiceActionInfo() []schemas.Action {
 			Description:        "This action is a specific weather action",
 			Service:            service.serviceInfo,
 			Option:             optionSpecificWeather,
+			OptionSchema:       newOptionSchema(schemas.OpenWeatherMapActionSpecificWeatherSchema),
 			MinimumRefreshRate: 10,
 		},
 		{
@@ -201,6 +212,7 @@ func (service *openWeatherMapService) GetServiceActionInfo() []schemas.Action {
 			Description:        "This action triggers when the temperature is a specific value",
 			Service:            service.serviceInfo,
 			Option:             optionSpecificTemperature,
+			OptionSchema:       schemaSpecificTemperature,
 			MinimumRefreshRate: 10,
 		},
 		{
@@ -208,6 +220,7 @@ func (service *openWeatherMapService) GetServiceActionInfo() []schemas.Action {
 			Description:        "This action triggers when the temperature is above a specific value",
 			Service:            service.serviceInfo,
 			Option:             optionSpecificTemperature,
+			OptionSchema:       schemaSpecificTemperature,
 			MinimumRefreshRate: 10,
 		},
 		{
@@ -215,6 +228,7 @@ func (service *openWeatherMapService) GetServiceActionInfo() []schemas.Action {
 			Description:        "This action triggers when the temperature is below a specific value",
 			Service:            service.serviceInfo,
 			Option:             optionSpecificTemperature,
+			OptionSchema:       schemaSpecificTemperature,
 			MinimumRefreshRate: 10,
 		},
 	}
@@ -244,16 +258,18 @@ func (service *openWeatherMapService) GetServiceReactionInfo() []schemas.Reactio
 	}
 	return []schemas.Reaction{
 		{
-			Name:        string(schemas.CurrentWeather),
-			Description: "This reaction is a current weather reaction",
-			Service:     service.serviceInfo,
-			Option:      option,
+			Name:         string(schemas.CurrentWeather),
+			Description:  "This reaction is a current weather reaction",
+			Service:      service.serviceInfo,
+			Option:       option,
+			OptionSchema: newOptionSchema(schemas.OpenWeatherMapReactionOptionSchema),
 		},
 		{
-			Name:        string(schemas.CurrentTemperature),
-			Description: "This reaction is a current teamperature reaction",
-			Service:     service.serviceInfo,
-			Option:      option,
+			Name:         string(schemas.CurrentTemperature),
+			Description:  "This reaction is a current teamperature reaction",
+			Service:      service.serviceInfo,
+			Option:       option,
+			OptionSchema: newOptionSchema(schemas.OpenWeatherMapReactionOptionSchema),
 		},
 	}
 }
@@ -275,12 +291,15 @@ func (service *openWeatherMapService) GetServiceReactionInfo() []schemas.Reactio
 //
 // Example usage:
 //
-//	coordinates, err := getCoordinatesOfCity("London")
+//	coordinates, err := service.getCoordinatesOfCity(ctx, "London")
 //	if err != nil {
 //	    log.Fatalf("Error retrieving coordinates: %v", err)
 //	}
 //	fmt.Printf("Coordinates of London: Lat=%f, Lon=%f\n", coordinates.Lat, coordinates.Lon)
-func getCoordinatesOfCity(city string) (coordinates struct {
+func (service *openWeatherMapService) getCoordinatesOfCity(
+	ctx context.Context,
+	city string,
+) (coordinates struct {
 	Lat float64
 	Lon float64
 }, err error,
@@ -296,8 +315,6 @@ func getCoordinatesOfCity(city string) (coordinates struct {
 	data.Set("limit", "1")
 	data.Set("appid", APIKey)
 
-	ctx := context.Background()
-
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
 		return coordinates, fmt.Errorf("unable to create request because %w", err)
@@ -306,8 +323,7 @@ func getCoordinatesOfCity(city string) (coordinates struct {
 	req.URL.RawQuery = data.Encode()
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.OpenWeatherMap, req)
 	if err != nil {
 		return coordinates, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -332,6 +348,7 @@ func getCoordinatesOfCity(city string) (coordinates struct {
 // from the OpenWeatherMap API.
 //
 // Parameters:
+//   - ctx: The context of the request, cancelling it when done.
 //   - coordinates: A struct containing the latitude (Lat) and longitude (Lon) of the location.
 //
 // Returns:
@@ -341,10 +358,12 @@ func getCoordinatesOfCity(city string) (coordinates struct {
 // The function retrieves the OpenWeatherMap API key from the environment variable "OPENWEATHERMAP_API_KEY".
 // If the API key is not set, it returns an error. It constructs the API request URL with the provided coordinates
 // and sends a GET request to the OpenWeatherMap API. The response is decoded into the weather struct and returned.
-func getWeatherOfCoordinate(coordinates struct {
-	Lat float64
-	Lon float64
-},
+func (service *openWeatherMapService) getWeatherOfCoordinate(
+	ctx context.Context,
+	coordinates struct {
+		Lat float64
+		Lon float64
+	},
 ) (weather schemas.OpenWeatherMapCoordinatesWeatherResponse, err error) {
 	APIKey := os.Getenv("OPENWEATHERMAP_API_KEY")
 	if APIKey == "" {
@@ -358,8 +377,6 @@ func getWeatherOfCoordinate(coordinates struct {
 	data.Set("appid", APIKey)
 	data.Set("units", "metric") // to get temperature in celsius
 
-	ctx := context.Background()
-
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
 		return weather, fmt.Errorf("unable to create request because %w", err)
@@ -368,8 +385,7 @@ func getWeatherOfCoordinate(coordinates struct {
 	req.URL.RawQuery = data.Encode()
 	req.Header.Set("Accept", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.OpenWeatherMap, req)
 	if err != nil {
 		return weather, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -421,7 +437,7 @@ func initializedOpenWeatherMapStorageVariable(
 				println("error marshalling storage variable: " + err.Error())
 				return variable, err
 			}
-			err = service.areaRepository.Update(area)
+			err = service.areaRepository.UpdateStorageVariable(area)
 			if err != nil {
 				println("error updating area: " + err.Error())
 				return variable, err
@@ -436,7 +452,7 @@ func initializedOpenWeatherMapStorageVariable(
 			println("error marshalling storage variable: " + err.Error())
 			return variable, err
 		}
-		err = service.areaRepository.Update(area)
+		err = service.areaRepository.UpdateStorageVariable(area)
 		if err != nil {
 			println("error updating area: " + err.Error())
 			return variable, err
@@ -445,13 +461,43 @@ func initializedOpenWeatherMapStorageVariable(
 	return variable, nil
 }
 
+// pollWeatherOfCity fetches the weather of the city for the action of the area through the
+// poll cache, so that the areas watching the same city share a single fetch of its
+// coordinates and of its weather per refresh window.
+//
+// Parameters:
+//   - ctx: The context of the check of the area.
+//   - area: The area whose action watches the weather of the city.
+//   - city: The name of the city.
+//
+// Returns:
+//   - schemas.OpenWeatherMapCoordinatesWeatherResponse: The current weather of the city.
+//   - error: An error if the coordinates or the weather of the city can not be fetched.
+func (service *openWeatherMapService) pollWeatherOfCity(
+	ctx context.Context,
+	area schemas.Area,
+	city string,
+) (schemas.OpenWeatherMapCoordinatesWeatherResponse, error) {
+	return cachedPoll(ctx, service.pollCache, area, schemas.PollKey{
+		Provider: schemas.OpenWeatherMap,
+		Resource: "weather?q=" + city,
+	}, func(ctx context.Context) (schemas.OpenWeatherMapCoordinatesWeatherResponse, error) {
+		coordinates, err := service.getCoordinatesOfCity(ctx, city)
+		if err != nil {
+			return schemas.OpenWeatherMapCoordinatesWeatherResponse{}, fmt.Errorf(
+				"error get coordinates of city: %w", err)
+		}
+		return service.getWeatherOfCoordinate(ctx, coordinates)
+	})
+}
+
 // Actions functions
 
 // OpenWeatherMapActionSpecificWeather retrieves the weather information for a specified city
 // and updates the area storage variable based on the weather condition.
 //
 // Parameters:
-// - channel: A channel to send the response string.
+// - ctx: The context of the check, cancelling the OpenWeatherMap requests when done.
 // - option: A JSON raw message containing the weather options.
 // - area: The area schema containing the action and storage variable.
 //
@@ -462,21 +508,18 @@ func initializedOpenWeatherMapStorageVariable(
 // 4. Fetches the weather information for the retrieved coordinates.
 // 5. Compares the current weather with the specified weather condition.
 // 6. Updates the storage variable and area repository based on the weather condition.
-// 7. Sends the response string to the provided channel if the weather condition matches.
-// 8. Sleeps for a duration based on the area's action refresh rate or minimum refresh rate.
+// 7. Returns a trigger if the weather condition matches.
 func (service *openWeatherMapService) OpenWeatherMapActionSpecificWeather(
-	channel chan string,
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) {
+) ([]schemas.ActionResult, error) {
 	// Find the area
 	optionJSON := schemas.OpenWeatherMapActionSpecificWeather{}
 
 	err := json.Unmarshal([]byte(option), &optionJSON)
 	if err != nil {
-		println("error unmarshal weather option: " + err.Error())
-		time.Sleep(time.Second)
-		return
+		return nil, fmt.Errorf("error unmarshal weather option: %w", err)
 	}
 
 	variableDatabaseStorage, err := initializedOpenWeatherMapStorageVariable(area, *service)
@@ -484,14 +527,9 @@ func (service *openWeatherMapService) OpenWeatherMapActionSpecificWeather(
 		println("error initializing storage variable: " + err.Error())
 	}
 
-	coordinates, err := getCoordinatesOfCity(optionJSON.City)
+	weatherOfSpecifiedCity, err := service.pollWeatherOfCity(ctx, area, optionJSON.City)
 	if err != nil {
-		fmt.Println(err)
-	}
-
-	weatherOfSpecifiedCity, err := getWeatherOfCoordinate(coordinates)
-	if err != nil {
-		println("error get actual weather info" + err.Error())
+		return nil, fmt.Errorf("error get actual weather info: %w", err)
 	} else {
 		if weatherOfSpecifiedCity.Weather[0].Main == optionJSON.Weather {
 			if variableDatabaseStorage == schemas.OpenWeatherMapStorageVariableFalse {
@@ -499,48 +537,43 @@ func (service *openWeatherMapService) OpenWeatherMapActionSpecificWeather(
 				variableDatabaseStorage = schemas.OpenWeatherMapStorageVariableTrue
 				area.StorageVariable, err = json.Marshal(variableDatabaseStorage)
 				if err != nil {
-					println("error marshalling storage variable: " + err.Error())
-					return
+					return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 				}
-				err = service.areaRepository.Update(area)
+				err = service.areaRepository.UpdateStorageVariable(area)
 				if err != nil {
-					println("error updating area: " + err.Error())
-					return
+					return nil, fmt.Errorf("error updating area: %w", err)
 				}
 				println(response)
-				channel <- response
+				return newTrigger(response, schemas.OpenWeatherMapWeatherPayload{
+					City:    optionJSON.City,
+					Weather: weatherOfSpecifiedCity.Weather[0].Main,
+				})
 			}
 		} else {
 			if variableDatabaseStorage == schemas.OpenWeatherMapStorageVariableTrue {
 				variableDatabaseStorage = schemas.OpenWeatherMapStorageVariableFalse
 				area.StorageVariable, err = json.Marshal(variableDatabaseStorage)
 				if err != nil {
-					println("error marshalling storage variable: " + err.Error())
-					return
+					return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 				}
-				err = service.areaRepository.Update(area)
+				err = service.areaRepository.UpdateStorageVariable(area)
 				if err != nil {
-					println("error updating area: " + err.Error())
-					return
+					return nil, fmt.Errorf("error updating area: %w", err)
 				}
 			}
 		}
 	}
 
-	if (area.Action.MinimumRefreshRate) > area.ActionRefreshRate {
-		time.Sleep(time.Second * time.Duration(area.Action.MinimumRefreshRate))
-	} else {
-		time.Sleep(time.Second * time.Duration(area.ActionRefreshRate))
-	}
+	return nil, nil
 }
 
 // OpenWeatherMapActionSpecificTemperature checks the current temperature of a specified city
 // and updates the storage variable accordingly. If the current temperature matches the specified
-// temperature, it sends a response message to the provided channel. The function also handles
+// temperature, it returns a trigger describing the temperature. The function also handles
 // updating the area repository with the new storage variable state.
 //
 // Parameters:
-// - channel: A channel to send the response message if the temperature matches.
+// - ctx: The context of the check, cancelling the OpenWeatherMap requests when done.
 // - option: A JSON raw message containing the city and temperature to check against.
 // - area: The area schema containing the action and storage variable information.
 //
@@ -551,19 +584,16 @@ func (service *openWeatherMapService) OpenWeatherMapActionSpecificWeather(
 //  4. Gets the current weather information for the city's coordinates.
 //  5. Compares the current temperature with the specified temperature and updates the storage
 //     variable and area repository accordingly.
-//  6. Sleeps for a duration based on the area's action refresh rate or minimum refresh rate.
 func (service *openWeatherMapService) OpenWeatherMapActionSpecificTemperature(
-	channel chan string,
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) {
+) ([]schemas.ActionResult, error) {
 	optionJSON := schemas.OpenWeatherMapActionSpecificTemperature{}
 
 	err := json.Unmarshal([]byte(option), &optionJSON)
 	if err != nil {
-		println("error unmarshal temperature option: " + err.Error())
-		time.Sleep(time.Second)
-		return
+		return nil, fmt.Errorf("error unmarshal temperature option: %w", err)
 	}
 
 	variableDatabaseStorage, err := initializedOpenWeatherMapStorageVariable(area, *service)
@@ -571,13 +601,9 @@ func (service *openWeatherMapService) OpenWeatherMapActionSpecificTemperature(
 		println("error initializing storage variable: " + err.Error())
 	}
 
-	coordinates, err := getCoordinatesOfCity(optionJSON.City)
+	weatherOfSpecifiedCity, err := service.pollWeatherOfCity(ctx, area, optionJSON.City)
 	if err != nil {
-		fmt.Println(err)
-	}
-	weatherOfSpecifiedCity, err := getWeatherOfCoordinate(coordinates)
-	if err != nil {
-		println("error get actual temperature info" + err.Error())
+		return nil, fmt.Errorf("error get actual temperature info: %w", err)
 	} else {
 		if int64(math.Round(weatherOfSpecifiedCity.Main.Temp)) == optionJSON.Temperature {
 			if variableDatabaseStorage == schemas.OpenWeatherMapStorageVariableFalse {
@@ -586,63 +612,56 @@ func (service *openWeatherMapService) OpenWeatherMapActionSpecificTemperature(
 					weatherOfSpecifiedCity.Main.Temp,
 				) + "°C"
 				println(response)
-				channel <- response
 				variableDatabaseStorage = schemas.OpenWeatherMapStorageVariableTrue
 				area.StorageVariable, err = json.Marshal(variableDatabaseStorage)
 				if err != nil {
-					println("error marshalling storage variable: " + err.Error())
-					return
+					return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 				}
-				err = service.areaRepository.Update(area)
+				err = service.areaRepository.UpdateStorageVariable(area)
 				if err != nil {
-					println("error updating area: " + err.Error())
-					return
+					return nil, fmt.Errorf("error updating area: %w", err)
 				}
+				return newTrigger(response, schemas.OpenWeatherMapTemperaturePayload{
+					City:        optionJSON.City,
+					Temperature: weatherOfSpecifiedCity.Main.Temp,
+				})
 			}
 		} else {
 			if variableDatabaseStorage == schemas.OpenWeatherMapStorageVariableTrue {
 				variableDatabaseStorage = schemas.OpenWeatherMapStorageVariableFalse
 				area.StorageVariable, err = json.Marshal(variableDatabaseStorage)
 				if err != nil {
-					println("error marshalling storage variable: " + err.Error())
-					return
+					return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 				}
-				err = service.areaRepository.Update(area)
+				err = service.areaRepository.UpdateStorageVariable(area)
 				if err != nil {
-					println("error updating area: " + err.Error())
-					return
+					return nil, fmt.Errorf("error updating area: %w", err)
 				}
 			}
 		}
 	}
 
-	if (area.Action.MinimumRefreshRate) > area.ActionRefreshRate {
-		time.Sleep(time.Second * time.Duration(area.Action.MinimumRefreshRate))
-	} else {
-		time.Sleep(time.Second * time.Duration(area.ActionRefreshRate))
-	}
+	return nil, nil
 }
 
 // OpenWeatherMapActionAboveTemperature checks if the temperature in a specified city is above a given threshold.
-// If the temperature is above the threshold and the storage variable indicates it was previously below, it sends a message to the provided channel and updates the storage variable.
+// If the temperature is above the threshold and the storage variable indicates it was previously below, it updates the storage variable and returns a trigger.
 // If the temperature is below the threshold and the storage variable indicates it was previously above, it updates the storage variable accordingly.
 // The function also handles errors related to JSON unmarshalling, storage variable initialization, coordinate retrieval, and weather data retrieval.
 // Parameters:
-// - channel: A channel to send messages when the temperature condition is met.
+// - ctx: The context of the check, cancelling the OpenWeatherMap requests when done.
 // - option: A JSON raw message containing the city and temperature threshold.
 // - area: The area schema containing action and storage variable information.
 func (service *openWeatherMapService) OpenWeatherMapActionAboveTemperature(
-	channel chan string,
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) {
+) ([]schemas.ActionResult, error) {
 	optionJSON := schemas.OpenWeatherMapActionSpecificTemperature{}
 
 	err := json.Unmarshal([]byte(option), &optionJSON)
 	if err != nil {
-		println("error unmarshal temperature option: " + err.Error())
-		time.Sleep(time.Second)
-		return
+		return nil, fmt.Errorf("error unmarshal temperature option: %w", err)
 	}
 
 	variableDatabaseStorage, err := initializedOpenWeatherMapStorageVariable(area, *service)
@@ -650,13 +669,9 @@ func (service *openWeatherMapService) OpenWeatherMapActionAboveTemperature(
 		println("error initializing storage variable: " + err.Error())
 	}
 
-	coordinates, err := getCoordinatesOfCity(optionJSON.City)
+	weatherOfSpecifiedCity, err := service.pollWeatherOfCity(ctx, area, optionJSON.City)
 	if err != nil {
-		fmt.Println(err)
-	}
-	weatherOfSpecifiedCity, err := getWeatherOfCoordinate(coordinates)
-	if err != nil {
-		println("error get actual temperature info" + err.Error())
+		return nil, fmt.Errorf("error get actual temperature info: %w", err)
 	} else {
 		if int64(math.Round(weatherOfSpecifiedCity.Main.Temp)) > optionJSON.Temperature {
 			if variableDatabaseStorage == schemas.OpenWeatherMapStorageVariableFalse {
@@ -665,52 +680,47 @@ func (service *openWeatherMapService) OpenWeatherMapActionAboveTemperature(
 					weatherOfSpecifiedCity.Main.Temp,
 				) + "°C"
 				println(response)
-				channel <- response
 				variableDatabaseStorage = schemas.OpenWeatherMapStorageVariableTrue
 				area.StorageVariable, err = json.Marshal(variableDatabaseStorage)
 				if err != nil {
-					println("error marshalling storage variable: " + err.Error())
-					return
+					return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 				}
-				err = service.areaRepository.Update(area)
+				err = service.areaRepository.UpdateStorageVariable(area)
 				if err != nil {
-					println("error updating area: " + err.Error())
-					return
+					return nil, fmt.Errorf("error updating area: %w", err)
 				}
+				return newTrigger(response, schemas.OpenWeatherMapTemperaturePayload{
+					City:        optionJSON.City,
+					Temperature: weatherOfSpecifiedCity.Main.Temp,
+				})
 			}
 		} else {
 			if variableDatabaseStorage == schemas.OpenWeatherMapStorageVariableTrue {
 				variableDatabaseStorage = schemas.OpenWeatherMapStorageVariableFalse
 				area.StorageVariable, err = json.Marshal(variableDatabaseStorage)
 				if err != nil {
-					println("error marshalling storage variable: " + err.Error())
-					return
+					return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 				}
-				err = service.areaRepository.Update(area)
+				err = service.areaRepository.UpdateStorageVariable(area)
 				if err != nil {
-					println("error updating area: " + err.Error())
-					return
+					return nil, fmt.Errorf("error updating area: %w", err)
 				}
 			}
 		}
 	}
 
-	if (area.Action.MinimumRefreshRate) > area.ActionRefreshRate {
-		time.Sleep(time.Second * time.Duration(area.Action.MinimumRefreshRate))
-	} else {
-		time.Sleep(time.Second * time.Duration(area.ActionRefreshRate))
-	}
+	return nil, nil
 }
 
 // OpenWeatherMapActionBelowTemperature checks if the temperature of a specified city
 // is below a given threshold and updates the area storage variable accordingly.
-// If the temperature is below the threshold and the storage variable is false, it sends
-// a message to the provided channel and updates the storage variable to true.
+// If the temperature is below the threshold and the storage variable is false, it updates
+// the storage variable to true and returns a trigger.
 // If the temperature is above the threshold and the storage variable is true, it updates
 // the storage variable to false.
 //
 // Parameters:
-//   - channel: A channel to send the response message if the temperature is below the threshold.
+//   - ctx: The context of the check, cancelling the OpenWeatherMap requests when done.
 //   - option: A JSON raw message containing the city and temperature threshold.
 //   - area: The area schema containing the storage variable and action refresh rates.
 //
@@ -720,19 +730,16 @@ func (service *openWeatherMapService) OpenWeatherMapActionAboveTemperature(
 //  3. Retrieves the coordinates of the specified city.
 //  4. Gets the weather information for the city's coordinates.
 //  5. Checks if the temperature is below the threshold and updates the storage variable and area accordingly.
-//  6. Sleeps for the minimum refresh rate or action refresh rate of the area.
 func (service *openWeatherMapService) OpenWeatherMapActionBelowTemperature(
-	channel chan string,
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) {
+) ([]schemas.ActionResult, error) {
 	optionJSON := schemas.OpenWeatherMapActionSpecificTemperature{}
 
 	err := json.Unmarshal([]byte(option), &optionJSON)
 	if err != nil {
-		println("error unmarshal temperature option: " + err.Error())
-		time.Sleep(time.Second)
-		return
+		return nil, fmt.Errorf("error unmarshal temperature option: %w", err)
 	}
 
 	variableDatabaseStorage, err := initializedOpenWeatherMapStorageVariable(area, *service)
@@ -740,13 +747,9 @@ func (service *openWeatherMapService) OpenWeatherMapActionBelowTemperature(
 		println("error initializing storage variable: " + err.Error())
 	}
 
-	coordinates, err := getCoordinatesOfCity(optionJSON.City)
+	weatherOfSpecifiedCity, err := service.pollWeatherOfCity(ctx, area, optionJSON.City)
 	if err != nil {
-		fmt.Println(err)
-	}
-	weatherOfSpecifiedCity, err := getWeatherOfCoordinate(coordinates)
-	if err != nil {
-		println("error get actual temperature info" + err.Error())
+		return nil, fmt.Errorf("error get actual temperature info: %w", err)
 	} else {
 		if int64(math.Round(weatherOfSpecifiedCity.Main.Temp)) < optionJSON.Temperature {
 			if variableDatabaseStorage == schemas.OpenWeatherMapStorageVariableFalse {
@@ -755,125 +758,126 @@ func (service *openWeatherMapService) OpenWeatherMapActionBelowTemperature(
 					weatherOfSpecifiedCity.Main.Temp,
 				) + "°C"
 				println(response)
-				channel <- response
 				variableDatabaseStorage = schemas.OpenWeatherMapStorageVariableTrue
 				area.StorageVariable, err = json.Marshal(variableDatabaseStorage)
 				if err != nil {
-					println("error marshalling storage variable: " + err.Error())
-					return
+					return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 				}
-				err = service.areaRepository.Update(area)
+				err = service.areaRepository.UpdateStorageVariable(area)
 				if err != nil {
-					println("error updating area: " + err.Error())
-					return
+					return nil, fmt.Errorf("error updating area: %w", err)
 				}
+				return newTrigger(response, schemas.OpenWeatherMapTemperaturePayload{
+					City:        optionJSON.City,
+					Temperature: weatherOfSpecifiedCity.Main.Temp,
+				})
 			}
 		} else {
 			if variableDatabaseStorage == schemas.OpenWeatherMapStorageVariableTrue {
 				variableDatabaseStorage = schemas.OpenWeatherMapStorageVariableFalse
 				area.StorageVariable, err = json.Marshal(variableDatabaseStorage)
 				if err != nil {
-					println("error marshalling storage variable: " + err.Error())
-					return
+					return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 				}
-				err = service.areaRepository.Update(area)
+				err = service.areaRepository.UpdateStorageVariable(area)
 				if err != nil {
-					println("error updating area: " + err.Error())
-					return
+					return nil, fmt.Errorf("error updating area: %w", err)
 				}
 			}
 		}
 	}
 
-	if (area.Action.MinimumRefreshRate) > area.ActionRefreshRate {
-		time.Sleep(time.Second * time.Duration(area.Action.MinimumRefreshRate))
-	} else {
-		time.Sleep(time.Second * time.Duration(area.ActionRefreshRate))
-	}
+	return nil, nil
 }
 
 // Reactions functions
 
 // OpenWeatherMapReactionCurrentWeather retrieves the current weather for a specified city
-// using the OpenWeatherMap API and returns a string describing the weather.
+// using the OpenWeatherMap API and returns a result describing the weather.
 //
 // Parameters:
+//   - ctx: the context of the reaction, cancelling the OpenWeatherMap requests when done.
 //   - option: a JSON-encoded raw message containing the city name.
 //   - area: a schemas.Area object (not used in the function).
 //
 // Returns:
 //
-//	A string describing the current weather in the specified city, or an error message
+//	The result describing the current weather in the specified city, with the city and the
+//	weather as its payload, or an error
 //	if there was an issue with unmarshalling the option or retrieving the weather information.
 func (service *openWeatherMapService) OpenWeatherMapReactionCurrentWeather(
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) string {
+) (schemas.ReactionResult, error) {
 	optionJSON := schemas.OpenWeatherMapReactionOption{}
 
 	err := json.Unmarshal([]byte(option), &optionJSON)
 	if err != nil {
-		println("error unmarshal weather option: " + err.Error())
-		time.Sleep(time.Second)
-		return "error unmarshal weather option: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error unmarshal weather option: %w", err)
 	}
 
-	coordinates, err := getCoordinatesOfCity(optionJSON.City)
+	coordinates, err := service.getCoordinatesOfCity(ctx, optionJSON.City)
 	if err != nil {
-		fmt.Println(err)
+		return schemas.ReactionResult{}, fmt.Errorf("error get coordinates of city: %w", err)
 	}
 
-	weatherOfSpecifiedCity, err := getWeatherOfCoordinate(coordinates)
+	weatherOfSpecifiedCity, err := service.getWeatherOfCoordinate(ctx, coordinates)
 	if err != nil {
-		println("error get actual weather info" + err.Error())
-		return "error get actual weather info" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error get actual weather info: %w", err)
 	} else {
 		response := "current weather in " + optionJSON.City + " is " + string(weatherOfSpecifiedCity.Weather[0].Main)
 		println(response)
-		return response
+		return newReactionResult(response, schemas.OpenWeatherMapWeatherPayload{
+			City:    optionJSON.City,
+			Weather: weatherOfSpecifiedCity.Weather[0].Main,
+		})
 	}
 }
 
 // OpenWeatherMapReactionCurrentTemperature retrieves the current temperature for a specified city
-// using the OpenWeatherMap API and returns a formatted string with the temperature information.
+// using the OpenWeatherMap API and returns a result with the temperature information.
 //
 // Parameters:
+//   - ctx: The context of the reaction, cancelling the OpenWeatherMap requests when done.
 //   - option: A JSON raw message containing the city information.
 //   - area: An area schema (not used in the current implementation).
 //
 // Returns:
 //
-//	A string containing the current temperature in the specified city or an error message if any error occurs during the process.
+//	The result containing the current temperature in the specified city, with the city and the
+//	temperature as its payload, or an error if any error occurs during the process.
 //
 // The function performs the following steps:
 //  1. Unmarshals the JSON option to extract the city information.
 //  2. Retrieves the coordinates of the specified city.
 //  3. Fetches the weather information for the retrieved coordinates.
-//  4. Formats and returns the current temperature information or an error message if any step fails.
+//  4. Formats and returns the current temperature information or an error if any step fails.
 func (service *openWeatherMapService) OpenWeatherMapReactionCurrentTemperature(
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) string {
+) (schemas.ReactionResult, error) {
 	optionJSON := schemas.OpenWeatherMapReactionOption{}
 
 	err := json.Unmarshal([]byte(option), &optionJSON)
 	if err != nil {
-		println("error unmarshal temperature option: " + err.Error())
-		time.Sleep(time.Second)
-		return "error unmarshal temperature option: " + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error unmarshal temperature option: %w", err)
 	}
-	coordinates, err := getCoordinatesOfCity(optionJSON.City)
+	coordinates, err := service.getCoordinatesOfCity(ctx, optionJSON.City)
 	if err != nil {
-		fmt.Println(err)
+		return schemas.ReactionResult{}, fmt.Errorf("error get coordinates of city: %w", err)
 	}
-	weatherOfSpecifiedCity, err := getWeatherOfCoordinate(coordinates)
+	weatherOfSpecifiedCity, err := service.getWeatherOfCoordinate(ctx, coordinates)
 	if err != nil {
-		println("error get actual temperature info" + err.Error())
-		return "error get actual temperature info" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error get actual temperature info: %w", err)
 	} else {
 		response := "current temperature in " + optionJSON.City + " is " + fmt.Sprintf("%f", weatherOfSpecifiedCity.Main.Temp) + "°C"
 		println(response)
-		return response
+		return newReactionResult(response, schemas.OpenWeatherMapTemperaturePayload{
+			City:        optionJSON.City,
+			Temperature: weatherOfSpecifiedCity.Main.Temp,
+		})
 		// TODO: save to database
 	}
 }
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"area/schemas"
+)
+
+// PollCache defines the interface of the cache shared by the actions of the areas polling the
+// providers. The areas polling the same resource of a provider with the same credential reuse
+// a single fetch per refresh window instead of fetching it each, while every area keeps its
+// own cursor in its storage variable.
+type PollCache interface {
+	// Fetch returns the value fetched for the key at most maxAge ago, calling fetch otherwise.
+	Fetch(
+		ctx context.Context,
+		key schemas.PollKey,
+		maxAge time.Duration,
+		fetch func(ctx context.Context) (any, error),
+	) (any, error)
+}
+
+// pollEntry is a fetch of the poll cache.
+//
+// Fields:
+//   - done: Closed once the fetch is over.
+//   - value: The value fetched.
+//   - err: The error of the fetch.
+//   - fetchedAt: The start of the fetch.
+type pollEntry struct {
+	done      chan struct{}
+	value     any
+	err       error
+	fetchedAt time.Time
+}
+
+// pollCache is the implementation of PollCache.
+//
+// Fields:
+//   - mutex: Guards entries.
+//   - entries: The fetches by key, in progress or over.
+type pollCache struct {
+	mutex   sync.Mutex
+	entries map[schemas.PollKey]*pollEntry
+}
+
+// NewPollCache creates the poll cache shared by the services. A single instance must be shared
+// by all the services, so that the areas of all the users share their fetches.
+//
+// Returns:
+//   - PollCache: a new instance of PollCache.
+func NewPollCache() PollCache {
+	return &pollCache{
+		entries: make(map[schemas.PollKey]*pollEntry),
+	}
+}
+
+// Fetch returns the value fetched for the key if the fetch started at most maxAge ago, or
+// calls fetch to get a new one. The areas whose refresh rate is maxAge get data as fresh as
+// when they fetched it themselves. A fetch in progress for the key is waited for rather than
+// done twice, and a failed fetch is not kept. The value is shared by all the callers and must
+// not be modified.
+//
+// Parameters:
+//   - ctx: The context of the caller, bounding the wait and the fetch.
+//   - key: The provider, resource and credential of the fetch.
+//   - maxAge: The longest time since the start of a fetch whose value is reused.
+//   - fetch: The function fetching the value.
+//
+// Returns:
+//   - any: The value fetched for the key.
+//   - error: The error of the fetch.
+func (cache *pollCache) Fetch(
+	ctx context.Context,
+	key schemas.PollKey,
+	maxAge time.Duration,
+	fetch func(ctx context.Context) (any, error),
+) (any, error) {
+	key.Credential = hashCredential(key.Credential)
+	for {
+		cache.mutex.Lock()
+		entry, ok := cache.entries[key]
+		if ok {
+			select {
+			case <-entry.done:
+				if time.Since(entry.fetchedAt) < maxAge {
+					cache.mutex.Unlock()
+					return entry.value, nil
+				}
+			default:
+				cache.mutex.Unlock()
+				select {
+				case <-ctx.Done():
+					return nil, ctx.Err()
+				case <-entry.done:
+				}
+				// the fetch of a caller that stopped waiting is done again for this one
+				if entry.err != nil && ctx.Err() == nil &&
+					(errors.Is(entry.err, context.Canceled) ||
+						errors.Is(entry.err, context.DeadlineExceeded)) {
+					continue
+				}
+				return entry.value, entry.err
+			}
+		}
+		entry = &pollEntry{done: make(chan struct{}), fetchedAt: time.Now()}
+		cache.entries[key] = entry
+		cache.prune(entry.fetchedAt)
+		cache.mutex.Unlock()
+
+		entry.value, entry.err = fetch(ctx)
+
+		cache.mutex.Lock()
+		if entry.err != nil && cache.entries[key] == entry {
+			delete(cache.entries, key)
+		}
+		close(entry.done)
+		cache.mutex.Unlock()
+		return entry.value, entry.err
+	}
+}
+
+// prune removes the fetches over for more than PollCacheRetention seconds.
+// The mutex must be held.
+func (cache *pollCache) prune(now time.Time) {
+	for key, entry := range cache.entries {
+		select {
+		case <-entry.done:
+			if now.Sub(entry.fetchedAt) > time.Second*schemas.PollCacheRetention {
+				delete(cache.entries, key)
+			}
+		default:
+		}
+	}
+}
+
+// cachedPoll fetches the value of the key through the poll cache for the area, reusing a
+// fetch started within the refresh interval of the area.
+//
+// Parameters:
+//   - ctx: The context of the check of the area.
+//   - cache: The poll cache shared by the services.
+//   - area: The area whose action polls the provider.
+//   - key: The provider, resource and credential of the fetch.
+//   - fetch: The function fetching the value.
+//
+// Returns:
+//   - T: The value fetched for the key, shared with the other areas.
+//   - error: The error of the fetch.
+func cachedPoll[T any](
+	ctx context.Context,
+	cache PollCache,
+	area schemas.Area,
+	key schemas.PollKey,
+	fetch func(ctx context.Context) (T, error),
+) (T, error) {
+	var zero T
+	value, err := cache.Fetch(
+		ctx,
+		key,
+		refreshInterval(area),
+		func(ctx context.Context) (any, error) { return fetch(ctx) },
+	)
+	if err != nil {
+		return zero, err
+	}
+	typed, ok := value.(T)
+	if !ok {
+		return zero, fmt.Errorf("unable to reuse poll of %s: unexpected %T", key.Resource, value)
+	}
+	return typed, nil
+}
+
+// conditionalPoll is the outcome of a conditional fetch kept in the poll cache.
+//
+// Fields:
+//   - value: The value fetched, the zero value when not modified.
+//   - validators: The validators of the response.
+//   - notModified: Whether the provider answered 304 Not Modified.
+type conditionalPoll[T any] struct {
+	value       T
+	validators  schemas.ConditionalState
+	notModified bool
+}
+
+// cachedConditionalPoll fetches the value of the key through the poll cache for the area with
+// a conditional request, sending the validators of the last response seen by the area. The
+// areas holding the same validators share the fetch, its 304 Not Modified response included.
+//
+// Parameters:
+//   - ctx: The context of the check of the area.
+//   - cache: The poll cache shared by the services.
+//   - area: The area whose action polls the provider.
+//   - key: The provider, resource and credential of the fetch.
+//   - validators: The validators of the last response seen by the area, if any.
+//   - fetch: The function fetching the value with the validators, returning an error wrapping
+//     schemas.ErrNotModified when the provider answers 304 Not Modified.
+//
+// Returns:
+//   - T: The value fetched for the key, shared with the other areas.
+//   - schemas.ConditionalState: The validators to keep for the next poll of the area.
+//   - error: The error of the fetch, or schemas.ErrNotModified when the value did not change
+//     since the validators.
+func cachedConditionalPoll[T any](
+	ctx context.Context,
+	cache PollCache,
+	area schemas.Area,
+	key schemas.PollKey,
+	validators schemas.ConditionalState,
+	fetch func(
+		ctx context.Context,
+		validators schemas.ConditionalState,
+	) (T, schemas.ConditionalState, error),
+) (T, schemas.ConditionalState, error) {
+	key.Resource += "#" + validators.ETag + "#" + validators.LastModified
+	poll, err := cachedPoll(ctx, cache, area, key,
+		func(ctx context.Context) (conditionalPoll[T], error) {
+			value, newValidators, err := fetch(ctx, validators)
+			if errors.Is(err, schemas.ErrNotModified) {
+				return conditionalPoll[T]{validators: validators, notModified: true}, nil
+			}
+			return conditionalPoll[T]{value: value, validators: newValidators}, err
+		})
+	if err != nil {
+		return poll.value, validators, err
+	}
+	if poll.notModified {
+		return poll.value, validators, schemas.ErrNotModified
+	}
+	return poll.value, poll.validators, nil
+}
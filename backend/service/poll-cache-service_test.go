@@ -0,0 +1,91 @@
+package service_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/schemas"
+	"area/service"
+)
+
+func TestPollCacheSharesFetchWithinWindow(t *testing.T) {
+	cache := service.NewPollCache()
+	var fetches atomic.Int32
+	fetch := func(ctx context.Context) (any, error) {
+		fetches.Add(1)
+		return []string{"commit"}, nil
+	}
+	key := schemas.PollKey{
+		Provider:   schemas.Github,
+		Resource:   "repos/a/b/commits",
+		Credential: "token",
+	}
+
+	for range 10 {
+		value, err := cache.Fetch(context.Background(), key, time.Minute, fetch)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"commit"}, value)
+	}
+	assert.Equal(t, int32(1), fetches.Load())
+
+	// another credential or an expired fetch are fetched again
+	other := key
+	other.Credential = "other"
+	_, err := cache.Fetch(context.Background(), other, time.Minute, fetch)
+	assert.NoError(t, err)
+	_, err = cache.Fetch(context.Background(), key, 0, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, int32(3), fetches.Load())
+}
+
+func TestPollCacheWaitsForFetchInProgress(t *testing.T) {
+	cache := service.NewPollCache()
+	var fetches atomic.Int32
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) (any, error) {
+		fetches.Add(1)
+		<-release
+		return 42, nil
+	}
+	key := schemas.PollKey{Provider: schemas.OpenWeatherMap, Resource: "weather?q=Paris"}
+
+	var waitGroup sync.WaitGroup
+	for range 5 {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			value, err := cache.Fetch(context.Background(), key, time.Minute, fetch)
+			assert.NoError(t, err)
+			assert.Equal(t, 42, value)
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	waitGroup.Wait()
+	assert.Equal(t, int32(1), fetches.Load())
+}
+
+func TestPollCacheDoesNotKeepFailedFetch(t *testing.T) {
+	cache := service.NewPollCache()
+	var fetches atomic.Int32
+	fetch := func(ctx context.Context) (any, error) {
+		if fetches.Add(1) == 1 {
+			return nil, errors.New("unavailable")
+		}
+		return "weather", nil
+	}
+	key := schemas.PollKey{Provider: schemas.OpenWeatherMap, Resource: "weather?q=Paris"}
+
+	_, err := cache.Fetch(context.Background(), key, time.Minute, fetch)
+	assert.Error(t, err)
+	value, err := cache.Fetch(context.Background(), key, time.Minute, fetch)
+	assert.NoError(t, err)
+	assert.Equal(t, "weather", value)
+	assert.Equal(t, int32(2), fetches.Load())
+}
@@ -0,0 +1,367 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"area/schemas"
+)
+
+// ProviderClient defines the interface of the HTTP client shared by the services to send their
+// requests to the providers, such as GitHub, Spotify, Microsoft Graph or Dropbox.
+// It bounds every request with a timeout, follows the rate limits announced by the providers in
+// their response headers, sends the rate limited requests again after a backoff, and stops
+// sending requests to a provider that keeps failing with a circuit breaker.
+type ProviderClient interface {
+	// Do sends the request to the provider and returns its response, like http.Client.Do.
+	Do(provider schemas.ServiceName, req *http.Request) (*http.Response, error)
+
+	// GetMetrics returns the counters of the requests sent to every provider by this replica.
+	GetMetrics() []schemas.ProviderMetrics
+}
+
+// providerWideRateLimits are the providers whose rate limits apply to the application as a
+// whole rather than to the token of the request: Spotify counts the requests of all the users
+// of the application in a single rolling window.
+var providerWideRateLimits = map[schemas.ServiceName]bool{
+	schemas.Spotify: true,
+}
+
+// providerState is the state of the requests to a provider.
+//
+// Fields:
+//   - metrics: The counters of the requests to the provider.
+//   - latency: The total duration of the requests to the provider.
+//   - failures: The consecutive failures of the provider.
+//   - reopenAt: The end of the cooldown of the open circuit.
+//   - probing: Whether the probe request of the half-open circuit is being sent.
+//   - limitedUntil: The end of the rate limit of the whole provider.
+//   - tokens: The end of the rate limits of the tokens, by hash of the token.
+type providerState struct {
+	metrics      schemas.ProviderMetrics
+	latency      time.Duration
+	failures     int
+	reopenAt     time.Time
+	probing      bool
+	limitedUntil time.Time
+	tokens       map[string]time.Time
+}
+
+// providerClient is the implementation of ProviderClient.
+//
+// Fields:
+//   - client: The HTTP client sending the requests.
+//   - mutex: Guards providers.
+//   - providers: The state of the requests to every provider, by name of the provider.
+type providerClient struct {
+	client    *http.Client
+	mutex     sync.Mutex
+	providers map[schemas.ServiceName]*providerState
+}
+
+// NewProviderClient creates the HTTP client shared by the services, whose requests time out
+// after ProviderTimeout seconds. A single instance must be shared by all the services, so that
+// the rate limits and the circuits of the providers apply to all their requests.
+//
+// Returns:
+//   - ProviderClient: a new instance of ProviderClient.
+func NewProviderClient() ProviderClient {
+	return &providerClient{
+		client:    &http.Client{Timeout: time.Second * schemas.ProviderTimeout},
+		providers: make(map[schemas.ServiceName]*providerState),
+	}
+}
+
+// state returns the state of the provider, creating it on its first request.
+// The mutex must be held.
+func (client *providerClient) state(provider schemas.ServiceName) *providerState {
+	state, ok := client.providers[provider]
+	if !ok {
+		state = &providerState{
+			metrics: schemas.ProviderMetrics{Provider: provider, Circuit: schemas.CircuitClosed},
+			tokens:  make(map[string]time.Time),
+		}
+		client.providers[provider] = state
+	}
+	return state
+}
+
+// hashCredential returns a hash of the credential, so that the credentials are not kept in
+// memory as keys, or "" for an empty credential.
+func hashCredential(credential string) string {
+	if credential == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(credential))
+	return hex.EncodeToString(sum[:16])
+}
+
+// tokenKey returns the key of the rate limits of the token of the request, a hash of its
+// Authorization header, or "" without header.
+func tokenKey(req *http.Request) string {
+	return hashCredential(req.Header.Get("Authorization"))
+}
+
+// Do sends the request to the provider once its rate limit, if any, ended and its circuit
+// lets it through. A 429 response is followed by up to ProviderMaxRetries new attempts,
+// after the wait asked by the provider or an exponential backoff, when the body of the request
+// can be sent again and the wait is at most ProviderMaxWait seconds. Otherwise the 429
+// response is returned as is.
+//
+// Parameters:
+//   - provider: The name of the provider the request is sent to.
+//   - req: The request, whose context bounds the waits and the attempts.
+//
+// Returns:
+//   - *http.Response: The response of the provider, whose body must be closed.
+//   - error: The error of the request, wrapping schemas.ErrRateLimited when the rate limit
+//     of the provider ends after ProviderMaxWait seconds, or schemas.ErrCircuitOpen when the
+//     provider keeps failing. Both wrap schemas.ErrTransient.
+func (client *providerClient) Do(
+	provider schemas.ServiceName,
+	req *http.Request,
+) (*http.Response, error) {
+	key := tokenKey(req)
+	for attempt := 0; ; attempt++ {
+		err := client.acquire(req.Context(), provider, key)
+		if err != nil {
+			return nil, err
+		}
+		startedAt := time.Now()
+		resp, err := client.client.Do(req)
+		client.record(req.Context(), provider, key, resp, err, time.Since(startedAt))
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests || attempt >= schemas.ProviderMaxRetries {
+			return resp, nil
+		}
+		end := rateLimitEnd(resp.Header, time.Now())
+		if time.Until(end) > time.Second*schemas.ProviderMaxWait {
+			return resp, nil
+		}
+		next, err := replayRequest(req)
+		if err != nil {
+			return resp, nil
+		}
+		if end.IsZero() {
+			// the provider did not tell when to retry
+			client.limit(provider, key, time.Now().Add(time.Second<<attempt))
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		client.mutex.Lock()
+		client.state(provider).metrics.Retries++
+		client.mutex.Unlock()
+		req = next
+	}
+}
+
+// replayRequest returns a copy of the request to send it again, with a new body.
+func replayRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.Body == nil || req.Body == http.NoBody {
+		return next, nil
+	}
+	if req.GetBody == nil {
+		return nil, errors.New("request body can not be sent again")
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, err
+	}
+	next.Body = body
+	return next, nil
+}
+
+// acquire waits for the end of the rate limit of the provider and of the token, then takes the
+// permission of the circuit of the provider to send a request.
+func (client *providerClient) acquire(
+	ctx context.Context,
+	provider schemas.ServiceName,
+	key string,
+) error {
+	client.mutex.Lock()
+	state := client.state(provider)
+	until := state.limitedUntil
+	if tokenUntil := state.tokens[key]; key != "" && tokenUntil.After(until) {
+		until = tokenUntil
+	}
+	wait := time.Until(until)
+	if wait > 0 {
+		state.metrics.Throttled++
+	}
+	client.mutex.Unlock()
+
+	if wait > time.Second*schemas.ProviderMaxWait {
+		return fmt.Errorf("%w until %s: %w",
+			schemas.ErrRateLimited, until.Format(time.RFC3339), schemas.ErrTransient)
+	}
+	if wait > 0 && !waitRetry(ctx, wait) {
+		return ctx.Err()
+	}
+
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	switch state.metrics.Circuit {
+	case schemas.CircuitOpen:
+		if time.Now().Before(state.reopenAt) {
+			state.metrics.Rejected++
+			return circuitOpenError(provider)
+		}
+		state.metrics.Circuit = schemas.CircuitHalfOpen
+		state.probing = true
+	case schemas.CircuitHalfOpen:
+		if state.probing {
+			state.metrics.Rejected++
+			return circuitOpenError(provider)
+		}
+		state.probing = true
+	}
+	return nil
+}
+
+// circuitOpenError returns the error of a request refused by the open circuit of the provider.
+func circuitOpenError(provider schemas.ServiceName) error {
+	return fmt.Errorf("%w for %s: %w", schemas.ErrCircuitOpen, provider, schemas.ErrTransient)
+}
+
+// record counts the request in the metrics of the provider, updates its circuit with the
+// outcome of the request, and its rate limits with the headers of the response.
+// A request cancelled by its own context is not a failure of the provider.
+func (client *providerClient) record(
+	ctx context.Context,
+	provider schemas.ServiceName,
+	key string,
+	resp *http.Response,
+	err error,
+	latency time.Duration,
+) {
+	client.mutex.Lock()
+	state := client.state(provider)
+	state.metrics.Requests++
+	state.latency += latency
+	state.probing = false
+	failed := (err != nil && ctx.Err() == nil) ||
+		(resp != nil && resp.StatusCode >= http.StatusInternalServerError)
+	if failed {
+		state.metrics.Failures++
+		state.failures++
+		if state.metrics.Circuit == schemas.CircuitHalfOpen ||
+			state.failures >= schemas.ProviderFailures {
+			state.metrics.Circuit = schemas.CircuitOpen
+			state.reopenAt = time.Now().Add(time.Second * schemas.ProviderCooldown)
+		}
+	} else if err == nil {
+		state.failures = 0
+		state.metrics.Circuit = schemas.CircuitClosed
+	}
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		state.metrics.RateLimited++
+	}
+	client.mutex.Unlock()
+
+	if resp != nil {
+		until := rateLimitEnd(resp.Header, time.Now())
+		if !until.IsZero() {
+			client.limit(provider, key, until)
+		}
+	}
+}
+
+// limit records that the provider refuses the requests of the token until the given time,
+// or the requests of all the tokens when the provider limits the application as a whole or
+// the request had no token.
+func (client *providerClient) limit(provider schemas.ServiceName, key string, until time.Time) {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	state := client.state(provider)
+	if key == "" || providerWideRateLimits[provider] {
+		if until.After(state.limitedUntil) {
+			state.limitedUntil = until
+		}
+		return
+	}
+	now := time.Now()
+	for tokenKey, tokenUntil := range state.tokens {
+		if tokenUntil.Before(now) {
+			delete(state.tokens, tokenKey)
+		}
+	}
+	if until.After(state.tokens[key]) {
+		state.tokens[key] = until
+	}
+}
+
+// rateLimitEnd returns the time until which the provider refuses the requests, read from the
+// Retry-After header, or from the X-RateLimit-Reset header when X-RateLimit-Remaining is 0.
+// The reset is either a Unix timestamp, as sent by GitHub, or a number of seconds.
+//
+// Parameters:
+//   - header: The headers of the response.
+//   - now: The time the delays are counted from.
+//
+// Returns:
+//   - time.Time: The end of the rate limit, or the zero time if the provider has none.
+func rateLimitEnd(header http.Header, now time.Time) time.Time {
+	if retryAfter := header.Get("Retry-After"); retryAfter != "" {
+		seconds, err := strconv.ParseInt(retryAfter, 10, 64)
+		if err == nil {
+			return now.Add(time.Duration(seconds) * time.Second)
+		}
+		date, err := http.ParseTime(retryAfter)
+		if err == nil {
+			return date
+		}
+	}
+	if header.Get("X-RateLimit-Remaining") != "0" {
+		return time.Time{}
+	}
+	reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64)
+	if err != nil {
+		return time.Time{}
+	}
+	// a number of seconds is far smaller than any recent Unix timestamp
+	if reset < 1_000_000_000 {
+		return now.Add(time.Duration(reset) * time.Second)
+	}
+	return time.Unix(reset, 0)
+}
+
+// GetMetrics returns the counters of the requests sent by this replica to every provider,
+// ordered by name of the provider. The counters are not shared between the replicas.
+//
+// Returns:
+//   - []schemas.ProviderMetrics: The counters of every provider requested since the start.
+func (client *providerClient) GetMetrics() []schemas.ProviderMetrics {
+	client.mutex.Lock()
+	defer client.mutex.Unlock()
+	metrics := make([]schemas.ProviderMetrics, 0, len(client.providers))
+	now := time.Now()
+	for _, state := range client.providers {
+		providerMetrics := state.metrics
+		if providerMetrics.Requests > 0 {
+			providerMetrics.AverageLatencyMs = float64(state.latency.Milliseconds()) /
+				float64(providerMetrics.Requests)
+		}
+		if state.limitedUntil.After(now) {
+			limitedUntil := state.limitedUntil
+			providerMetrics.RateLimitedUntil = &limitedUntil
+		}
+		metrics = append(metrics, providerMetrics)
+	}
+	slices.SortFunc(metrics, func(a, b schemas.ProviderMetrics) int {
+		return cmp.Compare(a.Provider, b.Provider)
+	})
+	return metrics
+}
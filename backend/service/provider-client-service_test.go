@@ -0,0 +1,140 @@
+package service_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/schemas"
+	"area/service"
+)
+
+func sendProviderRequest(
+	client service.ProviderClient,
+	provider schemas.ServiceName,
+	url string,
+	token string,
+) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	resp, err := client.Do(provider, req)
+	if err == nil {
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestProviderClientRetriesRateLimitedRequest(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := service.NewProviderClient()
+	resp, err := sendProviderRequest(client, schemas.Dropbox, server.URL, "token")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, int32(2), requests.Load())
+
+	metrics := client.GetMetrics()
+	assert.Len(t, metrics, 1)
+	assert.Equal(t, schemas.Dropbox, metrics[0].Provider)
+	assert.Equal(t, uint64(2), metrics[0].Requests)
+	assert.Equal(t, uint64(1), metrics[0].RateLimited)
+	assert.Equal(t, uint64(1), metrics[0].Retries)
+}
+
+func TestProviderClientFollowsTokenRateLimit(t *testing.T) {
+	var requests atomic.Int32
+	reset := strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.Header().Set("X-RateLimit-Reset", reset)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := service.NewProviderClient()
+	_, err := sendProviderRequest(client, schemas.Github, server.URL, "first")
+	assert.NoError(t, err)
+
+	// the rate limit of the first token does not apply to the second one
+	_, err = sendProviderRequest(client, schemas.Github, server.URL, "first")
+	assert.ErrorIs(t, err, schemas.ErrRateLimited)
+	assert.ErrorIs(t, err, schemas.ErrTransient)
+	_, err = sendProviderRequest(client, schemas.Github, server.URL, "second")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+	assert.Equal(t, uint64(1), client.GetMetrics()[0].Throttled)
+}
+
+func TestProviderClientFollowsProviderWideRateLimit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	client := service.NewProviderClient()
+	resp, err := sendProviderRequest(client, schemas.Spotify, server.URL, "first")
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+
+	_, err = sendProviderRequest(client, schemas.Spotify, server.URL, "second")
+	assert.ErrorIs(t, err, schemas.ErrRateLimited)
+	assert.Equal(t, int32(1), requests.Load())
+	assert.NotNil(t, client.GetMetrics()[0].RateLimitedUntil)
+}
+
+func TestProviderClientOpensCircuit(t *testing.T) {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	client := service.NewProviderClient()
+	for range schemas.ProviderFailures {
+		resp, err := sendProviderRequest(client, schemas.Microsoft, server.URL, "token")
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadGateway, resp.StatusCode)
+	}
+
+	_, err := sendProviderRequest(client, schemas.Microsoft, server.URL, "token")
+	assert.ErrorIs(t, err, schemas.ErrCircuitOpen)
+	assert.ErrorIs(t, err, schemas.ErrTransient)
+	assert.Equal(t, int32(schemas.ProviderFailures), requests.Load())
+
+	// the circuit of a provider does not stop the requests to the others
+	_, err = sendProviderRequest(client, schemas.Github, server.URL, "token")
+	assert.NoError(t, err)
+
+	metrics := client.GetMetrics()
+	assert.Equal(t, schemas.Github, metrics[0].Provider)
+	assert.Equal(t, schemas.CircuitClosed, metrics[0].Circuit)
+	assert.Equal(t, schemas.Microsoft, metrics[1].Provider)
+	assert.Equal(t, schemas.CircuitOpen, metrics[1].Circuit)
+	assert.Equal(t, uint64(schemas.ProviderFailures), metrics[1].Failures)
+	assert.Equal(t, uint64(1), metrics[1].Rejected)
+}
@@ -109,8 +109,9 @@ func (service *reactionService) GetAllServicesByServiceId(
 // checks if they implement the ServiceReaction interface, and if so, processes
 // their reactions. For each reaction, it attempts to find an existing reaction
 // by name in the repository. If no existing reaction is found, it saves the new
-// reaction to the repository. Errors encountered during these operations are
-// logged to the console.
+// reaction to the repository, otherwise it updates the option schema of the existing
+// reaction so that it follows the one declared by the service. Errors encountered
+// during these operations are logged to the console.
 func (service *reactionService) SaveAllReaction() {
 	for _, services := range service.serviceService.GetServices() {
 		if serviceReaction, ok := services.(ServiceReaction); ok {
@@ -125,6 +126,12 @@ func (service *reactionService) SaveAllReaction() {
 					if err != nil {
 						println("Error when save reaction")
 					}
+				} else if optionChanged(reactionByName[0].OptionSchema, reaction.OptionSchema) {
+					reactionByName[0].OptionSchema = reaction.OptionSchema
+					err = service.repository.Update(reactionByName[0])
+					if err != nil {
+						println("Error when update reaction")
+					}
 				}
 			}
 		} else {
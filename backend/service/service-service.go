@@ -1,9 +1,11 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"strings"
 
@@ -12,6 +14,129 @@ import (
 	"area/tools"
 )
 
+// ActionFunc checks an action once.
+// It returns the triggers detected since the previous check, which may be none.
+// The check must stop and return the context error when the context is cancelled.
+type ActionFunc func(
+	ctx context.Context,
+	option json.RawMessage,
+	area schemas.Area,
+) ([]schemas.ActionResult, error)
+
+// ReactionFunc runs a reaction once.
+// The reaction must stop and return the context error when the context is cancelled.
+type ReactionFunc func(
+	ctx context.Context,
+	option json.RawMessage,
+	area schemas.Area,
+) (schemas.ReactionResult, error)
+
+// newOptionSchema marshals the JSON Schema of the option of an action or a reaction.
+// An error is printed to the console and an empty schema returned if it can not be marshalled.
+//
+// Parameters:
+//   - schema: The JSON Schema of the option.
+//
+// Returns:
+//   - json.RawMessage: The marshalled JSON Schema.
+func newOptionSchema(schema schemas.OptionSchema) json.RawMessage {
+	optionSchema, err := json.Marshal(schema)
+	if err != nil {
+		println("error marshal option schema: " + err.Error())
+	}
+	return optionSchema
+}
+
+// newTrigger builds the result of an action check reporting one trigger.
+//
+// Parameters:
+//   - message: The description of the event that triggered the action.
+//   - payload: The structured description of the event, marshalled as a JSON object.
+//
+// Returns:
+//   - []schemas.ActionResult: The trigger reported by the action.
+//   - error: An error if the payload can not be marshalled.
+func newTrigger(message string, payload any) ([]schemas.ActionResult, error) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("unable to marshal trigger payload because %w", err)
+	}
+	return []schemas.ActionResult{{Message: message, Payload: payloadJSON}}, nil
+}
+
+// newReactionResult builds the result of a reaction with a typed output.
+//
+// Parameters:
+//   - message: The description of what the reaction did.
+//   - payload: The structured output of the reaction, marshalled as a JSON object.
+//
+// Returns:
+//   - schemas.ReactionResult: The result of the reaction.
+//   - error: An error if the payload can not be marshalled.
+func newReactionResult(message string, payload any) (schemas.ReactionResult, error) {
+	payloadJSON, err := json.Marshal(payload)
+	if err != nil {
+		return schemas.ReactionResult{}, fmt.Errorf(
+			"unable to marshal reaction payload because %w",
+			err,
+		)
+	}
+	return schemas.ReactionResult{Message: message, Payload: payloadJSON}, nil
+}
+
+// setConditionalHeaders asks the provider to answer 304 Not Modified to the request when the
+// resource did not change since the response whose validators are given.
+//
+// Parameters:
+//   - req: The request polling the resource.
+//   - validators: The validators of the last response of the provider, if any.
+func setConditionalHeaders(req *http.Request, validators schemas.ConditionalState) {
+	if validators.ETag != "" {
+		req.Header.Set("If-None-Match", validators.ETag)
+	}
+	if validators.LastModified != "" {
+		req.Header.Set("If-Modified-Since", validators.LastModified)
+	}
+}
+
+// responseValidators returns the validators of the response, to send back on the next poll.
+//
+// Parameters:
+//   - resp: The response of the provider.
+//
+// Returns:
+//   - schemas.ConditionalState: The ETag and Last-Modified headers of the response.
+func responseValidators(resp *http.Response) schemas.ConditionalState {
+	return schemas.ConditionalState{
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+	}
+}
+
+// statusError returns the error of a request that failed with the given status code.
+// The error wraps schemas.ErrTransient when the status reports that the service may accept
+// the same request later: request timeouts, rate limits and server errors.
+//
+// Parameters:
+//   - statusCode: The status code of the response.
+//   - err: The error describing the failed request.
+//
+// Returns:
+//   - error: The error of the request.
+func statusError(statusCode int, err error) error {
+	if statusCode == http.StatusRequestTimeout ||
+		statusCode == http.StatusTooManyRequests ||
+		statusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("%w: %w", err, schemas.ErrTransient)
+	}
+	return err
+}
+
+// RefreshTokenFunc exchanges a refresh token for a new access token.
+// The returned token has an empty refresh token when the service keeps the previous one.
+// It returns an error wrapping schemas.ErrRefreshTokenRejected when the service rejects the refresh token.
+type RefreshTokenFunc func(ctx context.Context, refreshToken string) (schemas.Token, error)
+
 // ServiceService defines the interface for service-related operations.
 type ServiceService interface {
 	// FindAll retrieves all services.
@@ -30,10 +155,10 @@ type ServiceService interface {
 	GetServicesInfo() (allService []schemas.Service, err error)
 
 	// FindActionByName retrieves an action function by its name.
-	FindActionByName(name string) func(c chan string, option json.RawMessage, area schemas.Area)
+	FindActionByName(name string) ActionFunc
 
 	// FindReactionByName retrieves a reaction function by its name.
-	FindReactionByName(name string) func(option json.RawMessage, area schemas.Area) string
+	FindReactionByName(name string) ReactionFunc
 
 	// FindServiceByName retrieves a service by its name.
 	FindServiceByName(name string) schemas.Service
@@ -68,23 +193,34 @@ type ServiceService interface {
 
 	// GetServiceById retrieves a service by its ID.
 	GetServiceById(serverId uint64) schemas.Service
+
+	// FindTokenRefresherByServiceId retrieves the function refreshing the tokens of a service.
+	// It returns nil if the tokens of the service do not expire.
+	FindTokenRefresherByServiceId(serviceId uint64) RefreshTokenFunc
 }
 
 // ServiceInterface defines the methods that a service must implement to interact with actions and reactions.
 // It includes methods to find actions and reactions by name, and to retrieve service information.
 type ServiceInterface interface {
-	// FindActionByName returns a function that processes an action based on its name.
-	// The returned function takes a channel to send strings, a JSON raw message for options, and an area schema.
-	FindActionByName(name string) func(c chan string, option json.RawMessage, area schemas.Area)
+	// FindActionByName returns the function checking the action with the given name, or nil.
+	FindActionByName(name string) ActionFunc
 
-	// FindReactionByName returns a function that processes a reaction based on its name.
-	// The returned function takes a JSON raw message for options and an area schema, and returns a string.
-	FindReactionByName(name string) func(option json.RawMessage, area schemas.Area) string
+	// FindReactionByName returns the function running the reaction with the given name, or nil.
+	FindReactionByName(name string) ReactionFunc
 
 	// GetServiceInfo returns the service information as a Service schema.
 	GetServiceInfo() schemas.Service
 }
 
+// TokenRefresher is implemented by the services whose OAuth tokens expire.
+type TokenRefresher interface {
+	// RefreshServiceAccessToken exchanges the refresh token of a user for a new access token.
+	RefreshServiceAccessToken(
+		ctx context.Context,
+		refreshToken string,
+	) (token schemas.Token, err error)
+}
+
 // serviceService is a struct that provides services related to the ServiceRepository.
 // It contains a repository field which is an instance of ServiceRepository and an allService
 // field which is a slice of interfaces representing all services.
@@ -106,6 +242,7 @@ type serviceService struct {
 //   - dropboxService: an instance of DropboxService for Dropbox-related operations.
 //   - microsoftService: an instance of MicrosoftService for Microsoft-related operations.
 //   - openWeatherMapService: an instance of OpenWeatherMapService for weather-related operations.
+//   - webhookService: an instance of WebhookService for inbound webhook operations.
 //
 // Returns:
 //   - ServiceService: a new instance of ServiceService initialized with the provided dependencies.
@@ -118,6 +255,7 @@ func NewServiceService(
 	dropboxService DropboxService,
 	microsoftService MicrosoftService,
 	openWeatherMapService OpenWeatherMapService,
+	webhookService WebhookService,
 ) ServiceService {
 	newService := serviceService{
 		repository: repository,
@@ -129,6 +267,7 @@ func NewServiceService(
 			dropboxService,
 			microsoftService,
 			openWeatherMapService,
+			webhookService,
 		},
 	}
 	newService.InitialSaveService()
@@ -197,6 +336,45 @@ func getRedirectURI(
 	return host + "/services/" + strings.ToLower(string(serviceName)), nil
 }
 
+// getWebhookURL constructs the URL of the webhooks of a service, under which the backend
+// receives their deliveries. The ID of a webhook is appended to it as its last path segment.
+//
+// Environment Variables:
+// - BACKEND_PORT: The port number of the backend.
+// - BACKEND_EXTERNAL_HOST: The external host address of the backend, reachable by the services.
+// - IS_PRODUCTION: A flag indicating whether the environment is production.
+//
+// Parameters:
+// - serviceName: The name of the service sending the deliveries.
+//
+// Returns:
+// - webhookURL: The URL of the webhooks of the service, without trailing slash.
+// - err: An error if any required environment variable is not set.
+func getWebhookURL(serviceName schemas.ServiceName) (webhookURL string, err error) {
+	backendPort := os.Getenv("BACKEND_PORT")
+	if backendPort == "" {
+		return "", schemas.ErrBackendPortNotSet
+	}
+	backendExternalHost := os.Getenv("BACKEND_EXTERNAL_HOST")
+	if backendExternalHost == "" {
+		return "", schemas.ErrBackendExternalHostNotSet
+	}
+
+	isProd := os.Getenv("IS_PRODUCTION")
+	if isProd == "" {
+		return "", schemas.ErrIsProductionNotSet
+	}
+
+	host := ""
+	if isProd == "true" {
+		host = "https://" + backendExternalHost
+	} else {
+		host = "http://" + backendExternalHost + ":" + backendPort
+	}
+
+	return host + "/api/v1/" + strings.ToLower(string(serviceName)) + "/webhook", nil
+}
+
 // RedirectToServiceOauthPage generates an OAuth authorization URL for the specified service.
 //
 // Parameters:
@@ -529,18 +707,17 @@ func (service *serviceService) GetServices() []interface{} {
 }
 
 // FindActionByName searches for an action by its name within the service's list of all services.
-// It returns a function that takes a channel, an option in the form of json.RawMessage, and an area of type schemas.Area.
+// It returns the ActionFunc checking the action.
 // If no action is found with the given name, it returns nil.
 //
 // Parameters:
 //   - name: The name of the action to search for.
 //
 // Returns:
-//   - A function that takes a channel (chan string), an option (json.RawMessage), and an area (schemas.Area),
-//     or nil if no action with the specified name is found.
+//   - The ActionFunc of the action, or nil if no action with the specified name is found.
 func (service *serviceService) FindActionByName(
 	name string,
-) func(c chan string, option json.RawMessage, area schemas.Area) {
+) ActionFunc {
 	for _, service := range service.allService {
 		if service.(ServiceInterface).FindActionByName(name) != nil {
 			return service.(ServiceInterface).FindActionByName(name)
@@ -550,18 +727,18 @@ func (service *serviceService) FindActionByName(
 }
 
 // FindReactionByName searches for a reaction by its name within the service's list of all services.
-// If a matching reaction is found, it returns a function that takes a JSON raw message and an area schema,
-// and returns a string. If no matching reaction is found, it returns nil.
+// If a matching reaction is found, it returns the ReactionFunc running it.
+// If no matching reaction is found, it returns nil.
 //
 // Parameters:
 //   - name: The name of the reaction to search for.
 //
 // Returns:
-//   - A function that takes a JSON raw message and an area schema, and returns a string, if a matching reaction is found.
+//   - The ReactionFunc of the reaction, if a matching reaction is found.
 //   - nil if no matching reaction is found.
 func (service *serviceService) FindReactionByName(
 	name string,
-) func(option json.RawMessage, area schemas.Area) string {
+) ReactionFunc {
 	for _, service := range service.allService {
 		if service.(ServiceInterface).FindReactionByName(name) != nil {
 			return service.(ServiceInterface).FindReactionByName(name)
@@ -591,3 +768,30 @@ func (service *serviceService) GetServiceById(id uint64) schemas.Service {
 	}
 	return foundService
 }
+
+// FindTokenRefresherByServiceId searches for the service with the given ID within the service's
+// list of all services, and returns the function refreshing its tokens.
+// If the service is unknown or its tokens do not expire, it returns nil.
+//
+// Parameters:
+//   - serviceId: The ID of the service whose tokens are refreshed.
+//
+// Returns:
+//   - The RefreshTokenFunc of the service, or nil if its tokens can not be refreshed.
+func (service *serviceService) FindTokenRefresherByServiceId(serviceId uint64) RefreshTokenFunc {
+	foundService, err := service.repository.FindById(serviceId)
+	if err != nil {
+		return nil
+	}
+	for _, oneService := range service.allService {
+		if oneService.(ServiceInterface).GetServiceInfo().Name != foundService.Name {
+			continue
+		}
+		refresher, ok := oneService.(TokenRefresher)
+		if !ok {
+			return nil
+		}
+		return refresher.RefreshServiceAccessToken
+	}
+	return nil
+}
@@ -20,18 +20,34 @@ import (
 
 type SpotifyService interface {
 	// Service interface functions
-	FindActionByName(name string) func(c chan string, option json.RawMessage, area schemas.Area)
-	FindReactionByName(name string) func(option json.RawMessage, area schemas.Area) string
+	FindActionByName(name string) ActionFunc
+	FindReactionByName(name string) ReactionFunc
 	GetServiceActionInfo() []schemas.Action
 	GetServiceReactionInfo() []schemas.Reaction
 	// Service specific functions
 	AuthGetServiceAccessToken(code string) (token schemas.Token, err error)
+	RefreshServiceAccessToken(
+		ctx context.Context,
+		refreshToken string,
+	) (token schemas.Token, err error)
 	GetUserInfo(accessToken string) (user schemas.User, err error)
 	// Actions functions
-	SpotifyActionMusicPlayed(c chan string, option json.RawMessage, area schemas.Area)
+	SpotifyActionMusicPlayed(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) ([]schemas.ActionResult, error)
 	// Reactions functions
-	SpotifyReactionSkipNextMusic(option json.RawMessage, area schemas.Area) string
-	SpotifyReactionSkipPreviousMusic(option json.RawMessage, area schemas.Area) string
+	SpotifyReactionSkipNextMusic(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) (schemas.ReactionResult, error)
+	SpotifyReactionSkipPreviousMusic(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) (schemas.ReactionResult, error)
 }
 
 type spotifyService struct {
@@ -39,6 +55,8 @@ type spotifyService struct {
 	serviceRepository repository.ServiceRepository // This is a repository for the service
 	areaRepository    repository.AreaRepository    // This is a repository for the area
 	tokenRepository   repository.TokenRepository   // This is a repository for the token
+	providerClient    ProviderClient               // This is the shared HTTP client of the providers
+	pollCache         PollCache                    // This is the cache of the polls shared by the areas
 	serviceInfo       schemas.Service              // This is the service information
 }
 
@@ -50,6 +68,8 @@ type spotifyService struct {
 //   - serviceRepository: repository.ServiceRepository - Repository for handling service-related operations.
 //   - areaRepository: repository.AreaRepository - Repository for handling area-related operations.
 //   - tokenRepository: repository.TokenRepository - Repository for handling general token operations.
+//   - providerClient: ProviderClient - Shared HTTP client sending the requests to Spotify.
+//   - pollCache: PollCache - Cache of the polls of Spotify shared by the areas.
 //
 // Returns:
 //   - SpotifyService: A new instance of SpotifyService.
@@ -58,12 +78,16 @@ func NewSpotifyService(
 	serviceRepository repository.ServiceRepository,
 	areaRepository repository.AreaRepository,
 	tokenRepository repository.TokenRepository,
+	providerClient ProviderClient,
+	pollCache PollCache,
 ) SpotifyService {
 	return &spotifyService{
 		repository:        githubTokenRepository,
 		serviceRepository: serviceRepository,
 		areaRepository:    areaRepository,
 		tokenRepository:   tokenRepository,
+		providerClient:    providerClient,
+		pollCache:         pollCache,
 		serviceInfo: schemas.Service{
 			Name:        schemas.Spotify,
 			Description: "This service is a music service",
@@ -87,7 +111,7 @@ func (service *spotifyService) GetServiceInfo() schemas.Service {
 }
 
 // FindActionByName returns a function that matches the given action name.
-// The returned function takes a channel, a JSON raw message, and an area schema as parameters.
+// The returned ActionFunc takes a context, a JSON raw message, and an area schema as parameters.
 // If the action name matches a predefined action, the corresponding function is returned.
 // If the action name does not match any predefined actions, nil is returned.
 //
@@ -98,7 +122,7 @@ func (service *spotifyService) GetServiceInfo() schemas.Service {
 // - A function that matches the given action name, or nil if no match is found.
 func (service *spotifyService) FindActionByName(
 	name string,
-) func(c chan string, option json.RawMessage, area schemas.Area) {
+) ActionFunc {
 	switch name {
 	case string(schemas.MusicPlayed):
 		return service.SpotifyActionMusicPlayed
@@ -108,19 +132,18 @@ func (service *spotifyService) FindActionByName(
 }
 
 // FindReactionByName returns a function that performs a specific Spotify reaction
-// based on the provided name. The returned function takes a JSON raw message and
-// an area schema as parameters and returns a string.
+// based on the provided name. The returned ReactionFunc takes a context, a JSON
+// raw message and an area schema as parameters.
 //
 // Parameters:
 //   - name: The name of the reaction to find.
 //
 // Returns:
-//   - A function that takes a JSON raw message and an area schema as parameters
-//     and returns a string. If the name does not match any known reactions, it
-//     returns nil.
+//   - The ReactionFunc of the reaction. If the name does not match any known
+//     reactions, it returns nil.
 func (service *spotifyService) FindReactionByName(
 	name string,
-) func(option json.RawMessage, area schemas.Area) string {
+) ReactionFunc {
 	switch name {
 	case string(schemas.SkipNextMusic):
 		return service.SpotifyReactionSkipNextMusic
@@ -160,6 +183,7 @@ func (service *spotifyService) GetServiceActionInfo() []schemas.Action {
 			Description:        "This action check if a music is played",
 			Service:            service.serviceInfo,
 			Option:             option,
+			OptionSchema:       newOptionSchema(schemas.SpotifyActionMusicPlayedOptionSchema),
 			MinimumRefreshRate: 10,
 		},
 	}
@@ -185,16 +209,18 @@ func (service *spotifyService) GetServiceReactionInfo() []schemas.Reaction {
 	}
 	return []schemas.Reaction{
 		{
-			Name:        string(schemas.SkipNextMusic),
-			Description: "This reaction will skip to the next music",
-			Service:     service.serviceInfo,
-			Option:      option,
+			Name:         string(schemas.SkipNextMusic),
+			Description:  "This reaction will skip to the next music",
+			Service:      service.serviceInfo,
+			Option:       option,
+			OptionSchema: newOptionSchema(schemas.SpotifyReactionSkipMusicSchema),
 		},
 		{
-			Name:        string(schemas.SkipPreviousMusic),
-			Description: "This reaction will skip to the previous music",
-			Service:     service.serviceInfo,
-			Option:      option,
+			Name:         string(schemas.SkipPreviousMusic),
+			Description:  "This reaction will skip to the previous music",
+			Service:      service.serviceInfo,
+			Option:       option,
+			OptionSchema: newOptionSchema(schemas.SpotifyReactionSkipMusicSchema),
 		},
 	}
 }
@@ -251,8 +277,7 @@ func (service *spotifyService) AuthGetServiceAccessToken(
 	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
 	req.SetBasicAuth(clientID, clientSecret)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Spotify, req)
 	if err != nil {
 		return schemas.Token{}, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -292,6 +317,81 @@ func (service *spotifyService) AuthGetServiceAccessToken(
 	return token, nil
 }
 
+// RefreshServiceAccessToken exchanges a Spotify refresh token for a new access token.
+// Spotify may send a new refresh token, in which case it replaces the previous one.
+//
+// Parameters:
+//   - ctx: The context of the request.
+//   - refreshToken: The last refresh token received from Spotify.
+//
+// Returns:
+//   - token: A Token schema containing the new access token and its expiration time,
+//     and the new refresh token if Spotify rotated it.
+//   - err: An error if the environment variables are missing or the request fails.
+//     It wraps schemas.ErrRefreshTokenRejected when Spotify rejects the refresh token.
+func (service *spotifyService) RefreshServiceAccessToken(
+	ctx context.Context,
+	refreshToken string,
+) (token schemas.Token, err error) {
+	clientID := os.Getenv("SPOTIFY_CLIENT_ID")
+	if clientID == "" {
+		return schemas.Token{}, schemas.ErrSpotifyClientIdNotSet
+	}
+
+	clientSecret := os.Getenv("SPOTIFY_SECRET")
+	if clientSecret == "" {
+		return schemas.Token{}, schemas.ErrSpotifySecretNotSet
+	}
+
+	apiURL := "https://accounts.spotify.com/api/token"
+
+	data := url.Values{}
+	data.Set("refresh_token", refreshToken)
+	data.Set("grant_type", "refresh_token")
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, nil)
+	if err != nil {
+		return schemas.Token{}, fmt.Errorf("unable to create request because %w", err)
+	}
+
+	req.URL.RawQuery = data.Encode()
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.SetBasicAuth(clientID, clientSecret)
+
+	resp, err := service.providerClient.Do(schemas.Spotify, req)
+	if err != nil {
+		return schemas.Token{}, fmt.Errorf("unable to make request because %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnauthorized {
+		return schemas.Token{}, fmt.Errorf(
+			"unable to refresh token because %w",
+			schemas.ErrRefreshTokenRejected,
+		)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return schemas.Token{}, fmt.Errorf("unable to refresh token because %v", resp.Status)
+	}
+
+	var result schemas.SpotifyTokenResponse
+	err = json.NewDecoder(resp.Body).Decode(&result)
+	if err != nil {
+		return schemas.Token{}, fmt.Errorf("unable to decode response because %w", err)
+	}
+
+	if result.AccessToken == "" {
+		return schemas.Token{}, schemas.ErrAccessTokenNotFoundInResponse
+	}
+
+	token = schemas.Token{
+		Token:        result.AccessToken,
+		RefreshToken: result.RefreshToken,
+		ExpireAt:     time.Now().Add(time.Duration(result.ExpiresIn) * time.Second),
+	}
+	return token, nil
+}
+
 // GetUserInfo retrieves the Spotify user information using the provided access token.
 // It sends a GET request to the Spotify API endpoint "https://api.spotify.com/v1/me".
 // The access token is included in the Authorization header of the request.
@@ -325,9 +425,8 @@ func (service *spotifyService) GetUserInfo(accessToken string) (user schemas.Use
 
 	println("accessToken", accessToken)
 
-	// Make the request using the default HTTP client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Make the request through the shared provider client
+	resp, err := service.providerClient.Do(schemas.Spotify, req)
 	if err != nil {
 		return schemas.User{}, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -379,15 +478,18 @@ func (service *spotifyService) GetUserInfo(accessToken string) (user schemas.Use
 // 6. Returns the decoded playback response and any error encountered during the process.
 //
 // Parameters:
+// - ctx: The context of the request, cancelling it when done.
 // - token: schemas.Token containing the access token for Spotify API authentication.
 //
 // Returns:
 // - schemas.SpotifyPlaybackResponse: The current playback state from the Spotify API.
 // - error: An error if any occurred during the request or response processing.
-func getSpotifyPlaybackResponse(token schemas.Token) (schemas.SpotifyPlaybackResponse, error) {
+func (service *spotifyService) getSpotifyPlaybackResponse(
+	ctx context.Context,
+	token schemas.Token,
+) (schemas.SpotifyPlaybackResponse, error) {
 	apiURL := "https://api.spotify.com/v1/me/player"
 
-	ctx := context.Background()
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
 		fmt.Println("Error creating request:", err)
@@ -397,17 +499,25 @@ func getSpotifyPlaybackResponse(token schemas.Token) (schemas.SpotifyPlaybackRes
 	req.Header.Set("Authorization", "Bearer "+token.Token)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Spotify, req)
 	if err != nil {
 		fmt.Println("Error making request:", err)
 		return schemas.SpotifyPlaybackResponse{}, err
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return schemas.SpotifyPlaybackResponse{}, fmt.Errorf(
+			"error status code %d: %w",
+			resp.StatusCode,
+			schemas.ErrUnauthorized,
+		)
+	}
 	if resp.StatusCode != http.StatusOK {
-		fmt.Printf("Error: Status code %d\n", resp.StatusCode)
-		return schemas.SpotifyPlaybackResponse{}, err
+		return schemas.SpotifyPlaybackResponse{}, fmt.Errorf(
+			"error status code %d",
+			resp.StatusCode,
+		)
 	}
 
 	var playbackResponse schemas.SpotifyPlaybackResponse
@@ -449,7 +559,7 @@ func (service *spotifyService) InitializedSpotifyStorageVariable(
 				println("error marshalling storage variable: " + err.Error())
 				return variable, err
 			}
-			err = service.areaRepository.Update(area)
+			err = service.areaRepository.UpdateStorageVariable(area)
 			if err != nil {
 				println("error updating area: " + err.Error())
 				return variable, err
@@ -464,7 +574,7 @@ func (service *spotifyService) InitializedSpotifyStorageVariable(
 			println("error marshalling storage variable: " + err.Error())
 			return variable, err
 		}
-		err = service.areaRepository.Update(area)
+		err = service.areaRepository.UpdateStorageVariable(area)
 		if err != nil {
 			println("error updating area: " + err.Error())
 			return variable, err
@@ -477,10 +587,10 @@ func (service *spotifyService) InitializedSpotifyStorageVariable(
 
 // SpotifyActionMusicPlayed handles the action when music is played on Spotify.
 // It checks the current playback status and updates the area storage variable accordingly.
-// If the currently playing track matches the expected track, it sends a message to the provided channel.
+// If the currently playing track matches the expected track, it returns a trigger describing the track.
 //
 // Parameters:
-//   - c: A channel to send messages.
+//   - ctx: The context of the check, cancelling the Spotify request when done.
 //   - option: A JSON raw message containing the options for the action.
 //   - area: The area schema containing user and action details.
 //
@@ -490,19 +600,17 @@ func (service *spotifyService) InitializedSpotifyStorageVariable(
 //  3. Retrieves the token for the user and service.
 //  4. Gets the current playback response from Spotify.
 //  5. Checks if music is currently playing and if the track matches the expected track.
-//  6. Updates the area storage variable and sends a message if the track matches.
+//  6. Updates the area storage variable and returns a trigger if the track matches.
 //  7. Updates the area storage variable if the track does not match or no music is playing.
-//  8. Sleeps for the minimum refresh rate or the action refresh rate, whichever is greater.
 func (service *spotifyService) SpotifyActionMusicPlayed(
-	c chan string,
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) {
+) ([]schemas.ActionResult, error) {
 	optionJSON := schemas.SpotifyActionMusicPlayedOption{}
 	err := json.Unmarshal(option, &optionJSON)
 	if err != nil {
-		fmt.Println("Error unmarshalling option:", err)
-		return
+		return nil, fmt.Errorf("error unmarshalling option: %w", err)
 	}
 
 	variableDatabaseStorage, err := service.InitializedSpotifyStorageVariable(area)
@@ -514,15 +622,22 @@ func (service *spotifyService) SpotifyActionMusicPlayed(
 		area.UserId,
 		area.Action.ServiceId,
 	)
-	if err != nil || token.Token == "" {
-		fmt.Println("Error finding token or token not found")
-		return
+	if err != nil {
+		return nil, fmt.Errorf("error finding token: %w", err)
+	}
+	if token.Token == "" {
+		return nil, schemas.ErrTokenNotFound
 	}
 
-	playbackResponse, err := getSpotifyPlaybackResponse(token)
+	playbackResponse, err := cachedPoll(ctx, service.pollCache, area, schemas.PollKey{
+		Provider:   schemas.Spotify,
+		Resource:   "me/player",
+		Credential: token.Token,
+	}, func(ctx context.Context) (schemas.SpotifyPlaybackResponse, error) {
+		return service.getSpotifyPlaybackResponse(ctx, token)
+	})
 	if err != nil {
-		fmt.Println("Error getting playback response:", err)
-		return
+		return nil, fmt.Errorf("error getting playback response: %w", err)
 	}
 
 	if playbackResponse.IsPlaying {
@@ -539,29 +654,28 @@ func (service *spotifyService) SpotifyActionMusicPlayed(
 				variableDatabaseStorage = schemas.SpotifyStorageVariableTrue
 				area.StorageVariable, err = json.Marshal(variableDatabaseStorage)
 				if err != nil {
-					println("error marshalling storage variable: " + err.Error())
-					return
+					return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 				}
-				err = service.areaRepository.Update(area)
+				err = service.areaRepository.UpdateStorageVariable(area)
 				if err != nil {
-					println("error updating area: " + err.Error())
-					return
+					return nil, fmt.Errorf("error updating area: %w", err)
 				}
 				fmt.Println(message)
-				c <- message
+				return newTrigger(message, schemas.SpotifyTrackPayload{
+					Track:   playbackResponse.Item.Name,
+					Artists: strings.Join(artistNames, ", "),
+				})
 			}
 		} else {
 			if variableDatabaseStorage == schemas.SpotifyStorageVariableTrue {
 				variableDatabaseStorage = schemas.SpotifyStorageVariableFalse
 				area.StorageVariable, err = json.Marshal(variableDatabaseStorage)
 				if err != nil {
-					println("error marshalling storage variable: " + err.Error())
-					return
+					return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 				}
-				err = service.areaRepository.Update(area)
+				err = service.areaRepository.UpdateStorageVariable(area)
 				if err != nil {
-					println("error updating area: " + err.Error())
-					return
+					return nil, fmt.Errorf("error updating area: %w", err)
 				}
 			}
 			message := fmt.Sprintf("Currently playing: %s by %s, but expected: %s",
@@ -576,23 +690,17 @@ func (service *spotifyService) SpotifyActionMusicPlayed(
 			variableDatabaseStorage = schemas.SpotifyStorageVariableFalse
 			area.StorageVariable, err = json.Marshal(variableDatabaseStorage)
 			if err != nil {
-				println("error marshalling storage variable: " + err.Error())
-				return
+				return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 			}
-			err = service.areaRepository.Update(area)
+			err = service.areaRepository.UpdateStorageVariable(area)
 			if err != nil {
-				println("error updating area: " + err.Error())
-				return
+				return nil, fmt.Errorf("error updating area: %w", err)
 			}
 		}
 		fmt.Println("No music is currently playing.")
 	}
 
-	if (area.Action.MinimumRefreshRate) > area.ActionRefreshRate {
-		time.Sleep(time.Second * time.Duration(area.Action.MinimumRefreshRate))
-	} else {
-		time.Sleep(time.Second * time.Duration(area.ActionRefreshRate))
-	}
+	return nil, nil
 }
 
 // Reactions functions
@@ -601,34 +709,33 @@ func (service *spotifyService) SpotifyActionMusicPlayed(
 // It takes a JSON raw message option and an Area schema as parameters.
 // The function retrieves the user's Spotify token from the token repository using the user ID and service ID.
 // If the token is found, it sends a POST request to the Spotify API to skip to the next track.
-// The function returns a string indicating the result of the operation.
+// The function returns the result of the operation, or an error if the request fails.
 //
 // Parameters:
+//   - ctx: context.Context - The context of the reaction, cancelling the Spotify request when done.
 //   - option: json.RawMessage - The raw JSON message containing options for the reaction.
 //   - area: schemas.Area - The area schema containing user and reaction information.
 //
 // Returns:
-//   - string: A message indicating the result of the operation.
+//   - schemas.ReactionResult: The result of the operation.
+//   - error: An error if the token or the request fails.
 func (service *spotifyService) SpotifyReactionSkipNextMusic(
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) string {
+) (schemas.ReactionResult, error) {
 	token, err := service.tokenRepository.FindByUserIdAndServiceId(
 		area.UserId,
 		area.Reaction.ServiceId,
 	)
 	if err != nil {
-		fmt.Println("Error finding token:", err)
-		return "Error finding token:" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error finding token: %w", err)
 	}
 	if token.Token == "" {
-		fmt.Println("Error: Token not found")
-		return "Error: Token not found"
+		return schemas.ReactionResult{}, schemas.ErrTokenNotFound
 	}
 	apiURL := "https://api.spotify.com/v1/me/player/next"
 
-	ctx := context.Background()
-
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
@@ -636,59 +743,68 @@ func (service *spotifyService) SpotifyReactionSkipNextMusic(
 		bytes.NewBuffer([]byte("{}")),
 	)
 	if err != nil {
-		fmt.Println("Error creating request:", err)
-		return "Error creating request:" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error creating request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+token.Token)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Spotify, req)
 	if err != nil {
-		fmt.Println("Error making request:", err)
-		return "Error making request:" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error making request: %w", err)
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return schemas.ReactionResult{}, fmt.Errorf(
+			"error status code %d: %w",
+			resp.StatusCode,
+			schemas.ErrUnauthorized,
+		)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return schemas.ReactionResult{}, statusError(
+			resp.StatusCode,
+			fmt.Errorf("error status code %d", resp.StatusCode),
+		)
+	}
+
 	fmt.Println("Response Status:", resp.Status)
-	return "Spotify skip next music"
+	return schemas.ReactionResult{Message: "Spotify skip next music"}, nil
 }
 
 // SpotifyReactionSkipPreviousMusic skips to the previous track in the user's Spotify player.
-// It takes a JSON raw message option and an Area schema as parameters, and returns a string message.
+// It takes a JSON raw message option and an Area schema as parameters, and returns the result of the operation.
 //
 // Parameters:
+//   - ctx: context.Context cancelling the Spotify request when done.
 //   - option: json.RawMessage containing additional options for the reaction.
 //   - area: schemas.Area containing user and reaction information.
 //
 // Returns:
-//   - A string message indicating the result of the operation.
+//   - The result of the operation, or an error if the token or the request fails.
 //
 // The function retrieves the user's Spotify token from the token repository using the user ID and service ID.
 // If the token is found and valid, it sends a POST request to the Spotify API to skip to the previous track.
-// The function handles errors related to token retrieval, request creation, and request execution, and logs appropriate messages.
+// The function returns the errors related to token retrieval, request creation, and request execution.
 func (service *spotifyService) SpotifyReactionSkipPreviousMusic(
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) string {
+) (schemas.ReactionResult, error) {
 	token, err := service.tokenRepository.FindByUserIdAndServiceId(
 		area.UserId,
 		area.Reaction.ServiceId,
 	)
 	if err != nil {
-		fmt.Println("Error finding token:", err)
-		return "Error finding token:" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error finding token: %w", err)
 	}
 	if token.Token == "" {
-		fmt.Println("Error: Token not found")
-		return "Error: Token not found"
+		return schemas.ReactionResult{}, schemas.ErrTokenNotFound
 	}
 	apiURL := "https://api.spotify.com/v1/me/player/previous"
 
-	ctx := context.Background()
-
 	req, err := http.NewRequestWithContext(
 		ctx,
 		http.MethodPost,
@@ -696,22 +812,33 @@ func (service *spotifyService) SpotifyReactionSkipPreviousMusic(
 		bytes.NewBuffer([]byte("{}")),
 	)
 	if err != nil {
-		fmt.Println("Error creating request:", err)
-		return "Error creating request:" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error creating request: %w", err)
 	}
 
 	req.Header.Set("Authorization", "Bearer "+token.Token)
 	req.Header.Set("Content-Type", "application/json")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Spotify, req)
 	if err != nil {
-		fmt.Println("Error making request:", err)
-		return "Error making request:" + err.Error()
+		return schemas.ReactionResult{}, fmt.Errorf("error making request: %w", err)
 	}
 
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusUnauthorized {
+		return schemas.ReactionResult{}, fmt.Errorf(
+			"error status code %d: %w",
+			resp.StatusCode,
+			schemas.ErrUnauthorized,
+		)
+	}
+	if resp.StatusCode >= http.StatusBadRequest {
+		return schemas.ReactionResult{}, statusError(
+			resp.StatusCode,
+			fmt.Errorf("error status code %d", resp.StatusCode),
+		)
+	}
+
 	fmt.Println("Response Status:", resp.Status)
-	return "SpotifyR skip to previous music"
+	return schemas.ReactionResult{Message: "SpotifyR skip to previous music"}, nil
 }
@@ -17,13 +17,21 @@ type TimerService interface {
 	// Service interface functions
 	GetServiceActionInfo() []schemas.Action
 	GetServiceReactionInfo() []schemas.Reaction
-	FindActionByName(name string) func(c chan string, option json.RawMessage, area schemas.Area)
-	FindReactionByName(name string) func(option json.RawMessage, area schemas.Area) string
+	FindActionByName(name string) ActionFunc
+	FindReactionByName(name string) ReactionFunc
 	// Service specific functions
 	// Actions functions
-	TimerActionSpecificHour(c chan string, option json.RawMessage, area schemas.Area)
+	TimerActionSpecificHour(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) ([]schemas.ActionResult, error)
 	// Reactions functions
-	TimerReactionGiveTime(option json.RawMessage, area schemas.Area) string
+	TimerReactionGiveTime(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) (schemas.ReactionResult, error)
 }
 
 // timerService is a struct that provides services related to timers.
@@ -39,6 +47,7 @@ type timerService struct {
 	repository        repository.TimerRepository
 	serviceRepository repository.ServiceRepository
 	areaRepository    repository.AreaRepository
+	providerClient    ProviderClient
 	serviceInfo       schemas.Service
 }
 
@@ -49,6 +58,7 @@ type timerService struct {
 //   - repository: an instance of TimerRepository for accessing timer data.
 //   - serviceRepository: an instance of ServiceRepository for accessing service data.
 //   - areaRepository: an instance of AreaRepository for accessing area data.
+//   - providerClient: an instance of ProviderClient for sending the requests to timeapi.io.
 //
 // Returns:
 //   - TimerService: a new instance of TimerService.
@@ -56,11 +66,13 @@ func NewTimerService(
 	repository repository.TimerRepository,
 	serviceRepository repository.ServiceRepository,
 	areaRepository repository.AreaRepository,
+	providerClient ProviderClient,
 ) TimerService {
 	return &timerService{
 		repository:        repository,
 		serviceRepository: serviceRepository,
 		areaRepository:    areaRepository,
+		providerClient:    providerClient,
 		serviceInfo: schemas.Service{
 			Name:        schemas.Timer,
 			Description: "This service is a time service",
@@ -83,8 +95,7 @@ func (service *timerService) GetServiceInfo() schemas.Service {
 	return service.serviceInfo
 }
 
-// FindActionByName returns a function that matches the given action name.
-// The returned function takes a channel, a JSON raw message, and an area schema as parameters.
+// FindActionByName returns the ActionFunc that matches the given action name.
 // If the action name matches a specific time, it returns the TimerActionSpecificHour function.
 // If no match is found, it returns nil.
 //
@@ -92,10 +103,10 @@ func (service *timerService) GetServiceInfo() schemas.Service {
 //   - name: The name of the action to find.
 //
 // Returns:
-//   - A function that matches the given action name, or nil if no match is found.
+//   - The ActionFunc that matches the given action name, or nil if no match is found.
 func (service *timerService) FindActionByName(
 	name string,
-) func(c chan string, option json.RawMessage, area schemas.Area) {
+) ActionFunc {
 	switch name {
 	case string(schemas.SpecificTime):
 		return service.TimerActionSpecificHour
@@ -104,20 +115,17 @@ func (service *timerService) FindActionByName(
 	}
 }
 
-// FindReactionByName returns a function that matches the given reaction name.
-// The returned function takes a json.RawMessage option and a schemas.Area as parameters,
-// and returns a string. If the reaction name does not match any known reactions,
-// it returns nil.
+// FindReactionByName returns the ReactionFunc that matches the given reaction name.
+// If the reaction name does not match any known reactions, it returns nil.
 //
 // Parameters:
 //   - name: The name of the reaction to find.
 //
 // Returns:
-//   - A function that takes a json.RawMessage option and a schemas.Area, and returns a string.
-//     If the reaction name does not match any known reactions, it returns nil.
+//   - The ReactionFunc that matches the given reaction name, or nil if no match is found.
 func (service *timerService) FindReactionByName(
 	name string,
-) func(option json.RawMessage, area schemas.Area) string {
+) ReactionFunc {
 	switch name {
 	case string(schemas.GiveTime):
 		return service.TimerReactionGiveTime
@@ -158,6 +166,7 @@ func (service *timerService) GetServiceActionInfo() []schemas.Action {
 			Description:        "This action is a specific time action",
 			Service:            service.serviceInfo,
 			Option:             option,
+			OptionSchema:       newOptionSchema(schemas.TimerActionSpecificHourSchema),
 			MinimumRefreshRate: 10,
 		},
 	}
@@ -186,10 +195,11 @@ func (service *timerService) GetServiceReactionInfo() []schemas.Reaction {
 	}
 	return []schemas.Reaction{
 		{
-			Name:        string(schemas.GiveTime),
-			Description: "This reaction is a give time reaction",
-			Service:     service.serviceInfo,
-			Option:      option,
+			Name:         string(schemas.GiveTime),
+			Description:  "This reaction is a give time reaction",
+			Service:      service.serviceInfo,
+			Option:       option,
+			OptionSchema: newOptionSchema(schemas.TimerReactionGiveTimeSchema),
 		},
 	}
 }
@@ -198,6 +208,7 @@ func (service *timerService) GetServiceReactionInfo() []schemas.Reaction {
 
 // getActualTime fetches the current time for the Europe/Paris timezone from the timeapi.io API.
 // It returns a schemas.TimeApiResponse containing the time data or an error if the request fails.
+// The request is cancelled when the given context is cancelled.
 //
 // Returns:
 //   - schemas.TimeApiResponse: The response containing the current time data.
@@ -208,23 +219,26 @@ func (service *timerService) GetServiceReactionInfo() []schemas.Reaction {
 //   - schemas.ErrDoRequest: If there is an error executing the HTTP request.
 //   - schemas.ErrDecode: If there is an error decoding the response body.
 //   - fmt.Errorf: If the response status code is not 200 OK.
-func getActualTime() (schemas.TimeApiResponse, error) {
+func (service *timerService) getActualTime(ctx context.Context) (schemas.TimeApiResponse, error) {
 	apiURL := "https://www.timeapi.io/api/time/current/zone?timeZone=Europe/Paris"
 
-	ctx := context.Background()
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
 		return schemas.TimeApiResponse{}, schemas.ErrCreateRequest
 	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := service.providerClient.Do(schemas.Timer, req)
 	if err != nil {
 		return schemas.TimeApiResponse{}, schemas.ErrDoRequest
 	}
 
+	defer resp.Body.Close()
+
 	if resp.StatusCode != http.StatusOK {
-		return schemas.TimeApiResponse{}, fmt.Errorf("error status code %d", resp.StatusCode)
+		return schemas.TimeApiResponse{}, statusError(
+			resp.StatusCode,
+			fmt.Errorf("error status code %d", resp.StatusCode),
+		)
 	}
 
 	var result schemas.TimeApiResponse
@@ -233,7 +247,6 @@ func getActualTime() (schemas.TimeApiResponse, error) {
 		return schemas.TimeApiResponse{}, schemas.ErrDecode
 	}
 
-	resp.Body.Close()
 	return result, nil
 }
 
@@ -243,35 +256,34 @@ func getActualTime() (schemas.TimeApiResponse, error) {
 // It unmarshals the provided JSON option into a TimerActionSpecificHour struct,
 // retrieves the current time from an external API, and updates the storage variable
 // in the area repository if necessary. If the current time matches the specified hour
-// and minute in the option, it sends a response message to the provided channel.
+// and minute in the option, it returns a trigger describing the current time.
 //
 // Parameters:
-//   - c: A channel to send the response message.
+//   - ctx: The context of the check, cancelling the time request when done.
 //   - option: A JSON raw message containing the timer action options.
 //   - area: The area schema containing the storage variable.
 //
-// The function handles errors by printing error messages and sleeping for a second
-// before returning. It also ensures that the storage variable is initialized and updated
-// in the area repository if it is not already set.
+// Returns:
+//   - []schemas.ActionResult: The trigger, or nil if it is not the specified time yet.
+//   - error: An error if the option, the time request or the storage variable update fails.
+//
+// It also ensures that the storage variable is initialized and updated in the area
+// repository if it is not already set.
 func (service *timerService) TimerActionSpecificHour(
-	c chan string,
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) {
+) ([]schemas.ActionResult, error) {
 	optionJSON := schemas.TimerActionSpecificHour{}
 
 	err := json.Unmarshal(option, &optionJSON)
 	if err != nil {
-		println("error unmarshal timer option: " + err.Error())
-		time.Sleep(time.Second)
-		return
+		return nil, fmt.Errorf("error unmarshal timer option: %w", err)
 	}
 
-	actualTimeApi, err := getActualTime()
+	actualTimeApi, err := service.getActualTime(ctx)
 	if err != nil {
-		println("error get actual time" + err.Error())
-		time.Sleep(time.Second)
-		return
+		return nil, fmt.Errorf("error get actual time: %w", err)
 	}
 
 	databaseStored := schemas.TimerActionSpecificHourStorage{}
@@ -280,8 +292,7 @@ func (service *timerService) TimerActionSpecificHour(
 		toto := struct{}{}
 		err = json.Unmarshal(area.StorageVariable, &toto)
 		if err != nil {
-			println("error unmarshalling storage variable: " + err.Error())
-			return
+			return nil, fmt.Errorf("error unmarshalling storage variable: %w", err)
 		} else {
 			println("initializing storage variable")
 			databaseStored = schemas.TimerActionSpecificHourStorage{
@@ -289,13 +300,11 @@ func (service *timerService) TimerActionSpecificHour(
 			}
 			area.StorageVariable, err = json.Marshal(databaseStored)
 			if err != nil {
-				println("error marshalling storage variable: " + err.Error())
-				return
+				return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 			}
-			err = service.areaRepository.Update(area)
+			err = service.areaRepository.UpdateStorageVariable(area)
 			if err != nil {
-				println("error updating area: " + err.Error())
-				return
+				return nil, fmt.Errorf("error updating area: %w", err)
 			}
 		}
 	}
@@ -307,13 +316,11 @@ func (service *timerService) TimerActionSpecificHour(
 		}
 		area.StorageVariable, err = json.Marshal(databaseStored)
 		if err != nil {
-			println("error marshalling storage variable: " + err.Error())
-			return
+			return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 		}
-		err = service.areaRepository.Update(area)
+		err = service.areaRepository.UpdateStorageVariable(area)
 		if err != nil {
-			println("error updating area: " + err.Error())
-			return
+			return nil, fmt.Errorf("error updating area: %w", err)
 		}
 	}
 
@@ -335,50 +342,47 @@ func (service *timerService) TimerActionSpecificHour(
 			databaseStored.Time = time.Now().Add(time.Minute)
 			area.StorageVariable, err = json.Marshal(databaseStored)
 			if err != nil {
-				println("error marshalling storage variable: " + err.Error())
-				return
+				return nil, fmt.Errorf("error marshalling storage variable: %w", err)
 			}
-			err = service.areaRepository.Update(area)
+			err = service.areaRepository.UpdateStorageVariable(area)
 			if err != nil {
-				println("error updating area: " + err.Error())
-				return
+				return nil, fmt.Errorf("error updating area: %w", err)
 			}
-			println(response)
-			c <- response
+			return newTrigger(response, schemas.TimerPayload{
+				Time: actualTimeApi.Time,
+				Date: actualTimeApi.Date,
+			})
 		}
 	}
 
-	if (area.Action.MinimumRefreshRate) > area.ActionRefreshRate {
-		time.Sleep(time.Second * time.Duration(area.Action.MinimumRefreshRate))
-	} else {
-		time.Sleep(time.Second * time.Duration(area.ActionRefreshRate))
-	}
+	return nil, nil
 }
 
 // Reactions functions
 
-// TimerReactionGiveTime retrieves the current time from an external API and returns it as a string.
-// If there is an error while fetching the time, it logs the error and returns an error message.
+// TimerReactionGiveTime retrieves the current time from an external API and returns it.
 //
 // Parameters:
 //
+//	ctx - the context of the reaction, cancelling the time request when done.
 //	option - a JSON raw message containing additional options (currently unused).
 //	area - a schemas.Area object representing the area (currently unused).
 //
 // Returns:
 //
-//	A string containing the current time or an error message if the time could not be retrieved.
+//	The result containing the current time, with the time and the date as its payload,
+//	or an error if the time could not be retrieved.
 func (service *timerService) TimerReactionGiveTime(
+	ctx context.Context,
 	option json.RawMessage,
 	area schemas.Area,
-) string {
-	actualTimeApi, err := getActualTime()
+) (schemas.ReactionResult, error) {
+	actualTimeApi, err := service.getActualTime(ctx)
 	if err != nil {
-		println("error get actual time" + err.Error())
-		return "error get actual time"
-	} else {
-		response := "current time is " + actualTimeApi.Time
-		println(response)
-		return response
+		return schemas.ReactionResult{}, fmt.Errorf("error get actual time: %w", err)
 	}
+	return newReactionResult("current time is "+actualTimeApi.Time, schemas.TimerPayload{
+		Time: actualTimeApi.Time,
+		Date: actualTimeApi.Date,
+	})
 }
@@ -3,17 +3,21 @@ package service
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
+	"time"
 
 	"area/repository"
 	"area/schemas"
 )
 
 // TokenService defines the interface for managing tokens.
-// It provides methods to save, update, delete, and retrieve tokens.
+// It provides methods to save, update, delete, retrieve and refresh tokens.
 type TokenService interface {
 	// SaveToken saves a new token and returns its ID.
+	// The token of a user already authorized for the service is replaced instead.
 	// Returns an error if the operation fails.
 	SaveToken(token schemas.Token) (tokenID uint64, err error)
 
@@ -43,29 +47,79 @@ type TokenService interface {
 		token string,
 		tokenToDelete struct{ Id uint64 },
 	) (deletedToken schemas.Token, err error)
+
+	// RefreshTokenIfExpiring refreshes the token of the user for the service if it expires soon.
+	// Returns an error if the token is missing, needs to be authorized again, or can not be refreshed.
+	RefreshTokenIfExpiring(ctx context.Context, userId uint64, serviceId uint64) error
+
+	// RefreshToken refreshes the token of the user for the service, whatever its expiration.
+	// Returns an error if the token is missing, needs to be authorized again, or can not be refreshed.
+	RefreshToken(ctx context.Context, userId uint64, serviceId uint64) error
 }
 
+// tokenService is the implementation of TokenService.
+//
+// Fields:
+//   - repository: Repository used to store the tokens.
+//   - serviceUser: Service used to identify the user deleting a token.
+//   - serviceService: Service used to find the function refreshing the tokens of a service.
+//   - providerClient: Shared HTTP client sending the requests to the providers.
+//   - refreshMutex: Guards refreshes.
+//   - refreshes: The refreshes of the tokens, by user and service.
 type tokenService struct {
-	repository  repository.TokenRepository
-	serviceUser UserService
+	repository     repository.TokenRepository
+	serviceUser    UserService
+	serviceService ServiceService
+	providerClient ProviderClient
+	refreshMutex   sync.Mutex
+	refreshes      map[tokenRefreshKey]*tokenRefresh
+}
+
+// tokenRefreshKey identifies the token of a user for a service.
+type tokenRefreshKey struct {
+	userId    uint64
+	serviceId uint64
+}
+
+// tokenRefresh serializes the refreshes of the token of a user for a service in this process,
+// so that its refresh token is not used twice at the same time, while the tokens of the other
+// users and services are refreshed meanwhile. The refreshes of the other replicas are detected
+// from the token in the database.
+//
+// Fields:
+//   - mutex: Held during a refresh of the token.
+type tokenRefresh struct {
+	mutex sync.Mutex
 }
 
 // NewTokenService creates a new instance of TokenService with the provided
-// TokenRepository and UserService. It initializes the tokenService struct
-// with the given repository and serviceUser, and returns a pointer to the
-// newly created tokenService.
+// TokenRepository, UserService, ServiceService and ProviderClient. It initializes the tokenService
+// struct with the given dependencies, and returns a pointer to the newly created
+// tokenService.
 //
 // Parameters:
 //   - repository: an instance of TokenRepository used for token-related
 //     database operations.
 //   - serviceUser: an instance of UserService used for user-related operations.
+//   - serviceService: an instance of ServiceService used to refresh the tokens
+//     of the services.
+//   - providerClient: an instance of ProviderClient used to send the requests
+//     to the providers.
 //
 // Returns:
 //   - TokenService: a pointer to the newly created tokenService instance.
-func NewTokenService(repository repository.TokenRepository, serviceUser UserService) TokenService {
+func NewTokenService(
+	repository repository.TokenRepository,
+	serviceUser UserService,
+	serviceService ServiceService,
+	providerClient ProviderClient,
+) TokenService {
 	newService := tokenService{
-		repository:  repository,
-		serviceUser: serviceUser,
+		repository:     repository,
+		serviceUser:    serviceUser,
+		serviceService: serviceService,
+		providerClient: providerClient,
+		refreshes:      make(map[tokenRefreshKey]*tokenRefresh),
 	}
 	return &newService
 }
@@ -73,8 +127,9 @@ func NewTokenService(repository repository.TokenRepository, serviceUser UserServ
 // SaveToken saves a token to the repository if it does not already exist.
 // It first checks if the token already exists in the repository. If it does,
 // it returns the existing token ID and an error indicating that the token
-// already exists. If the token does not exist, it saves the token to the
-// repository and then retrieves the token ID.
+// already exists. If the user already has a token for the service, that token
+// is replaced by the new one, which clears its need to be authorized again.
+// Otherwise, it saves the token to the repository and then retrieves the token ID.
 //
 // Parameters:
 //
@@ -97,6 +152,27 @@ func (service *tokenService) SaveToken(
 		}
 	}
 
+	if token.User.Id != 0 && token.Service.Id != 0 {
+		userToken, err := service.repository.FindByUserIdAndServiceId(
+			token.User.Id,
+			token.Service.Id,
+		)
+		if err == nil {
+			userToken.Token = token.Token
+			if token.RefreshToken != "" {
+				userToken.RefreshToken = token.RefreshToken
+			}
+			userToken.ExpireAt = token.ExpireAt
+			userToken.NeedsReauth = false
+			userToken.UpdateAt = time.Now()
+			err = service.repository.Update(userToken)
+			if err != nil {
+				return 0, err
+			}
+			return userToken.Id, nil
+		}
+	}
+
 	err = service.repository.Save(token)
 	if err != nil {
 		return 0, err
@@ -135,9 +211,8 @@ func (service *tokenService) GetUserInfo(accessToken string) (schemas.GmailUserI
 	// Add the Authorization header
 	req.Header.Set("Authorization", "Bearer "+accessToken)
 
-	// Make the request using the default HTTP client
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	// Make the request through the shared provider client
+	resp, err := service.providerClient.Do(schemas.Github, req)
 	if err != nil {
 		return schemas.GmailUserInfo{}, fmt.Errorf("unable to make request because %w", err)
 	}
@@ -270,3 +345,165 @@ func (service *tokenService) DeleteUserToken(
 		return deletedToken, fmt.Errorf("token not found")
 	}
 }
+
+// RefreshTokenIfExpiring refreshes the token of the user for the service when it expires
+// in less than schemas.TokenRefreshMargin minutes. The tokens of the services that do not
+// expire, and the tokens without expiration time, are left untouched.
+//
+// Parameters:
+//   - ctx: The context of the refresh request.
+//   - userId: The ID of the user owning the token.
+//   - serviceId: The ID of the service of the token.
+//
+// Returns:
+//   - error: An error if the token is missing, wraps schemas.ErrTokenNeedsReauth if the user
+//     must authorize the service again, or an error if the refresh fails.
+func (service *tokenService) RefreshTokenIfExpiring(
+	ctx context.Context,
+	userId uint64,
+	serviceId uint64,
+) error {
+	refresh := service.serviceService.FindTokenRefresherByServiceId(serviceId)
+	if refresh == nil {
+		return nil
+	}
+
+	token, err := service.repository.FindByUserIdAndServiceId(userId, serviceId)
+	if err != nil {
+		return fmt.Errorf("unable to find token because %w", err)
+	}
+	if token.NeedsReauth {
+		return schemas.ErrTokenNeedsReauth
+	}
+	if token.ExpireAt.IsZero() ||
+		time.Until(token.ExpireAt) > time.Minute*schemas.TokenRefreshMargin {
+		return nil
+	}
+	return service.refreshToken(ctx, token, refresh)
+}
+
+// RefreshToken refreshes the token of the user for the service, whatever its expiration.
+// It is used when the service rejected the token with a 401 status.
+//
+// Parameters:
+//   - ctx: The context of the refresh request.
+//   - userId: The ID of the user owning the token.
+//   - serviceId: The ID of the service of the token.
+//
+// Returns:
+//   - error: schemas.ErrTokenNotRefreshable if the tokens of the service do not expire,
+//     an error if the token is missing, wraps schemas.ErrTokenNeedsReauth if the user
+//     must authorize the service again, or an error if the refresh fails.
+func (service *tokenService) RefreshToken(
+	ctx context.Context,
+	userId uint64,
+	serviceId uint64,
+) error {
+	refresh := service.serviceService.FindTokenRefresherByServiceId(serviceId)
+	if refresh == nil {
+		return schemas.ErrTokenNotRefreshable
+	}
+
+	token, err := service.repository.FindByUserIdAndServiceId(userId, serviceId)
+	if err != nil {
+		return fmt.Errorf("unable to find token because %w", err)
+	}
+	if token.NeedsReauth {
+		return schemas.ErrTokenNeedsReauth
+	}
+	return service.refreshToken(ctx, token, refresh)
+}
+
+// refreshToken exchanges the refresh token of the token for a new access token and persists
+// the rotated token. The token is read again once its refresh lock is held, and is not
+// refreshed if another refresh, of this process or of another replica, replaced it meanwhile.
+// The rotated token is only written while the refresh token in the database is the one
+// exchanged. When the service rejects the refresh token, or the token has none, the token is
+// marked as needing to be authorized again.
+//
+// Parameters:
+//   - ctx: The context of the refresh request.
+//   - token: The token to refresh.
+//   - refresh: The function refreshing the tokens of the service.
+//
+// Returns:
+//   - error: An error wrapping schemas.ErrTokenNeedsReauth if the token can not be refreshed
+//     anymore, or an error if the refresh or the update fails.
+func (service *tokenService) refreshToken(
+	ctx context.Context,
+	token schemas.Token,
+	refresh RefreshTokenFunc,
+) error {
+	lock := service.tokenRefresh(token.UserId, token.ServiceId)
+	lock.mutex.Lock()
+	defer lock.mutex.Unlock()
+	current, err := service.repository.FindById(token.Id)
+	if err != nil {
+		return fmt.Errorf("unable to find token because %w", err)
+	}
+	if current.NeedsReauth {
+		return schemas.ErrTokenNeedsReauth
+	}
+	if current.Token != token.Token {
+		return nil
+	}
+
+	if current.RefreshToken == "" {
+		return service.markNeedsReauth(current, schemas.ErrRefreshTokenRejected)
+	}
+
+	newToken, err := refresh(ctx, current.RefreshToken)
+	if err != nil {
+		if errors.Is(err, schemas.ErrRefreshTokenRejected) {
+			return service.markNeedsReauth(current, err)
+		}
+		return fmt.Errorf("unable to refresh token because %w", err)
+	}
+
+	exchanged := current.RefreshToken
+	current.Token = newToken.Token
+	if newToken.RefreshToken != "" {
+		current.RefreshToken = newToken.RefreshToken
+	}
+	current.ExpireAt = newToken.ExpireAt
+	// a refresh of another replica rotating the token meanwhile is kept instead
+	_, err = service.repository.UpdateRefreshed(current, exchanged)
+	if err != nil {
+		return fmt.Errorf("unable to update token because %w", err)
+	}
+	return nil
+}
+
+// tokenRefresh returns the refreshes of the token of the user for the service.
+func (service *tokenService) tokenRefresh(userId uint64, serviceId uint64) *tokenRefresh {
+	service.refreshMutex.Lock()
+	defer service.refreshMutex.Unlock()
+	key := tokenRefreshKey{userId: userId, serviceId: serviceId}
+	refresh, ok := service.refreshes[key]
+	if !ok {
+		refresh = &tokenRefresh{}
+		service.refreshes[key] = refresh
+	}
+	return refresh
+}
+
+// markNeedsReauth marks the token as needing to be authorized again by the user, unless
+// another refresh rotated it since it was read.
+//
+// Parameters:
+//   - token: The token that can not be refreshed anymore.
+//   - cause: The reason why the token can not be refreshed.
+//
+// Returns:
+//   - error: An error wrapping schemas.ErrTokenNeedsReauth and cause, nil if the token was
+//     rotated meanwhile, or an error if the update fails.
+func (service *tokenService) markNeedsReauth(token schemas.Token, cause error) error {
+	marked, err := service.repository.MarkNeedsReauth(token)
+	if err != nil {
+		return fmt.Errorf("unable to update token because %w", err)
+	}
+	if !marked {
+		return nil
+	}
+	return fmt.Errorf("%w because %w", schemas.ErrTokenNeedsReauth, cause)
+}
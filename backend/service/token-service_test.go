@@ -0,0 +1,236 @@
+package service_test
+
+import (
+	"context"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"area/schemas"
+	"area/service"
+	"area/test"
+)
+
+func newTokenRefresher(
+	token schemas.Token,
+	err error,
+	calls *int,
+) service.RefreshTokenFunc {
+	return func(ctx context.Context, refreshToken string) (schemas.Token, error) {
+		*calls++
+		return token, err
+	}
+}
+
+func TestRefreshTokenIfExpiringRefreshesExpiringToken(t *testing.T) {
+	token := schemas.Token{
+		Id:           1,
+		Token:        "old access token",
+		RefreshToken: "refresh token",
+		ExpireAt:     time.Now().Add(time.Minute),
+	}
+	mockRepo := new(test.MockTokenRepository)
+	mockRepo.On("FindByUserIdAndServiceId", uint64(1), uint64(2)).Return(token, nil)
+	mockRepo.On("FindById", uint64(1)).Return(token, nil)
+	mockRepo.On("UpdateRefreshed", mock.Anything, "refresh token").Return(true, nil)
+
+	calls := 0
+	expireAt := time.Now().Add(time.Hour)
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindTokenRefresherByServiceId", uint64(2)).
+		Return(newTokenRefresher(schemas.Token{
+			Token:    "new access token",
+			ExpireAt: expireAt,
+		}, nil, &calls))
+
+	tokenService := service.NewTokenService(mockRepo, nil, mockServiceService, nil)
+	err := tokenService.RefreshTokenIfExpiring(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	updated := mockRepo.Calls[2].Arguments.Get(0).(schemas.Token)
+	assert.Equal(t, "new access token", updated.Token)
+	assert.Equal(t, "refresh token", updated.RefreshToken)
+	assert.Equal(t, expireAt, updated.ExpireAt)
+	assert.False(t, updated.NeedsReauth)
+}
+
+func TestRefreshTokenIfExpiringKeepsValidToken(t *testing.T) {
+	token := schemas.Token{
+		Id:           1,
+		Token:        "access token",
+		RefreshToken: "refresh token",
+		ExpireAt:     time.Now().Add(time.Hour),
+	}
+	mockRepo := new(test.MockTokenRepository)
+	mockRepo.On("FindByUserIdAndServiceId", uint64(1), uint64(2)).Return(token, nil)
+
+	calls := 0
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindTokenRefresherByServiceId", uint64(2)).
+		Return(newTokenRefresher(schemas.Token{}, nil, &calls))
+
+	tokenService := service.NewTokenService(mockRepo, nil, mockServiceService, nil)
+	err := tokenService.RefreshTokenIfExpiring(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 0, calls)
+	mockRepo.AssertNotCalled(t, "UpdateRefreshed", mock.Anything, mock.Anything)
+}
+
+func TestRefreshTokenIfExpiringIgnoresServiceWithoutRefresh(t *testing.T) {
+	mockRepo := new(test.MockTokenRepository)
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindTokenRefresherByServiceId", uint64(2)).
+		Return(service.RefreshTokenFunc(nil))
+
+	tokenService := service.NewTokenService(mockRepo, nil, mockServiceService, nil)
+	err := tokenService.RefreshTokenIfExpiring(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	mockRepo.AssertNotCalled(t, "FindByUserIdAndServiceId", mock.Anything, mock.Anything)
+}
+
+func TestRefreshTokenMarksRejectedTokenNeedsReauth(t *testing.T) {
+	token := schemas.Token{
+		Id:           1,
+		Token:        "access token",
+		RefreshToken: "revoked refresh token",
+		ExpireAt:     time.Now().Add(time.Hour),
+	}
+	mockRepo := new(test.MockTokenRepository)
+	mockRepo.On("FindByUserIdAndServiceId", uint64(1), uint64(2)).Return(token, nil)
+	mockRepo.On("FindById", uint64(1)).Return(token, nil)
+	mockRepo.On("MarkNeedsReauth", token).Return(true, nil)
+
+	calls := 0
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindTokenRefresherByServiceId", uint64(2)).
+		Return(newTokenRefresher(
+			schemas.Token{},
+			fmt.Errorf("unable to refresh token because %w", schemas.ErrRefreshTokenRejected),
+			&calls,
+		))
+
+	tokenService := service.NewTokenService(mockRepo, nil, mockServiceService, nil)
+	err := tokenService.RefreshToken(context.Background(), 1, 2)
+
+	assert.ErrorIs(t, err, schemas.ErrTokenNeedsReauth)
+	assert.Equal(t, 1, calls)
+	mockRepo.AssertCalled(t, "MarkNeedsReauth", token)
+
+	token.NeedsReauth = true
+	mockRepo.ExpectedCalls = nil
+	mockRepo.On("FindByUserIdAndServiceId", uint64(1), uint64(2)).Return(token, nil)
+	err = tokenService.RefreshToken(context.Background(), 1, 2)
+
+	assert.ErrorIs(t, err, schemas.ErrTokenNeedsReauth)
+	assert.Equal(t, 1, calls)
+}
+
+func TestRefreshTokenKeepsTokenRotatedByAnotherReplica(t *testing.T) {
+	token := schemas.Token{
+		Id:           1,
+		Token:        "old access token",
+		RefreshToken: "refresh token",
+		ExpireAt:     time.Now().Add(time.Minute),
+	}
+	rotated := token
+	rotated.Token = "new access token"
+	rotated.RefreshToken = "new refresh token"
+	mockRepo := new(test.MockTokenRepository)
+	mockRepo.On("FindByUserIdAndServiceId", uint64(1), uint64(2)).Return(token, nil)
+	mockRepo.On("FindById", uint64(1)).Return(rotated, nil).Once()
+
+	calls := 0
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindTokenRefresherByServiceId", uint64(2)).
+		Return(newTokenRefresher(
+			schemas.Token{},
+			fmt.Errorf("unable to refresh token because %w", schemas.ErrRefreshTokenRejected),
+			&calls,
+		))
+
+	tokenService := service.NewTokenService(mockRepo, nil, mockServiceService, nil)
+	err := tokenService.RefreshToken(context.Background(), 1, 2)
+
+	// the token was rotated before the refresh lock was held
+	assert.NoError(t, err)
+	assert.Equal(t, 0, calls)
+
+	// the token is rotated while the refresh token is exchanged
+	mockRepo.On("FindById", uint64(1)).Return(token, nil)
+	mockRepo.On("MarkNeedsReauth", token).Return(false, nil)
+	err = tokenService.RefreshToken(context.Background(), 1, 2)
+
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+	mockRepo.AssertNotCalled(t, "Update", mock.Anything)
+}
+
+func TestRefreshTokenIfExpiringRefreshesEachTokenOnce(t *testing.T) {
+	expiring := time.Now().Add(time.Minute)
+	token := schemas.Token{
+		Id:           1,
+		UserId:       1,
+		ServiceId:    2,
+		Token:        "old access token",
+		RefreshToken: "refresh token",
+		ExpireAt:     expiring,
+	}
+	otherToken := schemas.Token{
+		Id:           2,
+		UserId:       3,
+		ServiceId:    2,
+		Token:        "other access token",
+		RefreshToken: "other refresh token",
+		ExpireAt:     expiring,
+	}
+	refreshed := token
+	refreshed.Token = "new access token"
+	mockRepo := new(test.MockTokenRepository)
+	mockRepo.On("FindByUserIdAndServiceId", uint64(1), uint64(2)).Return(token, nil)
+	mockRepo.On("FindByUserIdAndServiceId", uint64(3), uint64(2)).Return(otherToken, nil)
+	// the token is read again by each refresh, after the first one replaced it
+	mockRepo.On("FindById", uint64(1)).Return(token, nil).Once()
+	mockRepo.On("FindById", uint64(1)).Return(refreshed, nil)
+	mockRepo.On("FindById", uint64(2)).Return(otherToken, nil)
+	mockRepo.On("UpdateRefreshed", mock.Anything, mock.Anything).Return(true, nil)
+
+	var calls atomic.Int32
+	started := make(chan struct{}, 2)
+	release := make(chan struct{})
+	mockServiceService := new(test.MockServiceService)
+	mockServiceService.On("FindTokenRefresherByServiceId", uint64(2)).
+		Return(service.RefreshTokenFunc(func(
+			ctx context.Context,
+			refreshToken string,
+		) (schemas.Token, error) {
+			calls.Add(1)
+			if refreshToken == "refresh token" {
+				started <- struct{}{}
+				<-release
+			}
+			return schemas.Token{Token: "new access token", ExpireAt: time.Now().Add(time.Hour)}, nil
+		}))
+
+	tokenService := service.NewTokenService(mockRepo, nil, mockServiceService, nil)
+	done := make(chan error, 2)
+	for range 2 {
+		go func() {
+			done <- tokenService.RefreshTokenIfExpiring(context.Background(), 1, 2)
+		}()
+	}
+	<-started
+	// the token of another user is refreshed meanwhile
+	assert.NoError(t, tokenService.RefreshTokenIfExpiring(context.Background(), 3, 2))
+	close(release)
+
+	assert.NoError(t, <-done)
+	assert.NoError(t, <-done)
+	assert.Equal(t, int32(2), calls.Load())
+}
@@ -0,0 +1,366 @@
+package service
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"area/repository"
+	"area/schemas"
+	"area/tools"
+)
+
+// Constructor
+
+// WebhookService defines the interface of the Webhook service, whose action gives each area a
+// URL that triggers it when JSON is posted to it.
+//
+// Methods:
+//   - GetServiceActionInfo: Returns a list of available actions for the service.
+//   - GetServiceReactionInfo: Returns a list of available reactions for the service, none.
+//   - FindActionByName: Finds an action by its name.
+//   - FindReactionByName: Finds a reaction by its name.
+//   - GetAreaWebhook: Returns the URL of an area of a user, creating it on first use.
+//   - HandleDelivery: Turns a JSON object posted to the URL of an area into its trigger.
+//   - WebhookActionReceive: Checks the action of an area triggered by its URL.
+type WebhookService interface {
+	// Service interface functions
+	GetServiceActionInfo() []schemas.Action
+	GetServiceReactionInfo() []schemas.Reaction
+	FindActionByName(name string) ActionFunc
+	FindReactionByName(name string) ReactionFunc
+	// Service specific functions
+	GetAreaWebhook(
+		userId uint64,
+		areaId uint64,
+	) (info schemas.WebhookEndpointInfo, err error)
+	HandleDelivery(
+		token string,
+		secret string,
+		signature string,
+		body []byte,
+	) (triggers []schemas.AreaTrigger, err error)
+	// Actions functions
+	WebhookActionReceive(
+		ctx context.Context,
+		option json.RawMessage,
+		area schemas.Area,
+	) ([]schemas.ActionResult, error)
+}
+
+// webhookService is the implementation of WebhookService.
+//
+// Fields:
+// - repository: Interface for accessing the endpoints of the areas.
+// - serviceRepository: Interface for accessing service data.
+// - areaRepository: Interface for accessing area data.
+// - serviceInfo: Information about the service.
+type webhookService struct {
+	repository        repository.WebhookRepository
+	serviceRepository repository.ServiceRepository
+	areaRepository    repository.AreaRepository
+	serviceInfo       schemas.Service
+}
+
+// NewWebhookService creates a new instance of WebhookService with the provided repositories.
+// It initializes the serviceInfo field with predefined values for the Webhook service.
+//
+// Parameters:
+//   - repository: an instance of WebhookRepository for accessing the endpoints of the areas.
+//   - serviceRepository: an instance of ServiceRepository for accessing service data.
+//   - areaRepository: an instance of AreaRepository for accessing area data.
+//
+// Returns:
+//   - WebhookService: a new instance of WebhookService.
+func NewWebhookService(
+	repository repository.WebhookRepository,
+	serviceRepository repository.ServiceRepository,
+	areaRepository repository.AreaRepository,
+) WebhookService {
+	return &webhookService{
+		repository:        repository,
+		serviceRepository: serviceRepository,
+		areaRepository:    areaRepository,
+		serviceInfo: schemas.Service{
+			Name:        schemas.Webhook,
+			Description: "This service triggers areas from JSON posted to their URL",
+			Oauth:       false,
+			Color:       "#C73A63",
+			Icon:        "https://api.iconify.design/mdi:webhook.svg?color=%23FFFFFF",
+		},
+	}
+}
+
+// Service interface functions
+
+// GetServiceInfo returns the service information.
+//
+// Returns:
+//
+//	schemas.Service: The service information.
+func (service *webhookService) GetServiceInfo() schemas.Service {
+	return service.serviceInfo
+}
+
+// FindActionByName returns the ActionFunc that matches the given action name.
+// If no match is found, it returns nil.
+//
+// Parameters:
+//   - name: The name of the action to find.
+//
+// Returns:
+//   - The ActionFunc that matches the given action name, or nil if no match is found.
+func (service *webhookService) FindActionByName(
+	name string,
+) ActionFunc {
+	switch name {
+	case string(schemas.ReceiveWebhook):
+		return service.WebhookActionReceive
+	default:
+		return nil
+	}
+}
+
+// FindReactionByName returns nil, the Webhook service having no reaction.
+//
+// Parameters:
+//   - name: The name of the reaction to find.
+//
+// Returns:
+//   - nil.
+func (service *webhookService) FindReactionByName(
+	name string,
+) ReactionFunc {
+	return nil
+}
+
+// GetServiceActionInfo retrieves the action information for the Webhook service.
+// The action is checked once an hour at most: its deliveries trigger the area, not its checks.
+//
+// Returns:
+//
+//	[]schemas.Action: A slice containing the receive webhook action.
+func (service *webhookService) GetServiceActionInfo() []schemas.Action {
+	defaultValue := schemas.WebhookActionReceive{
+		Verification: schemas.WebhookVerificationNone,
+	}
+	option, err := json.Marshal(defaultValue)
+	if err != nil {
+		println("error marshal webhook option: " + err.Error())
+	}
+	service.serviceInfo, err = service.serviceRepository.FindByName(
+		schemas.Webhook,
+	) // must update the serviceInfo
+	if err != nil {
+		println("error find service by name: " + err.Error())
+	}
+	return []schemas.Action{
+		{
+			Name:               string(schemas.ReceiveWebhook),
+			Description:        "This action trigger when JSON is posted to the URL of the area",
+			Service:            service.serviceInfo,
+			Option:             option,
+			OptionSchema:       newOptionSchema(schemas.WebhookActionReceiveSchema),
+			MinimumRefreshRate: 3600,
+		},
+	}
+}
+
+// GetServiceReactionInfo returns the reactions of the Webhook service, none.
+//
+// Returns:
+//
+//	[]schemas.Reaction: An empty slice.
+func (service *webhookService) GetServiceReactionInfo() []schemas.Reaction {
+	return []schemas.Reaction{}
+}
+
+// Service specific functions
+
+// webhookVerification returns the way the deliveries of an area are authenticated, from the
+// option of its action. An option without verification accepts any delivery.
+//
+// Parameters:
+//   - option: The option of the action of the area.
+//
+// Returns:
+//   - schemas.WebhookVerification: The way the deliveries are authenticated.
+//   - error: An error if the option can not be decoded.
+func webhookVerification(option json.RawMessage) (schemas.WebhookVerification, error) {
+	options := schemas.WebhookActionReceive{}
+	if len(option) > 0 {
+		err := json.Unmarshal(option, &options)
+		if err != nil {
+			return "", fmt.Errorf("unable to unmarshal webhook option: %w", err)
+		}
+	}
+	if options.Verification == "" {
+		return schemas.WebhookVerificationNone, nil
+	}
+	return options.Verification, nil
+}
+
+// GetAreaWebhook returns the URL of the area, which the user gives to the sender of the
+// deliveries along with its secret. The URL is created with a random token and a random secret
+// the first time it is asked for, and does not change afterwards.
+//
+// Parameters:
+//   - userId: The ID of the user asking for the URL.
+//   - areaId: The ID of the area.
+//
+// Returns:
+//   - info: The URL of the area, the way its deliveries are authenticated and its secret.
+//   - err: schemas.ErrAreaNotFound if the user has no such area, schemas.ErrNotWebhookArea if
+//     its action is not ReceiveWebhook, or an error if the URL can not be created.
+func (service *webhookService) GetAreaWebhook(
+	userId uint64,
+	areaId uint64,
+) (info schemas.WebhookEndpointInfo, err error) {
+	area, err := service.areaRepository.FindById(areaId)
+	if err != nil {
+		return info, fmt.Errorf("unable to find area: %w", err)
+	}
+	if area.UserId != userId {
+		return info, schemas.ErrAreaNotFound
+	}
+	if area.Action.Name != string(schemas.ReceiveWebhook) {
+		return info, schemas.ErrNotWebhookArea
+	}
+	verification, err := webhookVerification(area.ActionOption)
+	if err != nil {
+		return info, err
+	}
+	webhookURL, err := getWebhookURL(schemas.Webhook)
+	if err != nil {
+		return info, fmt.Errorf("unable to get webhook url: %w", err)
+	}
+
+	endpoint, err := service.repository.FindEndpointByAreaId(areaId)
+	if errors.Is(err, schemas.ErrWebhookEndpointNotFound) {
+		endpoint, err = service.createEndpoint(areaId)
+	}
+	if err != nil {
+		return info, fmt.Errorf("unable to find webhook endpoint: %w", err)
+	}
+	return schemas.WebhookEndpointInfo{
+		URL:          webhookURL + "/" + endpoint.Token,
+		Verification: verification,
+		Secret:       endpoint.Secret,
+	}, nil
+}
+
+// createEndpoint saves a new endpoint for the area, with a random token and a random secret.
+func (service *webhookService) createEndpoint(
+	areaId uint64,
+) (endpoint schemas.WebhookEndpoint, err error) {
+	token, err := tools.GenerateWebhookSecret()
+	if err != nil {
+		return endpoint, err
+	}
+	secret, err := tools.GenerateWebhookSecret()
+	if err != nil {
+		return endpoint, err
+	}
+	endpoint = schemas.WebhookEndpoint{AreaId: areaId, Token: token, Secret: secret}
+	err = service.repository.SaveEndpoint(endpoint)
+	if err != nil {
+		return endpoint, fmt.Errorf("unable to save webhook endpoint: %w", err)
+	}
+	return endpoint, nil
+}
+
+// HandleDelivery authenticates a delivery posted to the URL of an area, as asked by the option
+// of its action, and turns its body into the payload of a trigger of the area. A disabled area
+// is not triggered.
+//
+// Parameters:
+//   - token: The token of the URL of the area, from the URL of the delivery.
+//   - secret: The secret of the delivery, from the X-Webhook-Secret header.
+//   - signature: The signature of the delivery, from the X-Webhook-Signature header.
+//   - body: The raw body of the delivery.
+//
+// Returns:
+//   - triggers: The trigger of the area, to queue for its reaction workers.
+//   - err: schemas.ErrWebhookEndpointNotFound if no area has this URL,
+//     schemas.ErrInvalidWebhookSecret or schemas.ErrInvalidWebhookSignature if the delivery
+//     is not authenticated, schemas.ErrInvalidWebhookPayload if its body is not a JSON
+//     object, or an error if the area can not be found.
+func (service *webhookService) HandleDelivery(
+	token string,
+	secret string,
+	signature string,
+	body []byte,
+) (triggers []schemas.AreaTrigger, err error) {
+	endpoint, err := service.repository.FindEndpointByToken(token)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find webhook endpoint: %w", err)
+	}
+	area, err := service.areaRepository.FindById(endpoint.AreaId)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find area: %w", err)
+	}
+	verification, err := webhookVerification(area.ActionOption)
+	if err != nil {
+		return nil, err
+	}
+	switch verification {
+	case schemas.WebhookVerificationNone:
+	case schemas.WebhookVerificationSecret:
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(endpoint.Secret)) != 1 {
+			return nil, schemas.ErrInvalidWebhookSecret
+		}
+	case schemas.WebhookVerificationHmac:
+		if !strings.HasPrefix(signature, schemas.WebhookSignaturePrefix) ||
+			!tools.VerifySignature(
+				endpoint.Secret,
+				body,
+				strings.TrimPrefix(signature, schemas.WebhookSignaturePrefix),
+			) {
+			return nil, schemas.ErrInvalidWebhookSignature
+		}
+	default:
+		// an option saved with an unknown verification authenticates nothing
+		return nil, schemas.ErrInvalidWebhookSignature
+	}
+
+	// the payload is rendered in the options of the reactions, whose fields it must name
+	var payload map[string]any
+	err = json.Unmarshal(body, &payload)
+	if err != nil || payload == nil {
+		return nil, schemas.ErrInvalidWebhookPayload
+	}
+	if !area.Enable {
+		return nil, nil
+	}
+	return []schemas.AreaTrigger{{
+		Area: area,
+		Trigger: schemas.ActionResult{
+			Message: "webhook received",
+			Payload: json.RawMessage(body),
+		},
+	}}, nil
+}
+
+// Actions functions
+
+// WebhookActionReceive checks the action of an area triggered by its URL. Its checks report
+// nothing, the deliveries posted to the URL triggering the area right away.
+//
+// Parameters:
+//   - ctx: The context of the check.
+//   - option: The option of the action.
+//   - area: The area whose action is checked.
+//
+// Returns:
+//   - []schemas.ActionResult: No trigger.
+//   - error: Always nil.
+func (service *webhookService) WebhookActionReceive(
+	ctx context.Context,
+	option json.RawMessage,
+	area schemas.Area,
+) ([]schemas.ActionResult, error) {
+	return nil, nil
+}
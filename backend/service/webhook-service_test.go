@@ -0,0 +1,144 @@
+package service_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+
+	"area/schemas"
+	"area/service"
+	"area/test"
+	"area/tools"
+)
+
+func webhookArea(verification schemas.WebhookVerification) schemas.Area {
+	return schemas.Area{
+		Id:           1,
+		UserId:       7,
+		Enable:       true,
+		Action:       schemas.Action{Name: string(schemas.ReceiveWebhook)},
+		ActionOption: json.RawMessage(`{"verification":"` + verification + `"}`),
+	}
+}
+
+func newWebhookService(
+	area schemas.Area,
+) (service.WebhookService, *test.MockWebhookRepository) {
+	mockWebhookRepository := new(test.MockWebhookRepository)
+	endpoint := schemas.WebhookEndpoint{AreaId: 1, Token: "token", Secret: "secret"}
+	mockWebhookRepository.On("FindEndpointByToken", "token").Return(endpoint, nil)
+	mockWebhookRepository.On("FindEndpointByToken", mock.Anything).
+		Return(schemas.WebhookEndpoint{}, schemas.ErrWebhookEndpointNotFound)
+	mockAreaRepository := new(test.MockAreaRepository)
+	mockAreaRepository.On("FindById", uint64(1)).Return(area, nil)
+	webhookService := service.NewWebhookService(mockWebhookRepository, nil, mockAreaRepository)
+	return webhookService, mockWebhookRepository
+}
+
+func TestWebhookDeliveryTriggersArea(t *testing.T) {
+	webhookService, _ := newWebhookService(webhookArea(schemas.WebhookVerificationNone))
+	body := []byte(`{"status":"deployed","build":{"number":42}}`)
+
+	triggers, err := webhookService.HandleDelivery("token", "", "", body)
+	assert.NoError(t, err)
+	assert.Len(t, triggers, 1)
+	assert.Equal(t, uint64(1), triggers[0].Area.Id)
+	assert.JSONEq(t, string(body), string(triggers[0].Trigger.Payload))
+
+	_, err = webhookService.HandleDelivery("other", "", "", body)
+	assert.ErrorIs(t, err, schemas.ErrWebhookEndpointNotFound)
+	for _, invalid := range []string{``, `[1, 2]`, `"deployed"`, `null`, `{"status":`} {
+		_, err = webhookService.HandleDelivery("token", "", "", []byte(invalid))
+		assert.ErrorIs(t, err, schemas.ErrInvalidWebhookPayload, invalid)
+	}
+}
+
+func TestWebhookDeliveryIgnoredByDisabledArea(t *testing.T) {
+	area := webhookArea(schemas.WebhookVerificationNone)
+	area.Enable = false
+	webhookService, _ := newWebhookService(area)
+
+	triggers, err := webhookService.HandleDelivery("token", "", "", []byte(`{}`))
+	assert.NoError(t, err)
+	assert.Empty(t, triggers)
+}
+
+func TestWebhookDeliveryChecksSecret(t *testing.T) {
+	webhookService, _ := newWebhookService(webhookArea(schemas.WebhookVerificationSecret))
+	body := []byte(`{"status":"deployed"}`)
+
+	for _, secret := range []string{"", "other", "secret "} {
+		_, err := webhookService.HandleDelivery("token", secret, "", body)
+		assert.ErrorIs(t, err, schemas.ErrInvalidWebhookSecret)
+	}
+	triggers, err := webhookService.HandleDelivery("token", "secret", "", body)
+	assert.NoError(t, err)
+	assert.Len(t, triggers, 1)
+}
+
+func TestWebhookDeliveryChecksSignature(t *testing.T) {
+	webhookService, _ := newWebhookService(webhookArea(schemas.WebhookVerificationHmac))
+	body := []byte(`{"status":"deployed"}`)
+
+	for _, signature := range []string{
+		"",
+		tools.SignPayload("secret", body),
+		schemas.WebhookSignaturePrefix + tools.SignPayload("other", body),
+		schemas.WebhookSignaturePrefix + tools.SignPayload("secret", []byte(`{}`)),
+	} {
+		// the secret itself does not authenticate a signed delivery
+		_, err := webhookService.HandleDelivery("token", "secret", signature, body)
+		assert.ErrorIs(t, err, schemas.ErrInvalidWebhookSignature)
+	}
+	triggers, err := webhookService.HandleDelivery(
+		"token",
+		"",
+		schemas.WebhookSignaturePrefix+tools.SignPayload("secret", body),
+		body,
+	)
+	assert.NoError(t, err)
+	assert.Len(t, triggers, 1)
+}
+
+func TestWebhookDeliveryRejectsUnknownVerification(t *testing.T) {
+	webhookService, _ := newWebhookService(webhookArea("HMAC"))
+	body := []byte(`{"status":"deployed"}`)
+
+	_, err := webhookService.HandleDelivery(
+		"token",
+		"secret",
+		schemas.WebhookSignaturePrefix+tools.SignPayload("secret", body),
+		body,
+	)
+	assert.ErrorIs(t, err, schemas.ErrInvalidWebhookSignature)
+}
+
+func TestWebhookAreaURLCreatedOnce(t *testing.T) {
+	t.Setenv("BACKEND_PORT", "8080")
+	t.Setenv("BACKEND_EXTERNAL_HOST", "localhost")
+	t.Setenv("IS_PRODUCTION", "false")
+	webhookService, mockWebhookRepository := newWebhookService(
+		webhookArea(schemas.WebhookVerificationHmac),
+	)
+	mockWebhookRepository.On("FindEndpointByAreaId", uint64(1)).
+		Return(schemas.WebhookEndpoint{}, schemas.ErrWebhookEndpointNotFound)
+	mockWebhookRepository.On("SaveEndpoint", mock.Anything).Return(nil)
+
+	info, err := webhookService.GetAreaWebhook(7, 1)
+	assert.NoError(t, err)
+	saved := mockWebhookRepository.Calls[1].Arguments.Get(0).(schemas.WebhookEndpoint)
+	assert.Equal(t, uint64(1), saved.AreaId)
+	assert.Len(t, saved.Token, 2*schemas.WebhookSecretLength)
+	assert.NotEqual(t, saved.Token, saved.Secret)
+	assert.Equal(t, "http://localhost:8080/api/v1/webhook/webhook/"+saved.Token, info.URL)
+	assert.Equal(t, schemas.WebhookVerificationHmac, info.Verification)
+	assert.Equal(t, saved.Secret, info.Secret)
+	assert.NotContains(t, info.URL, saved.Secret)
+
+	// the area of another user has no URL for the user
+	_, err = webhookService.GetAreaWebhook(8, 1)
+	assert.ErrorIs(t, err, schemas.ErrAreaNotFound)
+	mockWebhookRepository.AssertNumberOfCalls(t, "SaveEndpoint", 1)
+}
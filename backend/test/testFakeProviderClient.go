@@ -0,0 +1,32 @@
+package test
+
+import (
+	"net/http"
+	"net/url"
+
+	"area/schemas"
+)
+
+// FakeProviderClient sends the requests of the services to a local fake provider instead of
+// the real one, keeping their path and query.
+type FakeProviderClient struct {
+	URL string // Base URL of the fake provider, such as the URL of an httptest server
+}
+
+func (c *FakeProviderClient) Do(
+	provider schemas.ServiceName,
+	req *http.Request,
+) (*http.Response, error) {
+	target, err := url.Parse(c.URL)
+	if err != nil {
+		return nil, err
+	}
+	req.URL.Scheme = target.Scheme
+	req.URL.Host = target.Host
+	req.Host = target.Host
+	return http.DefaultClient.Do(req)
+}
+
+func (c *FakeProviderClient) GetMetrics() []schemas.ProviderMetrics {
+	return nil
+}
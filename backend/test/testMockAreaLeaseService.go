@@ -0,0 +1,36 @@
+package test
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"area/schemas"
+)
+
+type MockAreaLeaseService struct {
+	mock.Mock
+}
+
+func (m *MockAreaLeaseService) NodeId() string {
+	args := m.Called()
+	return args.String(0)
+}
+
+func (m *MockAreaLeaseService) Owns(areaId uint64) bool {
+	args := m.Called(areaId)
+	return args.Bool(0)
+}
+
+func (m *MockAreaLeaseService) Rebalance() (acquired []uint64, released []uint64, err error) {
+	args := m.Called()
+	return args.Get(0).([]uint64), args.Get(1).([]uint64), args.Error(2)
+}
+
+func (m *MockAreaLeaseService) Leave() error {
+	args := m.Called()
+	return args.Error(0)
+}
+
+func (m *MockAreaLeaseService) GetAreaOwnership() (schemas.AreaOwnership, error) {
+	args := m.Called()
+	return args.Get(0).(schemas.AreaOwnership), args.Error(1)
+}
@@ -0,0 +1,79 @@
+package test
+
+import (
+	"encoding/json"
+
+	"area/schemas"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockAreaRepository struct {
+	mock.Mock
+}
+
+func (m *MockAreaRepository) SaveArea(area schemas.Area) (uint64, error) {
+	args := m.Called(area)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockAreaRepository) Save(area schemas.Area) error {
+	args := m.Called(area)
+	return args.Error(0)
+}
+
+func (m *MockAreaRepository) Update(area schemas.Area) error {
+	args := m.Called(area)
+	return args.Error(0)
+}
+
+func (m *MockAreaRepository) UpdateStorageVariable(area schemas.Area) error {
+	args := m.Called(area)
+	return args.Error(0)
+}
+
+func (m *MockAreaRepository) SwapStorageVariable(
+	area schemas.Area,
+	storageVariable json.RawMessage,
+) (bool, error) {
+	args := m.Called(area, storageVariable)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAreaRepository) UpdateColumns(areaId uint64, columns map[string]any) error {
+	args := m.Called(areaId, columns)
+	return args.Error(0)
+}
+
+func (m *MockAreaRepository) Delete(area schemas.Area) error {
+	args := m.Called(area)
+	return args.Error(0)
+}
+
+func (m *MockAreaRepository) FindAll() ([]schemas.Area, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.Area), args.Error(1)
+}
+
+func (m *MockAreaRepository) FindEnabledIds() ([]uint64, error) {
+	args := m.Called()
+	return args.Get(0).([]uint64), args.Error(1)
+}
+
+func (m *MockAreaRepository) FindByUserId(userID uint64) ([]schemas.Area, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]schemas.Area), args.Error(1)
+}
+
+func (m *MockAreaRepository) FindById(id uint64) (schemas.Area, error) {
+	args := m.Called(id)
+	return args.Get(0).(schemas.Area), args.Error(1)
+}
+
+func (m *MockAreaRepository) ReplaceReactions(
+	areaId uint64,
+	reactions []schemas.AreaReaction,
+) error {
+	args := m.Called(areaId, reactions)
+	return args.Error(0)
+}
@@ -0,0 +1,31 @@
+package test
+
+import (
+	"area/schemas"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockDeadLetterRepository struct {
+	mock.Mock
+}
+
+func (m *MockDeadLetterRepository) Save(deadLetter schemas.DeadLetter) error {
+	args := m.Called(deadLetter)
+	return args.Error(0)
+}
+
+func (m *MockDeadLetterRepository) Delete(deadLetter schemas.DeadLetter) error {
+	args := m.Called(deadLetter)
+	return args.Error(0)
+}
+
+func (m *MockDeadLetterRepository) FindById(id uint64) (schemas.DeadLetter, error) {
+	args := m.Called(id)
+	return args.Get(0).(schemas.DeadLetter), args.Error(1)
+}
+
+func (m *MockDeadLetterRepository) FindByUserId(userId uint64) ([]schemas.DeadLetter, error) {
+	args := m.Called(userId)
+	return args.Get(0).([]schemas.DeadLetter), args.Error(1)
+}
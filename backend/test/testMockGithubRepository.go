@@ -0,0 +1,39 @@
+package test
+
+import (
+	"area/schemas"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockGithubRepository struct {
+	mock.Mock
+}
+
+func (m *MockGithubRepository) SaveWebhook(webhook schemas.GithubWebhook) (uint64, error) {
+	args := m.Called(webhook)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockGithubRepository) UpdateWebhook(webhook schemas.GithubWebhook) error {
+	args := m.Called(webhook)
+	return args.Error(0)
+}
+
+func (m *MockGithubRepository) DeleteWebhook(webhook schemas.GithubWebhook) error {
+	args := m.Called(webhook)
+	return args.Error(0)
+}
+
+func (m *MockGithubRepository) FindWebhookById(id uint64) (schemas.GithubWebhook, error) {
+	args := m.Called(id)
+	return args.Get(0).(schemas.GithubWebhook), args.Error(1)
+}
+
+func (m *MockGithubRepository) FindWebhookByUserIdAndRepoName(
+	userId uint64,
+	repoName string,
+) (schemas.GithubWebhook, error) {
+	args := m.Called(userId, repoName)
+	return args.Get(0).(schemas.GithubWebhook), args.Error(1)
+}
@@ -0,0 +1,46 @@
+package test
+
+import (
+	"area/schemas"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockMicrosoftRepository struct {
+	mock.Mock
+}
+
+func (m *MockMicrosoftRepository) SaveSubscription(
+	subscription schemas.MicrosoftSubscription,
+) error {
+	args := m.Called(subscription)
+	return args.Error(0)
+}
+
+func (m *MockMicrosoftRepository) UpdateSubscription(
+	subscription schemas.MicrosoftSubscription,
+) error {
+	args := m.Called(subscription)
+	return args.Error(0)
+}
+
+func (m *MockMicrosoftRepository) DeleteSubscription(
+	subscription schemas.MicrosoftSubscription,
+) error {
+	args := m.Called(subscription)
+	return args.Error(0)
+}
+
+func (m *MockMicrosoftRepository) FindSubscriptionByAreaId(
+	areaId uint64,
+) (schemas.MicrosoftSubscription, error) {
+	args := m.Called(areaId)
+	return args.Get(0).(schemas.MicrosoftSubscription), args.Error(1)
+}
+
+func (m *MockMicrosoftRepository) FindSubscriptionBySubscriptionId(
+	subscriptionId string,
+) (schemas.MicrosoftSubscription, error) {
+	args := m.Called(subscriptionId)
+	return args.Get(0).(schemas.MicrosoftSubscription), args.Error(1)
+}
@@ -0,0 +1,25 @@
+package test
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockNotificationRepository struct {
+	mock.Mock
+}
+
+func (m *MockNotificationRepository) Notify(channel string, payload string) error {
+	args := m.Called(channel, payload)
+	return args.Error(0)
+}
+
+func (m *MockNotificationRepository) Listen(
+	ctx context.Context,
+	channels []string,
+	handle func(channel string, payload string),
+) error {
+	args := m.Called(ctx, channels, handle)
+	return args.Error(0)
+}
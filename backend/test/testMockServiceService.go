@@ -1,8 +1,6 @@
 package test
 
 import (
-	"encoding/json"
-
 	"area/schemas"
 	"area/service"
 
@@ -53,11 +51,9 @@ func (m *MockServiceService) GetServices() []interface{} {
 	return args.Get(0).([]interface{})
 }
 
-func (m *MockServiceService) FindActionByName(
-	name string,
-) func(chan string, json.RawMessage, schemas.Area) {
+func (m *MockServiceService) FindActionByName(name string) service.ActionFunc {
 	args := m.Called(name)
-	return args.Get(0).(func(chan string, json.RawMessage, schemas.Area))
+	return args.Get(0).(service.ActionFunc)
 }
 
 func (m *MockServiceService) FindByName(name schemas.ServiceName) schemas.Service {
@@ -70,11 +66,9 @@ func (m *MockServiceService) FindAll() []schemas.Service {
 	return args.Get(0).([]schemas.Service)
 }
 
-func (m *MockServiceService) FindReactionByName(
-	name string,
-) func(json.RawMessage, schemas.Area) string {
+func (m *MockServiceService) FindReactionByName(name string) service.ReactionFunc {
 	args := m.Called(name)
-	return args.Get(0).(func(json.RawMessage, schemas.Area) string)
+	return args.Get(0).(service.ReactionFunc)
 }
 
 func (m *MockServiceService) FindServiceByName(name string) schemas.Service {
@@ -87,6 +81,13 @@ func (m *MockServiceService) GetServiceById(id uint64) schemas.Service {
 	return args.Get(0).(schemas.Service)
 }
 
+func (m *MockServiceService) FindTokenRefresherByServiceId(
+	serviceId uint64,
+) service.RefreshTokenFunc {
+	args := m.Called(serviceId)
+	return args.Get(0).(service.RefreshTokenFunc)
+}
+
 func (m *MockServiceService) GetServicesInfo() ([]schemas.Service, error) {
 	args := m.Called()
 	return args.Get(0).([]schemas.Service), args.Error(1)
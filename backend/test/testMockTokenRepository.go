@@ -0,0 +1,67 @@
+package test
+
+import (
+	"github.com/stretchr/testify/mock"
+
+	"area/schemas"
+)
+
+type MockTokenRepository struct {
+	mock.Mock
+}
+
+func (m *MockTokenRepository) Save(token schemas.Token) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) Update(token schemas.Token) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) UpdateRefreshed(
+	token schemas.Token,
+	refreshToken string,
+) (bool, error) {
+	args := m.Called(token, refreshToken)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) MarkNeedsReauth(token schemas.Token) (bool, error) {
+	args := m.Called(token)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockTokenRepository) Delete(token schemas.Token) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockTokenRepository) FindAll() ([]schemas.Token, error) {
+	args := m.Called()
+	return args.Get(0).([]schemas.Token), args.Error(1)
+}
+
+func (m *MockTokenRepository) FindByToken(token string) ([]schemas.Token, error) {
+	args := m.Called(token)
+	return args.Get(0).([]schemas.Token), args.Error(1)
+}
+
+func (m *MockTokenRepository) FindById(id uint64) (schemas.Token, error) {
+	args := m.Called(id)
+	return args.Get(0).(schemas.Token), args.Error(1)
+}
+
+func (m *MockTokenRepository) FindByUserId(userID uint64) ([]schemas.Token, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]schemas.Token), args.Error(1)
+}
+
+func (m *MockTokenRepository) FindByUserIdAndServiceId(
+	id uint64,
+	serviceId uint64,
+) (schemas.Token, error) {
+	args := m.Called(id, serviceId)
+	return args.Get(0).(schemas.Token), args.Error(1)
+}
@@ -0,0 +1,69 @@
+package test
+
+import (
+	"context"
+
+	"github.com/stretchr/testify/mock"
+
+	"area/schemas"
+)
+
+type MockTokenService struct {
+	mock.Mock
+}
+
+func (m *MockTokenService) SaveToken(token schemas.Token) (tokenID uint64, err error) {
+	args := m.Called(token)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockTokenService) Update(token schemas.Token) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockTokenService) Delete(token schemas.Token) error {
+	args := m.Called(token)
+	return args.Error(0)
+}
+
+func (m *MockTokenService) FindAll() []schemas.Token {
+	args := m.Called()
+	return args.Get(0).([]schemas.Token)
+}
+
+func (m *MockTokenService) GetTokenById(id uint64) (schemas.Token, error) {
+	args := m.Called(id)
+	return args.Get(0).(schemas.Token), args.Error(1)
+}
+
+func (m *MockTokenService) GetTokenByUserId(userID uint64) ([]schemas.Token, error) {
+	args := m.Called(userID)
+	return args.Get(0).([]schemas.Token), args.Error(1)
+}
+
+func (m *MockTokenService) DeleteUserToken(
+	token string,
+	tokenToDelete struct{ Id uint64 },
+) (schemas.Token, error) {
+	args := m.Called(token, tokenToDelete)
+	return args.Get(0).(schemas.Token), args.Error(1)
+}
+
+func (m *MockTokenService) RefreshTokenIfExpiring(
+	ctx context.Context,
+	userId uint64,
+	serviceId uint64,
+) error {
+	args := m.Called(userId, serviceId)
+	return args.Error(0)
+}
+
+func (m *MockTokenService) RefreshToken(
+	ctx context.Context,
+	userId uint64,
+	serviceId uint64,
+) error {
+	args := m.Called(userId, serviceId)
+	return args.Error(0)
+}
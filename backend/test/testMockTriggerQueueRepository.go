@@ -0,0 +1,61 @@
+package test
+
+import (
+	"time"
+
+	"area/schemas"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockTriggerQueueRepository struct {
+	mock.Mock
+}
+
+func (m *MockTriggerQueueRepository) Enqueue(trigger schemas.QueuedTrigger) (uint64, error) {
+	args := m.Called(trigger)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockTriggerQueueRepository) Claim(
+	lease time.Duration,
+) (schemas.QueuedTrigger, bool, error) {
+	args := m.Called(lease)
+	return args.Get(0).(schemas.QueuedTrigger), args.Bool(1), args.Error(2)
+}
+
+func (m *MockTriggerQueueRepository) Progress(
+	trigger schemas.QueuedTrigger,
+	workflowRunId uint64,
+	position int,
+	lease time.Duration,
+) error {
+	args := m.Called(trigger, workflowRunId, position, lease)
+	return args.Error(0)
+}
+
+func (m *MockTriggerQueueRepository) Renew(
+	trigger schemas.QueuedTrigger,
+	lease time.Duration,
+) error {
+	args := m.Called(trigger, lease)
+	return args.Error(0)
+}
+
+func (m *MockTriggerQueueRepository) Release(
+	trigger schemas.QueuedTrigger,
+	delay time.Duration,
+) error {
+	args := m.Called(trigger, delay)
+	return args.Error(0)
+}
+
+func (m *MockTriggerQueueRepository) Ack(trigger schemas.QueuedTrigger) error {
+	args := m.Called(trigger)
+	return args.Error(0)
+}
+
+func (m *MockTriggerQueueRepository) FindByAreaId(areaId uint64) ([]schemas.QueuedTrigger, error) {
+	args := m.Called(areaId)
+	return args.Get(0).([]schemas.QueuedTrigger), args.Error(1)
+}
@@ -0,0 +1,30 @@
+package test
+
+import (
+	"area/schemas"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWebhookRepository struct {
+	mock.Mock
+}
+
+func (m *MockWebhookRepository) SaveEndpoint(endpoint schemas.WebhookEndpoint) error {
+	args := m.Called(endpoint)
+	return args.Error(0)
+}
+
+func (m *MockWebhookRepository) FindEndpointByAreaId(
+	areaId uint64,
+) (schemas.WebhookEndpoint, error) {
+	args := m.Called(areaId)
+	return args.Get(0).(schemas.WebhookEndpoint), args.Error(1)
+}
+
+func (m *MockWebhookRepository) FindEndpointByToken(
+	token string,
+) (schemas.WebhookEndpoint, error) {
+	args := m.Called(token)
+	return args.Get(0).(schemas.WebhookEndpoint), args.Error(1)
+}
@@ -0,0 +1,34 @@
+package test
+
+import (
+	"area/schemas"
+
+	"github.com/stretchr/testify/mock"
+)
+
+type MockWorkflowRunRepository struct {
+	mock.Mock
+}
+
+func (m *MockWorkflowRunRepository) Save(run schemas.WorkflowRun) (uint64, error) {
+	args := m.Called(run)
+	return args.Get(0).(uint64), args.Error(1)
+}
+
+func (m *MockWorkflowRunRepository) Update(run schemas.WorkflowRun) error {
+	args := m.Called(run)
+	return args.Error(0)
+}
+
+func (m *MockWorkflowRunRepository) FindById(id uint64) (schemas.WorkflowRun, error) {
+	args := m.Called(id)
+	return args.Get(0).(schemas.WorkflowRun), args.Error(1)
+}
+
+func (m *MockWorkflowRunRepository) FindByAreaIdAndFilter(
+	areaId uint64,
+	filter schemas.WorkflowRunFilter,
+) ([]schemas.WorkflowRun, int64, error) {
+	args := m.Called(areaId, filter)
+	return args.Get(0).([]schemas.WorkflowRun), args.Get(1).(int64), args.Error(2)
+}
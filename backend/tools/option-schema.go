@@ -0,0 +1,260 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"area/schemas"
+)
+
+// ValidateOption checks an action or a reaction option against its JSON Schema.
+// When templated is true, the string fields holding {{action.field}} or {{steps.N.field}}
+// placeholders are only checked to be strings, since their value is only known once the
+// action triggers.
+//
+// Parameters:
+//   - name: The name of the option, action or reaction, used in the error message.
+//   - schema: The JSON Schema of the option.
+//   - option: The option to check.
+//   - templated: Whether the option can hold {{action.field}} or {{steps.N.field}} placeholders.
+//
+// Returns:
+//   - error: A *schemas.OptionValidationError listing the fields that do not match the
+//     schema, or an error if the schema or the option is not valid JSON.
+func ValidateOption(
+	name string,
+	schema json.RawMessage,
+	option json.RawMessage,
+	templated bool,
+) error {
+	var optionSchema schemas.OptionSchema
+	err := json.Unmarshal(schema, &optionSchema)
+	if err != nil {
+		return fmt.Errorf("unable to decode %s option schema: %w", name, err)
+	}
+
+	var value any
+	err = decodeJSON(option, &value)
+	if err != nil {
+		return fmt.Errorf("unable to decode %s option: %w", name, err)
+	}
+
+	validator := optionValidator{templated: templated}
+	validator.validate("", optionSchema, value)
+	if len(validator.fields) > 0 {
+		return &schemas.OptionValidationError{Option: name, Fields: validator.fields}
+	}
+	return nil
+}
+
+// optionValidator collects the fields of an option that do not match their schema.
+type optionValidator struct {
+	templated bool
+	fields    []schemas.OptionFieldError
+}
+
+// fail records that the field at path does not match its schema.
+func (validator *optionValidator) fail(path string, format string, args ...any) {
+	if path == "" {
+		path = "option"
+	}
+	validator.fields = append(validator.fields, schemas.OptionFieldError{
+		Field:   path,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// validate checks value, found at path, against schema.
+func (validator *optionValidator) validate(path string, schema schemas.OptionSchema, value any) {
+	if !matchesType(schema.Type, value) {
+		validator.fail(path, "must be of type %s", schema.Type)
+		return
+	}
+	if text, ok := value.(string); ok && validator.templated &&
+		templatePlaceholder.MatchString(text) {
+		return
+	}
+	if len(schema.Enum) > 0 && !matchesEnum(schema.Enum, value) {
+		validator.fail(path, "must be one of %s", formatEnum(schema.Enum))
+		return
+	}
+
+	switch typed := value.(type) {
+	case map[string]any:
+		validator.validateObject(path, schema, typed)
+	case []any:
+		if schema.Items != nil {
+			for index, item := range typed {
+				validator.validate(fmt.Sprintf("%s[%d]", path, index), *schema.Items, item)
+			}
+		}
+	case json.Number:
+		validator.validateNumber(path, schema, typed)
+	case string:
+		validator.validateString(path, schema, typed)
+	}
+}
+
+// validateObject checks the required, known and unknown fields of an object.
+func (validator *optionValidator) validateObject(
+	path string,
+	schema schemas.OptionSchema,
+	object map[string]any,
+) {
+	for _, required := range schema.Required {
+		if _, ok := object[required]; !ok {
+			validator.fail(joinPath(path, required), "is required")
+		}
+	}
+
+	keys := make([]string, 0, len(object))
+	for key := range object {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		property, ok := schema.Properties[key]
+		if !ok {
+			if schema.AdditionalProperties != nil && !*schema.AdditionalProperties {
+				validator.fail(joinPath(path, key), "is not allowed")
+			}
+			continue
+		}
+		validator.validate(joinPath(path, key), property, object[key])
+	}
+}
+
+// validateNumber checks the range of a number.
+func (validator *optionValidator) validateNumber(
+	path string,
+	schema schemas.OptionSchema,
+	number json.Number,
+) {
+	value, err := number.Float64()
+	if err != nil {
+		validator.fail(path, "must be a number")
+		return
+	}
+	if schema.Minimum != nil && value < *schema.Minimum {
+		validator.fail(path, "must be greater than or equal to %v", *schema.Minimum)
+	}
+	if schema.Maximum != nil && value > *schema.Maximum {
+		validator.fail(path, "must be less than or equal to %v", *schema.Maximum)
+	}
+}
+
+// validateString checks the length, the pattern and the format of a string.
+func (validator *optionValidator) validateString(
+	path string,
+	schema schemas.OptionSchema,
+	text string,
+) {
+	length := utf8.RuneCountInString(text)
+	if schema.MinLength != nil && length < *schema.MinLength {
+		validator.fail(path, "must be at least %d characters long", *schema.MinLength)
+	}
+	if schema.MaxLength != nil && length > *schema.MaxLength {
+		validator.fail(path, "must be at most %d characters long", *schema.MaxLength)
+	}
+	if schema.Pattern != "" {
+		pattern, err := regexp.Compile(schema.Pattern)
+		if err != nil {
+			validator.fail(path, "has an invalid pattern in its schema")
+		} else if !pattern.MatchString(text) {
+			validator.fail(path, "must match the pattern %s", schema.Pattern)
+		}
+	}
+	if schema.Format != "" && !matchesFormat(schema.Format, text) {
+		validator.fail(path, "must be a valid %s", schema.Format)
+	}
+}
+
+// joinPath returns the path of the field key of the object at path.
+func joinPath(path string, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// matchesType reports whether value has the JSON type named by kind.
+// An empty kind matches every value.
+func matchesType(kind string, value any) bool {
+	switch kind {
+	case "":
+		return true
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := value.(json.Number)
+		return ok
+	case "integer":
+		number, ok := value.(json.Number)
+		if !ok {
+			return false
+		}
+		_, err := number.Int64()
+		return err == nil
+	default:
+		return false
+	}
+}
+
+// matchesEnum reports whether value is one of the values of enum.
+func matchesEnum(enum []any, value any) bool {
+	encoded, err := json.Marshal(value)
+	if err != nil {
+		return false
+	}
+	for _, allowed := range enum {
+		encodedAllowed, err := json.Marshal(allowed)
+		if err == nil && string(encodedAllowed) == string(encoded) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatEnum returns the values of enum separated by commas.
+func formatEnum(enum []any) string {
+	values := make([]string, 0, len(enum))
+	for _, allowed := range enum {
+		values = append(values, fmt.Sprint(allowed))
+	}
+	return strings.Join(values, ", ")
+}
+
+// matchesFormat reports whether text is written in the given format.
+// Unknown formats match every string.
+func matchesFormat(format string, text string) bool {
+	switch format {
+	case "email":
+		address, err := mail.ParseAddress(text)
+		return err == nil && address.Address == text
+	case "uri":
+		parsed, err := url.ParseRequestURI(text)
+		return err == nil && parsed.Scheme != "" && parsed.Host != ""
+	case "date-time":
+		_, err := time.Parse(time.RFC3339, text)
+		return err == nil
+	default:
+		return true
+	}
+}
@@ -0,0 +1,88 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/schemas"
+	"area/tools"
+)
+
+func marshalSchema(t *testing.T, schema schemas.OptionSchema) json.RawMessage {
+	t.Helper()
+	encoded, err := json.Marshal(schema)
+	assert.NoError(t, err)
+	return encoded
+}
+
+func TestValidateOptionAcceptsValidOption(t *testing.T) {
+	schema := marshalSchema(t, schemas.TimerActionSpecificHourSchema)
+
+	err := tools.ValidateOption("action", schema, json.RawMessage(`{"hour":13,"minute":7}`), false)
+	assert.NoError(t, err)
+}
+
+func TestValidateOptionReportsEveryField(t *testing.T) {
+	schema := marshalSchema(t, schemas.TimerActionSpecificHourSchema)
+
+	err := tools.ValidateOption(
+		"action",
+		schema,
+		json.RawMessage(`{"hour":24,"second":3}`),
+		false,
+	)
+
+	var validationErr *schemas.OptionValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "action", validationErr.Option)
+	assert.Equal(t, []schemas.OptionFieldError{
+		{Field: "minute", Message: "is required"},
+		{Field: "hour", Message: "must be less than or equal to 23"},
+		{Field: "second", Message: "is not allowed"},
+	}, validationErr.Fields)
+}
+
+func TestValidateOptionChecksTypesEnumsAndPatterns(t *testing.T) {
+	weather := marshalSchema(t, schemas.OpenWeatherMapActionSpecificWeatherSchema)
+	err := tools.ValidateOption(
+		"action",
+		weather,
+		json.RawMessage(`{"city":"Bordeaux","weather":"Sunny"}`),
+		false,
+	)
+	assert.ErrorContains(t, err, "weather: must be one of")
+
+	temperature := marshalSchema(t, schemas.OpenWeatherMapActionSpecificTemperatureSchema)
+	err = tools.ValidateOption(
+		"action",
+		temperature,
+		json.RawMessage(`{"city":"Bordeaux","temperature":"12"}`),
+		false,
+	)
+	assert.ErrorContains(t, err, "temperature: must be of type integer")
+
+	github := marshalSchema(t, schemas.GithubActionOptionSchema)
+	err = tools.ValidateOption("action", github, json.RawMessage(`{"repo_name":"area"}`), false)
+	assert.ErrorContains(t, err, "repo_name: must match the pattern")
+	err = tools.ValidateOption(
+		"action",
+		github,
+		json.RawMessage(`{"repo_name":"octocat/area"}`),
+		false,
+	)
+	assert.NoError(t, err)
+}
+
+func TestValidateOptionAllowsPlaceholdersInTemplatedOption(t *testing.T) {
+	schema := marshalSchema(t, schemas.GmailReactionSendMailOptionSchema)
+	option := json.RawMessage(`{"to":"{{action.sender}}","subject":"Hi","body":"{{action.subject}}"}`)
+
+	err := tools.ValidateOption("reaction", schema, option, true)
+	assert.NoError(t, err)
+
+	err = tools.ValidateOption("action", schema, option, false)
+	assert.ErrorContains(t, err, "to: must be a valid email")
+}
@@ -0,0 +1,45 @@
+package tools
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+)
+
+// RendezvousOwner picks the node owning a key with rendezvous hashing: every node gets a
+// score for the key, and the node with the highest score owns it. The nodes agree on the
+// owner of every key as long as they know the same nodes, and a node joining or leaving
+// only moves the keys it gains or owned, about one key out of the number of nodes.
+//
+// Parameters:
+//   - nodeIds: The IDs of the nodes sharing the keys.
+//   - key: The key to place, such as the ID of an area.
+//
+// Returns:
+//   - string: The ID of the node owning the key, empty if there is no node.
+func RendezvousOwner(nodeIds []string, key uint64) string {
+	owner := ""
+	var best uint64
+	for _, nodeId := range nodeIds {
+		score := rendezvousScore(nodeId, key)
+		if owner == "" || score > best || (score == best && nodeId < owner) {
+			owner = nodeId
+			best = score
+		}
+	}
+	return owner
+}
+
+// rendezvousScore hashes a node ID and a key into the score of the node for the key.
+func rendezvousScore(nodeId string, key uint64) uint64 {
+	hash := fnv.New64a()
+	_, _ = hash.Write([]byte(nodeId))
+	_, _ = hash.Write(binary.BigEndian.AppendUint64(nil, key))
+	// spread the close hashes of the close keys with the finalizer of splitmix64
+	score := hash.Sum64()
+	score ^= score >> 30
+	score *= 0xbf58476d1ce4e5b9
+	score ^= score >> 27
+	score *= 0x94d049bb133111eb
+	score ^= score >> 31
+	return score
+}
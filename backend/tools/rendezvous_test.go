@@ -0,0 +1,44 @@
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/tools"
+)
+
+func TestRendezvousOwnerWithoutNode(t *testing.T) {
+	assert.Empty(t, tools.RendezvousOwner(nil, 1))
+}
+
+func TestRendezvousOwnerIgnoresNodeOrder(t *testing.T) {
+	for key := range uint64(100) {
+		assert.Equal(t,
+			tools.RendezvousOwner([]string{"a", "b", "c"}, key),
+			tools.RendezvousOwner([]string{"c", "a", "b"}, key),
+		)
+	}
+}
+
+func TestRendezvousOwnerSpreadsKeys(t *testing.T) {
+	nodes := []string{"a", "b", "c"}
+	owned := map[string]int{}
+	for key := range uint64(300) {
+		owned[tools.RendezvousOwner(nodes, key)]++
+	}
+	for _, node := range nodes {
+		assert.Greater(t, owned[node], 50, node)
+	}
+}
+
+func TestRendezvousOwnerMovesOnlyKeysOfChangedNode(t *testing.T) {
+	before := []string{"a", "b", "c"}
+	after := []string{"a", "b", "c", "d"}
+	for key := range uint64(300) {
+		owner := tools.RendezvousOwner(after, key)
+		if owner != "d" {
+			assert.Equal(t, tools.RendezvousOwner(before, key), owner)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package tools
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"net"
+	"time"
+
+	"area/schemas"
+)
+
+// IsTransientError reports whether err may not happen again, so that the failed call is
+// worth retrying: errors wrapping schemas.ErrTransient, such as the 5xx and 429 responses
+// of the services, and network errors.
+//
+// Parameters:
+//   - err: The error of the failed call.
+//
+// Returns:
+//   - bool: Whether the error is transient.
+func IsTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+	if errors.Is(err, schemas.ErrTransient) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// RetryDelay returns the wait before a retry of a reaction, growing exponentially with the
+// number of retries already done and randomly changed by the jitter of the policy.
+//
+// Parameters:
+//   - policy: The retry policy, with its defaults applied.
+//   - retry: The number of the retry, starting at 1.
+//
+// Returns:
+//   - time.Duration: The wait before the retry.
+func RetryDelay(policy schemas.RetryPolicy, retry int) time.Duration {
+	delay := float64(policy.InitialBackoffMs) * math.Pow(policy.Multiplier, float64(retry-1))
+	delay = math.Min(delay, float64(policy.MaxBackoffMs))
+	if policy.Jitter > 0 {
+		delay += delay * policy.Jitter * (2*rand.Float64() - 1)
+	}
+	return time.Duration(delay) * time.Millisecond
+}
@@ -0,0 +1,61 @@
+package tools_test
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/schemas"
+	"area/tools"
+)
+
+func TestIsTransientError(t *testing.T) {
+	assert.False(t, tools.IsTransientError(nil))
+	assert.False(t, tools.IsTransientError(errors.New("bad request")))
+	assert.False(t, tools.IsTransientError(schemas.ErrUnauthorized))
+	assert.True(t, tools.IsTransientError(fmt.Errorf("status 503: %w", schemas.ErrTransient)))
+	assert.True(t, tools.IsTransientError(fmt.Errorf("request failed: %w", &net.DNSError{})))
+}
+
+func TestRetryDelayGrowsExponentially(t *testing.T) {
+	policy := schemas.RetryPolicy{
+		MaxAttempts:      5,
+		InitialBackoffMs: 100,
+		MaxBackoffMs:     1000,
+		Multiplier:       3,
+	}
+
+	assert.Equal(t, 100*time.Millisecond, tools.RetryDelay(policy, 1))
+	assert.Equal(t, 300*time.Millisecond, tools.RetryDelay(policy, 2))
+	assert.Equal(t, 900*time.Millisecond, tools.RetryDelay(policy, 3))
+	assert.Equal(t, time.Second, tools.RetryDelay(policy, 4))
+}
+
+func TestRetryDelayAppliesJitter(t *testing.T) {
+	policy := schemas.RetryPolicy{
+		MaxAttempts:      3,
+		InitialBackoffMs: 1000,
+		MaxBackoffMs:     1000,
+		Multiplier:       2,
+		Jitter:           0.5,
+	}
+
+	for range 100 {
+		delay := tools.RetryDelay(policy, 1)
+		assert.GreaterOrEqual(t, delay, 500*time.Millisecond)
+		assert.LessOrEqual(t, delay, 1500*time.Millisecond)
+	}
+}
+
+func TestRetryPolicyWithDefaults(t *testing.T) {
+	policy := schemas.RetryPolicy{InitialBackoffMs: 60000}.WithDefaults()
+
+	assert.Equal(t, schemas.DefaultRetryPolicy.MaxAttempts, policy.MaxAttempts)
+	assert.Equal(t, uint64(60000), policy.MaxBackoffMs)
+	assert.Equal(t, schemas.DefaultRetryPolicy.Multiplier, policy.Multiplier)
+	assert.Zero(t, policy.Jitter)
+}
@@ -0,0 +1,61 @@
+package tools
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+
+	"area/schemas"
+)
+
+// GenerateWebhookSecret generates a random secret shared with a provider to sign the
+// deliveries of a webhook.
+//
+// Returns:
+//   - string: The secret, hex encoded.
+//   - error: An error if the random bytes can not be read.
+func GenerateWebhookSecret() (string, error) {
+	bytes := make([]byte, schemas.WebhookSecretLength)
+	_, err := rand.Read(bytes)
+	if err != nil {
+		return "", fmt.Errorf("unable to generate webhook secret: %w", err)
+	}
+	return hex.EncodeToString(bytes), nil
+}
+
+// SignPayload returns the HMAC-SHA256 signature of the payload with the secret, hex encoded,
+// as sent by GitHub in the X-Hub-Signature-256 header after its "sha256=" prefix.
+//
+// Parameters:
+//   - secret: The secret shared with the sender of the payload.
+//   - payload: The raw body of the request.
+//
+// Returns:
+//   - string: The signature of the payload, hex encoded.
+func SignPayload(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifySignature reports whether the signature is the HMAC-SHA256 signature of the payload
+// with the secret. The signatures are compared in constant time.
+//
+// Parameters:
+//   - secret: The secret shared with the sender of the payload.
+//   - payload: The raw body of the request.
+//   - signature: The signature sent with the payload, hex encoded.
+//
+// Returns:
+//   - bool: Whether the signature matches the payload.
+func VerifySignature(secret string, payload []byte, signature string) bool {
+	received, err := hex.DecodeString(signature)
+	if err != nil {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return hmac.Equal(received, mac.Sum(nil))
+}
@@ -0,0 +1,38 @@
+package tools_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/tools"
+)
+
+func TestSignPayload(t *testing.T) {
+	// example of the GitHub documentation on validating webhook deliveries
+	signature := tools.SignPayload("It's a Secret to Everybody", []byte("Hello, World!"))
+	assert.Equal(t,
+		"757107ea0eb2509fc211221cce984b8a37570b6d7586c22c46f4379c8b043e17",
+		signature,
+	)
+}
+
+func TestVerifySignature(t *testing.T) {
+	payload := []byte(`{"zen":"Keep it logically awesome."}`)
+	signature := tools.SignPayload("secret", payload)
+
+	assert.True(t, tools.VerifySignature("secret", payload, signature))
+	assert.False(t, tools.VerifySignature("other", payload, signature))
+	assert.False(t, tools.VerifySignature("secret", []byte(`{}`), signature))
+	assert.False(t, tools.VerifySignature("secret", payload, "not hex"))
+	assert.False(t, tools.VerifySignature("secret", payload, ""))
+}
+
+func TestGenerateWebhookSecret(t *testing.T) {
+	first, err := tools.GenerateWebhookSecret()
+	assert.NoError(t, err)
+	second, err := tools.GenerateWebhookSecret()
+	assert.NoError(t, err)
+	assert.Len(t, first, 64)
+	assert.NotEqual(t, first, second)
+}
@@ -0,0 +1,243 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"area/schemas"
+)
+
+// templatePlaceholder matches the {{action.field}} and {{steps.N.field}} placeholders of the
+// reaction options.
+var templatePlaceholder = regexp.MustCompile(`\{\{\s*((?:action|steps)\.[A-Za-z0-9_.]+)\s*\}\}`)
+
+// RenderActionTemplate replaces the {{action.field}} placeholders found in the string values
+// of a reaction option by the fields of the payload of the trigger.
+// Nested payload fields are reached with dotted names, such as {{action.author.name}}.
+// Placeholders naming a field missing from the payload are left untouched.
+//
+// Parameters:
+//   - option: The reaction option, a JSON value.
+//   - payload: The trigger payload, a JSON object. An empty payload renders nothing.
+//
+// Returns:
+//   - json.RawMessage: The rendered reaction option.
+//   - error: An error if the option or the payload is not valid JSON.
+func RenderActionTemplate(
+	option json.RawMessage,
+	payload json.RawMessage,
+) (json.RawMessage, error) {
+	return RenderWorkflowTemplate(option, payload, nil)
+}
+
+// RenderWorkflowTemplate replaces the placeholders found in the string values of the reaction
+// option of a workflow step: {{action.field}} by the fields of the payload of the trigger, and
+// {{steps.N.field}} by the fields of the output of the step at position N.
+// Placeholders naming a field missing from the payload or from the outputs, such as the
+// output of a step that did not succeed, are left untouched.
+//
+// Parameters:
+//   - option: The reaction option, a JSON value.
+//   - payload: The trigger payload, a JSON object.
+//   - outputs: The outputs of the previous steps indexed by position, nil for the steps
+//     without output.
+//
+// Returns:
+//   - json.RawMessage: The rendered reaction option.
+//   - error: An error if the option, the payload or an output is not valid JSON.
+func RenderWorkflowTemplate(
+	option json.RawMessage,
+	payload json.RawMessage,
+	outputs []json.RawMessage,
+) (json.RawMessage, error) {
+	if (len(payload) == 0 && len(outputs) == 0) || !bytes.Contains(option, []byte("{{")) {
+		return option, nil
+	}
+
+	fields := map[string]any{}
+	if len(payload) != 0 {
+		var action map[string]any
+		err := decodeJSON(payload, &action)
+		if err != nil {
+			return nil, fmt.Errorf("unable to decode trigger payload: %w", err)
+		}
+		fields["action"] = action
+	}
+	if len(outputs) != 0 {
+		steps := make([]any, len(outputs))
+		for position, output := range outputs {
+			if len(output) == 0 {
+				continue
+			}
+			err := decodeJSON(output, &steps[position])
+			if err != nil {
+				return nil, fmt.Errorf("unable to decode output of step %d: %w", position, err)
+			}
+		}
+		fields["steps"] = steps
+	}
+
+	var value any
+	err := decodeJSON(option, &value)
+	if err != nil {
+		return nil, fmt.Errorf("unable to decode reaction option: %w", err)
+	}
+
+	rendered, err := json.Marshal(renderValue(value, fields))
+	if err != nil {
+		return nil, fmt.Errorf("unable to encode reaction option: %w", err)
+	}
+	return rendered, nil
+}
+
+// ValidateStepPlaceholders checks that the {{steps.N.field}} placeholders of the reaction
+// option of a workflow step only name the steps running before it.
+//
+// Parameters:
+//   - name: The name of the option, used in the error message.
+//   - option: The reaction option, a JSON value.
+//   - position: The position of the step in the reactions of the area.
+//
+// Returns:
+//   - error: A *schemas.OptionValidationError listing the fields naming a step that does not
+//     run before, or an error if the option is not valid JSON.
+func ValidateStepPlaceholders(name string, option json.RawMessage, position int) error {
+	if !bytes.Contains(option, []byte("{{")) {
+		return nil
+	}
+	var value any
+	err := decodeJSON(option, &value)
+	if err != nil {
+		return fmt.Errorf("unable to decode %s option: %w", name, err)
+	}
+	validator := optionValidator{}
+	validateStepValue(&validator, "", value, position)
+	if len(validator.fields) > 0 {
+		return &schemas.OptionValidationError{Option: name, Fields: validator.fields}
+	}
+	return nil
+}
+
+// validateStepValue checks the step placeholders of every string found in value, at path.
+func validateStepValue(validator *optionValidator, path string, value any, position int) {
+	switch typed := value.(type) {
+	case string:
+		for _, match := range templatePlaceholder.FindAllStringSubmatch(typed, -1) {
+			keys := strings.Split(match[1], ".")
+			if keys[0] != "steps" {
+				continue
+			}
+			step, err := strconv.Atoi(keys[1])
+			if err != nil || step < 0 || step >= position {
+				validator.fail(path, "%s must name a previous step", match[0])
+			}
+		}
+	case map[string]any:
+		keys := make([]string, 0, len(typed))
+		for key := range typed {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			validateStepValue(validator, joinPath(path, key), typed[key], position)
+		}
+	case []any:
+		for index, item := range typed {
+			validateStepValue(validator, fmt.Sprintf("%s[%d]", path, index), item, position)
+		}
+	}
+}
+
+// decodeJSON decodes data into value, keeping the numbers as they were written.
+func decodeJSON(data []byte, value any) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(value)
+}
+
+// renderValue renders the placeholders of every string found in value.
+func renderValue(value any, fields map[string]any) any {
+	switch typed := value.(type) {
+	case string:
+		return renderString(typed, fields)
+	case map[string]any:
+		for key, item := range typed {
+			typed[key] = renderValue(item, fields)
+		}
+		return typed
+	case []any:
+		for index, item := range typed {
+			typed[index] = renderValue(item, fields)
+		}
+		return typed
+	default:
+		return value
+	}
+}
+
+// renderString replaces the placeholders of text by the fields they name.
+func renderString(text string, fields map[string]any) string {
+	return templatePlaceholder.ReplaceAllStringFunc(text, func(placeholder string) string {
+		path := templatePlaceholder.FindStringSubmatch(placeholder)[1]
+		field, ok := lookupField(fields, strings.Split(path, "."))
+		if !ok {
+			return placeholder
+		}
+		return formatField(field)
+	})
+}
+
+// lookupField returns the payload field found by following the keys of path.
+// The items of an array are reached with their index.
+func lookupField(fields map[string]any, path []string) (any, bool) {
+	field, ok := fields[path[0]]
+	if !ok {
+		return nil, false
+	}
+	return lookupNested(field, path[1:])
+}
+
+// lookupNested returns the field found in value by following the keys of path.
+func lookupNested(value any, path []string) (any, bool) {
+	if len(path) == 0 {
+		return value, true
+	}
+	switch typed := value.(type) {
+	case map[string]any:
+		return lookupField(typed, path)
+	case []any:
+		index, err := strconv.Atoi(path[0])
+		if err != nil || index < 0 || index >= len(typed) {
+			return nil, false
+		}
+		return lookupNested(typed[index], path[1:])
+	default:
+		return nil, false
+	}
+}
+
+// formatField returns the text inserted in place of a placeholder.
+// Objects and arrays are inserted as JSON.
+func formatField(field any) string {
+	switch typed := field.(type) {
+	case nil:
+		return ""
+	case string:
+		return typed
+	case json.Number:
+		return typed.String()
+	case bool:
+		return fmt.Sprint(typed)
+	default:
+		encoded, err := json.Marshal(typed)
+		if err != nil {
+			return ""
+		}
+		return string(encoded)
+	}
+}
@@ -0,0 +1,82 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/schemas"
+	"area/tools"
+)
+
+func TestRenderActionTemplate(t *testing.T) {
+	option := json.RawMessage(
+		`{"to":"{{ action.author.email }}","body":"{{action.title}} #{{action.number}}",` +
+			`"count":3,"tags":["{{action.missing}}"]}`,
+	)
+	payload := json.RawMessage(
+		`{"title":"Fix bug","number":42,"author":{"email":"octocat@github.com"}}`,
+	)
+
+	rendered, err := tools.RenderActionTemplate(option, payload)
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t,
+		`{"to":"octocat@github.com","body":"Fix bug #42","count":3,"tags":["{{action.missing}}"]}`,
+		string(rendered),
+	)
+}
+
+func TestRenderActionTemplateWithoutPayload(t *testing.T) {
+	option := json.RawMessage(`{"body":"{{action.title}}"}`)
+
+	rendered, err := tools.RenderActionTemplate(option, nil)
+	assert.NoError(t, err)
+	assert.Equal(t, option, rendered)
+}
+
+func TestRenderActionTemplateInvalidPayload(t *testing.T) {
+	option := json.RawMessage(`{"body":"{{action.title}}"}`)
+
+	_, err := tools.RenderActionTemplate(option, json.RawMessage(`not json`))
+	assert.Error(t, err)
+}
+
+func TestRenderWorkflowTemplate(t *testing.T) {
+	option := json.RawMessage(
+		`{"subject":"{{steps.0.name}} on {{action.branch}}",` +
+			`"body":"{{steps.0.url}} {{steps.1.id}}"}`,
+	)
+	payload := json.RawMessage(`{"branch":"main"}`)
+	outputs := []json.RawMessage{
+		json.RawMessage(`{"name":"CI","url":"https://github.com/octocat/hello/actions/runs/1"}`),
+		nil,
+	}
+
+	rendered, err := tools.RenderWorkflowTemplate(option, payload, outputs)
+	assert.NoError(t, err)
+	assert.JSONEq(
+		t,
+		`{"subject":"CI on main",`+
+			`"body":"https://github.com/octocat/hello/actions/runs/1 {{steps.1.id}}"}`,
+		string(rendered),
+	)
+}
+
+func TestValidateStepPlaceholders(t *testing.T) {
+	option := json.RawMessage(
+		`{"subject":"{{steps.0.name}}","body":"{{steps.2.url}}","to":"{{action.author}}"}`,
+	)
+
+	assert.NoError(t, tools.ValidateStepPlaceholders("reactions[2]", option, 3))
+
+	err := tools.ValidateStepPlaceholders("reactions[1]", option, 1)
+	var validationErr *schemas.OptionValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []schemas.OptionFieldError{{
+		Field:   "body",
+		Message: "{{steps.2.url}} must name a previous step",
+	}}, validationErr.Fields)
+}
@@ -0,0 +1,215 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"area/schemas"
+)
+
+// ValidateTriggerFilter checks that a trigger filter is well formed: known operators,
+// groups with conditions, comparisons with a field and a value, valid regular expressions,
+// and numbers for the numeric comparisons.
+//
+// Parameters:
+//   - filter: The trigger filter, a JSON object. An empty or null filter is valid.
+//
+// Returns:
+//   - error: A *schemas.OptionValidationError listing the conditions that are not valid,
+//     or an error if the filter is not valid JSON.
+func ValidateTriggerFilter(filter json.RawMessage) error {
+	if len(filter) == 0 || bytes.Equal(filter, []byte("null")) {
+		return nil
+	}
+	var condition schemas.TriggerFilter
+	err := decodeJSON(filter, &condition)
+	if err != nil {
+		return fmt.Errorf("unable to decode trigger filter: %w", err)
+	}
+	validator := optionValidator{}
+	validateCondition(&validator, "", condition)
+	if len(validator.fields) > 0 {
+		return &schemas.OptionValidationError{Option: "filter", Fields: validator.fields}
+	}
+	return nil
+}
+
+// validateCondition checks one condition of a trigger filter, found at path.
+func validateCondition(
+	validator *optionValidator,
+	path string,
+	condition schemas.TriggerFilter,
+) {
+	switch condition.Operator {
+	case schemas.FilterAnd, schemas.FilterOr:
+		if len(condition.Conditions) == 0 {
+			validator.fail(joinPath(path, "conditions"), "must not be empty")
+		}
+		for index, nested := range condition.Conditions {
+			nestedPath := fmt.Sprintf("%s[%d]", joinPath(path, "conditions"), index)
+			validateCondition(validator, nestedPath, nested)
+		}
+	case schemas.FilterEquals, schemas.FilterContains:
+		validateComparison(validator, path, condition)
+	case schemas.FilterRegex:
+		validateComparison(validator, path, condition)
+		pattern, ok := condition.Value.(string)
+		if !ok {
+			validator.fail(joinPath(path, "value"), "must be a regular expression")
+		} else if _, err := regexp.Compile(pattern); err != nil {
+			validator.fail(joinPath(path, "value"), "must be a valid regular expression")
+		}
+	case schemas.FilterGreaterThan, schemas.FilterGreaterOrEqual,
+		schemas.FilterLessThan, schemas.FilterLessOrEqual:
+		validateComparison(validator, path, condition)
+		if _, ok := toNumber(condition.Value); !ok {
+			validator.fail(joinPath(path, "value"), "must be a number")
+		}
+	default:
+		validator.fail(joinPath(path, "operator"), "must be one of and, or, equals, contains, "+
+			"regex, gt, gte, lt, lte")
+	}
+}
+
+// validateComparison checks that a comparison has a field and a value.
+func validateComparison(
+	validator *optionValidator,
+	path string,
+	condition schemas.TriggerFilter,
+) {
+	if condition.Field == "" {
+		validator.fail(joinPath(path, "field"), "is required")
+	}
+	if condition.Value == nil {
+		validator.fail(joinPath(path, "value"), "is required")
+	}
+}
+
+// MatchTriggerFilter reports whether the payload of a trigger matches a trigger filter.
+// A comparison of a field missing from the payload does not match.
+//
+// Parameters:
+//   - filter: The trigger filter, a JSON object. An empty or null filter matches every payload.
+//   - payload: The trigger payload, a JSON object.
+//
+// Returns:
+//   - bool: Whether the payload matches the filter.
+//   - error: An error if the filter or the payload is not valid JSON.
+func MatchTriggerFilter(filter json.RawMessage, payload json.RawMessage) (bool, error) {
+	if len(filter) == 0 || bytes.Equal(filter, []byte("null")) {
+		return true, nil
+	}
+	var condition schemas.TriggerFilter
+	err := decodeJSON(filter, &condition)
+	if err != nil {
+		return false, fmt.Errorf("unable to decode trigger filter: %w", err)
+	}
+	fields := map[string]any{}
+	if len(payload) != 0 {
+		err = decodeJSON(payload, &fields)
+		if err != nil {
+			return false, fmt.Errorf("unable to decode trigger payload: %w", err)
+		}
+	}
+	return matchCondition(condition, fields), nil
+}
+
+// matchCondition reports whether the payload fields match one condition of a filter.
+func matchCondition(condition schemas.TriggerFilter, fields map[string]any) bool {
+	switch condition.Operator {
+	case schemas.FilterAnd:
+		for _, nested := range condition.Conditions {
+			if !matchCondition(nested, fields) {
+				return false
+			}
+		}
+		return true
+	case schemas.FilterOr:
+		for _, nested := range condition.Conditions {
+			if matchCondition(nested, fields) {
+				return true
+			}
+		}
+		return false
+	}
+
+	field, ok := lookupField(fields, strings.Split(condition.Field, "."))
+	if !ok {
+		return false
+	}
+	switch condition.Operator {
+	case schemas.FilterEquals:
+		return valuesEqual(field, condition.Value)
+	case schemas.FilterContains:
+		if items, ok := field.([]any); ok {
+			for _, item := range items {
+				if valuesEqual(item, condition.Value) {
+					return true
+				}
+			}
+			return false
+		}
+		return strings.Contains(formatField(field), formatField(condition.Value))
+	case schemas.FilterRegex:
+		pattern, err := regexp.Compile(formatField(condition.Value))
+		return err == nil && pattern.MatchString(formatField(field))
+	default:
+		return compareNumbers(condition.Operator, field, condition.Value)
+	}
+}
+
+// valuesEqual reports whether a payload field equals a filter value, comparing numbers
+// by value and everything else by its text.
+func valuesEqual(field any, value any) bool {
+	fieldNumber, fieldIsNumber := toNumber(field)
+	valueNumber, valueIsNumber := toNumber(value)
+	if fieldIsNumber && valueIsNumber {
+		return fieldNumber == valueNumber
+	}
+	return formatField(field) == formatField(value)
+}
+
+// compareNumbers reports whether a payload field compares with a filter value as the
+// numeric operator requires. Values that are not numbers never match.
+func compareNumbers(operator schemas.TriggerFilterOperator, field any, value any) bool {
+	fieldNumber, ok := toNumber(field)
+	if !ok {
+		return false
+	}
+	valueNumber, ok := toNumber(value)
+	if !ok {
+		return false
+	}
+	switch operator {
+	case schemas.FilterGreaterThan:
+		return fieldNumber > valueNumber
+	case schemas.FilterGreaterOrEqual:
+		return fieldNumber >= valueNumber
+	case schemas.FilterLessThan:
+		return fieldNumber < valueNumber
+	case schemas.FilterLessOrEqual:
+		return fieldNumber <= valueNumber
+	default:
+		return false
+	}
+}
+
+// toNumber converts a decoded JSON number, or a string holding a number, to a float64.
+func toNumber(value any) (float64, bool) {
+	switch typed := value.(type) {
+	case json.Number:
+		number, err := typed.Float64()
+		return number, err == nil
+	case float64:
+		return typed, true
+	case string:
+		number, err := strconv.ParseFloat(strings.TrimSpace(typed), 64)
+		return number, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,81 @@
+package tools_test
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"area/schemas"
+	"area/tools"
+)
+
+func TestMatchTriggerFilter(t *testing.T) {
+	payload := json.RawMessage(
+		`{"sender":"boss@example.com","subject":"[URGENT] Release","size":42,` +
+			`"labels":["work","inbox"],"author":{"login":"octocat"}}`,
+	)
+
+	tests := []struct {
+		name    string
+		filter  string
+		matched bool
+	}{
+		{"no filter", ``, true},
+		{"equals", `{"operator":"equals","field":"sender","value":"boss@example.com"}`, true},
+		{"equals nested", `{"operator":"equals","field":"author.login","value":"hubot"}`, false},
+		{"contains text", `{"operator":"contains","field":"subject","value":"URGENT"}`, true},
+		{"contains item", `{"operator":"contains","field":"labels","value":"work"}`, true},
+		{"regex", `{"operator":"regex","field":"subject","value":"^\\[URGENT\\]"}`, true},
+		{"greater than", `{"operator":"gt","field":"size","value":41}`, true},
+		{"less or equal", `{"operator":"lte","field":"size","value":41}`, false},
+		{"numeric on text", `{"operator":"gt","field":"sender","value":1}`, false},
+		{"missing field", `{"operator":"equals","field":"missing","value":"x"}`, false},
+		{
+			"and group",
+			`{"operator":"and","conditions":[` +
+				`{"operator":"contains","field":"subject","value":"Release"},` +
+				`{"operator":"equals","field":"author.login","value":"hubot"}]}`,
+			false,
+		},
+		{
+			"or group",
+			`{"operator":"or","conditions":[` +
+				`{"operator":"contains","field":"subject","value":"Release"},` +
+				`{"operator":"equals","field":"author.login","value":"hubot"}]}`,
+			true,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			matched, err := tools.MatchTriggerFilter(json.RawMessage(test.filter), payload)
+			assert.NoError(t, err)
+			assert.Equal(t, test.matched, matched)
+		})
+	}
+}
+
+func TestValidateTriggerFilter(t *testing.T) {
+	err := tools.ValidateTriggerFilter(json.RawMessage(
+		`{"operator":"or","conditions":[{"operator":"equals","field":"sender","value":"a"}]}`,
+	))
+	assert.NoError(t, err)
+
+	err = tools.ValidateTriggerFilter(json.RawMessage(
+		`{"operator":"and","conditions":[` +
+			`{"operator":"regex","field":"subject","value":"("},` +
+			`{"operator":"gt","field":"size","value":"big"},` +
+			`{"operator":"like","field":"subject","value":"x"}]}`,
+	))
+	var validationErr *schemas.OptionValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, []schemas.OptionFieldError{
+		{Field: "conditions[0].value", Message: "must be a valid regular expression"},
+		{Field: "conditions[1].value", Message: "must be a number"},
+		{
+			Field:   "conditions[2].operator",
+			Message: "must be one of and, or, equals, contains, regex, gt, gte, lt, lte",
+		},
+	}, validationErr.Fields)
+}